@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"route/internal/app/cli"
+	"route/internal/app/client"
+	order "route/pkg/api/proto/order/v1/order/v1"
+)
+
+// main is entry point of the pickup point client, commands are run by the order service
+func main() {
+	var cfg client.Config
+	flag.StringVar(&cfg.Addr, "addr", "localhost:50051", "address of the order service")
+	flag.BoolVar(&cfg.TLS, "tls", false, "connect over TLS")
+	flag.StringVar(&cfg.CAFile, "tls-ca", "", "CA certificate verifying the server, system roots are used if empty")
+	flag.StringVar(&cfg.ServerName, "tls-server-name", "", "server name in the certificate, the host of addr by default")
+	flag.StringVar(&cfg.PointToken, "point-token", os.Getenv("PVZ_POINT_TOKEN"), "token of the pickup point, PVZ_POINT_TOKEN by default")
+	flag.StringVar(&cfg.ManagerToken, "manager-token", os.Getenv("PVZ_MANAGER_TOKEN"), "manager token, PVZ_MANAGER_TOKEN by default")
+	flag.BoolVar(&cfg.Insecure, "insecure", false, "send tokens without TLS, for a server on the same host only")
+	timeout := flag.Duration("timeout", client.DefaultTimeout, "timeout of a call")
+	flag.Parse()
+
+	conn, err := client.Dial(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if cfg.Insecure && !cfg.TLS {
+		fmt.Fprintln(os.Stderr, "Внимание: соединение без TLS, токены передаются открытым текстом")
+	}
+
+	orders := order.NewOrderServiceClient(conn)
+	commands := cli.NewCommands(client.NewModule(orders, *timeout), client.NewExporter(orders),
+		client.NewDeadLetters(orders, *timeout), client.NewWebhooks(orders, *timeout), client.NewContacts(orders, *timeout))
+
+	cliCommands := cli.New(commands)
+	// Running commands are finished before the connection is closed
+	cliCommands.AddShutdownHook(func() {
+		conn.Close()
+	})
+
+	if err = cliCommands.Run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
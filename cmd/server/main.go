@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	service "route/internal/app/api"
+	"route/internal/app/cache"
+	"route/internal/app/config"
+	"route/internal/app/kafka"
+	"route/internal/app/manifest"
+	"route/internal/app/metrics"
+	"route/internal/app/models"
+	"route/internal/app/module"
+	"route/internal/app/notification"
+	"route/internal/app/outbox"
+	"route/internal/app/repository/cached"
+	"route/internal/app/repository/database"
+	"route/internal/app/repository/postgresql"
+	"route/internal/app/scheduler"
+	"route/internal/app/sink"
+	"route/internal/app/webhook"
+	order "route/pkg/api/proto/order/v1/order/v1"
+)
+
+// main is entry point of the program
+func main() {
+
+	cfg, err := config.New()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	// Initialize KafkaProducer and KafkaConsumer
+	producer, err := kafka.NewKafkaProducer(cfg.KafkaConfig)
+	if err != nil {
+		fmt.Println("Failed to create Kafka producer:", err)
+		os.Exit(1)
+	}
+
+	// Failed messages are forwarded to retry and dead-letter topics by the producer
+	consumer, err := kafka.NewKafkaConsumer(cfg.KafkaConfig, cfg.KafkaConfig.Topic, cfg.KafkaConfig.GroupID, producer)
+	if err != nil {
+		fmt.Println("Failed to create Kafka consumer:", err)
+		os.Exit(1)
+	}
+
+	dlq, err := kafka.NewDeadLetterQueue(cfg.KafkaConfig, producer)
+	if err != nil {
+		fmt.Println("Failed to create Kafka dead-letter queue reader:", err)
+		os.Exit(1)
+	}
+
+	// Create a new connection pool to database
+	pool, err := database.NewPool(cfg.DbUrl)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	defer pool.Close()
+
+	// Create a new Database with connection pool
+	db := database.NewDatabase(pool)
+
+	// Create a new repo with Database
+	repo := postgresql.New(*db)
+
+	// The process serves one pickup point of the network
+	pickupPoints := postgresql.NewPickupPoint(*db)
+	if _, err = pickupPoints.GetPickupPoint(cfg.PickupPoint.ID); err != nil {
+		fmt.Printf("Failed to get pickup point %d: %v\n", cfg.PickupPoint.ID, err)
+		os.Exit(1)
+	}
+
+	// Create in-memory cache
+	imCache := cache.NewIMCache[int, models.Order](cfg.CacheTTL)
+
+	// Wrap repo with read-through/write-through cache, changes made through gRPC are attributed to the gRPC actor.
+	// Repos of other actors share the same cache
+	grpcRepo := cached.New(repo.WithActor(models.NewActor(models.ActorGRPC, "")), imCache)
+
+	// Reload orders cached before the previous shutdown, the snapshot has their IDs only,
+	// so orders changed while the service was down are cached as they are now
+	if cfg.CacheSnapshot != "" {
+		orderIDs, err := imCache.LoadSnapshot(cfg.CacheSnapshot)
+		if err != nil {
+			log.Printf("failed to restore cache snapshot: %v", err)
+		} else if restored, err := grpcRepo.Reload(orderIDs); err != nil {
+			log.Printf("failed to reload orders of cache snapshot: %v", err)
+		} else {
+			log.Printf("restored %d orders from cache snapshot", restored)
+		}
+	}
+
+	// Preload orders waiting for pickup
+	if cfg.CacheWarmUp {
+		loaded, err := cached.New(repo.ForPoint(cfg.PickupPoint.ID), imCache).WarmUp()
+		if err != nil {
+			log.Printf("failed to warm up cache: %v", err)
+		} else {
+			log.Printf("warmed up cache with %d orders", loaded)
+		}
+	}
+
+	// Notify clients about their orders
+	notifications, err := notification.New(cfg.Notifications, postgresql.NewNotification(*db))
+	if err != nil {
+		fmt.Println("Failed to create notifications:", err)
+		os.Exit(1)
+	}
+
+	// Couriers and their acceptance sessions
+	couriers := postgresql.NewCourier(*db)
+	authorizations := postgresql.NewAuthorization(*db)
+	storage := postgresql.NewStorage(*db)
+	stocktakes := postgresql.NewStocktake(*db)
+	reports := postgresql.NewReport(*db)
+
+	// Webhook subscriptions of partner systems
+	webhookRepo := postgresql.NewWebhook(*db)
+	webhooks := webhook.NewRegistry(webhookRepo)
+
+	// Functions run on graceful shutdown after the gRPC server is stopped
+	var shutdownHooks []func()
+
+	// Publish events written by the repository to every configured sink
+	sinks, err := sink.New(cfg.Sinks, producer)
+	if err != nil {
+		fmt.Println("Failed to create event sinks:", err)
+		os.Exit(1)
+	}
+	// Events are also queued for delivery to webhook subscribers
+	sinks = append(sinks, sink.Named{Name: "webhooks", EventSink: webhook.NewSink(webhookRepo)})
+	fanOut := outbox.NewFanOut(postgresql.NewOutbox(*db), sinks)
+
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		fanOut.Run(relayCtx)
+	}()
+
+	// Stop the relays and publish what is left on graceful shutdown
+	shutdownHooks = append(shutdownHooks, func() {
+		stopRelay()
+		<-relayDone
+		if _, err := fanOut.Flush(); err != nil {
+			log.Printf("failed to flush outbox: %v", err)
+		}
+		if err := sink.Close(sinks); err != nil {
+			log.Printf("failed to close event sinks: %v", err)
+		}
+	})
+
+	// Deliver queued webhooks, deliveries left pending are sent after restart
+	dispatcher := webhook.NewDispatcher(webhookRepo, cfg.WebhookConfig)
+	dispatcherCtx, stopDispatcher := context.WithCancel(context.Background())
+	go dispatcher.Run(dispatcherCtx)
+	shutdownHooks = append(shutdownHooks, stopDispatcher)
+
+	// Save cache snapshot on graceful shutdown
+	if cfg.CacheSnapshot != "" {
+		shutdownHooks = append(shutdownHooks, func() {
+			if err := imCache.SaveSnapshot(cfg.CacheSnapshot); err != nil {
+				log.Printf("failed to save cache snapshot: %v", err)
+			}
+		})
+	}
+
+	// Read events back from Kafka
+	if cfg.Sinks.Has(config.OutputKafka) {
+		for _, eventType := range []models.EventType{models.OrderAccepted, models.OrderIssued, models.OrderReturnedToCourier,
+			models.ReturnAccepted, models.OrderExpired, models.OrderRefused,
+			models.OrderLocked, models.OrderUnlocked, models.OrderInTransit, models.OrderTransferred,
+			models.ManifestItemAccepted, models.ManifestItemRejected} {
+			consumer.Handle(eventType, kafka.LogHandler)
+		}
+		shutdownHooks = append(shutdownHooks, startConsumer(consumer))
+	}
+
+	// Accept orders from courier manifests, acknowledgements are sent to the events topic
+	if cfg.KafkaConfig.ManifestTopic != "" {
+		manifestConsumer, err := kafka.NewKafkaConsumer(cfg.KafkaConfig, cfg.KafkaConfig.ManifestTopic, cfg.KafkaConfig.GroupID+"-manifests", producer)
+		if err != nil {
+			fmt.Println("Failed to create Kafka manifest consumer:", err)
+			os.Exit(1)
+		}
+
+		manifestRepo := cached.New(repo.WithActor(models.NewActor(models.ActorKafka, "courier-manifest")), imCache)
+		ingestor := manifest.NewIngestor(module.New(manifestRepo).WithNotifications(notifications).ForPoint(cfg.PickupPoint.ID),
+			manifestRepo.ForPoint(cfg.PickupPoint.ID), postgresql.NewManifest(*db), producer)
+		manifestConsumer.Handle(models.CourierManifest, ingestor.Handle)
+		shutdownHooks = append(shutdownHooks, startConsumer(manifestConsumer))
+	}
+
+	// Create a new gRPC server, pvz clients connect to it over TLS if the certificate is set
+	var serverOpts []grpc.ServerOption
+	if cfg.ServerConfig.TLSCertFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(cfg.ServerConfig.TLSCertFile, cfg.ServerConfig.TLSKeyFile)
+		if err != nil {
+			fmt.Println("Failed to load TLS certificate:", err)
+			os.Exit(1)
+		}
+		serverOpts = append(serverOpts, grpc.Creds(creds))
+	}
+	grpcServer := grpc.NewServer(serverOpts...)
+
+	grpcModule := module.New(grpcRepo).WithNotifications(notifications).WithCouriers(couriers).WithAuthorizations(authorizations).
+		WithPickupPoints(pickupPoints).WithStorage(storage).WithStocktakes(stocktakes).
+		WithReports(reports).ForPoint(cfg.PickupPoint.ID)
+	orderService := service.New(grpcModule, webhooks).
+		WithDeadLetters(dlq).
+		WithContacts(notifications).
+		WithManagerToken(cfg.ServerConfig.ManagerToken).
+		WithPointTokens(cfg.PickupPoint.Tokens, func(pointID int) module.Module { return grpcModule.ForPoint(pointID) })
+
+	// Register the service with the server
+	order.RegisterOrderServiceServer(grpcServer, orderService)
+
+	listener, err := net.Listen("tcp", cfg.ServerConfig.GrpcPort)
+	log.Println("Start")
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+	log.Println("gRPC server listening on", cfg.ServerConfig.GrpcPort)
+
+	// Run periodic jobs, jobs shared by replicas are run by the replica holding the job's lock
+	jobs, err := scheduler.New(postgresql.NewScheduler(*db), cfg.Scheduler.Tick)
+	if err != nil {
+		log.Fatalf("failed to create scheduler: %v", err)
+	}
+	// Jobs run once for the whole network, so their repo is not limited to a pickup point
+	schedulerRepo := cached.New(repo.WithActor(models.NewActor(models.ActorSystem, "scheduler")), imCache)
+	jobs.Register(scheduler.ExpireOrders(schedulerRepo, cfg.Scheduler.ExpireEvery))
+	jobs.Register(scheduler.ReturnList(schedulerRepo,
+		scheduler.DailyAt{Hour: cfg.Scheduler.ReturnListHour, Minute: cfg.Scheduler.ReturnListMinute}, cfg.Scheduler.ReturnListDir))
+	jobs.Register(scheduler.SendReminders(notifications, cfg.Scheduler.RemindEvery, cfg.Notifications.ReminderBefore))
+	jobs.Register(scheduler.PurgeCache(imCache, cfg.Scheduler.CachePurgeEvery))
+	jobs.Register(scheduler.PurgeOutbox(postgresql.NewOutbox(*db), cfg.Scheduler.OutboxPurgeEvery, cfg.Scheduler.OutboxRetention))
+
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		jobs.Run(schedulerCtx)
+	}()
+	shutdownHooks = append(shutdownHooks, func() {
+		stopScheduler()
+		<-schedulerDone
+	})
+
+	go func() {
+		if err = grpcServer.Serve(listener); err != nil {
+			log.Fatalf("failed to serve: %v", err)
+		}
+	}()
+
+	// Register custom metrics
+	metrics.Init()
+
+	go func() {
+		http.Handle("/metrics", promhttp.Handler())
+		log.Fatal(http.ListenAndServe(":9090", nil))
+	}()
+
+	// Wait for a stop signal, running calls are finished before shutdown hooks
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+	log.Println("shutting down")
+	grpcServer.GracefulStop()
+	for _, hook := range shutdownHooks {
+		hook()
+	}
+	log.Println("stopped")
+}
+
+// startConsumer runs the consumer in background, the returned function makes it leave the group
+func startConsumer(consumer *kafka.KafkaConsumer) func() {
+	ctx, stop := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := consumer.Run(ctx); err != nil {
+			log.Printf("kafka consumer stopped: %v", err)
+		}
+	}()
+
+	return func() {
+		stop()
+		<-done
+	}
+}
@@ -1,13 +1,23 @@
 package service
 
 import (
+	"bufio"
 	"context"
+	"crypto/subtle"
+	"errors"
+	"io"
 	"log"
+	"os"
+	"time"
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"route/internal/app/exporter"
 	"route/internal/app/models"
 	"route/internal/app/module"
+	"route/internal/app/repository/postgresql"
+	"route/internal/app/webhook"
 	order "route/pkg/api/proto/order/v1/order/v1"
 )
 
@@ -15,97 +25,996 @@ type Service interface {
 	AcceptOrder(context.Context, *order.OrderRequest) (*order.OrderResponse, error)
 	ReturnOrder(context.Context, *order.OrderRequest) (*order.OrderResponse, error)
 	IssueOrder(context.Context, *order.OrderRequest) (*order.OrderResponse, error)
+	UnlockOrder(context.Context, *order.OrderRequest) (*order.OrderResponse, error)
+	RefuseOrder(context.Context, *order.RefuseOrderRequest) (*order.OrderResponse, error)
 	ListOrders(context.Context, *order.ListOrdersRequest) (*order.ListResponse, error)
 	AcceptReturn(context.Context, *order.OrderRequest) (*order.OrderResponse, error)
 	ListReturns(context.Context, *order.ListReturnsRequest) (*order.ListResponse, error)
+	CreateWebhook(context.Context, *order.CreateWebhookRequest) (*order.WebhookInfo, error)
+	ListWebhooks(context.Context, *order.ListWebhooksRequest) (*order.ListWebhooksResponse, error)
+	DeleteWebhook(context.Context, *order.DeleteWebhookRequest) (*order.OrderResponse, error)
+	ListWebhookDeliveries(context.Context, *order.ListWebhookDeliveriesRequest) (*order.ListWebhookDeliveriesResponse, error)
+	CreateCourier(context.Context, *order.CreateCourierRequest) (*order.CourierInfo, error)
+	OpenSession(context.Context, *order.OpenSessionRequest) (*order.SessionInfo, error)
+	CloseSession(context.Context, *order.CloseSessionRequest) (*order.SessionSummary, error)
+	CreateAuthorization(context.Context, *order.CreateAuthorizationRequest) (*order.AuthorizationInfo, error)
+	ListAuthorizations(context.Context, *order.ListAuthorizationsRequest) (*order.ListAuthorizationsResponse, error)
+	CreatePickupPoint(context.Context, *order.CreatePickupPointRequest) (*order.PickupPointInfo, error)
+	ListPickupPoints(context.Context, *order.ListPickupPointsRequest) (*order.ListPickupPointsResponse, error)
+	CreateTransfer(context.Context, *order.CreateTransferRequest) (*order.TransferInfo, error)
+	ReceiveTransfer(context.Context, *order.ReceiveTransferRequest) (*order.TransferInfo, error)
+	CreateRack(context.Context, *order.CreateRackRequest) (*order.RackInfo, error)
+	ListCells(context.Context, *order.ListCellsRequest) (*order.ListCellsResponse, error)
+	Stocktake(order.OrderService_StocktakeServer) error
+	ExportOrders(*order.ExportOrdersRequest, order.OrderService_ExportOrdersServer) error
+	GetReport(context.Context, *order.GetReportRequest) (*order.ReportInfo, error)
+	AcceptOrders(context.Context, *order.AcceptOrdersRequest) (*order.AcceptOrdersResponse, error)
+	CreateReturnManifest(context.Context, *order.CreateReturnManifestRequest) (*order.ReturnManifestInfo, error)
+	SetContact(context.Context, *order.SetContactRequest) (*order.OrderResponse, error)
+	ListDeadLetters(context.Context, *order.ListDeadLettersRequest) (*order.ListDeadLettersResponse, error)
+	ReplayDeadLetter(context.Context, *order.ReplayDeadLetterRequest) (*order.OrderResponse, error)
 }
 
+type WebhookRegistry interface {
+	Subscribe(url string, eventTypes []models.EventType, secret string) (*models.WebhookSubscription, error)
+	List() ([]models.WebhookSubscription, error)
+	Unsubscribe(id int64) error
+	ListDeliveries(subscriptionID int64, limit int) ([]models.WebhookDeliveryLog, error)
+}
+
+type DeadLetterQueue interface {
+	List(topic string, limit int) ([]models.DeadLetter, error)
+	Replay(topic string, partition int32, offset int64) error
+}
+
+type Contacts interface {
+	SetContact(contact models.Contact) error
+}
+
+const (
+	// managerTokenKey is the metadata key of the manager token
+	managerTokenKey = "x-manager-token"
+	// pointTokenKey is the metadata key of the token identifying the caller's pickup point
+	pointTokenKey = "x-point-token"
+)
+
 type OrderService struct {
-	mod module.Module
+	mod          module.Module
+	webhooks     WebhookRegistry
+	dlq          DeadLetterQueue
+	contacts     Contacts
+	managerToken string
+	pointTokens  map[string]int
+	forPoint     func(pointID int) module.Module
 	order.UnimplementedOrderServiceServer
 }
 
-func New(mod module.Module) *OrderService {
-	return &OrderService{mod: mod}
+func New(mod module.Module, webhooks WebhookRegistry) *OrderService {
+	return &OrderService{mod: mod, webhooks: webhooks}
 }
 
-func (o *OrderService) AcceptOrder(_ context.Context, req *order.OrderRequest) (*order.OrderResponse, error) {
-	or := orderToDomain(req)
-	err := o.mod.AcceptOrder(&or, models.ToPackageType(req.GetPackagingType()))
+// WithDeadLetters allows listing and replaying undelivered events
+func (o *OrderService) WithDeadLetters(dlq DeadLetterQueue) *OrderService {
+	o.dlq = dlq
+	return o
+}
+
+// WithContacts allows setting contacts clients are notified by
+func (o *OrderService) WithContacts(contacts Contacts) *OrderService {
+	o.contacts = contacts
+	return o
+}
+
+// WithManagerToken allows callers with the token in metadata to issue orders without pickup codes and unlock them
+func (o *OrderService) WithManagerToken(token string) *OrderService {
+	o.managerToken = token
+	return o
+}
+
+// WithPointTokens makes callers identify their pickup point with a token in metadata,
+// forPoint returns the module serving the point
+func (o *OrderService) WithPointTokens(tokens map[string]int, forPoint func(pointID int) module.Module) *OrderService {
+	o.pointTokens = tokens
+	o.forPoint = forPoint
+	return o
+}
+
+// moduleFor returns the module serving the caller's pickup point. Without point tokens
+// all callers are served by the pickup point of the process
+func (o *OrderService) moduleFor(ctx context.Context) (module.Module, error) {
+	if len(o.pointTokens) == 0 {
+		return o.mod, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, token := range md.Get(pointTokenKey) {
+		if pointID, ok := o.pointTokens[token]; ok {
+			return o.forPoint(pointID), nil
+		}
+	}
+	return nil, status.Error(codes.Unauthenticated, "неверный токен пункта выдачи")
+}
+
+// checkManager checks that the caller presented the manager token
+func (o *OrderService) checkManager(ctx context.Context) error {
+	if o.managerToken == "" {
+		return status.Error(codes.PermissionDenied, "действия менеджера отключены")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, token := range md.Get(managerTokenKey) {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(o.managerToken)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.PermissionDenied, "неверный токен менеджера")
+}
+
+func (o *OrderService) AcceptOrder(ctx context.Context, req *order.OrderRequest) (*order.OrderResponse, error) {
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	or, err := acceptedOrderToDomain(req)
+	if err != nil {
+		return nil, err
+	}
+	err = mod.AcceptOrder(&or, models.ToPackageType(req.GetPackagingType()))
+	var validationErr module.ValidationError
+	if errors.As(err, &validationErr) {
+		// The order is rejected, e.g. there is no free cell for it
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if err != nil {
 		log.Printf("Error accepting order: %v", err)
 		return nil, status.Error(codes.Internal, "internal error")
 	}
-	return &order.OrderResponse{Status: "success"}, nil
+	return &order.OrderResponse{Status: "success", PickupCode: or.PickupCode, Cell: or.Cell}, nil
 }
 
-func (o *OrderService) ReturnOrder(_ context.Context, req *order.OrderRequest) (*order.OrderResponse, error) {
+func (o *OrderService) ReturnOrder(ctx context.Context, req *order.OrderRequest) (*order.OrderResponse, error) {
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	or := orderToDomain(req)
-	err := o.mod.ReturnOrder(or.OrderID)
+	err = mod.ReturnOrder(or.OrderID)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 	return &order.OrderResponse{Status: "success"}, nil
 }
 
-func (o *OrderService) IssueOrder(_ context.Context, req *order.OrderRequest) (*order.OrderResponse, error) {
-	or := orderToDomain(req)
-	err := o.mod.IssueOrder(or.OrderID)
+func (o *OrderService) IssueOrder(ctx context.Context, req *order.OrderRequest) (*order.OrderResponse, error) {
+	if req.GetOverrideBy() != "" {
+		if err := o.checkManager(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	mod, err := o.moduleFor(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
+	}
+
+	err = mod.IssueOrder(models.IssueRequest{
+		OrderID:    int(req.GetOrderId()),
+		PickupCode: req.GetPickupCode(),
+		OverrideBy: req.GetOverrideBy(),
+		Recipient:  req.GetRecipient(),
+	})
+	if err != nil {
+		return nil, moduleError(err)
+	}
+	return &order.OrderResponse{Status: "success"}, nil
+}
+
+func (o *OrderService) UnlockOrder(ctx context.Context, req *order.OrderRequest) (*order.OrderResponse, error) {
+	if err := o.checkManager(ctx); err != nil {
+		return nil, err
+	}
+
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mod.UnlockOrder(int(req.GetOrderId()))
+	if err != nil {
+		return nil, moduleError(err)
 	}
 	return &order.OrderResponse{Status: "success"}, nil
 }
 
-func (o *OrderService) ListOrders(_ context.Context, req *order.ListOrdersRequest) (*order.ListResponse, error) {
-	listOr, err := o.mod.ListOrders(int(req.GetUserId()), int(req.GetLastN()))
+func (o *OrderService) RefuseOrder(ctx context.Context, req *order.RefuseOrderRequest) (*order.OrderResponse, error) {
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mod.RefuseOrder(int(req.GetOrderId()), req.GetReason())
+	if err != nil {
+		return nil, moduleError(err)
+	}
+	return &order.OrderResponse{Status: "success"}, nil
+}
+
+func (o *OrderService) ListOrders(ctx context.Context, req *order.ListOrdersRequest) (*order.ListResponse, error) {
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	listOr, err := mod.ListOrders(int(req.GetUserId()), int(req.GetLastN()))
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 	orders := make([]*order.OrderInfo, len(listOr))
 	for i, or := range listOr {
-		orders[i] = &order.OrderInfo{
-			OrderId: int32(or.OrderID),
-			UserId:  int32(or.UserID),
-			Status:  "Success",
-			Weight:  or.Weight,
-		}
+		orders[i] = orderToProto(or)
 	}
 	return &order.ListResponse{Orders: orders}, nil
 }
 
-func (o *OrderService) AcceptReturn(_ context.Context, req *order.OrderRequest) (*order.OrderResponse, error) {
+func (o *OrderService) AcceptReturn(ctx context.Context, req *order.OrderRequest) (*order.OrderResponse, error) {
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	or := orderToDomain(req)
-	err := o.mod.AcceptReturn(or.OrderID, or.UserID)
+	err = mod.AcceptReturn(or.OrderID, or.UserID)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 	return &order.OrderResponse{Status: "success"}, nil
 }
 
-func (o *OrderService) ListReturns(_ context.Context, req *order.ListReturnsRequest) (*order.ListResponse, error) {
-	listRet, err := o.mod.ListReturns(int(req.GetPage()), int(req.GetPageSize()))
+func (o *OrderService) ListReturns(ctx context.Context, req *order.ListReturnsRequest) (*order.ListResponse, error) {
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	listRet, err := mod.ListReturns(int(req.GetPage()), int(req.GetPageSize()))
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 	orders := make([]*order.OrderInfo, len(listRet))
 	for i, or := range listRet {
-		orders[i] = &order.OrderInfo{
-			OrderId: int32(or.OrderID),
-			UserId:  int32(or.UserID),
-			Status:  "Success",
-			Weight:  or.Weight,
-		}
+		orders[i] = orderToProto(or)
 	}
 	return &order.ListResponse{Orders: orders}, nil
 }
 
+// Webhooks send order events to any URL, so only managers manage them
+func (o *OrderService) CreateWebhook(ctx context.Context, req *order.CreateWebhookRequest) (*order.WebhookInfo, error) {
+	if err := o.checkManager(ctx); err != nil {
+		return nil, err
+	}
+
+	eventTypes := make([]models.EventType, len(req.GetEventTypes()))
+	for i, eventType := range req.GetEventTypes() {
+		eventTypes[i] = models.EventType(eventType)
+	}
+
+	sub, err := o.webhooks.Subscribe(req.GetUrl(), eventTypes, req.GetSecret())
+	if err != nil {
+		return nil, webhookError(err)
+	}
+
+	info := webhookToProto(*sub)
+	info.Secret = sub.Secret
+	return info, nil
+}
+
+func (o *OrderService) ListWebhooks(ctx context.Context, _ *order.ListWebhooksRequest) (*order.ListWebhooksResponse, error) {
+	if err := o.checkManager(ctx); err != nil {
+		return nil, err
+	}
+
+	subs, err := o.webhooks.List()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	webhooks := make([]*order.WebhookInfo, len(subs))
+	for i, sub := range subs {
+		webhooks[i] = webhookToProto(sub)
+	}
+	return &order.ListWebhooksResponse{Webhooks: webhooks}, nil
+}
+
+func (o *OrderService) DeleteWebhook(ctx context.Context, req *order.DeleteWebhookRequest) (*order.OrderResponse, error) {
+	if err := o.checkManager(ctx); err != nil {
+		return nil, err
+	}
+
+	err := o.webhooks.Unsubscribe(req.GetId())
+	if errors.Is(err, postgresql.ErrWebhookNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &order.OrderResponse{Status: "success"}, nil
+}
+
+func (o *OrderService) ListWebhookDeliveries(ctx context.Context, req *order.ListWebhookDeliveriesRequest) (*order.ListWebhookDeliveriesResponse, error) {
+	if err := o.checkManager(ctx); err != nil {
+		return nil, err
+	}
+
+	logs, err := o.webhooks.ListDeliveries(req.GetWebhookId(), int(req.GetLimit()))
+	if err != nil {
+		return nil, webhookError(err)
+	}
+
+	deliveries := make([]*order.WebhookDeliveryInfo, len(logs))
+	for i, entry := range logs {
+		deliveries[i] = &order.WebhookDeliveryInfo{
+			DeliveryId: entry.DeliveryID,
+			EventId:    entry.EventID,
+			EventType:  string(entry.EventType),
+			Attempt:    int32(entry.Attempt),
+			StatusCode: int32(entry.StatusCode),
+			Error:      entry.Error,
+			DurationMs: entry.Duration.Milliseconds(),
+			CreatedAt:  entry.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return &order.ListWebhookDeliveriesResponse{Deliveries: deliveries}, nil
+}
+
+func (o *OrderService) CreateCourier(ctx context.Context, req *order.CreateCourierRequest) (*order.CourierInfo, error) {
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	courier, err := mod.CreateCourier(req.GetName(), req.GetCompany())
+	if err != nil {
+		return nil, moduleError(err)
+	}
+	return &order.CourierInfo{Id: int32(courier.ID), Name: courier.Name, Company: courier.Company}, nil
+}
+
+func (o *OrderService) OpenSession(ctx context.Context, req *order.OpenSessionRequest) (*order.SessionInfo, error) {
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := mod.OpenSession(int(req.GetCourierId()), int(req.GetExpectedCount()))
+	if err != nil {
+		return nil, moduleError(err)
+	}
+	return sessionToProto(*session), nil
+}
+
+func (o *OrderService) CloseSession(ctx context.Context, req *order.CloseSessionRequest) (*order.SessionSummary, error) {
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := mod.CloseSession(req.GetSessionId())
+	if err != nil {
+		return nil, moduleError(err)
+	}
+
+	rejections := make([]*order.SessionRejection, len(summary.Rejections))
+	for i, rejection := range summary.Rejections {
+		rejections[i] = &order.SessionRejection{OrderId: int32(rejection.OrderID), Reason: rejection.Reason}
+	}
+	return &order.SessionSummary{
+		Session:     sessionToProto(summary.Session),
+		Accepted:    int32(summary.Accepted),
+		TotalWeight: summary.TotalWeight,
+		Missing:     int32(summary.Missing()),
+		Rejections:  rejections,
+	}, nil
+}
+
+func (o *OrderService) CreateAuthorization(ctx context.Context, req *order.CreateAuthorizationRequest) (*order.AuthorizationInfo, error) {
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	validUntil, err := time.Parse(time.RFC3339, req.GetValidUntil())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "неверный формат даты")
+	}
+
+	auth, err := mod.CreateAuthorization(models.Authorization{
+		UserID:     int(req.GetUserId()),
+		Recipient:  req.GetRecipient(),
+		OrderID:    int(req.GetOrderId()),
+		ValidUntil: validUntil,
+	})
+	if err != nil {
+		return nil, moduleError(err)
+	}
+	return authorizationToProto(*auth), nil
+}
+
+func (o *OrderService) ListAuthorizations(ctx context.Context, req *order.ListAuthorizationsRequest) (*order.ListAuthorizationsResponse, error) {
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	auths, err := mod.ListAuthorizations(int(req.GetUserId()))
+	if err != nil {
+		return nil, moduleError(err)
+	}
+
+	infos := make([]*order.AuthorizationInfo, len(auths))
+	for i, auth := range auths {
+		infos[i] = authorizationToProto(auth)
+	}
+	return &order.ListAuthorizationsResponse{Authorizations: infos}, nil
+}
+
+// Pickup points make up the network, so only managers add them
+func (o *OrderService) CreatePickupPoint(ctx context.Context, req *order.CreatePickupPointRequest) (*order.PickupPointInfo, error) {
+	if err := o.checkManager(ctx); err != nil {
+		return nil, err
+	}
+
+	point, err := o.mod.CreatePickupPoint(req.GetName(), req.GetAddress())
+	if err != nil {
+		return nil, moduleError(err)
+	}
+	return &order.PickupPointInfo{Id: int32(point.ID), Name: point.Name, Address: point.Address}, nil
+}
+
+// The network is listed to managers and to pickup points choosing where to transfer orders
+func (o *OrderService) ListPickupPoints(ctx context.Context, _ *order.ListPickupPointsRequest) (*order.ListPickupPointsResponse, error) {
+	if err := o.checkManager(ctx); err != nil {
+		if _, pointErr := o.moduleFor(ctx); pointErr != nil {
+			return nil, pointErr
+		}
+	}
+
+	points, err := o.mod.ListPickupPoints()
+	if err != nil {
+		return nil, moduleError(err)
+	}
+
+	infos := make([]*order.PickupPointInfo, len(points))
+	for i, point := range points {
+		infos[i] = &order.PickupPointInfo{Id: int32(point.ID), Name: point.Name, Address: point.Address}
+	}
+	return &order.ListPickupPointsResponse{PickupPoints: infos}, nil
+}
+
+func (o *OrderService) CreateTransfer(ctx context.Context, req *order.CreateTransferRequest) (*order.TransferInfo, error) {
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orderIDs := make([]int, len(req.GetOrderIds()))
+	for i, orderID := range req.GetOrderIds() {
+		orderIDs[i] = int(orderID)
+	}
+
+	transfer, err := mod.CreateTransfer(int(req.GetToPointId()), orderIDs)
+	if err != nil {
+		return nil, moduleError(err)
+	}
+	return transferToProto(*transfer), nil
+}
+
+func (o *OrderService) ReceiveTransfer(ctx context.Context, req *order.ReceiveTransferRequest) (*order.TransferInfo, error) {
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer, err := mod.ReceiveTransfer(req.GetTransferId())
+	if err != nil {
+		return nil, moduleError(err)
+	}
+	return transferToProto(*transfer), nil
+}
+
+func (o *OrderService) CreateRack(ctx context.Context, req *order.CreateRackRequest) (*order.RackInfo, error) {
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rack, err := mod.CreateRack(req.GetName(), int(req.GetCells()), models.ToCellSize(req.GetSize()), req.GetMaxWeight())
+	if err != nil {
+		return nil, moduleError(err)
+	}
+
+	info := &order.RackInfo{Id: int32(rack.ID), Name: rack.Name, Cells: make([]*order.CellInfo, len(rack.Cells))}
+	for i, cell := range rack.Cells {
+		info.Cells[i] = cellToProto(cell)
+	}
+	return info, nil
+}
+
+func (o *OrderService) ListCells(ctx context.Context, _ *order.ListCellsRequest) (*order.ListCellsResponse, error) {
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cells, err := mod.ListCells()
+	if err != nil {
+		return nil, moduleError(err)
+	}
+
+	infos := make([]*order.CellInfo, len(cells))
+	for i, cell := range cells {
+		infos[i] = cellToProto(cell)
+	}
+	return &order.ListCellsResponse{Cells: infos}, nil
+}
+
+// Stocktake collects scanned order IDs until the client closes the stream and replies with the saved report
+func (o *OrderService) Stocktake(stream order.OrderService_StocktakeServer) error {
+	mod, err := o.moduleFor(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	var scanned []int
+	for {
+		scan, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		for _, orderID := range scan.GetOrderIds() {
+			scanned = append(scanned, int(orderID))
+		}
+	}
+
+	report, err := mod.Stocktake(scanned)
+	if err != nil {
+		return moduleError(err)
+	}
+	return stream.SendAndClose(stocktakeToProto(*report))
+}
+
+// exportChunkSize is the maximum size of a file chunk sent by ExportOrders
+const exportChunkSize = 64 * 1024
+
+// ExportOrders writes the export file to a temporary file and then sends it in chunks. The orders are read
+// in one transaction, so the file is sent only after the transaction ends and a slow client doesn't hold it open
+func (o *OrderService) ExportOrders(req *order.ExportOrdersRequest, stream order.OrderService_ExportOrdersServer) error {
+	mod, err := o.moduleFor(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	format, err := exporter.ParseFormat(req.GetFormat())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	filter := models.ExportFilter{
+		Kind:     models.ExportKind(req.GetKind()),
+		UserID:   int(req.GetUserId()),
+		LastN:    int(req.GetLastN()),
+		Page:     int(req.GetPage()),
+		PageSize: int(req.GetPageSize()),
+	}
+	if filter.Kind == "" {
+		filter.Kind = models.OrdersExport
+	}
+	if !filter.Kind.Valid() {
+		return status.Errorf(codes.InvalidArgument, "неизвестный тип выгрузки: %s", filter.Kind)
+	}
+
+	file, err := os.CreateTemp("", "export-*")
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	buffered := bufio.NewWriter(file)
+	count, err := exporter.New(mod).Export(buffered, format, filter)
+	if err != nil {
+		return moduleError(err)
+	}
+	if err = buffered.Flush(); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	w := bufio.NewWriterSize(chunkWriter{stream: stream}, exportChunkSize)
+	if _, err = io.Copy(w, file); err != nil {
+		return err
+	}
+	if err = w.Flush(); err != nil {
+		return err
+	}
+	return stream.Send(&order.ExportChunk{Exported: int32(count)})
+}
+
+// chunkWriter sends written bytes to the export stream in chunks of at most exportChunkSize
+type chunkWriter struct {
+	stream order.OrderService_ExportOrdersServer
+}
+
+func (c chunkWriter) Write(p []byte) (int, error) {
+	for sent := 0; sent < len(p); sent += exportChunkSize {
+		end := min(sent+exportChunkSize, len(p))
+		if err := c.stream.Send(&order.ExportChunk{Data: p[sent:end]}); err != nil {
+			return sent, err
+		}
+	}
+	return len(p), nil
+}
+
+// reportDateLayout is the layout of report period dates
+const reportDateLayout = "2006-01-02"
+
+// GetReport returns the report of the caller's pickup point, the period is today if dates are omitted
+func (o *OrderService) GetReport(ctx context.Context, req *order.GetReportRequest) (*order.ReportInfo, error) {
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := parseReportDate(req.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseReportDate(req.GetTo())
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := mod.Report(from, to)
+	if err != nil {
+		return nil, moduleError(err)
+	}
+	return reportToProto(*report), nil
+}
+
+func parseReportDate(date string) (time.Time, error) {
+	if date == "" {
+		return time.Now(), nil
+	}
+	t, err := time.ParseInLocation(reportDateLayout, date, time.Local)
+	if err != nil {
+		return time.Time{}, status.Errorf(codes.InvalidArgument, "неверный формат даты: %s", date)
+	}
+	return t, nil
+}
+
+// AcceptOrders accepts the orders of a batch, an order failing checks doesn't stop the others
+func (o *OrderService) AcceptOrders(ctx context.Context, req *order.AcceptOrdersRequest) (*order.AcceptOrdersResponse, error) {
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reqs := make([]models.AcceptRequest, len(req.GetOrders()))
+	for i, orderReq := range req.GetOrders() {
+		or, err := acceptedOrderToDomain(orderReq)
+		if err != nil {
+			return nil, err
+		}
+		reqs[i] = models.AcceptRequest{Order: &or, PackagingType: models.ToPackageType(orderReq.GetPackagingType())}
+	}
+
+	errs := mod.AcceptOrders(reqs, req.GetDryRun())
+	results := make([]*order.AcceptOrderResult, len(reqs))
+	for i, acceptReq := range reqs {
+		if errs[i] != nil {
+			results[i] = &order.AcceptOrderResult{Error: errs[i].Error()}
+			continue
+		}
+		results[i] = &order.AcceptOrderResult{PickupCode: acceptReq.Order.PickupCode, Cell: acceptReq.Order.Cell}
+	}
+	return &order.AcceptOrdersResponse{Results: results}, nil
+}
+
+func (o *OrderService) CreateReturnManifest(ctx context.Context, req *order.CreateReturnManifestRequest) (*order.ReturnManifestInfo, error) {
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := mod.CreateReturnManifest(int(req.GetCourierId()))
+	if err != nil {
+		return nil, moduleError(err)
+	}
+
+	info := &order.ReturnManifestInfo{
+		Id:        manifest.ID,
+		CourierId: int32(manifest.CourierID),
+		CreatedAt: manifest.CreatedAt.Format(time.RFC3339),
+		Orders:    make([]*order.OrderInfo, len(manifest.Orders)),
+	}
+	for i, or := range manifest.Orders {
+		info.Orders[i] = orderToProto(or)
+	}
+	return info, nil
+}
+
+// Notifications carry pickup codes, so contacts are set only for clients with orders at the caller's point
+func (o *OrderService) SetContact(ctx context.Context, req *order.SetContactRequest) (*order.OrderResponse, error) {
+	if o.contacts == nil {
+		return nil, status.Error(codes.Unimplemented, "уведомления клиентов отключены")
+	}
+
+	mod, err := o.moduleFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := mod.ListOrders(int(req.GetUserId()), 1)
+	if err != nil {
+		return nil, moduleError(err)
+	}
+	if len(orders) == 0 {
+		return nil, status.Errorf(codes.NotFound, "у клиента %d нет заказов в пункте выдачи", req.GetUserId())
+	}
+
+	err = o.contacts.SetContact(models.Contact{
+		UserID: int(req.GetUserId()),
+		Phone:  req.GetPhone(),
+		Email:  req.GetEmail(),
+		Locale: req.GetLocale(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &order.OrderResponse{Status: "success"}, nil
+}
+
+// Dead letters contain events of all clients, so only managers read and replay them
+func (o *OrderService) ListDeadLetters(ctx context.Context, req *order.ListDeadLettersRequest) (*order.ListDeadLettersResponse, error) {
+	if o.dlq == nil {
+		return nil, status.Error(codes.Unimplemented, "очередь недоставленных сообщений отключена")
+	}
+	if err := o.checkManager(ctx); err != nil {
+		return nil, err
+	}
+
+	limit := int(req.GetLimit())
+	if limit <= 0 || limit > models.MaxDeadLetters {
+		return nil, status.Errorf(codes.InvalidArgument, "параметр limit должен быть от 1 до %d", models.MaxDeadLetters)
+	}
+
+	letters, err := o.dlq.List(req.GetTopic(), limit)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	infos := make([]*order.DeadLetterInfo, len(letters))
+	for i, letter := range letters {
+		infos[i] = &order.DeadLetterInfo{
+			Partition:     letter.Partition,
+			Offset:        letter.Offset,
+			Time:          letter.Time.Format(time.RFC3339),
+			Key:           letter.Key,
+			EventType:     string(letter.EventType),
+			Error:         letter.Error,
+			Attempts:      int32(letter.Attempts),
+			OriginalTopic: letter.OriginalTopic,
+		}
+	}
+	return &order.ListDeadLettersResponse{DeadLetters: infos}, nil
+}
+
+func (o *OrderService) ReplayDeadLetter(ctx context.Context, req *order.ReplayDeadLetterRequest) (*order.OrderResponse, error) {
+	if o.dlq == nil {
+		return nil, status.Error(codes.Unimplemented, "очередь недоставленных сообщений отключена")
+	}
+	if err := o.checkManager(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := o.dlq.Replay(req.GetTopic(), req.GetPartition(), req.GetOffset()); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &order.OrderResponse{Status: "success"}, nil
+}
+
 func (o *OrderService) mustEmbedUnimplementedOrderServiceServer() {}
 
+// moduleError converts violations of business rules to InvalidArgument and other errors to Internal
+func moduleError(err error) error {
+	var validationErr module.ValidationError
+	if errors.As(err, &validationErr) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// webhookError converts rejected subscriptions to InvalidArgument and other errors to Internal
+func webhookError(err error) error {
+	var validationErr webhook.ValidationError
+	if errors.As(err, &validationErr) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func sessionToProto(session models.AcceptanceSession) *order.SessionInfo {
+	info := &order.SessionInfo{
+		Id:            session.ID,
+		CourierId:     int32(session.CourierID),
+		ExpectedCount: int32(session.ExpectedCount),
+		OpenedAt:      session.OpenedAt.Format(time.RFC3339),
+	}
+	if session.Closed() {
+		info.ClosedAt = session.ClosedAt.Format(time.RFC3339)
+	}
+	return info
+}
+
+func stocktakeToProto(stocktake models.Stocktake) *order.StocktakeReport {
+	report := &order.StocktakeReport{
+		Id:            stocktake.ID,
+		PickupPointId: int32(stocktake.PickupPointID),
+		Scanned:       int32(stocktake.Scanned),
+		Stored:        int32(stocktake.Stored),
+		CreatedAt:     stocktake.CreatedAt.Format(time.RFC3339),
+		Discrepancies: make([]*order.StocktakeDiscrepancy, len(stocktake.Discrepancies)),
+	}
+	for i, d := range stocktake.Discrepancies {
+		report.Discrepancies[i] = &order.StocktakeDiscrepancy{OrderId: int32(d.OrderID), Kind: string(d.Kind)}
+	}
+	return report
+}
+
+func reportToProto(report models.Report) *order.ReportInfo {
+	info := &order.ReportInfo{
+		PickupPointId: int32(report.PickupPointID),
+		From:          report.From.Format(reportDateLayout),
+		To:            report.To.Format(reportDateLayout),
+		Rows:          make([]*order.ReportRow, len(report.Rows)),
+		Total:         reportRowToProto(report.Total()),
+	}
+	for i, row := range report.Rows {
+		info.Rows[i] = reportRowToProto(row)
+	}
+	return info
+}
+
+func reportRowToProto(row models.ReportRow) *order.ReportRow {
+	out := &order.ReportRow{
+		PackagingType:    string(row.PackagingType),
+		Accepted:         int32(row.Accepted),
+		Issued:           int32(row.Issued),
+		Returned:         int32(row.Returned),
+		SurchargeRevenue: row.SurchargeRevenue,
+		Refunds:          row.Refunds,
+	}
+	if !row.Day.IsZero() {
+		out.Day = row.Day.Format(reportDateLayout)
+	}
+	return out
+}
+
+func cellToProto(cell models.StorageCell) *order.CellInfo {
+	return &order.CellInfo{
+		Id:        int32(cell.ID),
+		Code:      cell.Code,
+		Size:      cell.Size.String(),
+		MaxWeight: cell.MaxWeight,
+		OrderId:   int32(cell.OrderID),
+	}
+}
+
+func transferToProto(transfer models.Transfer) *order.TransferInfo {
+	info := &order.TransferInfo{
+		Id:          transfer.ID,
+		FromPointId: int32(transfer.FromPointID),
+		ToPointId:   int32(transfer.ToPointID),
+		CreatedAt:   transfer.CreatedAt.Format(time.RFC3339),
+		OrderIds:    make([]int32, len(transfer.Orders)),
+	}
+	if transfer.Received() {
+		info.ReceivedAt = transfer.ReceivedAt.Format(time.RFC3339)
+	}
+	for i, or := range transfer.Orders {
+		info.OrderIds[i] = int32(or.OrderID)
+	}
+	return info
+}
+
+func authorizationToProto(auth models.Authorization) *order.AuthorizationInfo {
+	return &order.AuthorizationInfo{
+		Id:         auth.ID,
+		UserId:     int32(auth.UserID),
+		Recipient:  auth.Recipient,
+		OrderId:    int32(auth.OrderID),
+		ValidUntil: auth.ValidUntil.Format(time.RFC3339),
+		CreatedAt:  auth.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// webhookToProto converts the subscription without its secret
+func webhookToProto(sub models.WebhookSubscription) *order.WebhookInfo {
+	eventTypes := make([]string, len(sub.EventTypes))
+	for i, eventType := range sub.EventTypes {
+		eventTypes[i] = string(eventType)
+	}
+
+	return &order.WebhookInfo{
+		Id:         sub.ID,
+		Url:        sub.URL,
+		EventTypes: eventTypes,
+		CreatedAt:  sub.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// formatTime formats the time as RFC3339, zero time is empty
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func orderToProto(or models.Order) *order.OrderInfo {
+	return &order.OrderInfo{
+		OrderId:             int32(or.OrderID),
+		UserId:              int32(or.UserID),
+		Status:              "Success",
+		Weight:              or.Weight,
+		Deadline:            formatTime(or.Deadline),
+		PickupPointId:       int32(or.PickupPointID),
+		Cell:                or.Cell,
+		Cost:                or.Cost,
+		IsAtPickupPoint:     or.IsAtPickupPoint,
+		ReceivedFromCourier: or.ReceivedFromCourier,
+		IssuedToUser:        or.IssuedToUser,
+		IsReturned:          or.IsReturned,
+		SessionId:           or.SessionID,
+		TransferId:          or.TransferID,
+		IssuedAt:            formatTime(or.IssuedAt),
+		ReceivedBy:          or.ReceivedBy,
+		AuthorizationId:     or.AuthorizationID,
+		IssueOverrideBy:     or.IssueOverrideBy,
+		ExpiredAt:           formatTime(or.ExpiredAt),
+		ReturnedToCourierAt: formatTime(or.ReturnedToCourierAt),
+		RefusedAt:           formatTime(or.RefusedAt),
+		RefusalReason:       or.RefusalReason,
+		PickupFailures:      int32(or.PickupFailures),
+		PickupLastFailureAt: formatTime(or.PickupLastFailureAt),
+		PickupLockedAt:      formatTime(or.PickupLockedAt),
+	}
+}
+
+// acceptedOrderToDomain converts the order to accept, its deadline is optional
+func acceptedOrderToDomain(req *order.OrderRequest) (models.Order, error) {
+	or := orderToDomain(req)
+	or.Cost = req.GetCost()
+	if req.GetDeadline() != "" {
+		deadline, err := time.Parse(time.RFC3339, req.GetDeadline())
+		if err != nil {
+			return models.Order{}, status.Errorf(codes.InvalidArgument, "неверный формат даты: %s", req.GetDeadline())
+		}
+		or.Deadline = deadline
+	}
+	return or, nil
+}
+
 func orderToDomain(req *order.OrderRequest) models.Order {
 	return models.Order{
-		OrderID: int(req.GetOrderId()),
-		UserID:  int(req.GetUserId()),
-		Weight:  req.GetWeight(),
+		OrderID:   int(req.GetOrderId()),
+		UserID:    int(req.GetUserId()),
+		Weight:    req.GetWeight(),
+		SessionID: req.GetSessionId(),
 	}
 }
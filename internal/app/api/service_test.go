@@ -1,15 +1,26 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
 	"testing"
+	"time"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"route/internal/app/models"
+	"route/internal/app/module"
 	mockmodule "route/internal/app/module/mocks"
+	"route/internal/app/repository/postgresql"
+	"route/internal/app/webhook"
 	order "route/pkg/api/proto/order/v1/order/v1"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -19,7 +30,7 @@ func TestOrderService_AcceptOrder(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockModule := mockmodule.NewMockModule(ctrl)
-	orderService := New(mockModule)
+	orderService := New(mockModule, nil)
 
 	testCases := []struct {
 		name           string
@@ -63,6 +74,7 @@ func TestOrderService_AcceptOrder(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
+		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 			tc.mockSetup()
@@ -84,10 +96,12 @@ func TestOrderService_IssueOrder(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockModule := mockmodule.NewMockModule(ctrl)
-	orderService := New(mockModule)
+	orderService := New(mockModule, nil).WithManagerToken("manager-token")
+	managerCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(managerTokenKey, "manager-token"))
 
 	testCases := []struct {
 		name           string
+		ctx            context.Context
 		orderRequest   *order.OrderRequest
 		setupMock      func()
 		expectedResult *order.OrderResponse
@@ -95,12 +109,14 @@ func TestOrderService_IssueOrder(t *testing.T) {
 	}{
 		{
 			name: "successful issue",
+			ctx:  context.Background(),
 			orderRequest: &order.OrderRequest{
-				OrderId: 1,
+				OrderId:    1,
+				PickupCode: "123456",
 			},
 			setupMock: func() {
 				mockModule.EXPECT().
-					IssueOrder(gomock.Eq(1)).
+					IssueOrder(gomock.Eq(models.IssueRequest{OrderID: 1, PickupCode: "123456"})).
 					Return(nil).
 					Times(1)
 			},
@@ -109,25 +125,54 @@ func TestOrderService_IssueOrder(t *testing.T) {
 		},
 		{
 			name: "issue error",
+			ctx:  context.Background(),
 			orderRequest: &order.OrderRequest{
 				OrderId: 2,
 			},
 			setupMock: func() {
 				mockModule.EXPECT().
-					IssueOrder(gomock.Eq(2)).
+					IssueOrder(gomock.Eq(models.IssueRequest{OrderID: 2})).
 					Return(errors.New("issue error")).
 					Times(1)
 			},
 			expectedResult: nil,
 			expectedError:  "rpc error: code = Internal desc = issue error",
 		},
+		{
+			name: "override without manager token",
+			ctx:  context.Background(),
+			orderRequest: &order.OrderRequest{
+				OrderId:    3,
+				OverrideBy: "Иванов",
+			},
+			setupMock:      func() {},
+			expectedResult: nil,
+			expectedError:  "rpc error: code = PermissionDenied desc = неверный токен менеджера",
+		},
+		{
+			name: "override by manager",
+			ctx:  managerCtx,
+			orderRequest: &order.OrderRequest{
+				OrderId:    4,
+				OverrideBy: "Иванов",
+			},
+			setupMock: func() {
+				mockModule.EXPECT().
+					IssueOrder(gomock.Eq(models.IssueRequest{OrderID: 4, OverrideBy: "Иванов"})).
+					Return(nil).
+					Times(1)
+			},
+			expectedResult: &order.OrderResponse{Status: "success"},
+			expectedError:  "",
+		},
 	}
 
 	for _, tc := range testCases {
+		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 			tc.setupMock()
-			resp, err := orderService.IssueOrder(context.Background(), tc.orderRequest)
+			resp, err := orderService.IssueOrder(tc.ctx, tc.orderRequest)
 
 			if tc.expectedError != "" {
 				assert.Error(t, err)
@@ -146,7 +191,7 @@ func TestOrderService_ReturnOrder(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockModule := mockmodule.NewMockModule(ctrl)
-	orderService := New(mockModule)
+	orderService := New(mockModule, nil)
 
 	testCases := []struct {
 		name           string
@@ -180,6 +225,7 @@ func TestOrderService_ReturnOrder(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
+		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 			tc.setupMock()
@@ -202,7 +248,7 @@ func TestOrderService_ListOrders(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockModule := mockmodule.NewMockModule(ctrl)
-	orderService := New(mockModule)
+	orderService := New(mockModule, nil)
 
 	testCases := []struct {
 		name           string
@@ -246,6 +292,7 @@ func TestOrderService_ListOrders(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
+		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 			tc.setupMock()
@@ -268,7 +315,7 @@ func TestOrderService_AcceptReturn(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockModule := mockmodule.NewMockModule(ctrl)
-	orderService := New(mockModule)
+	orderService := New(mockModule, nil)
 
 	testCases := []struct {
 		name           string
@@ -302,6 +349,7 @@ func TestOrderService_AcceptReturn(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
+		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 			tc.setupMock()
@@ -324,7 +372,7 @@ func TestOrderService_ListReturns(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockModule := mockmodule.NewMockModule(ctrl)
-	orderService := New(mockModule)
+	orderService := New(mockModule, nil)
 
 	testCases := []struct {
 		name           string
@@ -366,6 +414,7 @@ func TestOrderService_ListReturns(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
+		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 			tc.setupMock()
@@ -381,3 +430,780 @@ func TestOrderService_ListReturns(t *testing.T) {
 		})
 	}
 }
+
+type fakeWebhookRegistry struct {
+	subs []models.WebhookSubscription
+}
+
+func (f *fakeWebhookRegistry) Subscribe(url string, eventTypes []models.EventType, secret string) (*models.WebhookSubscription, error) {
+	sub := models.WebhookSubscription{ID: int64(len(f.subs) + 1), URL: url, EventTypes: eventTypes, Secret: secret}
+	f.subs = append(f.subs, sub)
+	return &sub, nil
+}
+
+func (f *fakeWebhookRegistry) List() ([]models.WebhookSubscription, error) {
+	return f.subs, nil
+}
+
+func (f *fakeWebhookRegistry) Unsubscribe(id int64) error {
+	for i, sub := range f.subs {
+		if sub.ID == id {
+			f.subs = append(f.subs[:i], f.subs[i+1:]...)
+			return nil
+		}
+	}
+	return postgresql.ErrWebhookNotFound
+}
+
+func (f *fakeWebhookRegistry) ListDeliveries(int64, int) ([]models.WebhookDeliveryLog, error) {
+	return nil, nil
+}
+
+func TestOrderService_Webhooks(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	orderService := New(nil, &fakeWebhookRegistry{}).WithManagerToken("manager-token")
+	managerCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(managerTokenKey, "manager-token"))
+
+	// act
+	created, err := orderService.CreateWebhook(managerCtx, &order.CreateWebhookRequest{
+		Url:        "https://partner.example/hooks",
+		EventTypes: []string{"OrderIssued"},
+		Secret:     "secret",
+	})
+	assert.NoError(t, err)
+	listed, err := orderService.ListWebhooks(managerCtx, &order.ListWebhooksRequest{})
+	assert.NoError(t, err)
+	_, deleteErr := orderService.DeleteWebhook(managerCtx, &order.DeleteWebhookRequest{Id: 2})
+	_, anonymousErr := orderService.CreateWebhook(context.Background(), &order.CreateWebhookRequest{
+		Url:        "http://169.254.169.254/latest",
+		EventTypes: []string{"OrderIssued"},
+	})
+	_, anonymousListErr := orderService.ListWebhooks(context.Background(), &order.ListWebhooksRequest{})
+
+	// assert
+	assert.Equal(t, "secret", created.GetSecret())
+	assert.Equal(t, []string{"OrderIssued"}, created.GetEventTypes())
+	if assert.Len(t, listed.GetWebhooks(), 1) {
+		assert.Empty(t, listed.GetWebhooks()[0].GetSecret(), "Secret must not be listed")
+	}
+	assert.Equal(t, codes.NotFound, status.Code(deleteErr))
+	assert.Equal(t, codes.PermissionDenied, status.Code(anonymousErr), "Webhooks are managed only by managers")
+	assert.Equal(t, codes.PermissionDenied, status.Code(anonymousListErr))
+}
+
+func TestOrderService_CreateWebhookValidation(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	orderService := New(nil, webhook.NewRegistry(nil)).WithManagerToken("manager-token")
+	managerCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(managerTokenKey, "manager-token"))
+
+	// act
+	_, err := orderService.CreateWebhook(managerCtx, &order.CreateWebhookRequest{Url: "ftp://partner.example", EventTypes: []string{"OrderIssued"}})
+
+	// assert
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "некорректный URL вебхука: ftp://partner.example", status.Convert(err).Message())
+}
+
+func TestOrderService_CloseSession(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockModule := mockmodule.NewMockModule(ctrl)
+	orderService := New(mockModule, nil)
+
+	testCases := []struct {
+		name           string
+		mockSetup      func()
+		expectedResult *order.SessionSummary
+		expectedError  error
+	}{
+		{
+			name: "success",
+			mockSetup: func() {
+				mockModule.EXPECT().CloseSession(int64(1)).Return(&models.SessionSummary{
+					Session:     models.AcceptanceSession{ID: 1, CourierID: 2, ExpectedCount: 3},
+					Accepted:    2,
+					TotalWeight: 4.5,
+					Rejections:  []models.SessionRejection{{OrderID: 7, Reason: "заказ с ID 7 уже существует"}},
+				}, nil)
+			},
+			expectedResult: &order.SessionSummary{
+				Session:     &order.SessionInfo{Id: 1, CourierId: 2, ExpectedCount: 3, OpenedAt: "0001-01-01T00:00:00Z"},
+				Accepted:    2,
+				TotalWeight: 4.5,
+				Missing:     1,
+				Rejections:  []*order.SessionRejection{{OrderId: 7, Reason: "заказ с ID 7 уже существует"}},
+			},
+		},
+		{
+			name: "module error",
+			mockSetup: func() {
+				mockModule.EXPECT().CloseSession(int64(1)).Return(nil, errors.New("database error"))
+			},
+			expectedError: status.Error(codes.Internal, "database error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockSetup()
+
+			resp, err := orderService.CloseSession(context.Background(), &order.CloseSessionRequest{SessionId: 1})
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedResult, resp)
+			}
+		})
+	}
+}
+
+func TestOrderService_CreateAuthorization(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockModule := mockmodule.NewMockModule(ctrl)
+	orderService := New(mockModule, nil)
+	validUntil := time.Date(2030, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		request        *order.CreateAuthorizationRequest
+		mockSetup      func()
+		expectedResult *order.AuthorizationInfo
+		expectedCode   codes.Code
+	}{
+		{
+			name:         "invalid date",
+			request:      &order.CreateAuthorizationRequest{UserId: 1, Recipient: "Петров", ValidUntil: "завтра"},
+			mockSetup:    func() {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "success",
+			request: &order.CreateAuthorizationRequest{UserId: 1, Recipient: "Петров", OrderId: 5, ValidUntil: "2030-01-02T15:00:00Z"},
+			mockSetup: func() {
+				mockModule.EXPECT().CreateAuthorization(models.Authorization{UserID: 1, Recipient: "Петров", OrderID: 5, ValidUntil: validUntil}).
+					Return(&models.Authorization{ID: 3, UserID: 1, Recipient: "Петров", OrderID: 5, ValidUntil: validUntil}, nil)
+			},
+			expectedResult: &order.AuthorizationInfo{Id: 3, UserId: 1, Recipient: "Петров", OrderId: 5,
+				ValidUntil: "2030-01-02T15:00:00Z", CreatedAt: "0001-01-01T00:00:00Z"},
+			expectedCode: codes.OK,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockSetup()
+
+			resp, err := orderService.CreateAuthorization(context.Background(), tc.request)
+
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+			assert.Equal(t, tc.expectedResult, resp)
+		})
+	}
+}
+
+func TestOrderService_CreateTransfer(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockModule := mockmodule.NewMockModule(ctrl)
+	orderService := New(mockModule, nil)
+	createdAt := time.Date(2030, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		request        *order.CreateTransferRequest
+		mockSetup      func()
+		expectedResult *order.TransferInfo
+		expectedCode   codes.Code
+	}{
+		{
+			name:    "order already in transit",
+			request: &order.CreateTransferRequest{ToPointId: 2, OrderIds: []int32{1}},
+			mockSetup: func() {
+				mockModule.EXPECT().CreateTransfer(2, []int{1}).Return(nil, module.ValidationError{})
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "success",
+			request: &order.CreateTransferRequest{ToPointId: 2, OrderIds: []int32{3, 4}},
+			mockSetup: func() {
+				mockModule.EXPECT().CreateTransfer(2, []int{3, 4}).Return(&models.Transfer{ID: 7, FromPointID: 1, ToPointID: 2,
+					CreatedAt: createdAt, Orders: []models.Order{{OrderID: 3}, {OrderID: 4}}}, nil)
+			},
+			expectedResult: &order.TransferInfo{Id: 7, FromPointId: 1, ToPointId: 2, CreatedAt: "2030-01-02T15:00:00Z",
+				OrderIds: []int32{3, 4}},
+			expectedCode: codes.OK,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockSetup()
+
+			resp, err := orderService.CreateTransfer(context.Background(), tc.request)
+
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+			assert.Equal(t, tc.expectedResult, resp)
+		})
+	}
+}
+
+func TestOrderService_ListCells(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// arrange
+	mockModule := mockmodule.NewMockModule(ctrl)
+	orderService := New(mockModule, nil)
+	mockModule.EXPECT().ListCells().Return([]models.StorageCell{
+		{ID: 1, Code: "A-01", Size: models.CellLarge, MaxWeight: 30, OrderID: 5},
+		{ID: 2, Code: "A-02", Size: models.CellSmall, MaxWeight: 10},
+	}, nil)
+
+	// act
+	resp, err := orderService.ListCells(context.Background(), &order.ListCellsRequest{})
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []*order.CellInfo{
+		{Id: 1, Code: "A-01", Size: "L", MaxWeight: 30, OrderId: 5},
+		{Id: 2, Code: "A-02", Size: "S", MaxWeight: 10},
+	}, resp.GetCells())
+}
+
+// stocktakeStream is a client stream of scans for Stocktake
+type stocktakeStream struct {
+	grpc.ServerStream
+	scans  []*order.StocktakeScan
+	report *order.StocktakeReport
+}
+
+func (s *stocktakeStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *stocktakeStream) Recv() (*order.StocktakeScan, error) {
+	if len(s.scans) == 0 {
+		return nil, io.EOF
+	}
+	scan := s.scans[0]
+	s.scans = s.scans[1:]
+	return scan, nil
+}
+
+func (s *stocktakeStream) SendAndClose(report *order.StocktakeReport) error {
+	s.report = report
+	return nil
+}
+
+func TestOrderService_Stocktake(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// arrange
+	mockModule := mockmodule.NewMockModule(ctrl)
+	orderService := New(mockModule, nil)
+	stream := &stocktakeStream{scans: []*order.StocktakeScan{{OrderIds: []int32{1, 2}}, {OrderIds: []int32{7}}}}
+	mockModule.EXPECT().Stocktake([]int{1, 2, 7}).Return(&models.Stocktake{ID: 4, PickupPointID: 1, Scanned: 3, Stored: 3,
+		CreatedAt:     time.Date(2030, 1, 2, 15, 0, 0, 0, time.UTC),
+		Discrepancies: []models.Discrepancy{{OrderID: 3, Kind: models.Missing}, {OrderID: 7, Kind: models.WronglyIssued}}}, nil)
+
+	// act
+	err := orderService.Stocktake(stream)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, &order.StocktakeReport{Id: 4, PickupPointId: 1, Scanned: 3, Stored: 3, CreatedAt: "2030-01-02T15:00:00Z",
+		Discrepancies: []*order.StocktakeDiscrepancy{{OrderId: 3, Kind: "missing"}, {OrderId: 7, Kind: "wrongly_issued"}}}, stream.report)
+}
+
+// exportStream is a server stream collecting chunks of ExportOrders
+type exportStream struct {
+	grpc.ServerStream
+	data     []byte
+	chunks   int
+	exported int32
+}
+
+func (s *exportStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *exportStream) Send(chunk *order.ExportChunk) error {
+	s.data = append(s.data, chunk.GetData()...)
+	s.exported += chunk.GetExported()
+	s.chunks++
+	return nil
+}
+
+func TestOrderService_ExportOrders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		req              *order.ExportOrdersRequest
+		setupMocks       func(mockModule *mockmodule.MockModule)
+		expectedData     string
+		expectedExported int32
+		expectedCode     codes.Code
+	}{
+		{
+			name: "orders are sent as csv",
+			req:  &order.ExportOrdersRequest{Format: "csv", UserId: 7, LastN: 1},
+			setupMocks: func(mockModule *mockmodule.MockModule) {
+				mockModule.EXPECT().ExportOrders(models.ExportFilter{Kind: models.OrdersExport, UserID: 7, LastN: 1}, gomock.Any()).DoAndReturn(
+					func(_ models.ExportFilter, fx func(order models.Order) error) error {
+						return fx(models.Order{OrderID: 1, UserID: 7, PickupPointID: 1, Weight: 2, Cost: 10})
+					})
+			},
+			expectedData: "order_id,user_id,pickup_point_id,status,deadline,weight,cost,cell,issued_at,received_by,refused_at,refusal_reason\n" +
+				"1,7,1,stored,,2,10,,,,,\n",
+			expectedExported: 1,
+			expectedCode:     codes.OK,
+		},
+		{
+			name:         "unsupported format",
+			req:          &order.ExportOrdersRequest{Format: "pdf"},
+			setupMocks:   func(mockModule *mockmodule.MockModule) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "unknown kind",
+			req:          &order.ExportOrdersRequest{Kind: "parcels", Format: "jsonl"},
+			setupMocks:   func(mockModule *mockmodule.MockModule) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "invalid filter",
+			req:  &order.ExportOrdersRequest{Format: "jsonl", LastN: -1},
+			setupMocks: func(mockModule *mockmodule.MockModule) {
+				mockModule.EXPECT().ExportOrders(gomock.Any(), gomock.Any()).Return(module.ValidationError{})
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "nothing is sent if the export fails",
+			req:  &order.ExportOrdersRequest{Format: "csv"},
+			setupMocks: func(mockModule *mockmodule.MockModule) {
+				mockModule.EXPECT().ExportOrders(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ models.ExportFilter, fx func(order models.Order) error) error {
+						if err := fx(models.Order{OrderID: 1, UserID: 7, PickupPointID: 1, Weight: 2, Cost: 10}); err != nil {
+							return err
+						}
+						return errors.New("connection reset")
+					})
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockModule := mockmodule.NewMockModule(ctrl)
+			tt.setupMocks(mockModule)
+			stream := &exportStream{}
+
+			// act
+			err := New(mockModule, nil).ExportOrders(tt.req, stream)
+
+			// assert
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.expectedData, string(stream.data))
+			assert.Equal(t, tt.expectedExported, stream.exported)
+		})
+	}
+}
+
+func TestChunkWriter(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	stream := &exportStream{}
+	data := bytes.Repeat([]byte("x"), 2*exportChunkSize+1)
+
+	// act
+	n, err := chunkWriter{stream: stream}.Write(data)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, len(data), n)
+	assert.Equal(t, 3, stream.chunks)
+	assert.Equal(t, data, stream.data)
+}
+
+func TestOrderService_GetReport(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2030, 1, 2, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name           string
+		req            *order.GetReportRequest
+		setupMocks     func(mockModule *mockmodule.MockModule)
+		expectedReport *order.ReportInfo
+		expectedCode   codes.Code
+	}{
+		{
+			name: "report of the period",
+			req:  &order.GetReportRequest{From: "2030-01-02", To: "2030-01-03"},
+			setupMocks: func(mockModule *mockmodule.MockModule) {
+				mockModule.EXPECT().Report(day, day.AddDate(0, 0, 1)).Return(&models.Report{PickupPointID: 1, From: day, To: day.AddDate(0, 0, 1),
+					Rows: []models.ReportRow{
+						{Day: day, PackagingType: models.Box, Accepted: 2, Issued: 1, SurchargeRevenue: 20},
+						{Day: day.AddDate(0, 0, 1), PackagingType: models.Box, Returned: 1, Refunds: 120},
+					}}, nil)
+			},
+			expectedReport: &order.ReportInfo{PickupPointId: 1, From: "2030-01-02", To: "2030-01-03",
+				Rows: []*order.ReportRow{
+					{Day: "2030-01-02", PackagingType: "коробка", Accepted: 2, Issued: 1, SurchargeRevenue: 20},
+					{Day: "2030-01-03", PackagingType: "коробка", Returned: 1, Refunds: 120},
+				},
+				Total: &order.ReportRow{Accepted: 2, Issued: 1, Returned: 1, SurchargeRevenue: 20, Refunds: 120}},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "invalid date",
+			req:          &order.GetReportRequest{From: "02.01.2030"},
+			setupMocks:   func(mockModule *mockmodule.MockModule) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "invalid period",
+			req:  &order.GetReportRequest{From: "2030-01-03", To: "2030-01-02"},
+			setupMocks: func(mockModule *mockmodule.MockModule) {
+				mockModule.EXPECT().Report(gomock.Any(), gomock.Any()).Return(nil, module.ValidationError{})
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockModule := mockmodule.NewMockModule(ctrl)
+			tt.setupMocks(mockModule)
+
+			// act
+			report, err := New(mockModule, nil).GetReport(context.Background(), tt.req)
+
+			// assert
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.expectedReport, report)
+		})
+	}
+}
+
+func TestOrderService_PointTokens(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	defaultModule := mockmodule.NewMockModule(ctrl)
+	pointModule := mockmodule.NewMockModule(ctrl)
+	var servedPoint int
+	orderService := New(defaultModule, nil).WithPointTokens(map[string]int{"north": 2}, func(pointID int) module.Module {
+		servedPoint = pointID
+		return pointModule
+	})
+	pointModule.EXPECT().ListReturns(1, 10).Return(nil, nil)
+
+	// act
+	_, noTokenErr := orderService.ListReturns(context.Background(), &order.ListReturnsRequest{Page: 1, PageSize: 10})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(pointTokenKey, "north"))
+	_, err := orderService.ListReturns(ctx, &order.ListReturnsRequest{Page: 1, PageSize: 10})
+
+	// assert
+	assert.Equal(t, codes.Unauthenticated, status.Code(noTokenErr))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, servedPoint)
+}
+
+func TestOrderService_AcceptOrders(t *testing.T) {
+	t.Parallel()
+
+	deadline := time.Date(2030, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		req          *order.AcceptOrdersRequest
+		setupMocks   func(mockModule *mockmodule.MockModule)
+		expected     *order.AcceptOrdersResponse
+		expectedCode codes.Code
+	}{
+		{
+			name: "results in the order of requests",
+			req: &order.AcceptOrdersRequest{Orders: []*order.OrderRequest{
+				{OrderId: 1, UserId: 2, Weight: 3, PackagingType: "коробка", Deadline: "2030-01-02T15:00:00Z", Cost: 100},
+				{OrderId: 2, UserId: 2, Weight: 40, PackagingType: "пакет", Deadline: "2030-01-02T15:00:00Z", Cost: 100},
+			}},
+			setupMocks: func(mockModule *mockmodule.MockModule) {
+				converted := gomock.Cond(func(x any) bool {
+					reqs := x.([]models.AcceptRequest)
+					return len(reqs) == 2 && reqs[0].PackagingType == models.Box &&
+						*reqs[0].Order == models.Order{OrderID: 1, UserID: 2, Weight: 3, Deadline: deadline, Cost: 100}
+				})
+				mockModule.EXPECT().AcceptOrders(converted, false).DoAndReturn(func(reqs []models.AcceptRequest, _ bool) []error {
+					reqs[0].Order.PickupCode, reqs[0].Order.Cell = "123456", "A-01"
+					return []error{nil, errors.New("вес заказа превышает допустимый для пакета")}
+				})
+			},
+			expected: &order.AcceptOrdersResponse{Results: []*order.AcceptOrderResult{
+				{PickupCode: "123456", Cell: "A-01"},
+				{Error: "вес заказа превышает допустимый для пакета"},
+			}},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "invalid deadline",
+			req:          &order.AcceptOrdersRequest{Orders: []*order.OrderRequest{{OrderId: 1, Deadline: "завтра"}}},
+			setupMocks:   func(mockModule *mockmodule.MockModule) {},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockModule := mockmodule.NewMockModule(ctrl)
+			tt.setupMocks(mockModule)
+
+			// act
+			resp, err := New(mockModule, nil).AcceptOrders(context.Background(), tt.req)
+
+			// assert
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.expected, resp)
+		})
+	}
+}
+
+func TestOrderService_CreateReturnManifest(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctrl := gomock.NewController(t)
+	mockModule := mockmodule.NewMockModule(ctrl)
+	createdAt := time.Date(2030, 1, 2, 15, 0, 0, 0, time.UTC)
+	mockModule.EXPECT().CreateReturnManifest(3).Return(&models.ReturnManifest{ID: 7, CourierID: 3, CreatedAt: createdAt,
+		Orders: []models.Order{{OrderID: 1, UserID: 2, Weight: 5, ExpiredAt: createdAt, ReturnedToCourierAt: createdAt}}}, nil)
+
+	// act
+	manifest, err := New(mockModule, nil).CreateReturnManifest(context.Background(), &order.CreateReturnManifestRequest{CourierId: 3})
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, &order.ReturnManifestInfo{Id: 7, CourierId: 3, CreatedAt: "2030-01-02T15:00:00Z",
+		Orders: []*order.OrderInfo{{OrderId: 1, UserId: 2, Status: "Success", Weight: 5,
+			ExpiredAt: "2030-01-02T15:00:00Z", ReturnedToCourierAt: "2030-01-02T15:00:00Z"}}}, manifest)
+}
+
+// deadLetterQueue is a dead-letter queue holding one letter
+type deadLetterQueue struct {
+	letter   models.DeadLetter
+	topics   []string
+	replayed []int64
+}
+
+func (d *deadLetterQueue) List(topic string, _ int) ([]models.DeadLetter, error) {
+	d.topics = append(d.topics, topic)
+	return []models.DeadLetter{d.letter}, nil
+}
+
+func (d *deadLetterQueue) Replay(topic string, _ int32, offset int64) error {
+	d.topics = append(d.topics, topic)
+	d.replayed = append(d.replayed, offset)
+	return nil
+}
+
+func TestOrderService_DeadLetters(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	at := time.Date(2030, 1, 2, 15, 0, 0, 0, time.UTC)
+	dlq := &deadLetterQueue{letter: models.DeadLetter{Partition: 1, Offset: 42, Time: at, Key: "5",
+		EventType: models.OrderAccepted, Error: "timeout", Attempts: 5, OriginalTopic: "order_topic"}}
+	orderService := New(nil, nil).WithDeadLetters(dlq).WithManagerToken("manager-token")
+	managerCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(managerTokenKey, "manager-token"))
+
+	// act
+	list, listErr := orderService.ListDeadLetters(managerCtx, &order.ListDeadLettersRequest{Limit: 10})
+	_, replayErr := orderService.ReplayDeadLetter(managerCtx, &order.ReplayDeadLetterRequest{Topic: "manifests", Partition: 1, Offset: 42})
+	_, anonymousErr := orderService.ReplayDeadLetter(context.Background(), &order.ReplayDeadLetterRequest{Partition: 1, Offset: 42})
+	_, disabledErr := New(nil, nil).ListDeadLetters(context.Background(), &order.ListDeadLettersRequest{})
+	_, zeroLimitErr := orderService.ListDeadLetters(managerCtx, &order.ListDeadLettersRequest{})
+	_, negativeLimitErr := orderService.ListDeadLetters(managerCtx, &order.ListDeadLettersRequest{Limit: -1})
+	_, hugeLimitErr := orderService.ListDeadLetters(managerCtx, &order.ListDeadLettersRequest{Limit: models.MaxDeadLetters + 1})
+
+	// assert
+	require.NoError(t, listErr)
+	assert.Equal(t, []*order.DeadLetterInfo{{Partition: 1, Offset: 42, Time: "2030-01-02T15:00:00Z", Key: "5",
+		EventType: string(models.OrderAccepted), Error: "timeout", Attempts: 5, OriginalTopic: "order_topic"}}, list.GetDeadLetters())
+	require.NoError(t, replayErr)
+	assert.Equal(t, []int64{42}, dlq.replayed)
+	assert.Equal(t, []string{"", "manifests"}, dlq.topics)
+	assert.Equal(t, codes.PermissionDenied, status.Code(anonymousErr), "Dead letters are replayed only by managers")
+	assert.Equal(t, codes.Unimplemented, status.Code(disabledErr))
+	for _, err := range []error{zeroLimitErr, negativeLimitErr, hugeLimitErr} {
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+	assert.Equal(t, []string{"", "manifests"}, dlq.topics, "Invalid limits don't reach the queue")
+}
+
+type contacts struct {
+	saved []models.Contact
+}
+
+func (c *contacts) SetContact(contact models.Contact) error {
+	c.saved = append(c.saved, contact)
+	return nil
+}
+
+func TestOrderService_SetContact(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		token        string
+		userOrders   []models.Order
+		expectedCode codes.Code
+	}{
+		{
+			name:         "client with an order at the point",
+			token:        "north",
+			userOrders:   []models.Order{{OrderID: 1, UserID: 7, PickupPointID: 2}},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "client without orders at the point",
+			token:        "north",
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:         "unknown point token",
+			token:        "south",
+			expectedCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// arrange
+			pointModule := mockmodule.NewMockModule(ctrl)
+			saved := &contacts{}
+			orderService := New(nil, nil).WithContacts(saved).WithPointTokens(map[string]int{"north": 2}, func(int) module.Module {
+				return pointModule
+			})
+			if tt.expectedCode != codes.Unauthenticated {
+				pointModule.EXPECT().ListOrders(7, 1).Return(tt.userOrders, nil)
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(pointTokenKey, tt.token))
+
+			// act
+			_, err := orderService.SetContact(ctx, &order.SetContactRequest{UserId: 7, Phone: "+79990000000"})
+
+			// assert
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			if tt.expectedCode == codes.OK {
+				assert.Equal(t, []models.Contact{{UserID: 7, Phone: "+79990000000"}}, saved.saved)
+			} else {
+				assert.Empty(t, saved.saved, "Contact is not saved")
+			}
+		})
+	}
+}
+
+func TestOrderService_PickupPoints(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		md               metadata.MD
+		expectedCreate   codes.Code
+		expectedListCode codes.Code
+	}{
+		{
+			name:             "manager",
+			md:               metadata.Pairs(managerTokenKey, "manager-token"),
+			expectedCreate:   codes.OK,
+			expectedListCode: codes.OK,
+		},
+		{
+			name:             "pickup point",
+			md:               metadata.Pairs(pointTokenKey, "north"),
+			expectedCreate:   codes.PermissionDenied,
+			expectedListCode: codes.OK,
+		},
+		{
+			name:             "anonymous",
+			md:               metadata.MD{},
+			expectedCreate:   codes.PermissionDenied,
+			expectedListCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// arrange
+			mockModule := mockmodule.NewMockModule(ctrl)
+			orderService := New(mockModule, nil).WithManagerToken("manager-token").
+				WithPointTokens(map[string]int{"north": 2}, func(int) module.Module { return mockModule })
+			if tt.expectedCreate == codes.OK {
+				mockModule.EXPECT().CreatePickupPoint("Северный", "").Return(&models.PickupPoint{ID: 2, Name: "Северный"}, nil)
+			}
+			if tt.expectedListCode == codes.OK {
+				mockModule.EXPECT().ListPickupPoints().Return([]models.PickupPoint{{ID: 1, Name: "default"}}, nil)
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+
+			// act
+			_, createErr := orderService.CreatePickupPoint(ctx, &order.CreatePickupPointRequest{Name: "Северный"})
+			_, listErr := orderService.ListPickupPoints(ctx, &order.ListPickupPointsRequest{})
+
+			// assert
+			assert.Equal(t, tt.expectedCreate, status.Code(createErr))
+			assert.Equal(t, tt.expectedListCode, status.Code(listErr))
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package cache
 
 import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 )
@@ -77,3 +81,76 @@ func (c *IMCache[K, V]) InvalidateExpired() {
 		}
 	}
 }
+
+// snapshotEntry is a cache item as it is stored in a snapshot file. Values are not stored, they may change
+// while the service is down, so restored keys are loaded again from the source of the values
+type snapshotEntry[K comparable] struct {
+	Key       K         `json:"key"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+// SaveSnapshot writes keys of all not yet expired items of the cache to the file at path.
+// The file is written to a temporary file first and then renamed, so a crash
+// during shutdown never leaves a partially written snapshot.
+func (c *IMCache[K, V]) SaveSnapshot(path string) error {
+	now := time.Now()
+
+	c.lock.RLock()
+	entries := make([]snapshotEntry[K], 0, len(c.data))
+	for key, val := range c.data {
+		if val.Expired(now) {
+			continue
+		}
+		entries = append(entries, snapshotEntry[K]{Key: key, ExpiredAt: val.expiredAt})
+	}
+	c.lock.RUnlock()
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadSnapshot returns the keys of the snapshot file at path, dropping the ones expired while the service was down.
+// The cache is not changed, the caller loads the values of the keys again. A missing file is not an error.
+func (c *IMCache[K, V]) LoadSnapshot(path string) ([]K, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []snapshotEntry[K]
+	if err = json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	keys := make([]K, 0, len(entries))
+	for _, entry := range entries {
+		if entry.ExpiredAt.Before(now) {
+			continue
+		}
+		keys = append(keys, entry.Key)
+	}
+
+	return keys, nil
+}
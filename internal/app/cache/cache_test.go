@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIMCache_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	t.Run("restores keys of not expired items", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		path := filepath.Join(t.TempDir(), "cache.json")
+		c := NewIMCache[int, string](time.Hour)
+		c.Set(1, "first", time.Now())
+		c.Set(2, "expired", time.Now().Add(-2*time.Hour))
+
+		// act
+		require.NoError(t, c.SaveSnapshot(path))
+		restored := NewIMCache[int, string](time.Hour)
+		keys, err := restored.LoadSnapshot(path)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []int{1}, keys)
+		_, ok := restored.Get(1)
+		assert.False(t, ok, "Values are loaded again by the caller, not restored from the snapshot")
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "first", "Values are not saved")
+	})
+
+	t.Run("drops items expired while the service was down", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		path := filepath.Join(t.TempDir(), "cache.json")
+		data := `[{"key":1,"expired_at":"2000-01-01T00:00:00Z"}]`
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+		c := NewIMCache[int, string](time.Hour)
+
+		// act
+		keys, err := c.LoadSnapshot(path)
+
+		// assert
+		require.NoError(t, err)
+		assert.Empty(t, keys)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewIMCache[int, string](time.Hour)
+
+		keys, err := c.LoadSnapshot(filepath.Join(t.TempDir(), "missing.json"))
+
+		require.NoError(t, err)
+		assert.Empty(t, keys)
+	})
+}
@@ -3,11 +3,11 @@ package cli
 import (
 	"errors"
 	"flag"
-	"fmt"
 	"time"
 
 	"route/internal/app/models"
 	"route/internal/app/module"
+	"route/internal/app/pickup"
 )
 
 const acceptOrder = "accept-order"
@@ -28,12 +28,14 @@ func (a AcceptOrderCommand) Description() string {
 		"--deadline=SomeDate: обязательный параметр, дата, до которой будет хранится заказ на ПВЗ, указывается в формате RFC3339.\n" +
 		"--packagingType=SomeType: обязательный параметр, тип упаковки. Может иметь значения пакет, коробка, пакет\n" +
 		"--weight=SomeWeight: обязательный параметр, вес заказа.\n" +
-		"--cost=SomeCost: обязательный параметр, стоимость заказа."
+		"--cost=SomeCost: обязательный параметр, стоимость заказа.\n" +
+		"--sessionID=SomeID: опциональный параметр, ID открытой сессии приемки курьера."
 }
 
 // Call is a method to accept order from courier
-func (a AcceptOrderCommand) Call(args []string) error {
+func (a AcceptOrderCommand) Call(args []string) (Result, error) {
 	var orderID, userID int
+	var sessionID int64
 	var weight, cost float64
 	var deadline, packagingType string
 
@@ -45,44 +47,49 @@ func (a AcceptOrderCommand) Call(args []string) error {
 	fs.StringVar(&packagingType, "packagingType", "", "use --packagingType=SomeType")
 	fs.Float64Var(&weight, "weight", 0, "use --weight=SomeWeight")
 	fs.Float64Var(&cost, "cost", 0, "use --cost=SomeCost")
+	fs.Int64Var(&sessionID, "sessionID", 0, "use --sessionID=SomeID")
 
 	if err := fs.Parse(args); err != nil {
-		return err
+		return nil, err
 	}
 
 	if orderID == 0 {
-		return errors.New("не указан обязательный параметр orderID")
+		return nil, errors.New("не указан обязательный параметр orderID")
 	}
 	if userID == 0 {
-		return errors.New("не указан обязательный параметр userID")
+		return nil, errors.New("не указан обязательный параметр userID")
 	}
 	if deadline == "" {
-		return errors.New("не указан обязательный параметр deadline")
+		return nil, errors.New("не указан обязательный параметр deadline")
 	}
 	if packagingType == "" {
-		return errors.New("не указан обязательный параметр packagingType")
+		return nil, errors.New("не указан обязательный параметр packagingType")
 	}
 	if weight == 0 {
-		return errors.New("не указан обязательный параметр weight")
+		return nil, errors.New("не указан обязательный параметр weight")
 	}
 	if cost == 0 {
-		return errors.New("не указан обязательный параметр cost")
+		return nil, errors.New("не указан обязательный параметр cost")
 	}
 
 	parsedDeadline, err := parseTime(deadline)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	order := models.NewOrder(orderID, userID, parsedDeadline, cost, weight)
+	order.SessionID = sessionID
 
 	err = a.Module.AcceptOrder(order, models.ToPackageType(packagingType))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	fmt.Printf("Заказ успешно принят командой %s\n", a.Name())
-	return nil
+	return acceptedOrderView{
+		orderView:  newOrderView(*order),
+		PickupCode: order.PickupCode,
+		QRCode:     pickup.QRPayload(order.OrderID, order.PickupCode),
+	}, nil
 }
 
 // parseTime is a helper function to parse time
@@ -3,7 +3,6 @@ package cli
 import (
 	"errors"
 	"flag"
-	"fmt"
 
 	"route/internal/app/module"
 )
@@ -26,7 +25,7 @@ func (a AcceptReturnCommand) Description() string {
 }
 
 // Call is a method to accept return from client
-func (a AcceptReturnCommand) Call(args []string) error {
+func (a AcceptReturnCommand) Call(args []string) (Result, error) {
 	var orderID, userID int
 
 	// Parse flags
@@ -34,21 +33,20 @@ func (a AcceptReturnCommand) Call(args []string) error {
 	fs.IntVar(&orderID, "orderID", 0, "use --orderID=SomeID")
 	fs.IntVar(&userID, "userID", 0, "use --userID=SomeID")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return nil, err
 	}
 
 	if orderID == 0 {
-		return errors.New("не указан обязательный параметр orderID")
+		return nil, errors.New("не указан обязательный параметр orderID")
 	}
 	if userID == 0 {
-		return errors.New("не указан обязательный параметр userID")
+		return nil, errors.New("не указан обязательный параметр userID")
 	}
 
 	err := a.Module.AcceptReturn(orderID, userID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	fmt.Println("Возврат успешно принят")
-	return nil
+	return newMessage("Возврат успешно принят"), nil
 }
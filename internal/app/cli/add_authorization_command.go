@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"strings"
+
+	"route/internal/app/models"
+	"route/internal/app/module"
+)
+
+const addAuthorization = "add-authorization"
+
+type AddAuthorizationCommand struct {
+	Module module.Module
+}
+
+func (a AddAuthorizationCommand) Name() string {
+	return addAuthorization
+}
+
+func (a AddAuthorizationCommand) Description() string {
+	return "Добавить доверенность на получение заказов:" +
+		" использование add-authorization --userID=SomeID --recipient=SomeName --until=SomeDate\n" +
+		"--userID=SomeID: обязательный параметр, ID клиента, выдавшего доверенность.\n" +
+		"--recipient=SomeName: обязательный параметр, получатель по доверенности. Слова можно разделять символом _ .\n" +
+		"--until=SomeDate: обязательный параметр, дата окончания действия доверенности в формате RFC3339.\n" +
+		"--orderID=SomeID: опциональный параметр, ID заказа. Если не указан, доверенность действует на все заказы клиента."
+}
+
+// Call is a method to add power of attorney of client
+func (a AddAuthorizationCommand) Call(args []string) (Result, error) {
+	var userID, orderID int
+	var recipient, until string
+
+	// Parse flags
+	fs := flag.NewFlagSet(addAuthorization, flag.ContinueOnError)
+	fs.IntVar(&userID, "userID", 0, "use --userID=SomeID")
+	fs.StringVar(&recipient, "recipient", "", "use --recipient=SomeName")
+	fs.StringVar(&until, "until", "", "use --until=SomeDate")
+	fs.IntVar(&orderID, "orderID", 0, "use --orderID=SomeID")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if userID == 0 {
+		return nil, errors.New("не указан обязательный параметр userID")
+	}
+	if recipient == "" {
+		return nil, errors.New("не указан обязательный параметр recipient")
+	}
+	if until == "" {
+		return nil, errors.New("не указан обязательный параметр until")
+	}
+
+	validUntil, err := parseTime(until)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := a.Module.CreateAuthorization(models.Authorization{
+		UserID:     userID,
+		Recipient:  strings.ReplaceAll(recipient, "_", " "),
+		OrderID:    orderID,
+		ValidUntil: validUntil,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newAuthorizationView(*auth), nil
+}
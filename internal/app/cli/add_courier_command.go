@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+
+	"route/internal/app/module"
+)
+
+const addCourier = "add-courier"
+
+type AddCourierCommand struct {
+	Module module.Module
+}
+
+func (a AddCourierCommand) Name() string {
+	return addCourier
+}
+
+func (a AddCourierCommand) Description() string {
+	return "Добавить курьера:" +
+		" использование add-courier --name=SomeName --company=SomeCompany\n" +
+		"--name=SomeName: обязательный параметр, имя курьера.\n" +
+		"--company=SomeCompany: обязательный параметр, служба доставки."
+}
+
+// Call is a method to register a courier
+func (a AddCourierCommand) Call(args []string) (Result, error) {
+	var name, company string
+
+	// Parse flags
+	fs := flag.NewFlagSet(addCourier, flag.ContinueOnError)
+	fs.StringVar(&name, "name", "", "use --name=SomeName")
+	fs.StringVar(&company, "company", "", "use --company=SomeCompany")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		return nil, errors.New("не указан обязательный параметр name")
+	}
+	if company == "" {
+		return nil, errors.New("не указан обязательный параметр company")
+	}
+
+	courier, err := a.Module.CreateCourier(name, company)
+	if err != nil {
+		return nil, err
+	}
+
+	return courierView{ID: courier.ID, Name: courier.Name, Company: courier.Company, CreatedAt: courier.CreatedAt}, nil
+}
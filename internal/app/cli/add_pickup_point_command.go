@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"strings"
+
+	"route/internal/app/module"
+)
+
+const addPickupPoint = "add-pickup-point"
+
+type AddPickupPointCommand struct {
+	Module module.Module
+}
+
+func (a AddPickupPointCommand) Name() string {
+	return addPickupPoint
+}
+
+func (a AddPickupPointCommand) Description() string {
+	return "Добавить пункт выдачи в сеть:" +
+		" использование add-pickup-point --name=SomeName --address=SomeAddress\n" +
+		"--name=SomeName: обязательный параметр, название пункта выдачи.\n" +
+		"--address=SomeAddress: опциональный параметр, адрес пункта выдачи. Слова можно разделять символом _ ."
+}
+
+// Call is a method to add a pickup point
+func (a AddPickupPointCommand) Call(args []string) (Result, error) {
+	var name, address string
+
+	// Parse flags
+	fs := flag.NewFlagSet(addPickupPoint, flag.ContinueOnError)
+	fs.StringVar(&name, "name", "", "use --name=SomeName")
+	fs.StringVar(&address, "address", "", "use --address=SomeAddress")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		return nil, errors.New("не указан обязательный параметр name")
+	}
+
+	point, err := a.Module.CreatePickupPoint(name, strings.ReplaceAll(address, "_", " "))
+	if err != nil {
+		return nil, err
+	}
+
+	return newPickupPointView(*point), nil
+}
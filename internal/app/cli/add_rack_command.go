@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+
+	"route/internal/app/models"
+	"route/internal/app/module"
+)
+
+const addRack = "add-rack"
+
+type AddRackCommand struct {
+	Module module.Module
+}
+
+func (a AddRackCommand) Name() string {
+	return addRack
+}
+
+func (a AddRackCommand) Description() string {
+	return "Добавить стеллаж с ячейками хранения в пункт выдачи:" +
+		" использование add-rack --name=A --cells=10 --size=M --maxWeight=10\n" +
+		"--name=A: обязательный параметр, название стеллажа, ячейки получают адреса A-01, A-02, ... .\n" +
+		"--cells=10: обязательный параметр, количество ячеек стеллажа.\n" +
+		"--size=M: обязательный параметр, размер ячеек: S для пакетов, M для пленки, L для коробок. Большая ячейка вмещает заказы меньших.\n" +
+		"--maxWeight=10: обязательный параметр, допустимый вес заказа в ячейке."
+}
+
+// Call is a method to add a rack of storage cells
+func (a AddRackCommand) Call(args []string) (Result, error) {
+	var name, size string
+	var cells int
+	var maxWeight float64
+
+	// Parse flags
+	fs := flag.NewFlagSet(addRack, flag.ContinueOnError)
+	fs.StringVar(&name, "name", "", "use --name=SomeName")
+	fs.IntVar(&cells, "cells", 0, "use --cells=SomeNumber")
+	fs.StringVar(&size, "size", "", "use --size=S|M|L")
+	fs.Float64Var(&maxWeight, "maxWeight", 0, "use --maxWeight=SomeWeight")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		return nil, errors.New("не указан обязательный параметр name")
+	}
+	if cells == 0 {
+		return nil, errors.New("не указан обязательный параметр cells")
+	}
+	if size == "" {
+		return nil, errors.New("не указан обязательный параметр size")
+	}
+	if maxWeight == 0 {
+		return nil, errors.New("не указан обязательный параметр maxWeight")
+	}
+
+	rack, err := a.Module.CreateRack(name, cells, models.ToCellSize(size), maxWeight)
+	if err != nil {
+		return nil, err
+	}
+
+	return rackView{ID: rack.ID, PickupPointID: rack.PickupPointID, Name: rack.Name, CreatedAt: rack.CreatedAt,
+		Cells: newCellViews(rack.Cells)}, nil
+}
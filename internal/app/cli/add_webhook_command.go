@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"strings"
+
+	"route/internal/app/models"
+)
+
+const addWebhook = "add-webhook"
+
+type WebhookRegistry interface {
+	Subscribe(url string, eventTypes []models.EventType, secret string) (*models.WebhookSubscription, error)
+	List() ([]models.WebhookSubscription, error)
+	Unsubscribe(id int64) error
+	ListDeliveries(subscriptionID int64, limit int) ([]models.WebhookDeliveryLog, error)
+}
+
+type AddWebhookCommand struct {
+	Webhooks WebhookRegistry
+}
+
+func (a AddWebhookCommand) Name() string {
+	return addWebhook
+}
+
+func (a AddWebhookCommand) Description() string {
+	return "Подписать внешнюю систему на события заказов:" +
+		" использование add-webhook --url=SomeURL --events=SomeTypes [--secret=SomeSecret]\n" +
+		"--url=SomeURL: обязательный параметр, адрес, на который отправляются события.\n" +
+		"--events=SomeTypes: обязательный параметр, типы событий через запятую:" +
+		" OrderAccepted, OrderIssued, OrderReturnedToCourier, ReturnAccepted, OrderExpired, OrderRefused,\n" +
+		" OrderLocked, OrderUnlocked, OrderInTransit, OrderTransferred.\n" +
+		"--secret=SomeSecret: опциональный параметр, ключ подписи запросов, если не указан - генерируется."
+}
+
+// Call is a method to register a webhook subscription
+func (a AddWebhookCommand) Call(args []string) (Result, error) {
+	var url, events, secret string
+
+	// Parse flags
+	fs := flag.NewFlagSet(addWebhook, flag.ContinueOnError)
+	fs.StringVar(&url, "url", "", "use --url=SomeURL")
+	fs.StringVar(&events, "events", "", "use --events=SomeTypes")
+	fs.StringVar(&secret, "secret", "", "use --secret=SomeSecret")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if url == "" {
+		return nil, errors.New("не указан обязательный параметр url")
+	}
+	if events == "" {
+		return nil, errors.New("не указан обязательный параметр events")
+	}
+
+	var eventTypes []models.EventType
+	for _, eventType := range strings.Split(events, ",") {
+		eventTypes = append(eventTypes, models.EventType(strings.TrimSpace(eventType)))
+	}
+
+	sub, err := a.Webhooks.Subscribe(url, eventTypes, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return newWebhookView(*sub), nil
+}
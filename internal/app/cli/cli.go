@@ -2,7 +2,7 @@ package cli
 
 import (
 	"bufio"
-	"encoding/json"
+	"bytes"
 	"fmt"
 	"os"
 	"os/signal"
@@ -11,49 +11,64 @@ import (
 	"syscall"
 
 	"github.com/google/uuid"
-	"route/internal/app/models"
+	"route/internal/app/cli/output"
 	"route/internal/app/module"
 )
 
-type KafkaProducer interface {
-	SendMessage(message []byte) error
-}
+// outputFlag selects the format of command results, it is accepted by every command
+const outputFlag = "output"
 
-type KafkaConsumer interface {
-	ReadMessages() error
-}
+// stdin is shared by the command loop and interactive commands, so no input is lost in separate buffers
+var stdin = bufio.NewReader(os.Stdin)
 
 type Command interface {
 	Name() string
 	Description() string
-	Call(args []string) error
+	// Call runs the command and returns its result, nil result prints nothing
+	Call(args []string) (Result, error)
+}
+
+// Interactive is implemented by commands which read further input from the terminal,
+// such commands are run in the foreground and the next command is read after they finish
+type Interactive interface {
+	Interactive(args []string) bool
 }
 
 type CLI struct {
-	Module      module.Module
-	Producer    KafkaProducer
-	Consumer    KafkaConsumer
-	commands    map[string]Command
-	wg          sync.WaitGroup
-	cond        *sync.Cond
-	workerCount int
-	event       models.Event
-	outputMode  string
+	Module        module.Module
+	commands      map[string]Command
+	wg            sync.WaitGroup
+	cond          *sync.Cond
+	stdoutMu      sync.Mutex
+	workerCount   int
+	shutdownHooks []func()
 }
 
 // New is a constructor for CLI
-func New(commandMap map[string]Command, outputMode string, consumer KafkaConsumer, producer KafkaProducer) *CLI {
+func New(commandMap map[string]Command) *CLI {
 	workersCommand := commandMap["set-workers"].(*WorkersCommand)
 	return &CLI{
 		commands:    commandMap,
 		cond:        sync.NewCond(&sync.Mutex{}),
 		workerCount: workersCommand.GetWorkersCount(),
-		outputMode:  outputMode,
+	}
+}
+
+// AddShutdownHook registers a function to run once all tasks are finished on exit
+func (c *CLI) AddShutdownHook(hook func()) {
+	c.shutdownHooks = append(c.shutdownHooks, hook)
+}
+
+// shutdown waits for all running tasks and runs shutdown hooks
+func (c *CLI) shutdown() {
+	c.wg.Wait()
+	for _, hook := range c.shutdownHooks {
+		hook()
 	}
 }
 
 // NewCommands is a function to initialize all commands
-func NewCommands(module module.Module) map[string]Command {
+func NewCommands(module module.Module, exporter Exporter, dlq DeadLetterQueue, webhooks WebhookRegistry, contacts Contacts) map[string]Command {
 	workersCommand := WorkersCommand{}
 	workersCommand = workersCommand.NewWorkersCommand()
 
@@ -61,10 +76,37 @@ func NewCommands(module module.Module) map[string]Command {
 		"accept-order":  AcceptOrderCommand{Module: module},
 		"return-order":  ReturnOrderCommand{Module: module},
 		"issue-order":   IssueOrderCommand{Module: module},
+		"refuse-order":  RefuseOrderCommand{Module: module},
+		"unlock-order":  UnlockOrderCommand{Module: module},
 		"list-orders":   ListOrdersCommand{Module: module},
 		"accept-return": AcceptReturnCommand{Module: module},
 		"list-returns":  ListReturnsCommand{Module: module},
 		"set-workers":   &workersCommand,
+		"list-dlq":      ListDLQCommand{DLQ: dlq},
+		"replay-dlq":    ReplayDLQCommand{DLQ: dlq},
+
+		"create-return-manifest": CreateReturnManifestCommand{Module: module},
+		"add-courier":            AddCourierCommand{Module: module},
+		"open-session":           OpenSessionCommand{Module: module},
+		"close-session":          CloseSessionCommand{Module: module},
+		"add-authorization":      AddAuthorizationCommand{Module: module},
+		"list-authorizations":    ListAuthorizationsCommand{Module: module},
+		"add-pickup-point":       AddPickupPointCommand{Module: module},
+		"list-pickup-points":     ListPickupPointsCommand{Module: module},
+		"create-transfer":        CreateTransferCommand{Module: module},
+		"receive-transfer":       ReceiveTransferCommand{Module: module},
+		"add-rack":               AddRackCommand{Module: module},
+		"list-cells":             ListCellsCommand{Module: module},
+		"stocktake":              StocktakeCommand{Module: module, Input: stdin},
+		"import-orders":          ImportOrdersCommand{Module: module},
+		"export":                 ExportCommand{Exporter: exporter},
+		"report":                 ReportCommand{Module: module},
+
+		"add-webhook":             AddWebhookCommand{Webhooks: webhooks},
+		"list-webhooks":           ListWebhooksCommand{Webhooks: webhooks},
+		"delete-webhook":          DeleteWebhookCommand{Webhooks: webhooks},
+		"list-webhook-deliveries": ListWebhookDeliveriesCommand{Webhooks: webhooks},
+		"set-contact":             SetContactCommand{Contacts: contacts},
 	}
 }
 
@@ -78,22 +120,22 @@ func (c *CLI) Run() error {
 	// Start a goroutine that will perform cleanup when a signal is received
 	go func() {
 		<-sigChan
-		fmt.Println("Получен сигнал остановки. Ожидание завершения всех задач...")
-		c.wg.Wait()
-		fmt.Println("\"Все задачи завершены. Выход...")
+		fmt.Fprintln(os.Stderr, "Получен сигнал остановки. Ожидание завершения всех задач...")
+		c.shutdown()
+		fmt.Fprintln(os.Stderr, "Все задачи завершены. Выход...")
 		os.Exit(0)
 	}()
 
 	// Cycle for commands reading
 	for {
-		fmt.Print("> ")
-		reader := bufio.NewReader(os.Stdin)
-		commandLine, _ := reader.ReadString('\n')
+		fmt.Fprint(os.Stderr, "> ")
+		commandLine, _ := stdin.ReadString('\n')
 		commandLine = strings.TrimSuffix(commandLine, "\n")
 
 		// Exit from CLI
 		if commandLine == "exit" {
-			fmt.Println("Exiting...")
+			fmt.Fprintln(os.Stderr, "Exiting...")
+			c.shutdown()
 			return nil
 		}
 
@@ -117,35 +159,68 @@ func (c *CLI) executeCommand(commandName string, args []string) {
 
 	cmd, ok := c.commands[commandName]
 	if !ok {
-		fmt.Println("команда не установлена")
+		fmt.Fprintln(os.Stderr, "команда не установлена")
 		return
 	}
 
-	event := models.NewEvent(commandName, args)
-
-	eventBytes, err := json.Marshal(event)
+	args, format, err := parseOutputFlag(args)
 	if err != nil {
-		fmt.Println("Ошибка при сериализации события:", err)
+		fmt.Fprintln(os.Stderr, err)
 		return
 	}
 
-	// Check outputMode before sending message or printing to console
-	if c.outputMode == "kafka" {
-		if err = c.Producer.SendMessage(eventBytes); err != nil {
-			fmt.Println("Ошибка при отправке события в Kafka:", err)
-			return
+	// Execute the command without starting a new consumer for each command
+	done := c.executeCommandWithWorker(commandName, args, format, cmd)
+
+	// The command reads the terminal, commands are read again after it finishes
+	if interactive, ok := cmd.(Interactive); ok && interactive.Interactive(args) {
+		<-done
+	}
+}
+
+// parseOutputFlag removes the --output flag from the command arguments and returns the format,
+// table is used by default
+func parseOutputFlag(args []string) ([]string, string, error) {
+	format := output.Table
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if !strings.HasPrefix(args[i], "-") || name != outputFlag {
+			rest = append(rest, args[i])
+			continue
 		}
-		c.startReadingMessages()
-	} else if c.outputMode == "stdout" {
-		fmt.Printf("Event: %s\n", string(eventBytes))
+		if !hasValue {
+			if i+1 == len(args) {
+				return nil, "", fmt.Errorf("не указано значение параметра %s", outputFlag)
+			}
+			i++
+			value = args[i]
+		}
+		format = value
 	}
 
-	// Execute the command without starting a new consumer for each command
-	c.executeCommandWithWorker(commandName, args, cmd)
+	format, err := output.ParseFormat(format)
+	if err != nil {
+		return nil, "", err
+	}
+	return rest, format, nil
+}
+
+// printResult renders the whole result before writing it, so results of concurrent commands are not mixed
+func (c *CLI) printResult(result Result, format string) error {
+	var buf bytes.Buffer
+	if err := output.Render(&buf, result, format); err != nil {
+		return err
+	}
+
+	c.stdoutMu.Lock()
+	defer c.stdoutMu.Unlock()
+	_, err := buf.WriteTo(os.Stdout)
+	return err
 }
 
-// New method to handle command execution with worker
-func (c *CLI) executeCommandWithWorker(commandName string, args []string, cmd Command) {
+// New method to handle command execution with worker, the returned channel is closed when the command finishes
+func (c *CLI) executeCommandWithWorker(commandName string, args []string, format string, cmd Command) <-chan error {
 	// Create a channel to pass error from goroutine
 	errChan := make(chan error, 1)
 
@@ -169,24 +244,20 @@ func (c *CLI) executeCommandWithWorker(commandName string, args []string, cmd Co
 			c.cond.L.Unlock()
 			c.cond.Signal()
 		}()
-		fmt.Printf("Горутина с ID %s начала выполнение команды %s\n", goroutineID, commandName)
-		err := cmd.Call(args)
+		fmt.Fprintf(os.Stderr, "Горутина с ID %s начала выполнение команды %s\n", goroutineID, commandName)
+		result, err := cmd.Call(args)
+		if err == nil {
+			err = c.printResult(result, format)
+		}
 		if err != nil {
 			// Pass the error to the channel
 			errChan <- err
-			fmt.Printf("Горутина с ID %s не смогла выполнить команду %s из-за ошибки: %v\n", goroutineID, commandName, err)
+			fmt.Fprintf(os.Stderr, "Горутина с ID %s не смогла выполнить команду %s из-за ошибки: %v\n", goroutineID, commandName, err)
 		} else {
-			fmt.Printf("Горутина с ID %s успешно завершила выполнение команды %s\n", goroutineID, commandName)
+			fmt.Fprintf(os.Stderr, "Горутина с ID %s успешно завершила выполнение команды %s\n", goroutineID, commandName)
 		}
 		close(errChan)
 	}()
-}
 
-func (c *CLI) startReadingMessages() {
-	go func() {
-		err := c.Consumer.ReadMessages()
-		if err != nil {
-			fmt.Printf("Ошибка при чтении сообщений из Kafka: %v\n", err)
-		}
-	}()
+	return errChan
 }
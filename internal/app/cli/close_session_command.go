@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+
+	"route/internal/app/module"
+)
+
+const closeSession = "close-session"
+
+type CloseSessionCommand struct {
+	Module module.Module
+}
+
+func (c CloseSessionCommand) Name() string {
+	return closeSession
+}
+
+func (c CloseSessionCommand) Description() string {
+	return "Закрыть сессию приемки и вывести итоги:" +
+		" использование close-session --sessionID=SomeID\n" +
+		"--sessionID=SomeID: обязательный параметр, ID сессии приемки."
+}
+
+// Call is a method to close an acceptance session
+func (c CloseSessionCommand) Call(args []string) (Result, error) {
+	var sessionID int64
+
+	// Parse flags
+	fs := flag.NewFlagSet(closeSession, flag.ContinueOnError)
+	fs.Int64Var(&sessionID, "sessionID", 0, "use --sessionID=SomeID")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if sessionID == 0 {
+		return nil, errors.New("не указан обязательный параметр sessionID")
+	}
+
+	summary, err := c.Module.CloseSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSessionSummaryView(*summary), nil
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"route/internal/app/manifest"
+	"route/internal/app/models"
+	"route/internal/app/module"
+)
+
+const createReturnManifest = "create-return-manifest"
+
+type CreateReturnManifestCommand struct {
+	Module module.Module
+}
+
+func (c CreateReturnManifestCommand) Name() string {
+	return createReturnManifest
+}
+
+func (c CreateReturnManifestCommand) Description() string {
+	return "Передать курьеру просроченные заказы одной партией, кроме заказов других компаний:" +
+		" использование create-return-manifest --courierID=SomeID [--format=text|csv|json] [--file=SomePath]\n" +
+		"--courierID=SomeID: обязательный параметр, ID курьера.\n" +
+		"--format=text|csv|json: опциональный параметр, формат файла манифеста (по умолчанию text - акт для подписи).\n" +
+		"--file=SomePath: опциональный параметр, файл для сохранения манифеста. Переданные заказы выводятся в формате --output."
+}
+
+// Call is a method to hand over expired orders to courier and export the manifest
+func (c CreateReturnManifestCommand) Call(args []string) (Result, error) {
+	var courierID int
+	var format, path string
+
+	// Parse flags
+	fs := flag.NewFlagSet(createReturnManifest, flag.ContinueOnError)
+	fs.IntVar(&courierID, "courierID", 0, "use --courierID=SomeID")
+	fs.StringVar(&format, "format", manifest.FormatText, "use --format=text|csv|json")
+	fs.StringVar(&path, "file", "", "use --file=SomePath")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if courierID == 0 {
+		return nil, errors.New("не указан обязательный параметр courierID")
+	}
+
+	switch format {
+	case manifest.FormatText, manifest.FormatCSV, manifest.FormatJSON:
+	default:
+		return nil, fmt.Errorf("неизвестный формат: %s", format)
+	}
+
+	returnManifest, err := c.Module.CreateReturnManifest(courierID)
+	if err != nil {
+		return nil, err
+	}
+
+	if path != "" {
+		if err = saveReturnManifest(path, *returnManifest, format); err != nil {
+			return nil, fmt.Errorf("манифест № %d создан, но не сохранен в файл: %w", returnManifest.ID, err)
+		}
+	}
+
+	return returnManifestView{
+		ID:        returnManifest.ID,
+		CourierID: returnManifest.CourierID,
+		CreatedAt: returnManifest.CreatedAt,
+		File:      path,
+		Orders:    newOrderViews(returnManifest.Orders),
+	}, nil
+}
+
+// saveReturnManifest exports the manifest to the file in the given format
+func saveReturnManifest(path string, returnManifest models.ReturnManifest, format string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = manifest.ExportReturnManifest(file, returnManifest, format); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"route/internal/app/module"
+)
+
+const createTransfer = "create-transfer"
+
+type CreateTransferCommand struct {
+	Module module.Module
+}
+
+func (c CreateTransferCommand) Name() string {
+	return createTransfer
+}
+
+func (c CreateTransferCommand) Description() string {
+	return "Переместить заказы в другой пункт выдачи:" +
+		" использование create-transfer --toPointID=SomeID --orderIDs=ID1,ID2,ID3,...\n" +
+		"--toPointID=SomeID: обязательный параметр, ID пункта выдачи получателя.\n" +
+		"--orderIDs=ID1,ID2,ID3,...: обязательный параметр, ID заказов, которые необходимо переместить, разделенные запятой."
+}
+
+// Call is a method to send orders to another pickup point
+func (c CreateTransferCommand) Call(args []string) (Result, error) {
+	var toPointID int
+	var orderIDs string
+
+	// Parse flags
+	fs := flag.NewFlagSet(createTransfer, flag.ContinueOnError)
+	fs.IntVar(&toPointID, "toPointID", 0, "use --toPointID=SomeID")
+	fs.StringVar(&orderIDs, "orderIDs", "", "use --orderIDs=ID1,ID2,ID3,...")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if toPointID == 0 {
+		return nil, errors.New("не указан обязательный параметр toPointID")
+	}
+	if orderIDs == "" {
+		return nil, errors.New("не указан обязательный параметр orderIDs")
+	}
+
+	ids := make([]int, 0)
+	for _, orderIDStr := range strings.Split(orderIDs, ",") {
+		orderID, err := strconv.Atoi(strings.TrimSpace(orderIDStr))
+		if err != nil {
+			return nil, fmt.Errorf("не удалось преобразовать ID заказа в число: %v", err)
+		}
+		ids = append(ids, orderID)
+	}
+
+	transfer, err := c.Module.CreateTransfer(toPointID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTransferView(*transfer), nil
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+)
+
+const deleteWebhook = "delete-webhook"
+
+type DeleteWebhookCommand struct {
+	Webhooks WebhookRegistry
+}
+
+func (d DeleteWebhookCommand) Name() string {
+	return deleteWebhook
+}
+
+func (d DeleteWebhookCommand) Description() string {
+	return "Удалить подписку на события заказов: использование delete-webhook --id=SomeID\n" +
+		"--id=SomeID: обязательный параметр, ID подписки."
+}
+
+// Call is a method to delete a webhook subscription
+func (d DeleteWebhookCommand) Call(args []string) (Result, error) {
+	var id int64
+
+	// Parse flags
+	fs := flag.NewFlagSet(deleteWebhook, flag.ContinueOnError)
+	fs.Int64Var(&id, "id", 0, "use --id=SomeID")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if id == 0 {
+		return nil, errors.New("не указан обязательный параметр id")
+	}
+
+	if err := d.Webhooks.Unsubscribe(id); err != nil {
+		return nil, err
+	}
+
+	return newMessage("Вебхук успешно удален"), nil
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"route/internal/app/exporter"
+	"route/internal/app/models"
+)
+
+const export = "export"
+
+// Exporter writes orders selected by the filter to w in the format and returns their number
+type Exporter interface {
+	Export(w io.Writer, format exporter.Format, filter models.ExportFilter) (int, error)
+}
+
+type ExportCommand struct {
+	Exporter Exporter
+}
+
+func (e ExportCommand) Name() string {
+	return export
+}
+
+func (e ExportCommand) Description() string {
+	return "Выгрузить заказы или возвраты в файл:" +
+		" использование export --file=path --format=csv|jsonl|xlsx [--kind=orders|returns] [--userID=ID] [--lastN=Number] [--page=Number] [--pageSize=Number]\n" +
+		"--file=path: обязательный параметр, файл выгрузки.\n" +
+		"--format=csv|jsonl|xlsx: обязательный параметр, формат файла.\n" +
+		"--kind=orders|returns: опциональный параметр, выгрузить заказы как list-orders или возвраты как list-returns, по умолчанию orders.\n" +
+		"--userID=ID, --lastN=Number: фильтры заказов, по умолчанию выгружаются все заказы всех пользователей.\n" +
+		"--page=Number, --pageSize=Number: фильтры возвратов, по умолчанию выгружаются все возвраты."
+}
+
+// exportView is a summary of the export
+type exportView struct {
+	Exported int    `json:"exported" yaml:"exported"`
+	File     string `json:"file" yaml:"file"`
+}
+
+// Call is a method to export orders to a file
+func (e ExportCommand) Call(args []string) (Result, error) {
+	var file, format, kind string
+	var filter models.ExportFilter
+
+	// Parse flags
+	fs := flag.NewFlagSet(export, flag.ContinueOnError)
+	fs.StringVar(&file, "file", "", "use --file=path")
+	fs.StringVar(&format, "format", "", "use --format=csv|jsonl|xlsx")
+	fs.StringVar(&kind, "kind", string(models.OrdersExport), "use --kind=orders|returns")
+	fs.IntVar(&filter.UserID, "userID", 0, "use --userID=SomeID")
+	fs.IntVar(&filter.LastN, "lastN", 0, "use --lastN=SomeNumber")
+	fs.IntVar(&filter.Page, "page", 0, "use --page=pageNumber")
+	fs.IntVar(&filter.PageSize, "pageSize", 0, "use --pageSize=pageSizeNumber")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if file == "" {
+		return nil, errors.New("не указан обязательный параметр file")
+	}
+	if format == "" {
+		return nil, errors.New("не указан обязательный параметр format")
+	}
+	exportFormat, err := exporter.ParseFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	filter.Kind = models.ExportKind(kind)
+
+	dst, err := os.Create(file)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось создать файл выгрузки: %v", err)
+	}
+	defer dst.Close()
+
+	count, err := e.Exporter.Export(dst, exportFormat, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err = dst.Close(); err != nil {
+		return nil, err
+	}
+
+	return exportView{Exported: count, File: file}, nil
+}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"route/internal/app/importer"
+	"route/internal/app/module"
+)
+
+const importOrders = "import-orders"
+
+type ImportOrdersCommand struct {
+	Module module.Module
+}
+
+func (i ImportOrdersCommand) Name() string {
+	return importOrders
+}
+
+func (i ImportOrdersCommand) Description() string {
+	return "Принять заказы из файла:" +
+		" использование import-orders --file=path --format=csv|jsonl [--results=path] [--codes=path] [--dry-run] [--resume] [--batchSize=100]\n" +
+		"--file=path: обязательный параметр, файл с заказами. В CSV первая строка содержит колонки order_id,user_id,deadline,packaging_type,weight,cost," +
+		" в JSONL каждая строка - объект с такими же полями.\n" +
+		"--format=csv|jsonl: обязательный параметр, формат файла.\n" +
+		"--results=path: опциональный параметр, файл результатов по строкам, по умолчанию path.results.csv.\n" +
+		"--codes=path: опциональный параметр, файл с кодами получения принятых заказов, доступный только владельцу. Без него коды не сохраняются.\n" +
+		"--dry-run: опциональный параметр, только проверить заказы, не принимая их.\n" +
+		"--resume: опциональный параметр, продолжить с первой строки, которой нет в файле результатов.\n" +
+		"--batchSize=100: опциональный параметр, количество заказов, сохраняемых в одной транзакции."
+}
+
+// importView is a summary of the import, Accepted counts valid rows on a dry run
+type importView struct {
+	Processed int    `json:"processed" yaml:"processed"`
+	Accepted  int    `json:"accepted" yaml:"accepted"`
+	Rejected  int    `json:"rejected" yaml:"rejected"`
+	LastLine  int    `json:"last_line" yaml:"last_line"`
+	DryRun    bool   `json:"dry_run" yaml:"dry_run"`
+	Results   string `json:"results" yaml:"results"`
+	Codes     string `json:"codes,omitempty" yaml:"codes,omitempty"`
+}
+
+// Call is a method to import orders from a file
+func (i ImportOrdersCommand) Call(args []string) (Result, error) {
+	var file, format, resultsPath, codesPath string
+	var dryRun, resume bool
+	var batchSize int
+
+	// Parse flags
+	fs := flag.NewFlagSet(importOrders, flag.ContinueOnError)
+	fs.StringVar(&file, "file", "", "use --file=path")
+	fs.StringVar(&format, "format", "", "use --format=csv|jsonl")
+	fs.StringVar(&resultsPath, "results", "", "use --results=path")
+	fs.StringVar(&codesPath, "codes", "", "use --codes=path")
+	fs.BoolVar(&dryRun, "dry-run", false, "use --dry-run")
+	fs.BoolVar(&resume, "resume", false, "use --resume")
+	fs.IntVar(&batchSize, "batchSize", importer.DefaultBatchSize, "use --batchSize=SomeNumber")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if file == "" {
+		return nil, errors.New("не указан обязательный параметр file")
+	}
+	if format == "" {
+		return nil, errors.New("не указан обязательный параметр format")
+	}
+	if batchSize <= 0 {
+		return nil, errors.New("размер пакета должен быть больше нуля")
+	}
+	if resultsPath == "" {
+		resultsPath = file + ".results.csv"
+	}
+
+	src, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть файл: %v", err)
+	}
+	defer src.Close()
+
+	opts := importer.Options{BatchSize: batchSize, DryRun: dryRun}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if resume {
+		if opts.SkipLines, err = importer.LastLine(resultsPath); err != nil {
+			return nil, err
+		}
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+
+	dst, err := os.OpenFile(resultsPath, flags, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть файл результатов: %v", err)
+	}
+	defer dst.Close()
+
+	info, err := dst.Stat()
+	if err != nil {
+		return nil, err
+	}
+	results, err := importer.NewResultWriter(dst, info.Size() == 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pickup codes give away orders, so they are written only to the file asked for and readable by its owner only
+	if codesPath != "" {
+		codesDst, err := os.OpenFile(codesPath, flags, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось открыть файл кодов получения: %v", err)
+		}
+		defer codesDst.Close()
+
+		codesInfo, err := codesDst.Stat()
+		if err != nil {
+			return nil, err
+		}
+		if opts.Codes, err = importer.NewCodeWriter(codesDst, codesInfo.Size() == 0); err != nil {
+			return nil, err
+		}
+	}
+
+	summary, err := importer.New(i.Module).Import(src, importer.Format(format), results, opts)
+	if err != nil {
+		return nil, fmt.Errorf("импорт остановлен после строки %d: %v", summary.LastLine, err)
+	}
+
+	if opts.SkipLines > 0 {
+		fmt.Fprintf(os.Stderr, "Импорт продолжен после строки %d\n", opts.SkipLines)
+	}
+	return importView{
+		Processed: summary.Processed,
+		Accepted:  summary.Accepted,
+		Rejected:  summary.Rejected,
+		LastLine:  summary.LastLine,
+		DryRun:    dryRun,
+		Results:   resultsPath,
+		Codes:     codesPath,
+	}, nil
+}
@@ -7,7 +7,9 @@ import (
 	"strconv"
 	"strings"
 
+	"route/internal/app/models"
 	"route/internal/app/module"
+	"route/internal/app/pickup"
 )
 
 const issueOrder = "issue-order"
@@ -22,41 +24,83 @@ func (i IssueOrderCommand) Name() string {
 
 func (i IssueOrderCommand) Description() string {
 	return "Выдать заказ пользователю:" +
-		" использование issue-order --orderIDs=ID1,ID2,ID3,...\n" +
-		"--orderIDs=ID1,ID2,ID3,...: обязательный параметр, ID заказов, которые необходимо выдать пользователю, разделенные запятой."
+		" использование issue-order --orderIDs=ID1,ID2,ID3,... --codes=C1,C2,C3,...\n" +
+		"--orderIDs=ID1,ID2,ID3,...: обязательный параметр, ID заказов, которые необходимо выдать пользователю, разделенные запятой.\n" +
+		"--codes=C1,C2,C3,...: коды получения заказов в том же порядке, что и ID заказов.\n" +
+		"--qr=SomePayload: содержимое QR-кода заказа, используется вместо --orderIDs и --codes.\n" +
+		"--override=SomeManager: опциональный параметр, менеджер, выдающий заказы без кода получения.\n" +
+		"--recipient=SomeName: опциональный параметр, получатель по доверенности клиента. Слова можно разделять символом _ ."
 }
 
 // Call is a method to issue order to client
-func (i IssueOrderCommand) Call(args []string) error {
-	var orderIDs string
+func (i IssueOrderCommand) Call(args []string) (Result, error) {
+	var orderIDs, codes, qr, override, recipient string
 
 	// Parse flags
 	fs := flag.NewFlagSet(issueOrder, flag.ContinueOnError)
 	fs.StringVar(&orderIDs, "orderIDs", "", "use --orderIDs=ID1,ID2,ID3,...")
+	fs.StringVar(&codes, "codes", "", "use --codes=C1,C2,C3,...")
+	fs.StringVar(&qr, "qr", "", "use --qr=SomePayload")
+	fs.StringVar(&override, "override", "", "use --override=SomeManager")
+	fs.StringVar(&recipient, "recipient", "", "use --recipient=SomeName")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return nil, err
+	}
+
+	requests, err := issueRequests(orderIDs, codes, qr, override)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, req := range requests {
+		// CLI arguments are split by spaces, so words of the recipient are joined with underscores
+		req.Recipient = strings.ReplaceAll(recipient, "_", " ")
+		err = i.Module.IssueOrder(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newMessage("Заказы успешно выданы"), nil
+}
+
+// issueRequests matches order IDs with their pickup codes
+func issueRequests(orderIDs, codes, qr, override string) ([]models.IssueRequest, error) {
+	if qr != "" {
+		orderID, code, err := pickup.ParseQRPayload(qr)
+		if err != nil {
+			return nil, errors.New("некорректный QR-код заказа")
+		}
+		return []models.IssueRequest{{OrderID: orderID, PickupCode: code, OverrideBy: override}}, nil
 	}
 
 	if orderIDs == "" {
-		return errors.New("не указан обязательный параметр orderIDs")
+		return nil, errors.New("не указан обязательный параметр orderIDs")
 	}
 
 	// Split the orderIDs string into individual IDs
 	orderIDStrs := strings.Split(orderIDs, ",")
 
-	// Convert each ID to an integer and issue the order
-	for _, orderIDStr := range orderIDStrs {
+	var codeStrs []string
+	if codes != "" {
+		codeStrs = strings.Split(codes, ",")
+	}
+	if override == "" && len(codeStrs) != len(orderIDStrs) {
+		return nil, errors.New("количество кодов получения не совпадает с количеством заказов")
+	}
+
+	// Convert each ID to an integer
+	requests := make([]models.IssueRequest, len(orderIDStrs))
+	for idx, orderIDStr := range orderIDStrs {
 		orderID, err := strconv.Atoi(orderIDStr)
 		if err != nil {
-			return fmt.Errorf("не удалось преобразовать ID заказа в число: %v", err)
+			return nil, fmt.Errorf("не удалось преобразовать ID заказа в число: %v", err)
 		}
 
-		err = i.Module.IssueOrder(orderID)
-		if err != nil {
-			return err
+		requests[idx] = models.IssueRequest{OrderID: orderID, OverrideBy: override}
+		if idx < len(codeStrs) {
+			requests[idx].PickupCode = strings.TrimSpace(codeStrs[idx])
 		}
 	}
-
-	fmt.Println("Заказы успешно выданы")
-	return nil
+	return requests, nil
 }
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+
+	"route/internal/app/module"
+)
+
+const listAuthorizations = "list-authorizations"
+
+type ListAuthorizationsCommand struct {
+	Module module.Module
+}
+
+func (l ListAuthorizationsCommand) Name() string {
+	return listAuthorizations
+}
+
+func (l ListAuthorizationsCommand) Description() string {
+	return "Вывести доверенности клиента:" +
+		" использование list-authorizations --userID=SomeID\n" +
+		"--userID=SomeID: обязательный параметр, ID клиента."
+}
+
+// Call is a method to list powers of attorney of client
+func (l ListAuthorizationsCommand) Call(args []string) (Result, error) {
+	var userID int
+
+	// Parse flags
+	fs := flag.NewFlagSet(listAuthorizations, flag.ContinueOnError)
+	fs.IntVar(&userID, "userID", 0, "use --userID=SomeID")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if userID == 0 {
+		return nil, errors.New("не указан обязательный параметр userID")
+	}
+
+	auths, err := l.Module.ListAuthorizations(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]authorizationView, len(auths))
+	for i, auth := range auths {
+		views[i] = newAuthorizationView(auth)
+	}
+	return views, nil
+}
@@ -0,0 +1,27 @@
+package cli
+
+import "route/internal/app/module"
+
+const listCells = "list-cells"
+
+type ListCellsCommand struct {
+	Module module.Module
+}
+
+func (l ListCellsCommand) Name() string {
+	return listCells
+}
+
+func (l ListCellsCommand) Description() string {
+	return "Вывести ячейки хранения пункта выдачи и заказы в них: использование list-cells"
+}
+
+// Call is a method to list storage cells
+func (l ListCellsCommand) Call(_ []string) (Result, error) {
+	cells, err := l.Module.ListCells()
+	if err != nil {
+		return nil, err
+	}
+
+	return newCellViews(cells), nil
+}
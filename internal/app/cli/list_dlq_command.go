@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"route/internal/app/models"
+)
+
+const listDLQ = "list-dlq"
+
+type DeadLetterQueue interface {
+	List(topic string, limit int) ([]models.DeadLetter, error)
+	Replay(topic string, partition int32, offset int64) error
+}
+
+type ListDLQCommand struct {
+	DLQ DeadLetterQueue
+}
+
+func (l ListDLQCommand) Name() string {
+	return listDLQ
+}
+
+func (l ListDLQCommand) Description() string {
+	return "Вывести сообщения, которые не удалось обработать: использование list-dlq [--limit=Number] [--topic=Name].\n" +
+		"--limit=Number: опциональный параметр, количество последних сообщений от 1 до 1000 (по умолчанию 10).\n" +
+		"--topic=Name: опциональный параметр, топик, из которого пришли сообщения (по умолчанию топик событий)."
+}
+
+// Call is a method to list messages of the dead-letter topic
+func (l ListDLQCommand) Call(args []string) (Result, error) {
+	var limit int
+	var topic string
+
+	// Parse flags
+	fs := flag.NewFlagSet(listDLQ, flag.ContinueOnError)
+	fs.IntVar(&limit, "limit", 10, "use --limit=Number")
+	fs.StringVar(&topic, "topic", "", "use --topic=Name")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > models.MaxDeadLetters {
+		return nil, fmt.Errorf("параметр limit должен быть от 1 до %d", models.MaxDeadLetters)
+	}
+
+	letters, err := l.DLQ.List(topic, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]deadLetterView, len(letters))
+	for i, letter := range letters {
+		views[i] = deadLetterView{
+			Partition:     letter.Partition,
+			Offset:        letter.Offset,
+			Time:          letter.Time,
+			Key:           letter.Key,
+			EventType:     string(letter.EventType),
+			Attempts:      letter.Attempts,
+			Error:         letter.Error,
+			OriginalTopic: letter.OriginalTopic,
+		}
+	}
+	return views, nil
+}
@@ -3,7 +3,6 @@ package cli
 import (
 	"errors"
 	"flag"
-	"fmt"
 
 	"route/internal/app/module"
 )
@@ -26,7 +25,7 @@ func (l ListOrdersCommand) Description() string {
 }
 
 // Call is a method to list orders
-func (l ListOrdersCommand) Call(args []string) error {
+func (l ListOrdersCommand) Call(args []string) (Result, error) {
 	var lastN, userID int
 
 	// Parse flags
@@ -34,25 +33,17 @@ func (l ListOrdersCommand) Call(args []string) error {
 	fs.IntVar(&userID, "userID", 0, "use --userID=SomeID")
 	fs.IntVar(&lastN, "lastN", 5, "use --lastN=SomeNumber")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return nil, err
 	}
 
 	if userID == 0 {
-		return errors.New("не указан обязательный параметр userID")
+		return nil, errors.New("не указан обязательный параметр userID")
 	}
 
 	list, err := l.Module.ListOrders(userID, lastN)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if len(list) == 0 {
-		fmt.Println("По ID пользователя заказов в ПВЗ не найдено")
-		return nil
-	}
-
-	for _, order := range list {
-		fmt.Printf("OrderID: %v\nUserID: %v\n\n", order.OrderID, order.UserID)
-	}
-	return nil
+	return newOrderViews(list), nil
 }
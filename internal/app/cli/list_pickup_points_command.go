@@ -0,0 +1,31 @@
+package cli
+
+import "route/internal/app/module"
+
+const listPickupPoints = "list-pickup-points"
+
+type ListPickupPointsCommand struct {
+	Module module.Module
+}
+
+func (l ListPickupPointsCommand) Name() string {
+	return listPickupPoints
+}
+
+func (l ListPickupPointsCommand) Description() string {
+	return "Вывести пункты выдачи сети: использование list-pickup-points"
+}
+
+// Call is a method to list pickup points
+func (l ListPickupPointsCommand) Call(_ []string) (Result, error) {
+	points, err := l.Module.ListPickupPoints()
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]pickupPointView, len(points))
+	for i, point := range points {
+		views[i] = newPickupPointView(point)
+	}
+	return views, nil
+}
@@ -2,7 +2,6 @@ package cli
 
 import (
 	"flag"
-	"fmt"
 
 	"route/internal/app/module"
 )
@@ -25,7 +24,7 @@ func (l ListReturnsCommand) Description() string {
 }
 
 // Call is a method to list returns
-func (l ListReturnsCommand) Call(args []string) error {
+func (l ListReturnsCommand) Call(args []string) (Result, error) {
 	var page, pageSize int
 
 	// Parse flags
@@ -33,21 +32,13 @@ func (l ListReturnsCommand) Call(args []string) error {
 	fs.IntVar(&page, "page", 1, "use --page=pageNumber")
 	fs.IntVar(&pageSize, "pageSize", 5, "use --pageSize=pageSizeNumber")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return nil, err
 	}
 
 	list, err := l.Module.ListReturns(page, pageSize)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if len(list) == 0 {
-		fmt.Println("Список возвратов пуст")
-		return nil
-	}
-
-	for _, order := range list {
-		fmt.Printf("OrderID: %v\nUserID: %v\n", order.OrderID, order.UserID)
-	}
-	return nil
+	return newOrderViews(list), nil
 }
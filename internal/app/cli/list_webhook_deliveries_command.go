@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+)
+
+const listWebhookDeliveries = "list-webhook-deliveries"
+
+type ListWebhookDeliveriesCommand struct {
+	Webhooks WebhookRegistry
+}
+
+func (l ListWebhookDeliveriesCommand) Name() string {
+	return listWebhookDeliveries
+}
+
+func (l ListWebhookDeliveriesCommand) Description() string {
+	return "Вывести журнал доставки вебхука: использование list-webhook-deliveries --id=SomeID [--limit=Number]\n" +
+		"--id=SomeID: обязательный параметр, ID подписки.\n" +
+		"--limit=Number: опциональный параметр, количество последних попыток (по умолчанию 10)."
+}
+
+// Call is a method to list delivery attempts of a webhook subscription
+func (l ListWebhookDeliveriesCommand) Call(args []string) (Result, error) {
+	var id int64
+	var limit int
+
+	// Parse flags
+	fs := flag.NewFlagSet(listWebhookDeliveries, flag.ContinueOnError)
+	fs.Int64Var(&id, "id", 0, "use --id=SomeID")
+	fs.IntVar(&limit, "limit", 10, "use --limit=Number")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if id == 0 {
+		return nil, errors.New("не указан обязательный параметр id")
+	}
+
+	logs, err := l.Webhooks.ListDeliveries(id, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]deliveryLogView, len(logs))
+	for i, entry := range logs {
+		views[i] = deliveryLogView{
+			DeliveryID: entry.DeliveryID,
+			EventID:    entry.EventID,
+			EventType:  string(entry.EventType),
+			Attempt:    entry.Attempt,
+			StatusCode: entry.StatusCode,
+			Duration:   entry.Duration.String(),
+			Error:      entry.Error,
+			CreatedAt:  entry.CreatedAt,
+		}
+	}
+	return views, nil
+}
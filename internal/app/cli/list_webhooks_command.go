@@ -0,0 +1,33 @@
+package cli
+
+import ()
+
+const listWebhooks = "list-webhooks"
+
+type ListWebhooksCommand struct {
+	Webhooks WebhookRegistry
+}
+
+func (l ListWebhooksCommand) Name() string {
+	return listWebhooks
+}
+
+func (l ListWebhooksCommand) Description() string {
+	return "Вывести подписки на события заказов: использование list-webhooks"
+}
+
+// Call is a method to list webhook subscriptions
+func (l ListWebhooksCommand) Call(_ []string) (Result, error) {
+	subs, err := l.Webhooks.List()
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]webhookView, len(subs))
+	for i, sub := range subs {
+		// Secrets are shown only when the webhook is added
+		sub.Secret = ""
+		views[i] = newWebhookView(sub)
+	}
+	return views, nil
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+
+	"route/internal/app/module"
+)
+
+const openSession = "open-session"
+
+type OpenSessionCommand struct {
+	Module module.Module
+}
+
+func (o OpenSessionCommand) Name() string {
+	return openSession
+}
+
+func (o OpenSessionCommand) Description() string {
+	return "Открыть сессию приемки заказов от курьера:" +
+		" использование open-session --courierID=SomeID [--expected=SomeCount]\n" +
+		"--courierID=SomeID: обязательный параметр, ID курьера.\n" +
+		"--expected=SomeCount: опциональный параметр, количество заказов по накладной курьера."
+}
+
+// Call is a method to open an acceptance session
+func (o OpenSessionCommand) Call(args []string) (Result, error) {
+	var courierID, expected int
+
+	// Parse flags
+	fs := flag.NewFlagSet(openSession, flag.ContinueOnError)
+	fs.IntVar(&courierID, "courierID", 0, "use --courierID=SomeID")
+	fs.IntVar(&expected, "expected", 0, "use --expected=SomeCount")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if courierID == 0 {
+		return nil, errors.New("не указан обязательный параметр courierID")
+	}
+
+	session, err := o.Module.OpenSession(courierID, expected)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSessionView(*session), nil
+}
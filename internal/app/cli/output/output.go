@@ -0,0 +1,221 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats of command results
+const (
+	Table = "table"
+	JSON  = "json"
+	YAML  = "yaml"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// Tabler is implemented by results with their own table view
+type Tabler interface {
+	Table(w io.Writer) error
+}
+
+// ParseFormat checks the format of command results
+func ParseFormat(format string) (string, error) {
+	switch format {
+	case Table, JSON, YAML:
+		return format, nil
+	}
+	return "", fmt.Errorf("неизвестный формат вывода: %s", format)
+}
+
+// Render writes the result in the format, nil result is not written.
+// JSON and YAML keys are taken from the json and yaml tags of the result, the table
+// shows structs as lists of fields and slices of structs as tables with a column per field
+func Render(w io.Writer, result interface{}, format string) error {
+	if result == nil {
+		return nil
+	}
+
+	switch format {
+	case Table:
+		return renderTable(w, result)
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	case YAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("неизвестный формат вывода: %s", format)
+	}
+}
+
+func renderTable(w io.Writer, result interface{}) error {
+	if tabler, ok := result.(Tabler); ok {
+		return tabler.Table(w)
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(result))
+	switch {
+	case isStruct(v.Type()):
+		return writeStruct(w, v)
+	case v.Kind() == reflect.Slice && isStruct(elemType(v.Type())):
+		return writeList(w, v)
+	case v.Kind() == reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			fmt.Fprintln(w, formatValue(v.Index(i)))
+		}
+		return nil
+	}
+	_, err := fmt.Fprintln(w, formatValue(v))
+	return err
+}
+
+// field is a column of a table or a line of a struct
+type field struct {
+	name  string
+	index []int
+}
+
+// fields returns the tagged fields of a struct type, fields of embedded structs are inlined
+func fields(t reflect.Type) []field {
+	var out []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		// Fields of embedded structs are promoted even if the struct type is unexported, like in encoding/json
+		if f.Anonymous && isStruct(f.Type) {
+			for _, inner := range fields(f.Type) {
+				out = append(out, field{name: inner.name, index: append([]int{i}, inner.index...)})
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		out = append(out, field{name: name, index: []int{i}})
+	}
+	return out
+}
+
+// writeStruct writes a line per field, nested lists of structs are written as tables after the fields
+func writeStruct(w io.Writer, v reflect.Value) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	var lists []field
+	for _, f := range fields(v.Type()) {
+		value := reflect.Indirect(v.FieldByIndex(f.index))
+		if value.Kind() == reflect.Slice && isStruct(elemType(value.Type())) {
+			lists = append(lists, f)
+			continue
+		}
+		fmt.Fprintf(tw, "%s:\t%s\n", f.name, formatValue(value))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, f := range lists {
+		fmt.Fprintf(w, "\n%s:\n", f.name)
+		if err := writeList(w, v.FieldByIndex(f.index)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeList writes a slice of structs as a table with a header
+func writeList(w io.Writer, v reflect.Value) error {
+	columns := fields(elemType(v.Type()))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.name
+	}
+	fmt.Fprintln(tw, strings.Join(names, "\t"))
+
+	for i := 0; i < v.Len(); i++ {
+		row := reflect.Indirect(v.Index(i))
+		values := make([]string, len(columns))
+		for j, c := range columns {
+			values[j] = formatValue(row.FieldByIndex(c.index))
+		}
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	return tw.Flush()
+}
+
+func formatValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch value := v.Interface().(type) {
+	case time.Time:
+		if value.IsZero() {
+			return ""
+		}
+		return value.Format(timeLayout)
+	case time.Duration:
+		return value.String()
+	case fmt.Stringer:
+		return value.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return "да"
+		}
+		return "нет"
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Slice:
+		values := make([]string, v.Len())
+		for i := range values {
+			values[i] = formatValue(v.Index(i))
+		}
+		return strings.Join(values, ", ")
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func elemType(t reflect.Type) reflect.Type {
+	t = t.Elem()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isStruct reports whether values of the type are shown field by field, times are shown as values
+func isStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t != reflect.TypeOf(time.Time{})
+}
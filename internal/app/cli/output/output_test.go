@@ -0,0 +1,132 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	ID     int       `json:"id" yaml:"id"`
+	Name   string    `json:"name" yaml:"name"`
+	Weight float64   `json:"weight" yaml:"weight"`
+	Free   bool      `json:"free" yaml:"free"`
+	At     time.Time `json:"at" yaml:"at"`
+	secret string
+}
+
+type base struct {
+	ID int `json:"id" yaml:"id"`
+}
+
+type box struct {
+	base  `yaml:",inline"`
+	Tags  []string `json:"tags" yaml:"tags"`
+	Items []item   `json:"items" yaml:"items"`
+}
+
+type tabler struct{}
+
+func (tabler) Table(w io.Writer) error {
+	_, err := io.WriteString(w, "own table\n")
+	return err
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2024, 8, 7, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		result   interface{}
+		format   string
+		expected string
+	}{
+		{
+			name:     "nil result prints nothing",
+			result:   nil,
+			format:   Table,
+			expected: "",
+		},
+		{
+			name:   "struct is a list of fields",
+			result: item{ID: 1, Name: "коробка", Weight: 2.5, Free: true, At: at, secret: "hidden"},
+			format: Table,
+			expected: "id:      1\n" +
+				"name:    коробка\n" +
+				"weight:  2.5\n" +
+				"free:    да\n" +
+				"at:      2024-08-07 12:30:00\n",
+		},
+		{
+			name:   "slice of structs is a table",
+			result: []item{{ID: 1, Name: "a"}, {ID: 22, Name: "bb", Free: true, At: at}},
+			format: Table,
+			expected: "id  name  weight  free  at\n" +
+				"1   a     0       нет   \n" +
+				"22  bb    0       да    2024-08-07 12:30:00\n",
+		},
+		{
+			name:   "embedded fields are promoted and nested lists follow the fields",
+			result: box{base: base{ID: 7}, Tags: []string{"x", "y"}, Items: []item{{ID: 1, Name: "a"}}},
+			format: Table,
+			expected: "id:    7\n" +
+				"tags:  x, y\n" +
+				"\nitems:\n" +
+				"id  name  weight  free  at\n" +
+				"1   a     0       нет   \n",
+		},
+		{
+			name:     "result renders its own table",
+			result:   tabler{},
+			format:   Table,
+			expected: "own table\n",
+		},
+		{
+			name:     "json",
+			result:   box{base: base{ID: 7}, Tags: []string{"x"}},
+			format:   JSON,
+			expected: "{\n  \"id\": 7,\n  \"tags\": [\n    \"x\"\n  ],\n  \"items\": null\n}\n",
+		},
+		{
+			name:     "yaml",
+			result:   box{base: base{ID: 7}, Tags: []string{"x"}},
+			format:   YAML,
+			expected: "id: 7\ntags:\n  - x\nitems: []\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			var out bytes.Buffer
+
+			// act
+			err := Render(&out, tt.result, tt.format)
+
+			// assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, out.String())
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	// act
+	format, err := ParseFormat(YAML)
+	_, unknownErr := ParseFormat("xml")
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, YAML, format)
+	assert.EqualError(t, unknownErr, "неизвестный формат вывода: xml")
+}
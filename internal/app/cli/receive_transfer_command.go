@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+
+	"route/internal/app/module"
+)
+
+const receiveTransfer = "receive-transfer"
+
+type ReceiveTransferCommand struct {
+	Module module.Module
+}
+
+func (c ReceiveTransferCommand) Name() string {
+	return receiveTransfer
+}
+
+func (c ReceiveTransferCommand) Description() string {
+	return "Принять заказы, перемещенные из другого пункта выдачи:" +
+		" использование receive-transfer --transferID=SomeID\n" +
+		"--transferID=SomeID: обязательный параметр, ID перемещения."
+}
+
+// Call is a method to receive orders sent from another pickup point
+func (c ReceiveTransferCommand) Call(args []string) (Result, error) {
+	var transferID int64
+
+	// Parse flags
+	fs := flag.NewFlagSet(receiveTransfer, flag.ContinueOnError)
+	fs.Int64Var(&transferID, "transferID", 0, "use --transferID=SomeID")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if transferID == 0 {
+		return nil, errors.New("не указан обязательный параметр transferID")
+	}
+
+	transfer, err := c.Module.ReceiveTransfer(transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTransferView(*transfer), nil
+}
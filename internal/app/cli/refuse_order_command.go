@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"strings"
+
+	"route/internal/app/module"
+)
+
+const refuseOrder = "refuse-order"
+
+type RefuseOrderCommand struct {
+	Module module.Module
+}
+
+func (r RefuseOrderCommand) Name() string {
+	return refuseOrder
+}
+
+func (r RefuseOrderCommand) Description() string {
+	return "Оформить отказ клиента от заказа при выдаче:" +
+		" использование refuse-order --orderID=SomeID --reason=SomeReason\n" +
+		"--orderID=SomeID: обязательный параметр, ID заказа.\n" +
+		"--reason=SomeReason: обязательный параметр, причина отказа. Слова можно разделять символом _ ."
+}
+
+// Call is a method to record refusal of order by client
+func (r RefuseOrderCommand) Call(args []string) (Result, error) {
+	var orderID int
+	var reason string
+
+	// Parse flags
+	fs := flag.NewFlagSet(refuseOrder, flag.ContinueOnError)
+	fs.IntVar(&orderID, "orderID", 0, "use --orderID=SomeID")
+	fs.StringVar(&reason, "reason", "", "use --reason=SomeReason")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if orderID == 0 {
+		return nil, errors.New("не указан обязательный параметр orderID")
+	}
+	if reason == "" {
+		return nil, errors.New("не указан обязательный параметр reason")
+	}
+
+	// CLI arguments are split by spaces, so words of the reason are joined with underscores
+	err := r.Module.RefuseOrder(orderID, strings.ReplaceAll(reason, "_", " "))
+	if err != nil {
+		return nil, err
+	}
+
+	return newMessage("Отказ от заказа оформлен, заказ будет возвращен курьеру"), nil
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+)
+
+const replayDLQ = "replay-dlq"
+
+type ReplayDLQCommand struct {
+	DLQ DeadLetterQueue
+}
+
+func (r ReplayDLQCommand) Name() string {
+	return replayDLQ
+}
+
+func (r ReplayDLQCommand) Description() string {
+	return "Повторно отправить сообщение из очереди недоставленных на обработку:" +
+		" использование replay-dlq --partition=SomeNumber --offset=SomeNumber [--topic=Name]\n" +
+		"--partition=SomeNumber: обязательный параметр, партиция сообщения, выводится командой list-dlq.\n" +
+		"--offset=SomeNumber: обязательный параметр, offset сообщения, выводится командой list-dlq.\n" +
+		"--topic=Name: опциональный параметр, топик, из которого пришло сообщение (по умолчанию топик событий)."
+}
+
+// Call is a method to replay a message from the dead-letter topic
+func (r ReplayDLQCommand) Call(args []string) (Result, error) {
+	var partition int
+	var offset int64
+	var topic string
+
+	// Parse flags
+	fs := flag.NewFlagSet(replayDLQ, flag.ContinueOnError)
+	fs.IntVar(&partition, "partition", -1, "use --partition=SomeNumber")
+	fs.Int64Var(&offset, "offset", -1, "use --offset=SomeNumber")
+	fs.StringVar(&topic, "topic", "", "use --topic=Name")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if partition < 0 {
+		return nil, errors.New("не указан обязательный параметр partition")
+	}
+	if offset < 0 {
+		return nil, errors.New("не указан обязательный параметр offset")
+	}
+
+	err := r.DLQ.Replay(topic, int32(partition), offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return newMessage("Сообщение отправлено на повторную обработку"), nil
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"route/internal/app/models"
+	"route/internal/app/module"
+	"route/internal/app/report"
+)
+
+const reportCommand = "report"
+
+// reportDateLayout is the layout of report period dates
+const reportDateLayout = "2006-01-02"
+
+type ReportCommand struct {
+	Module module.Module
+}
+
+func (r ReportCommand) Name() string {
+	return reportCommand
+}
+
+func (r ReportCommand) Description() string {
+	return "Вывести отчет пункта выдачи по дням и типам упаковки:" +
+		" использование report [--from=YYYY-MM-DD] [--to=YYYY-MM-DD]\n" +
+		"--from=YYYY-MM-DD, --to=YYYY-MM-DD: опциональные параметры, период отчета включительно, по умолчанию сегодня.\n" +
+		"В отчете указано, сколько заказов принято, выдано и возвращено клиентами, доплаты за упаковку выданных заказов" +
+		" и стоимость возвращенных заказов."
+}
+
+// Call is a method to print the report
+func (r ReportCommand) Call(args []string) (Result, error) {
+	today := time.Now().Format(reportDateLayout)
+	var fromArg, toArg string
+
+	// Parse flags
+	fs := flag.NewFlagSet(reportCommand, flag.ContinueOnError)
+	fs.StringVar(&fromArg, "from", today, "use --from=YYYY-MM-DD")
+	fs.StringVar(&toArg, "to", today, "use --to=YYYY-MM-DD")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	from, err := time.ParseInLocation(reportDateLayout, fromArg, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("неверный формат даты from: %s", fromArg)
+	}
+	to, err := time.ParseInLocation(reportDateLayout, toArg, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("неверный формат даты to: %s", toArg)
+	}
+
+	shiftReport, err := r.Module.Report(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return reportView{Report: *shiftReport}, nil
+}
+
+// reportView prints the report as a table and marshals it with day dates
+type reportView struct {
+	models.Report
+}
+
+func (r reportView) Table(w io.Writer) error {
+	return report.Write(w, r.Report, report.FormatTable)
+}
+
+func (r reportView) MarshalJSON() ([]byte, error) {
+	return json.Marshal(report.NewView(r.Report))
+}
+
+func (r reportView) MarshalYAML() (interface{}, error) {
+	return report.NewView(r.Report), nil
+}
@@ -3,7 +3,6 @@ package cli
 import (
 	"errors"
 	"flag"
-	"fmt"
 
 	"route/internal/app/module"
 )
@@ -25,25 +24,24 @@ func (r ReturnOrderCommand) Description() string {
 }
 
 // Call is a method to return order to courier
-func (r ReturnOrderCommand) Call(args []string) error {
+func (r ReturnOrderCommand) Call(args []string) (Result, error) {
 	var orderID int
 
 	// Parse flags
 	fs := flag.NewFlagSet(returnOrder, flag.ContinueOnError)
 	fs.IntVar(&orderID, "orderID", 0, "use --orderID=SomeID")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return nil, err
 	}
 
 	if orderID == 0 {
-		return errors.New("не указан обязательный параметр orderID")
+		return nil, errors.New("не указан обязательный параметр orderID")
 	}
 
 	err := r.Module.ReturnOrder(orderID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	fmt.Println("Заказ успешно возвращен курьеру")
-	return nil
+	return newMessage("Заказ успешно возвращен курьеру"), nil
 }
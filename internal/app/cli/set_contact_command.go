@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+
+	"route/internal/app/models"
+)
+
+const setContact = "set-contact"
+
+type Contacts interface {
+	SetContact(contact models.Contact) error
+}
+
+type SetContactCommand struct {
+	Contacts Contacts
+}
+
+func (s SetContactCommand) Name() string {
+	return setContact
+}
+
+func (s SetContactCommand) Description() string {
+	return "Указать контакты клиента для уведомлений:" +
+		" использование set-contact --userID=SomeID [--phone=SomePhone] [--email=SomeEmail] [--locale=ru|en]\n" +
+		"--userID=SomeID: обязательный параметр, ID пользователя.\n" +
+		"--phone=SomePhone: телефон для SMS.\n" +
+		"--email=SomeEmail: адрес электронной почты. Необходимо указать телефон или email.\n" +
+		"--locale=ru|en: опциональный параметр, язык уведомлений (по умолчанию ru)."
+}
+
+// Call is a method to save contact data of a client
+func (s SetContactCommand) Call(args []string) (Result, error) {
+	var userID int
+	var phone, email, locale string
+
+	// Parse flags
+	fs := flag.NewFlagSet(setContact, flag.ContinueOnError)
+	fs.IntVar(&userID, "userID", 0, "use --userID=SomeID")
+	fs.StringVar(&phone, "phone", "", "use --phone=SomePhone")
+	fs.StringVar(&email, "email", "", "use --email=SomeEmail")
+	fs.StringVar(&locale, "locale", models.LocaleRu, "use --locale=ru|en")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if userID == 0 {
+		return nil, errors.New("не указан обязательный параметр userID")
+	}
+
+	err := s.Contacts.SetContact(models.Contact{UserID: userID, Phone: phone, Email: email, Locale: locale})
+	if err != nil {
+		return nil, err
+	}
+
+	return newMessage("Контакты клиента сохранены"), nil
+}
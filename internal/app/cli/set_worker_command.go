@@ -3,7 +3,6 @@ package cli
 import (
 	"errors"
 	"flag"
-	"fmt"
 	"sync/atomic"
 )
 
@@ -27,24 +26,23 @@ func (w WorkersCommand) Description() string {
 }
 
 // Call is a method to set the number of workers
-func (w *WorkersCommand) Call(args []string) error {
+func (w *WorkersCommand) Call(args []string) (Result, error) {
 	var count int
 
 	// Parse flags
 	fs := flag.NewFlagSet("set-workers", flag.ContinueOnError)
 	fs.IntVar(&count, "count", 2, "use --count=SomeNumber")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return nil, err
 	}
 
 	if count <= 0 {
-		return errors.New("количество горутин должно быть больше нуля")
+		return nil, errors.New("количество горутин должно быть больше нуля")
 	}
 
 	atomic.StoreInt32(w.workerCount, int32(count))
 
-	fmt.Printf("Количество горутин установлено на %d\n", *w.workerCount)
-	return nil
+	return newMessage("Количество горутин установлено на %d", atomic.LoadInt32(w.workerCount)), nil
 }
 
 // GetWorkersCount is a method to get the number of workers
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"route/internal/app/module"
+)
+
+const stocktake = "stocktake"
+
+// stocktakeEnd finishes interactive scanning
+const stocktakeEnd = "end"
+
+type StocktakeCommand struct {
+	Module module.Module
+	// Input is read for scanned order IDs when no file is given
+	Input io.Reader
+}
+
+func (s StocktakeCommand) Name() string {
+	return stocktake
+}
+
+func (s StocktakeCommand) Description() string {
+	return "Провести инвентаризацию пункта выдачи:" +
+		" использование stocktake [--file=path]\n" +
+		"--file=path: опциональный параметр, файл с отсканированными ID заказов, по одному или несколько через запятую в строке.\n" +
+		"Без --file ID заказов вводятся построчно, ввод завершается строкой end или пустой строкой."
+}
+
+// Interactive reports that the IDs are read from the terminal when no file is given
+func (s StocktakeCommand) Interactive(args []string) bool {
+	file, err := parseStocktakeFlags(args)
+	return err == nil && file == ""
+}
+
+func parseStocktakeFlags(args []string) (string, error) {
+	var file string
+
+	fs := flag.NewFlagSet(stocktake, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&file, "file", "", "use --file=path")
+	err := fs.Parse(args)
+	return file, err
+}
+
+// Call is a method to compare scanned orders with the database and save the discrepancy report
+func (s StocktakeCommand) Call(args []string) (Result, error) {
+	// Parse flags
+	file, err := parseStocktakeFlags(args)
+	if err != nil {
+		return nil, err
+	}
+
+	input := s.Input
+	if file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось открыть файл: %v", err)
+		}
+		defer f.Close()
+		input = f
+	} else {
+		fmt.Fprintln(os.Stderr, "Введите ID отсканированных заказов, для завершения введите end")
+	}
+
+	scanned, err := readScannedIDs(input, file == "")
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.Module.Stocktake(scanned)
+	if err != nil {
+		return nil, err
+	}
+
+	return newStocktakeView(*report), nil
+}
+
+// readScannedIDs reads order IDs separated by new lines, commas or spaces.
+// Interactive input ends with the end line or an empty line. Lines are read one by one,
+// so input after the end line is left to the command loop
+func readScannedIDs(r io.Reader, interactive bool) ([]int, error) {
+	var ids []int
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+
+		line = strings.TrimSpace(line)
+		if interactive && (line == "" || line == stocktakeEnd) {
+			return ids, nil
+		}
+
+		for _, field := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+			id, convErr := strconv.Atoi(field)
+			if convErr != nil {
+				return nil, fmt.Errorf("не удалось преобразовать ID заказа в число: %v", convErr)
+			}
+			ids = append(ids, id)
+		}
+
+		if errors.Is(err, io.EOF) {
+			return ids, nil
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+
+	"route/internal/app/module"
+)
+
+const unlockOrder = "unlock-order"
+
+type UnlockOrderCommand struct {
+	Module module.Module
+}
+
+func (u UnlockOrderCommand) Name() string {
+	return unlockOrder
+}
+
+func (u UnlockOrderCommand) Description() string {
+	return "Разблокировать заказ после неверных кодов получения:" +
+		" использование unlock-order --orderID=SomeID\n" +
+		"--orderID=SomeID: обязательный параметр, ID заказа."
+}
+
+// Call is a method to unlock order locked after wrong pickup codes
+func (u UnlockOrderCommand) Call(args []string) (Result, error) {
+	var orderID int
+
+	// Parse flags
+	fs := flag.NewFlagSet(unlockOrder, flag.ContinueOnError)
+	fs.IntVar(&orderID, "orderID", 0, "use --orderID=SomeID")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if orderID == 0 {
+		return nil, errors.New("не указан обязательный параметр orderID")
+	}
+
+	err := u.Module.UnlockOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newMessage("Заказ разблокирован"), nil
+}
@@ -0,0 +1,321 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"route/internal/app/models"
+)
+
+// Result is the value returned by a command, the CLI prints it in the format chosen with --output.
+// Nil result prints nothing
+type Result interface{}
+
+// message is the result of a command that only reports success
+type message struct {
+	Message string `json:"message" yaml:"message"`
+}
+
+func newMessage(format string, args ...interface{}) message {
+	return message{Message: fmt.Sprintf(format, args...)}
+}
+
+func (m message) Table(w io.Writer) error {
+	_, err := fmt.Fprintln(w, m.Message)
+	return err
+}
+
+// timeOrNil leaves unset times out of JSON and YAML
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// orderView shows all fields of an order except its secret hashes
+type orderView struct {
+	OrderID             int        `json:"order_id" yaml:"order_id"`
+	UserID              int        `json:"user_id" yaml:"user_id"`
+	PickupPointID       int        `json:"pickup_point_id" yaml:"pickup_point_id"`
+	Status              string     `json:"status" yaml:"status"`
+	Deadline            time.Time  `json:"deadline" yaml:"deadline"`
+	Weight              float64    `json:"weight" yaml:"weight"`
+	Cost                float64    `json:"cost" yaml:"cost"`
+	Cell                string     `json:"cell,omitempty" yaml:"cell,omitempty"`
+	IsAtPickupPoint     bool       `json:"is_at_pickup_point" yaml:"is_at_pickup_point"`
+	ReceivedFromCourier bool       `json:"received_from_courier" yaml:"received_from_courier"`
+	SessionID           int64      `json:"session_id,omitempty" yaml:"session_id,omitempty"`
+	TransferID          int64      `json:"transfer_id,omitempty" yaml:"transfer_id,omitempty"`
+	IssuedToUser        bool       `json:"issued_to_user" yaml:"issued_to_user"`
+	IssuedAt            *time.Time `json:"issued_at,omitempty" yaml:"issued_at,omitempty"`
+	ReceivedBy          string     `json:"received_by,omitempty" yaml:"received_by,omitempty"`
+	AuthorizationID     int64      `json:"authorization_id,omitempty" yaml:"authorization_id,omitempty"`
+	IssueOverrideBy     string     `json:"issue_override_by,omitempty" yaml:"issue_override_by,omitempty"`
+	IsReturned          bool       `json:"is_returned" yaml:"is_returned"`
+	ExpiredAt           *time.Time `json:"expired_at,omitempty" yaml:"expired_at,omitempty"`
+	ReturnedToCourierAt *time.Time `json:"returned_to_courier_at,omitempty" yaml:"returned_to_courier_at,omitempty"`
+	RefusedAt           *time.Time `json:"refused_at,omitempty" yaml:"refused_at,omitempty"`
+	RefusalReason       string     `json:"refusal_reason,omitempty" yaml:"refusal_reason,omitempty"`
+	PickupFailures      int        `json:"pickup_failures" yaml:"pickup_failures"`
+	PickupLastFailureAt *time.Time `json:"pickup_last_failure_at,omitempty" yaml:"pickup_last_failure_at,omitempty"`
+	PickupLockedAt      *time.Time `json:"pickup_locked_at,omitempty" yaml:"pickup_locked_at,omitempty"`
+}
+
+func newOrderView(order models.Order) orderView {
+	return orderView{
+		OrderID:             order.OrderID,
+		UserID:              order.UserID,
+		PickupPointID:       order.PickupPointID,
+		Status:              order.Status(),
+		Deadline:            order.Deadline,
+		Weight:              order.Weight,
+		Cost:                order.Cost,
+		Cell:                order.Cell,
+		IsAtPickupPoint:     order.IsAtPickupPoint,
+		ReceivedFromCourier: order.ReceivedFromCourier,
+		SessionID:           order.SessionID,
+		TransferID:          order.TransferID,
+		IssuedToUser:        order.IssuedToUser,
+		IssuedAt:            timeOrNil(order.IssuedAt),
+		ReceivedBy:          order.ReceivedBy,
+		AuthorizationID:     order.AuthorizationID,
+		IssueOverrideBy:     order.IssueOverrideBy,
+		IsReturned:          order.IsReturned,
+		ExpiredAt:           timeOrNil(order.ExpiredAt),
+		ReturnedToCourierAt: timeOrNil(order.ReturnedToCourierAt),
+		RefusedAt:           timeOrNil(order.RefusedAt),
+		RefusalReason:       order.RefusalReason,
+		PickupFailures:      order.PickupFailures,
+		PickupLastFailureAt: timeOrNil(order.PickupLastFailureAt),
+		PickupLockedAt:      timeOrNil(order.PickupLockedAt),
+	}
+}
+
+func newOrderViews(orders []models.Order) []orderView {
+	views := make([]orderView, len(orders))
+	for i, order := range orders {
+		views[i] = newOrderView(order)
+	}
+	return views
+}
+
+// acceptedOrderView is an accepted order with its pickup code, the code is shown only once
+type acceptedOrderView struct {
+	orderView  `yaml:",inline"`
+	PickupCode string `json:"pickup_code" yaml:"pickup_code"`
+	QRCode     string `json:"qr_code" yaml:"qr_code"`
+}
+
+type authorizationView struct {
+	ID         int64     `json:"id" yaml:"id"`
+	UserID     int       `json:"user_id" yaml:"user_id"`
+	Recipient  string    `json:"recipient" yaml:"recipient"`
+	OrderID    int       `json:"order_id,omitempty" yaml:"order_id,omitempty"`
+	ValidUntil time.Time `json:"valid_until" yaml:"valid_until"`
+	CreatedAt  time.Time `json:"created_at" yaml:"created_at"`
+}
+
+func newAuthorizationView(auth models.Authorization) authorizationView {
+	return authorizationView{
+		ID:         auth.ID,
+		UserID:     auth.UserID,
+		Recipient:  auth.Recipient,
+		OrderID:    auth.OrderID,
+		ValidUntil: auth.ValidUntil,
+		CreatedAt:  auth.CreatedAt,
+	}
+}
+
+type courierView struct {
+	ID        int       `json:"id" yaml:"id"`
+	Name      string    `json:"name" yaml:"name"`
+	Company   string    `json:"company,omitempty" yaml:"company,omitempty"`
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+}
+
+type sessionView struct {
+	ID            int64      `json:"id" yaml:"id"`
+	CourierID     int        `json:"courier_id" yaml:"courier_id"`
+	ExpectedCount int        `json:"expected_count,omitempty" yaml:"expected_count,omitempty"`
+	OpenedAt      time.Time  `json:"opened_at" yaml:"opened_at"`
+	ClosedAt      *time.Time `json:"closed_at,omitempty" yaml:"closed_at,omitempty"`
+}
+
+func newSessionView(session models.AcceptanceSession) sessionView {
+	return sessionView{
+		ID:            session.ID,
+		CourierID:     session.CourierID,
+		ExpectedCount: session.ExpectedCount,
+		OpenedAt:      session.OpenedAt,
+		ClosedAt:      timeOrNil(session.ClosedAt),
+	}
+}
+
+type rejectionView struct {
+	OrderID int    `json:"order_id" yaml:"order_id"`
+	Reason  string `json:"reason" yaml:"reason"`
+}
+
+// sessionSummaryView is a closed session, Missing is the difference with the declared number of orders
+type sessionSummaryView struct {
+	sessionView `yaml:",inline"`
+	Accepted    int             `json:"accepted" yaml:"accepted"`
+	TotalWeight float64         `json:"total_weight" yaml:"total_weight"`
+	Missing     int             `json:"missing" yaml:"missing"`
+	Rejections  []rejectionView `json:"rejections" yaml:"rejections"`
+}
+
+func newSessionSummaryView(summary models.SessionSummary) sessionSummaryView {
+	view := sessionSummaryView{
+		sessionView: newSessionView(summary.Session),
+		Accepted:    summary.Accepted,
+		TotalWeight: summary.TotalWeight,
+		Missing:     summary.Missing(),
+		Rejections:  make([]rejectionView, len(summary.Rejections)),
+	}
+	for i, rejection := range summary.Rejections {
+		view.Rejections[i] = rejectionView{OrderID: rejection.OrderID, Reason: rejection.Reason}
+	}
+	return view
+}
+
+type pickupPointView struct {
+	ID        int       `json:"id" yaml:"id"`
+	Name      string    `json:"name" yaml:"name"`
+	Address   string    `json:"address" yaml:"address"`
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+}
+
+func newPickupPointView(point models.PickupPoint) pickupPointView {
+	return pickupPointView{ID: point.ID, Name: point.Name, Address: point.Address, CreatedAt: point.CreatedAt}
+}
+
+type transferView struct {
+	ID          int64       `json:"id" yaml:"id"`
+	FromPointID int         `json:"from_point_id" yaml:"from_point_id"`
+	ToPointID   int         `json:"to_point_id" yaml:"to_point_id"`
+	CreatedAt   time.Time   `json:"created_at" yaml:"created_at"`
+	ReceivedAt  *time.Time  `json:"received_at,omitempty" yaml:"received_at,omitempty"`
+	Orders      []orderView `json:"orders" yaml:"orders"`
+}
+
+func newTransferView(transfer models.Transfer) transferView {
+	return transferView{
+		ID:          transfer.ID,
+		FromPointID: transfer.FromPointID,
+		ToPointID:   transfer.ToPointID,
+		CreatedAt:   transfer.CreatedAt,
+		ReceivedAt:  timeOrNil(transfer.ReceivedAt),
+		Orders:      newOrderViews(transfer.Orders),
+	}
+}
+
+// cellView is a storage cell, OrderID is zero if the cell is free
+type cellView struct {
+	Code      string  `json:"code" yaml:"code"`
+	Size      string  `json:"size" yaml:"size"`
+	MaxWeight float64 `json:"max_weight" yaml:"max_weight"`
+	Free      bool    `json:"free" yaml:"free"`
+	OrderID   int     `json:"order_id,omitempty" yaml:"order_id,omitempty"`
+}
+
+func newCellViews(cells []models.StorageCell) []cellView {
+	views := make([]cellView, len(cells))
+	for i, cell := range cells {
+		views[i] = cellView{Code: cell.Code, Size: cell.Size.String(), MaxWeight: cell.MaxWeight, Free: cell.OrderID == 0, OrderID: cell.OrderID}
+	}
+	return views
+}
+
+type rackView struct {
+	ID            int        `json:"id" yaml:"id"`
+	PickupPointID int        `json:"pickup_point_id" yaml:"pickup_point_id"`
+	Name          string     `json:"name" yaml:"name"`
+	CreatedAt     time.Time  `json:"created_at" yaml:"created_at"`
+	Cells         []cellView `json:"cells" yaml:"cells"`
+}
+
+type discrepancyView struct {
+	OrderID int    `json:"order_id" yaml:"order_id"`
+	Kind    string `json:"kind" yaml:"kind"`
+}
+
+type stocktakeView struct {
+	ID            int64             `json:"id" yaml:"id"`
+	PickupPointID int               `json:"pickup_point_id" yaml:"pickup_point_id"`
+	CreatedAt     time.Time         `json:"created_at" yaml:"created_at"`
+	Scanned       int               `json:"scanned" yaml:"scanned"`
+	Stored        int               `json:"stored" yaml:"stored"`
+	Missing       int               `json:"missing" yaml:"missing"`
+	Unexpected    int               `json:"unexpected" yaml:"unexpected"`
+	WronglyIssued int               `json:"wrongly_issued" yaml:"wrongly_issued"`
+	Discrepancies []discrepancyView `json:"discrepancies" yaml:"discrepancies"`
+}
+
+func newStocktakeView(stocktake models.Stocktake) stocktakeView {
+	view := stocktakeView{
+		ID:            stocktake.ID,
+		PickupPointID: stocktake.PickupPointID,
+		CreatedAt:     stocktake.CreatedAt,
+		Scanned:       stocktake.Scanned,
+		Stored:        stocktake.Stored,
+		Missing:       stocktake.Count(models.Missing),
+		Unexpected:    stocktake.Count(models.Unexpected),
+		WronglyIssued: stocktake.Count(models.WronglyIssued),
+		Discrepancies: make([]discrepancyView, len(stocktake.Discrepancies)),
+	}
+	for i, d := range stocktake.Discrepancies {
+		view.Discrepancies[i] = discrepancyView{OrderID: d.OrderID, Kind: string(d.Kind)}
+	}
+	return view
+}
+
+// returnManifestView is a manifest of orders handed over to the courier, File is the exported manifest
+type returnManifestView struct {
+	ID        int64       `json:"id" yaml:"id"`
+	CourierID int         `json:"courier_id" yaml:"courier_id"`
+	CreatedAt time.Time   `json:"created_at" yaml:"created_at"`
+	File      string      `json:"file,omitempty" yaml:"file,omitempty"`
+	Orders    []orderView `json:"orders" yaml:"orders"`
+}
+
+type webhookView struct {
+	ID         int64     `json:"id" yaml:"id"`
+	URL        string    `json:"url" yaml:"url"`
+	EventTypes []string  `json:"event_types" yaml:"event_types"`
+	Secret     string    `json:"secret,omitempty" yaml:"secret,omitempty"`
+	CreatedAt  time.Time `json:"created_at" yaml:"created_at"`
+}
+
+func newWebhookView(sub models.WebhookSubscription) webhookView {
+	view := webhookView{ID: sub.ID, URL: sub.URL, Secret: sub.Secret, CreatedAt: sub.CreatedAt,
+		EventTypes: make([]string, len(sub.EventTypes))}
+	for i, eventType := range sub.EventTypes {
+		view.EventTypes[i] = string(eventType)
+	}
+	return view
+}
+
+type deliveryLogView struct {
+	DeliveryID int64     `json:"delivery_id" yaml:"delivery_id"`
+	EventID    string    `json:"event_id" yaml:"event_id"`
+	EventType  string    `json:"event_type" yaml:"event_type"`
+	Attempt    int       `json:"attempt" yaml:"attempt"`
+	StatusCode int       `json:"status_code" yaml:"status_code"`
+	Duration   string    `json:"duration" yaml:"duration"`
+	Error      string    `json:"error,omitempty" yaml:"error,omitempty"`
+	CreatedAt  time.Time `json:"created_at" yaml:"created_at"`
+}
+
+type deadLetterView struct {
+	Partition     int32     `json:"partition" yaml:"partition"`
+	Offset        int64     `json:"offset" yaml:"offset"`
+	Time          time.Time `json:"time" yaml:"time"`
+	Key           string    `json:"key" yaml:"key"`
+	EventType     string    `json:"event_type" yaml:"event_type"`
+	Attempts      int       `json:"attempts" yaml:"attempts"`
+	Error         string    `json:"error" yaml:"error"`
+	OriginalTopic string    `json:"original_topic" yaml:"original_topic"`
+}
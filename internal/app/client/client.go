@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// managerTokenKey and pointTokenKey are the metadata keys of tokens checked by the order service
+	managerTokenKey = "x-manager-token"
+	pointTokenKey   = "x-point-token"
+)
+
+// DefaultTimeout limits calls returning a single response
+const DefaultTimeout = 30 * time.Second
+
+// Config is the address of the order service and the credentials of the client
+type Config struct {
+	Addr string
+	// TLS enables TLS, the server certificate is verified with CAFile or the system roots if it is empty
+	TLS        bool
+	CAFile     string
+	ServerName string
+	// PointToken identifies the pickup point of the client, ManagerToken allows manager actions
+	PointToken   string
+	ManagerToken string
+	// Insecure allows sending tokens without TLS, e.g. to a server on the same host
+	Insecure bool
+}
+
+// Dial connects to the order service, tokens are sent with every call.
+// Tokens are sent over TLS only, unless Insecure allows plaintext
+func Dial(cfg Config) (*grpc.ClientConn, error) {
+	if cfg.Addr == "" {
+		return nil, errors.New("не указан адрес сервера")
+	}
+	if !cfg.TLS && !cfg.Insecure && (cfg.PointToken != "" || cfg.ManagerToken != "") {
+		return nil, errors.New("токены передаются только по TLS: укажите --tls или --insecure для сервера без TLS")
+	}
+
+	creds := insecure.NewCredentials()
+	if cfg.TLS {
+		tlsConfig := &tls.Config{ServerName: cfg.ServerName, MinVersion: tls.VersionTLS12}
+		if cfg.CAFile != "" {
+			pem, err := os.ReadFile(cfg.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("не удалось прочитать сертификат CA: %w", err)
+			}
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("в файле %s нет сертификатов", cfg.CAFile)
+			}
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	var pairs []string
+	if cfg.PointToken != "" {
+		pairs = append(pairs, pointTokenKey, cfg.PointToken)
+	}
+	if cfg.ManagerToken != "" {
+		pairs = append(pairs, managerTokenKey, cfg.ManagerToken)
+	}
+
+	return grpc.NewClient(cfg.Addr,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{},
+			cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(withTokens(ctx, pairs), method, req, reply, cc, opts...)
+		}),
+		grpc.WithStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+			method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(withTokens(ctx, pairs), desc, cc, method, opts...)
+		}),
+	)
+}
+
+func withTokens(ctx context.Context, pairs []string) context.Context {
+	if len(pairs) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// CallError is an error returned by the service. Its message is written for the user,
+// its code tells e.g. a missing order from an unavailable service
+type CallError struct {
+	Code    codes.Code
+	Message string
+}
+
+func (e *CallError) Error() string {
+	return e.Message
+}
+
+// GRPCStatus makes status.Code return the code of the error
+func (e *CallError) GRPCStatus() *status.Status {
+	return status.New(e.Code, e.Message)
+}
+
+// callError converts the status error to CallError
+func callError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if st, ok := status.FromError(err); ok {
+		return &CallError{Code: st.Code(), Message: st.Message()}
+	}
+	return err
+}
+
+// parseTime parses RFC3339 times sent by the service, empty time is zero
+func parseTime(value string) time.Time {
+	t, _ := time.Parse(time.RFC3339, value)
+	return t
+}
+
+// formatTime formats the time as RFC3339, zero time is empty
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"route/internal/app/models"
+	order "route/pkg/api/proto/order/v1/order/v1"
+)
+
+func acceptRequest(or *models.Order, packagingType models.PackageType) *order.OrderRequest {
+	return &order.OrderRequest{
+		OrderId:       int32(or.OrderID),
+		UserId:        int32(or.UserID),
+		Weight:        or.Weight,
+		PackagingType: string(packagingType),
+		SessionId:     or.SessionID,
+		Deadline:      formatTime(or.Deadline),
+		Cost:          or.Cost,
+	}
+}
+
+func orderFromProto(info *order.OrderInfo) models.Order {
+	return models.Order{
+		OrderID:             int(info.GetOrderId()),
+		UserID:              int(info.GetUserId()),
+		PickupPointID:       int(info.GetPickupPointId()),
+		Deadline:            parseTime(info.GetDeadline()),
+		Weight:              info.GetWeight(),
+		Cost:                info.GetCost(),
+		Cell:                info.GetCell(),
+		IsAtPickupPoint:     info.GetIsAtPickupPoint(),
+		ReceivedFromCourier: info.GetReceivedFromCourier(),
+		IssuedToUser:        info.GetIssuedToUser(),
+		IsReturned:          info.GetIsReturned(),
+		SessionID:           info.GetSessionId(),
+		TransferID:          info.GetTransferId(),
+		IssuedAt:            parseTime(info.GetIssuedAt()),
+		ReceivedBy:          info.GetReceivedBy(),
+		AuthorizationID:     info.GetAuthorizationId(),
+		IssueOverrideBy:     info.GetIssueOverrideBy(),
+		ExpiredAt:           parseTime(info.GetExpiredAt()),
+		ReturnedToCourierAt: parseTime(info.GetReturnedToCourierAt()),
+		RefusedAt:           parseTime(info.GetRefusedAt()),
+		RefusalReason:       info.GetRefusalReason(),
+		PickupFailures:      int(info.GetPickupFailures()),
+		PickupLastFailureAt: parseTime(info.GetPickupLastFailureAt()),
+		PickupLockedAt:      parseTime(info.GetPickupLockedAt()),
+	}
+}
+
+func ordersFromProto(infos []*order.OrderInfo) []models.Order {
+	orders := make([]models.Order, len(infos))
+	for i, info := range infos {
+		orders[i] = orderFromProto(info)
+	}
+	return orders
+}
+
+func sessionFromProto(info *order.SessionInfo) models.AcceptanceSession {
+	return models.AcceptanceSession{
+		ID:            info.GetId(),
+		CourierID:     int(info.GetCourierId()),
+		ExpectedCount: int(info.GetExpectedCount()),
+		OpenedAt:      parseTime(info.GetOpenedAt()),
+		ClosedAt:      parseTime(info.GetClosedAt()),
+	}
+}
+
+func authorizationFromProto(info *order.AuthorizationInfo) models.Authorization {
+	return models.Authorization{
+		ID:         info.GetId(),
+		UserID:     int(info.GetUserId()),
+		Recipient:  info.GetRecipient(),
+		OrderID:    int(info.GetOrderId()),
+		ValidUntil: parseTime(info.GetValidUntil()),
+		CreatedAt:  parseTime(info.GetCreatedAt()),
+	}
+}
+
+func pickupPointFromProto(info *order.PickupPointInfo) models.PickupPoint {
+	return models.PickupPoint{ID: int(info.GetId()), Name: info.GetName(), Address: info.GetAddress()}
+}
+
+// transferFromProto converts the transfer, its orders have only IDs
+func transferFromProto(info *order.TransferInfo) models.Transfer {
+	transfer := models.Transfer{
+		ID:          info.GetId(),
+		FromPointID: int(info.GetFromPointId()),
+		ToPointID:   int(info.GetToPointId()),
+		CreatedAt:   parseTime(info.GetCreatedAt()),
+		ReceivedAt:  parseTime(info.GetReceivedAt()),
+		Orders:      make([]models.Order, len(info.GetOrderIds())),
+	}
+	for i, orderID := range info.GetOrderIds() {
+		transfer.Orders[i] = models.Order{OrderID: int(orderID)}
+	}
+	return transfer
+}
+
+func cellsFromProto(infos []*order.CellInfo) []models.StorageCell {
+	cells := make([]models.StorageCell, len(infos))
+	for i, info := range infos {
+		cells[i] = models.StorageCell{
+			ID:        int(info.GetId()),
+			Code:      info.GetCode(),
+			Size:      models.ToCellSize(info.GetSize()),
+			MaxWeight: info.GetMaxWeight(),
+			OrderID:   int(info.GetOrderId()),
+		}
+	}
+	return cells
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"route/internal/app/exporter"
+	"route/internal/app/models"
+	order "route/pkg/api/proto/order/v1/order/v1"
+)
+
+// Exporter receives export files written by the order service
+type Exporter struct {
+	client order.OrderServiceClient
+}
+
+// NewExporter is a constructor for Exporter
+func NewExporter(client order.OrderServiceClient) Exporter {
+	return Exporter{client: client}
+}
+
+// Export writes the file received from the service to w and returns the number of exported orders
+func (e Exporter) Export(w io.Writer, format exporter.Format, filter models.ExportFilter) (int, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := e.client.ExportOrders(ctx, &order.ExportOrdersRequest{
+		Kind:     string(filter.Kind),
+		Format:   string(format),
+		UserId:   int32(filter.UserID),
+		LastN:    int32(filter.LastN),
+		Page:     int32(filter.Page),
+		PageSize: int32(filter.PageSize),
+	})
+	if err != nil {
+		return 0, callError(err)
+	}
+
+	exported := 0
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return exported, nil
+		}
+		if err != nil {
+			return exported, callError(err)
+		}
+		if _, err = w.Write(chunk.GetData()); err != nil {
+			return exported, err
+		}
+		exported += int(chunk.GetExported())
+	}
+}
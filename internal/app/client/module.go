@@ -0,0 +1,405 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"route/internal/app/models"
+	"route/internal/app/module"
+	order "route/pkg/api/proto/order/v1/order/v1"
+)
+
+// stocktakeBatchSize is the number of scanned order IDs sent in one stocktake message
+const stocktakeBatchSize = 1000
+
+// reportDateLayout is the layout of report period dates
+const reportDateLayout = "2006-01-02"
+
+var _ module.Module = Module{}
+
+// Module calls the order service for every action of module.Module, so CLI commands
+// run against the pickup point of the client token
+type Module struct {
+	client  order.OrderServiceClient
+	timeout time.Duration
+}
+
+// NewModule is a constructor for Module, calls returning a single response are limited by the timeout
+func NewModule(client order.OrderServiceClient, timeout time.Duration) Module {
+	return Module{client: client, timeout: timeout}
+}
+
+func (m Module) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), m.timeout)
+}
+
+func (m Module) AcceptOrder(or *models.Order, packagingType models.PackageType) error {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	resp, err := m.client.AcceptOrder(ctx, acceptRequest(or, packagingType))
+	if err != nil {
+		return callError(err)
+	}
+	or.PickupCode, or.Cell = resp.GetPickupCode(), resp.GetCell()
+	return nil
+}
+
+// AcceptOrders fails every order of the batch if the service can't be called
+func (m Module) AcceptOrders(reqs []models.AcceptRequest, dryRun bool) []error {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	batch := &order.AcceptOrdersRequest{Orders: make([]*order.OrderRequest, len(reqs)), DryRun: dryRun}
+	for i, req := range reqs {
+		batch.Orders[i] = acceptRequest(req.Order, req.PackagingType)
+	}
+
+	errs := make([]error, len(reqs))
+	resp, err := m.client.AcceptOrders(ctx, batch)
+	if err == nil && len(resp.GetResults()) != len(reqs) {
+		err = errors.New("сервер вернул результаты не для всех заказов")
+	}
+	if err != nil {
+		for i := range errs {
+			errs[i] = callError(err)
+		}
+		return errs
+	}
+
+	for i, result := range resp.GetResults() {
+		if result.GetError() != "" {
+			errs[i] = errors.New(result.GetError())
+			continue
+		}
+		reqs[i].Order.PickupCode, reqs[i].Order.Cell = result.GetPickupCode(), result.GetCell()
+	}
+	return errs
+}
+
+func (m Module) ReturnOrder(orderID int) error {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	_, err := m.client.ReturnOrder(ctx, &order.OrderRequest{OrderId: int32(orderID)})
+	return callError(err)
+}
+
+func (m Module) IssueOrder(req models.IssueRequest) error {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	_, err := m.client.IssueOrder(ctx, &order.OrderRequest{
+		OrderId:    int32(req.OrderID),
+		PickupCode: req.PickupCode,
+		OverrideBy: req.OverrideBy,
+		Recipient:  req.Recipient,
+	})
+	return callError(err)
+}
+
+func (m Module) UnlockOrder(orderID int) error {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	_, err := m.client.UnlockOrder(ctx, &order.OrderRequest{OrderId: int32(orderID)})
+	return callError(err)
+}
+
+func (m Module) RefuseOrder(orderID int, reason string) error {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	_, err := m.client.RefuseOrder(ctx, &order.RefuseOrderRequest{OrderId: int32(orderID), Reason: reason})
+	return callError(err)
+}
+
+func (m Module) ListOrders(userID, lastN int) ([]models.Order, error) {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	resp, err := m.client.ListOrders(ctx, &order.ListOrdersRequest{UserId: int32(userID), LastN: int32(lastN)})
+	if err != nil {
+		return nil, callError(err)
+	}
+	return ordersFromProto(resp.GetOrders()), nil
+}
+
+func (m Module) AcceptReturn(orderID, userID int) error {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	_, err := m.client.AcceptReturn(ctx, &order.OrderRequest{OrderId: int32(orderID), UserId: int32(userID)})
+	return callError(err)
+}
+
+func (m Module) ListReturns(page, pageSize int) ([]models.Order, error) {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	resp, err := m.client.ListReturns(ctx, &order.ListReturnsRequest{Page: int32(page), PageSize: int32(pageSize)})
+	if err != nil {
+		return nil, callError(err)
+	}
+	return ordersFromProto(resp.GetOrders()), nil
+}
+
+// ExportOrders is not supported: the service sends export files instead of orders, they are received by Exporter
+func (m Module) ExportOrders(_ models.ExportFilter, _ func(order models.Order) error) error {
+	return errors.New("выгрузка заказов с сервера выполняется только в файл")
+}
+
+func (m Module) CreateReturnManifest(courierID int) (*models.ReturnManifest, error) {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	resp, err := m.client.CreateReturnManifest(ctx, &order.CreateReturnManifestRequest{CourierId: int32(courierID)})
+	if err != nil {
+		return nil, callError(err)
+	}
+	return &models.ReturnManifest{
+		ID:        resp.GetId(),
+		CourierID: int(resp.GetCourierId()),
+		CreatedAt: parseTime(resp.GetCreatedAt()),
+		Orders:    ordersFromProto(resp.GetOrders()),
+	}, nil
+}
+
+func (m Module) CreateCourier(name, company string) (*models.Courier, error) {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	resp, err := m.client.CreateCourier(ctx, &order.CreateCourierRequest{Name: name, Company: company})
+	if err != nil {
+		return nil, callError(err)
+	}
+	return &models.Courier{ID: int(resp.GetId()), Name: resp.GetName(), Company: resp.GetCompany()}, nil
+}
+
+func (m Module) OpenSession(courierID, expectedCount int) (*models.AcceptanceSession, error) {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	resp, err := m.client.OpenSession(ctx, &order.OpenSessionRequest{CourierId: int32(courierID), ExpectedCount: int32(expectedCount)})
+	if err != nil {
+		return nil, callError(err)
+	}
+	session := sessionFromProto(resp)
+	return &session, nil
+}
+
+func (m Module) CloseSession(sessionID int64) (*models.SessionSummary, error) {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	resp, err := m.client.CloseSession(ctx, &order.CloseSessionRequest{SessionId: sessionID})
+	if err != nil {
+		return nil, callError(err)
+	}
+
+	summary := &models.SessionSummary{
+		Session:     sessionFromProto(resp.GetSession()),
+		Accepted:    int(resp.GetAccepted()),
+		TotalWeight: resp.GetTotalWeight(),
+		Rejections:  make([]models.SessionRejection, len(resp.GetRejections())),
+	}
+	for i, rejection := range resp.GetRejections() {
+		summary.Rejections[i] = models.SessionRejection{OrderID: int(rejection.GetOrderId()), Reason: rejection.GetReason()}
+	}
+	return summary, nil
+}
+
+func (m Module) CreateAuthorization(auth models.Authorization) (*models.Authorization, error) {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	resp, err := m.client.CreateAuthorization(ctx, &order.CreateAuthorizationRequest{
+		UserId:     int32(auth.UserID),
+		Recipient:  auth.Recipient,
+		OrderId:    int32(auth.OrderID),
+		ValidUntil: formatTime(auth.ValidUntil),
+	})
+	if err != nil {
+		return nil, callError(err)
+	}
+	created := authorizationFromProto(resp)
+	return &created, nil
+}
+
+func (m Module) ListAuthorizations(userID int) ([]models.Authorization, error) {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	resp, err := m.client.ListAuthorizations(ctx, &order.ListAuthorizationsRequest{UserId: int32(userID)})
+	if err != nil {
+		return nil, callError(err)
+	}
+
+	auths := make([]models.Authorization, len(resp.GetAuthorizations()))
+	for i, auth := range resp.GetAuthorizations() {
+		auths[i] = authorizationFromProto(auth)
+	}
+	return auths, nil
+}
+
+func (m Module) CreatePickupPoint(name, address string) (*models.PickupPoint, error) {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	resp, err := m.client.CreatePickupPoint(ctx, &order.CreatePickupPointRequest{Name: name, Address: address})
+	if err != nil {
+		return nil, callError(err)
+	}
+	point := pickupPointFromProto(resp)
+	return &point, nil
+}
+
+func (m Module) ListPickupPoints() ([]models.PickupPoint, error) {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	resp, err := m.client.ListPickupPoints(ctx, &order.ListPickupPointsRequest{})
+	if err != nil {
+		return nil, callError(err)
+	}
+
+	points := make([]models.PickupPoint, len(resp.GetPickupPoints()))
+	for i, point := range resp.GetPickupPoints() {
+		points[i] = pickupPointFromProto(point)
+	}
+	return points, nil
+}
+
+func (m Module) CreateTransfer(toPointID int, orderIDs []int) (*models.Transfer, error) {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	req := &order.CreateTransferRequest{ToPointId: int32(toPointID), OrderIds: make([]int32, len(orderIDs))}
+	for i, orderID := range orderIDs {
+		req.OrderIds[i] = int32(orderID)
+	}
+	resp, err := m.client.CreateTransfer(ctx, req)
+	if err != nil {
+		return nil, callError(err)
+	}
+	transfer := transferFromProto(resp)
+	return &transfer, nil
+}
+
+func (m Module) ReceiveTransfer(transferID int64) (*models.Transfer, error) {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	resp, err := m.client.ReceiveTransfer(ctx, &order.ReceiveTransferRequest{TransferId: transferID})
+	if err != nil {
+		return nil, callError(err)
+	}
+	transfer := transferFromProto(resp)
+	return &transfer, nil
+}
+
+func (m Module) CreateRack(name string, cellCount int, size models.CellSize, maxWeight float64) (*models.Rack, error) {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	resp, err := m.client.CreateRack(ctx, &order.CreateRackRequest{
+		Name:      name,
+		Cells:     int32(cellCount),
+		Size:      size.String(),
+		MaxWeight: maxWeight,
+	})
+	if err != nil {
+		return nil, callError(err)
+	}
+	return &models.Rack{ID: int(resp.GetId()), Name: resp.GetName(), Cells: cellsFromProto(resp.GetCells())}, nil
+}
+
+func (m Module) ListCells() ([]models.StorageCell, error) {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	resp, err := m.client.ListCells(ctx, &order.ListCellsRequest{})
+	if err != nil {
+		return nil, callError(err)
+	}
+	return cellsFromProto(resp.GetCells()), nil
+}
+
+// Stocktake sends the scanned order IDs in batches of stocktakeBatchSize
+func (m Module) Stocktake(scanned []int) (*models.Stocktake, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := m.client.Stocktake(ctx)
+	if err != nil {
+		return nil, callError(err)
+	}
+	for start := 0; start < len(scanned); start += stocktakeBatchSize {
+		end := min(start+stocktakeBatchSize, len(scanned))
+		scan := &order.StocktakeScan{OrderIds: make([]int32, 0, end-start)}
+		for _, orderID := range scanned[start:end] {
+			scan.OrderIds = append(scan.OrderIds, int32(orderID))
+		}
+		if err = stream.Send(scan); err != nil {
+			break
+		}
+	}
+
+	// A failed send is reported by CloseAndRecv with the status of the call
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, callError(err)
+	}
+
+	stocktake := &models.Stocktake{
+		ID:            resp.GetId(),
+		PickupPointID: int(resp.GetPickupPointId()),
+		Scanned:       int(resp.GetScanned()),
+		Stored:        int(resp.GetStored()),
+		CreatedAt:     parseTime(resp.GetCreatedAt()),
+		Discrepancies: make([]models.Discrepancy, len(resp.GetDiscrepancies())),
+	}
+	for i, d := range resp.GetDiscrepancies() {
+		stocktake.Discrepancies[i] = models.Discrepancy{OrderID: int(d.GetOrderId()), Kind: models.DiscrepancyKind(d.GetKind())}
+	}
+	return stocktake, nil
+}
+
+func (m Module) Report(from, to time.Time) (*models.Report, error) {
+	ctx, cancel := m.context()
+	defer cancel()
+
+	resp, err := m.client.GetReport(ctx, &order.GetReportRequest{
+		From: from.Format(reportDateLayout),
+		To:   to.Format(reportDateLayout),
+	})
+	if err != nil {
+		return nil, callError(err)
+	}
+
+	report := &models.Report{
+		PickupPointID: int(resp.GetPickupPointId()),
+		From:          parseDay(resp.GetFrom()),
+		To:            parseDay(resp.GetTo()),
+		Rows:          make([]models.ReportRow, len(resp.GetRows())),
+	}
+	for i, row := range resp.GetRows() {
+		report.Rows[i] = models.ReportRow{
+			Day:              parseDay(row.GetDay()),
+			PackagingType:    models.PackageType(row.GetPackagingType()),
+			Accepted:         int(row.GetAccepted()),
+			Issued:           int(row.GetIssued()),
+			Returned:         int(row.GetReturned()),
+			SurchargeRevenue: row.GetSurchargeRevenue(),
+			Refunds:          row.GetRefunds(),
+		}
+	}
+	return report, nil
+}
+
+// parseDay parses report dates in the local time zone like the service does
+func parseDay(value string) time.Time {
+	t, _ := time.ParseInLocation(reportDateLayout, value, time.Local)
+	return t
+}
@@ -0,0 +1,243 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	service "route/internal/app/api"
+	"route/internal/app/exporter"
+	"route/internal/app/models"
+	mockmodule "route/internal/app/module/mocks"
+	order "route/pkg/api/proto/order/v1/order/v1"
+)
+
+// newTestClient serves the module by the order service in memory and returns the client of the service
+func newTestClient(t *testing.T, mod *mockmodule.MockModule) order.OrderServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	order.RegisterOrderServiceServer(server, service.New(mod, nil))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return order.NewOrderServiceClient(conn)
+}
+
+func TestModule_AcceptOrder(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctrl := gomock.NewController(t)
+	mockModule := mockmodule.NewMockModule(ctrl)
+	deadline := time.Date(2030, 1, 2, 15, 0, 0, 0, time.UTC)
+	accepted := gomock.Cond(func(x any) bool {
+		return *x.(*models.Order) == models.Order{OrderID: 1, UserID: 2, Deadline: deadline, Cost: 100, Weight: 3, SessionID: 4}
+	})
+	mockModule.EXPECT().AcceptOrder(accepted, models.Box).DoAndReturn(func(or *models.Order, _ models.PackageType) error {
+		or.PickupCode, or.Cell = "123456", "A-01"
+		return nil
+	})
+	or := &models.Order{OrderID: 1, UserID: 2, Deadline: deadline, Cost: 100, Weight: 3, SessionID: 4}
+
+	// act
+	err := NewModule(newTestClient(t, mockModule), DefaultTimeout).AcceptOrder(or, models.Box)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "123456", or.PickupCode)
+	assert.Equal(t, "A-01", or.Cell)
+}
+
+func TestModule_AcceptOrders(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctrl := gomock.NewController(t)
+	mockModule := mockmodule.NewMockModule(ctrl)
+	mockModule.EXPECT().AcceptOrders(gomock.Len(2), true).Return([]error{nil, errors.New("заказ с ID 2 уже принят")})
+	reqs := []models.AcceptRequest{
+		{Order: &models.Order{OrderID: 1, UserID: 2}, PackagingType: models.Package},
+		{Order: &models.Order{OrderID: 2, UserID: 2}, PackagingType: models.Package},
+	}
+
+	// act
+	errs := NewModule(newTestClient(t, mockModule), DefaultTimeout).AcceptOrders(reqs, true)
+
+	// assert
+	require.Len(t, errs, 2)
+	assert.NoError(t, errs[0])
+	assert.EqualError(t, errs[1], "заказ с ID 2 уже принят")
+}
+
+func TestModule_ListOrders(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctrl := gomock.NewController(t)
+	mockModule := mockmodule.NewMockModule(ctrl)
+	at := time.Date(2030, 1, 2, 15, 0, 0, 0, time.UTC)
+	stored := models.Order{OrderID: 1, UserID: 2, PickupPointID: 3, Deadline: at, Weight: 3, Cost: 100, Cell: "A-01",
+		IsAtPickupPoint: true, ReceivedFromCourier: true, SessionID: 4, PickupFailures: 1, PickupLastFailureAt: at}
+	issued := models.Order{OrderID: 5, UserID: 2, PickupPointID: 3, Deadline: at, IssuedToUser: true, IssuedAt: at,
+		ReceivedBy: "Иванов", AuthorizationID: 6, IssueOverrideBy: "manager"}
+	mockModule.EXPECT().ListOrders(2, 10).Return([]models.Order{stored, issued}, nil)
+
+	// act
+	orders, err := NewModule(newTestClient(t, mockModule), DefaultTimeout).ListOrders(2, 10)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []models.Order{stored, issued}, orders)
+}
+
+func TestModule_Errors(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctrl := gomock.NewController(t)
+	mockModule := mockmodule.NewMockModule(ctrl)
+	mockModule.EXPECT().RefuseOrder(5, "брак").Return(errors.New("заказ с ID 5 уже выдан"))
+
+	// act
+	err := NewModule(newTestClient(t, mockModule), DefaultTimeout).RefuseOrder(5, "брак")
+
+	// assert
+	assert.EqualError(t, err, "заказ с ID 5 уже выдан", "Message of the service is shown without the status code")
+	var callErr *CallError
+	require.ErrorAs(t, err, &callErr)
+	assert.Equal(t, codes.Internal, callErr.Code)
+	assert.Equal(t, codes.Internal, status.Code(err), "Code of the service is kept for callers")
+}
+
+func TestDial(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		cfg           Config
+		expectedError string
+	}{
+		{
+			name: "no tokens without TLS",
+			cfg:  Config{Addr: "localhost:50051"},
+		},
+		{
+			name:          "tokens without TLS",
+			cfg:           Config{Addr: "localhost:50051", PointToken: "north"},
+			expectedError: "токены передаются только по TLS: укажите --tls или --insecure для сервера без TLS",
+		},
+		{
+			name: "tokens with TLS",
+			cfg:  Config{Addr: "localhost:50051", TLS: true, ManagerToken: "secret"},
+		},
+		{
+			name: "tokens allowed without TLS",
+			cfg:  Config{Addr: "localhost:50051", PointToken: "north", Insecure: true},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			conn, err := Dial(tt.cfg)
+
+			// assert
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			conn.Close()
+		})
+	}
+}
+
+func TestModule_Stocktake(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctrl := gomock.NewController(t)
+	mockModule := mockmodule.NewMockModule(ctrl)
+	scanned := make([]int, stocktakeBatchSize+1)
+	for i := range scanned {
+		scanned[i] = i + 1
+	}
+	mockModule.EXPECT().Stocktake(scanned).Return(&models.Stocktake{ID: 7, PickupPointID: 1, Scanned: len(scanned), Stored: 2,
+		Discrepancies: []models.Discrepancy{{OrderID: 9999, Kind: models.Missing}}}, nil)
+
+	// act
+	stocktake, err := NewModule(newTestClient(t, mockModule), DefaultTimeout).Stocktake(scanned)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, &models.Stocktake{ID: 7, PickupPointID: 1, Scanned: len(scanned), Stored: 2,
+		Discrepancies: []models.Discrepancy{{OrderID: 9999, Kind: models.Missing}}}, stocktake)
+}
+
+func TestModule_Report(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctrl := gomock.NewController(t)
+	mockModule := mockmodule.NewMockModule(ctrl)
+	day := time.Date(2030, 1, 2, 0, 0, 0, 0, time.Local)
+	report := &models.Report{PickupPointID: 1, From: day, To: day,
+		Rows: []models.ReportRow{{Day: day, PackagingType: models.Box, Accepted: 2, Issued: 1, SurchargeRevenue: 20}}}
+	mockModule.EXPECT().Report(day, day).Return(report, nil)
+
+	// act
+	received, err := NewModule(newTestClient(t, mockModule), DefaultTimeout).Report(day, day)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, report, received)
+}
+
+func TestExporter_Export(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctrl := gomock.NewController(t)
+	mockModule := mockmodule.NewMockModule(ctrl)
+	filter := models.ExportFilter{Kind: models.ReturnsExport, Page: 1, PageSize: 2}
+	mockModule.EXPECT().ExportOrders(filter, gomock.Any()).DoAndReturn(func(_ models.ExportFilter, fx func(order models.Order) error) error {
+		if err := fx(models.Order{OrderID: 1, UserID: 2, IsReturned: true}); err != nil {
+			return err
+		}
+		return fx(models.Order{OrderID: 3, UserID: 2, IsReturned: true})
+	})
+	var out bytes.Buffer
+
+	// act
+	exported, err := NewExporter(newTestClient(t, mockModule)).Export(&out, exporter.JSONL, filter)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, exported)
+	assert.Equal(t, `{"order_id":1,"user_id":2,"pickup_point_id":0,"status":"returned","deadline":"","weight":0,"cost":0,"cell":"","issued_at":"","received_by":"","refused_at":"","refusal_reason":""}
+{"order_id":3,"user_id":2,"pickup_point_id":0,"status":"returned","deadline":"","weight":0,"cost":0,"cell":"","issued_at":"","received_by":"","refused_at":"","refusal_reason":""}
+`, out.String())
+}
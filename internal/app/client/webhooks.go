@@ -0,0 +1,168 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"route/internal/app/models"
+	order "route/pkg/api/proto/order/v1/order/v1"
+)
+
+// Webhooks manages webhook subscriptions of the order service
+type Webhooks struct {
+	client  order.OrderServiceClient
+	timeout time.Duration
+}
+
+// NewWebhooks is a constructor for Webhooks
+func NewWebhooks(client order.OrderServiceClient, timeout time.Duration) Webhooks {
+	return Webhooks{client: client, timeout: timeout}
+}
+
+func (w Webhooks) Subscribe(url string, eventTypes []models.EventType, secret string) (*models.WebhookSubscription, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	types := make([]string, len(eventTypes))
+	for i, eventType := range eventTypes {
+		types[i] = string(eventType)
+	}
+	resp, err := w.client.CreateWebhook(ctx, &order.CreateWebhookRequest{Url: url, EventTypes: types, Secret: secret})
+	if err != nil {
+		return nil, callError(err)
+	}
+	sub := webhookFromProto(resp)
+	return &sub, nil
+}
+
+func (w Webhooks) List() ([]models.WebhookSubscription, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	resp, err := w.client.ListWebhooks(ctx, &order.ListWebhooksRequest{})
+	if err != nil {
+		return nil, callError(err)
+	}
+
+	subs := make([]models.WebhookSubscription, len(resp.GetWebhooks()))
+	for i, info := range resp.GetWebhooks() {
+		subs[i] = webhookFromProto(info)
+	}
+	return subs, nil
+}
+
+func (w Webhooks) Unsubscribe(id int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	_, err := w.client.DeleteWebhook(ctx, &order.DeleteWebhookRequest{Id: id})
+	return callError(err)
+}
+
+func (w Webhooks) ListDeliveries(subscriptionID int64, limit int) ([]models.WebhookDeliveryLog, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	resp, err := w.client.ListWebhookDeliveries(ctx, &order.ListWebhookDeliveriesRequest{WebhookId: subscriptionID, Limit: int32(limit)})
+	if err != nil {
+		return nil, callError(err)
+	}
+
+	logs := make([]models.WebhookDeliveryLog, len(resp.GetDeliveries()))
+	for i, info := range resp.GetDeliveries() {
+		logs[i] = models.WebhookDeliveryLog{
+			DeliveryID:     info.GetDeliveryId(),
+			SubscriptionID: subscriptionID,
+			EventID:        info.GetEventId(),
+			EventType:      models.EventType(info.GetEventType()),
+			Attempt:        int(info.GetAttempt()),
+			StatusCode:     int(info.GetStatusCode()),
+			Error:          info.GetError(),
+			Duration:       time.Duration(info.GetDurationMs()) * time.Millisecond,
+			CreatedAt:      parseTime(info.GetCreatedAt()),
+		}
+	}
+	return logs, nil
+}
+
+func webhookFromProto(info *order.WebhookInfo) models.WebhookSubscription {
+	eventTypes := make([]models.EventType, len(info.GetEventTypes()))
+	for i, eventType := range info.GetEventTypes() {
+		eventTypes[i] = models.EventType(eventType)
+	}
+	return models.WebhookSubscription{
+		ID:         info.GetId(),
+		URL:        info.GetUrl(),
+		EventTypes: eventTypes,
+		Secret:     info.GetSecret(),
+		CreatedAt:  parseTime(info.GetCreatedAt()),
+	}
+}
+
+// DeadLetters lists and replays events of the dead-letter queue read by the order service
+type DeadLetters struct {
+	client  order.OrderServiceClient
+	timeout time.Duration
+}
+
+// NewDeadLetters is a constructor for DeadLetters
+func NewDeadLetters(client order.OrderServiceClient, timeout time.Duration) DeadLetters {
+	return DeadLetters{client: client, timeout: timeout}
+}
+
+func (d DeadLetters) List(topic string, limit int) ([]models.DeadLetter, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+
+	resp, err := d.client.ListDeadLetters(ctx, &order.ListDeadLettersRequest{Topic: topic, Limit: int32(limit)})
+	if err != nil {
+		return nil, callError(err)
+	}
+
+	letters := make([]models.DeadLetter, len(resp.GetDeadLetters()))
+	for i, info := range resp.GetDeadLetters() {
+		letters[i] = models.DeadLetter{
+			Partition:     info.GetPartition(),
+			Offset:        info.GetOffset(),
+			Time:          parseTime(info.GetTime()),
+			Key:           info.GetKey(),
+			EventType:     models.EventType(info.GetEventType()),
+			Error:         info.GetError(),
+			Attempts:      int(info.GetAttempts()),
+			OriginalTopic: info.GetOriginalTopic(),
+		}
+	}
+	return letters, nil
+}
+
+func (d DeadLetters) Replay(topic string, partition int32, offset int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+
+	_, err := d.client.ReplayDeadLetter(ctx, &order.ReplayDeadLetterRequest{Topic: topic, Partition: partition, Offset: offset})
+	return callError(err)
+}
+
+// Contacts sets contacts clients are notified by
+type Contacts struct {
+	client  order.OrderServiceClient
+	timeout time.Duration
+}
+
+// NewContacts is a constructor for Contacts
+func NewContacts(client order.OrderServiceClient, timeout time.Duration) Contacts {
+	return Contacts{client: client, timeout: timeout}
+}
+
+func (c Contacts) SetContact(contact models.Contact) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	_, err := c.client.SetContact(ctx, &order.SetContactRequest{
+		UserId: int32(contact.UserID),
+		Phone:  contact.Phone,
+		Email:  contact.Email,
+		Locale: contact.Locale,
+	})
+	return callError(err)
+}
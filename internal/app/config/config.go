@@ -3,20 +3,131 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
 
 var defaultGrpcPort = "50051"
 var defaultPrometheusPort = "9090"
+var defaultKafkaGroupID = "pick-up-point"
+var defaultRetryMaxAttempts = 5
+var defaultRetryBackoff = 100 * time.Millisecond
+var defaultRetryMaxBackoff = 10 * time.Second
+var defaultEventsFilePath = "events.jsonl"
+var defaultEventsFileMaxSize int64 = 10 << 20
+var defaultEventsFileMaxBackups = 5
+var defaultEventsHTTPTimeout = 5 * time.Second
+var defaultWebhookMaxAttempts = 10
+var defaultWebhookBackoff = 10 * time.Second
+var defaultWebhookMaxBackoff = time.Hour
+var defaultWebhookTimeout = 10 * time.Second
+var defaultNotifiers = "log"
+var defaultReminderBefore = 24 * time.Hour
+var defaultNotifyTimeout = 10 * time.Second
+var defaultSchedulerTick = 10 * time.Second
+var defaultExpireEvery = time.Minute
+var defaultRemindEvery = time.Hour
+var defaultCachePurgeEvery = time.Minute
+var defaultOutboxPurgeEvery = time.Hour
+var defaultOutboxRetention = 7 * 24 * time.Hour
+var defaultReturnListAt = "09:00"
+var defaultReturnListDir = "."
+var defaultPickupPointID = 1
+
+// Output modes of order events
+const (
+	OutputStdout = "stdout"
+	OutputKafka  = "kafka"
+	OutputFile   = "file"
+	OutputHTTP   = "http"
+)
 
 type KafkaConfig struct {
-	BrokerList []string
-	Topic      string
+	BrokerList    []string
+	Topic         string
+	ManifestTopic string
+	GroupID       string
+	Retry         RetryConfig
+}
+
+// RetryConfig is an exponential backoff policy for publishing and handling Kafka messages
+type RetryConfig struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// SinksConfig lists the sinks events are published to and their settings
+type SinksConfig struct {
+	Modes          []string
+	FilePath       string
+	FileMaxSize    int64
+	FileMaxBackups int
+	HTTPURL        string
+	HTTPTimeout    time.Duration
+}
+
+// Has reports whether events are published to the sink
+func (c SinksConfig) Has(mode string) bool {
+	for _, m := range c.Modes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookConfig is a delivery policy of webhooks, failed deliveries are retried with exponential backoff
+type WebhookConfig struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Timeout        time.Duration
+}
+
+// NotificationConfig lists the channels clients are notified through and their settings
+type NotificationConfig struct {
+	Notifiers      []string
+	LogPath        string
+	SMSURL         string
+	SMSToken       string
+	SMTPAddr       string
+	SMTPFrom       string
+	SMTPUser       string
+	SMTPPassword   string
+	Timeout        time.Duration
+	ReminderBefore time.Duration
+}
+
+// SchedulerConfig sets how often periodic jobs run, the return list is produced daily at ReturnListHour:ReturnListMinute
+type SchedulerConfig struct {
+	Tick             time.Duration
+	ExpireEvery      time.Duration
+	RemindEvery      time.Duration
+	CachePurgeEvery  time.Duration
+	OutboxPurgeEvery time.Duration
+	// OutboxRetention is how long messages delivered to every sink are kept in the outbox
+	OutboxRetention  time.Duration
+	ReturnListHour   int
+	ReturnListMinute int
+	ReturnListDir    string
 }
 
 type ServerConfig struct {
 	GrpcPort string
+	// TLSCertFile and TLSKeyFile enable TLS of the gRPC server if both are set
+	TLSCertFile string
+	TLSKeyFile  string
+	// ManagerToken allows issuing orders without pickup codes and unlocking them over gRPC, disabled if empty
+	ManagerToken string
+}
+
+// PickupPointConfig is the pickup point served by the process. Tokens identify pickup points of gRPC callers,
+// if they are set a caller is served by the point of its token
+type PickupPointConfig struct {
+	ID     int
+	Tokens map[string]int
 }
 
 type PrometheusConfig struct {
@@ -25,8 +136,14 @@ type PrometheusConfig struct {
 
 type Config struct {
 	DbUrl            string
-	OutputMode       string
+	Sinks            SinksConfig
+	WebhookConfig    WebhookConfig
+	Notifications    NotificationConfig
+	Scheduler        SchedulerConfig
+	PickupPoint      PickupPointConfig
 	CacheTTL         time.Duration
+	CacheWarmUp      bool
+	CacheSnapshot    string
 	KafkaConfig      KafkaConfig
 	ServerConfig     ServerConfig
 	PrometheusConfig PrometheusConfig
@@ -47,6 +164,18 @@ func New() (*Config, error) {
 		return nil, fmt.Errorf("ошибка при парсинге CACHE_TTL: %w", err)
 	}
 
+	// Warm-up is optional and disabled by default
+	cacheWarmUp := false
+	if strCacheWarmUp := os.Getenv("CACHE_WARMUP"); strCacheWarmUp != "" {
+		cacheWarmUp, err = strconv.ParseBool(strCacheWarmUp)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка при парсинге CACHE_WARMUP: %w", err)
+		}
+	}
+
+	// Snapshot is saved on shutdown and restored on boot only if the path is set
+	cacheSnapshot := os.Getenv("CACHE_SNAPSHOT_PATH")
+
 	brokers := os.Getenv("KAFKA_BROKERS")
 	if brokers == "" {
 		return nil, fmt.Errorf("KAFKA_BROKERS не задано")
@@ -55,14 +184,47 @@ func New() (*Config, error) {
 	brokerList := strings.Split(brokers, ",")
 	topic := os.Getenv("KAFKA_TOPIC")
 
-	outputMode := os.Getenv("OUTPUT_MODE")
-	if outputMode == "" {
-		outputMode = "stdout"
+	// Courier manifests are ingested only if the topic is set
+	manifestTopic := os.Getenv("KAFKA_MANIFEST_TOPIC")
+
+	groupID := os.Getenv("KAFKA_GROUP_ID")
+	if groupID == "" {
+		groupID = defaultKafkaGroupID
 	}
 
-	grpcPort := os.Getenv("GRPC_PORT")
-	if grpcPort == "" {
-		grpcPort = defaultGrpcPort
+	retryConfig, err := newRetryConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	sinksConfig, err := newSinksConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	webhookConfig, err := newWebhookConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	notificationConfig, err := newNotificationConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	schedulerConfig, err := newSchedulerConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	pickupPointConfig, err := newPickupPointConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	serverConfig, err := newServerConfig()
+	if err != nil {
+		return nil, err
 	}
 
 	promPort := os.Getenv("PROMETHEUS_PORT")
@@ -73,17 +235,303 @@ func New() (*Config, error) {
 	return &Config{
 		DbUrl: dbURL,
 		KafkaConfig: KafkaConfig{
-			BrokerList: brokerList,
-			Topic:      topic,
-		},
-		OutputMode: outputMode,
-		CacheTTL:   cacheTTL,
-		ServerConfig: ServerConfig{
-			GrpcPort: grpcPort,
+			BrokerList:    brokerList,
+			Topic:         topic,
+			ManifestTopic: manifestTopic,
+			GroupID:       groupID,
+			Retry:         *retryConfig,
 		},
+		Sinks:         *sinksConfig,
+		WebhookConfig: *webhookConfig,
+		Notifications: *notificationConfig,
+		Scheduler:     *schedulerConfig,
+		PickupPoint:   *pickupPointConfig,
+		CacheTTL:      cacheTTL,
+		CacheWarmUp:   cacheWarmUp,
+		CacheSnapshot: cacheSnapshot,
+		ServerConfig:  *serverConfig,
 		PrometheusConfig: PrometheusConfig{
 			PrometheusPort: promPort,
 		},
 	}, nil
 
 }
+
+// newServerConfig reads the gRPC server settings, TLS is enabled by setting both GRPC_TLS_CERT and GRPC_TLS_KEY
+func newServerConfig() (*ServerConfig, error) {
+	cfg := &ServerConfig{
+		GrpcPort:     os.Getenv("GRPC_PORT"),
+		TLSCertFile:  os.Getenv("GRPC_TLS_CERT"),
+		TLSKeyFile:   os.Getenv("GRPC_TLS_KEY"),
+		ManagerToken: os.Getenv("MANAGER_TOKEN"),
+	}
+	if cfg.GrpcPort == "" {
+		cfg.GrpcPort = defaultGrpcPort
+	}
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		return nil, fmt.Errorf("GRPC_TLS_CERT и GRPC_TLS_KEY должны быть заданы вместе")
+	}
+
+	return cfg, nil
+}
+
+func newRetryConfig() (*RetryConfig, error) {
+	cfg := &RetryConfig{
+		MaxAttempts:    defaultRetryMaxAttempts,
+		InitialBackoff: defaultRetryBackoff,
+		MaxBackoff:     defaultRetryMaxBackoff,
+	}
+
+	if strMaxAttempts := os.Getenv("KAFKA_RETRY_MAX_ATTEMPTS"); strMaxAttempts != "" {
+		maxAttempts, err := strconv.Atoi(strMaxAttempts)
+		if err != nil || maxAttempts <= 0 {
+			return nil, fmt.Errorf("KAFKA_RETRY_MAX_ATTEMPTS должно быть положительным числом")
+		}
+		cfg.MaxAttempts = maxAttempts
+	}
+
+	if strBackoff := os.Getenv("KAFKA_RETRY_BACKOFF"); strBackoff != "" {
+		backoff, err := time.ParseDuration(strBackoff)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка при парсинге KAFKA_RETRY_BACKOFF: %w", err)
+		}
+		cfg.InitialBackoff = backoff
+	}
+
+	if strMaxBackoff := os.Getenv("KAFKA_RETRY_MAX_BACKOFF"); strMaxBackoff != "" {
+		maxBackoff, err := time.ParseDuration(strMaxBackoff)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка при парсинге KAFKA_RETRY_MAX_BACKOFF: %w", err)
+		}
+		cfg.MaxBackoff = maxBackoff
+	}
+
+	return cfg, nil
+}
+
+// newSinksConfig parses OUTPUT_MODE as a comma-separated list of sinks, e.g. "kafka,file"
+func newSinksConfig() (*SinksConfig, error) {
+	cfg := &SinksConfig{
+		FilePath:       defaultEventsFilePath,
+		FileMaxSize:    defaultEventsFileMaxSize,
+		FileMaxBackups: defaultEventsFileMaxBackups,
+		HTTPTimeout:    defaultEventsHTTPTimeout,
+	}
+
+	outputMode := os.Getenv("OUTPUT_MODE")
+	if outputMode == "" {
+		outputMode = OutputStdout
+	}
+
+	for _, mode := range strings.Split(outputMode, ",") {
+		mode = strings.TrimSpace(mode)
+		switch mode {
+		case OutputStdout, OutputKafka, OutputFile, OutputHTTP:
+		default:
+			return nil, fmt.Errorf("неизвестный OUTPUT_MODE: %s", mode)
+		}
+		if !cfg.Has(mode) {
+			cfg.Modes = append(cfg.Modes, mode)
+		}
+	}
+
+	if filePath := os.Getenv("EVENTS_FILE_PATH"); filePath != "" {
+		cfg.FilePath = filePath
+	}
+
+	if strMaxSize := os.Getenv("EVENTS_FILE_MAX_SIZE"); strMaxSize != "" {
+		maxSize, err := strconv.ParseInt(strMaxSize, 10, 64)
+		if err != nil || maxSize <= 0 {
+			return nil, fmt.Errorf("EVENTS_FILE_MAX_SIZE должно быть положительным числом")
+		}
+		cfg.FileMaxSize = maxSize
+	}
+
+	if strMaxBackups := os.Getenv("EVENTS_FILE_MAX_BACKUPS"); strMaxBackups != "" {
+		maxBackups, err := strconv.Atoi(strMaxBackups)
+		if err != nil || maxBackups < 0 {
+			return nil, fmt.Errorf("EVENTS_FILE_MAX_BACKUPS должно быть неотрицательным числом")
+		}
+		cfg.FileMaxBackups = maxBackups
+	}
+
+	cfg.HTTPURL = os.Getenv("EVENTS_HTTP_URL")
+	if cfg.Has(OutputHTTP) && cfg.HTTPURL == "" {
+		return nil, fmt.Errorf("EVENTS_HTTP_URL не задан")
+	}
+
+	if strTimeout := os.Getenv("EVENTS_HTTP_TIMEOUT"); strTimeout != "" {
+		timeout, err := time.ParseDuration(strTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка при парсинге EVENTS_HTTP_TIMEOUT: %w", err)
+		}
+		cfg.HTTPTimeout = timeout
+	}
+
+	return cfg, nil
+}
+
+func newWebhookConfig() (*WebhookConfig, error) {
+	cfg := &WebhookConfig{
+		MaxAttempts:    defaultWebhookMaxAttempts,
+		InitialBackoff: defaultWebhookBackoff,
+		MaxBackoff:     defaultWebhookMaxBackoff,
+		Timeout:        defaultWebhookTimeout,
+	}
+
+	if strMaxAttempts := os.Getenv("WEBHOOK_MAX_ATTEMPTS"); strMaxAttempts != "" {
+		maxAttempts, err := strconv.Atoi(strMaxAttempts)
+		if err != nil || maxAttempts <= 0 {
+			return nil, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS должно быть положительным числом")
+		}
+		cfg.MaxAttempts = maxAttempts
+	}
+
+	durations := []struct {
+		env   string
+		value *time.Duration
+	}{
+		{"WEBHOOK_BACKOFF", &cfg.InitialBackoff},
+		{"WEBHOOK_MAX_BACKOFF", &cfg.MaxBackoff},
+		{"WEBHOOK_TIMEOUT", &cfg.Timeout},
+	}
+	for _, d := range durations {
+		str := os.Getenv(d.env)
+		if str == "" {
+			continue
+		}
+		value, err := time.ParseDuration(str)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка при парсинге %s: %w", d.env, err)
+		}
+		*d.value = value
+	}
+
+	return cfg, nil
+}
+
+// newNotificationConfig parses NOTIFIERS as a comma-separated list of channels, e.g. "sms,email"
+func newNotificationConfig() (*NotificationConfig, error) {
+	cfg := &NotificationConfig{
+		LogPath:        os.Getenv("NOTIFY_LOG_PATH"),
+		SMSURL:         os.Getenv("SMS_API_URL"),
+		SMSToken:       os.Getenv("SMS_API_TOKEN"),
+		SMTPAddr:       os.Getenv("SMTP_ADDR"),
+		SMTPFrom:       os.Getenv("SMTP_FROM"),
+		SMTPUser:       os.Getenv("SMTP_USER"),
+		SMTPPassword:   os.Getenv("SMTP_PASSWORD"),
+		Timeout:        defaultNotifyTimeout,
+		ReminderBefore: defaultReminderBefore,
+	}
+
+	notifiers := os.Getenv("NOTIFIERS")
+	if notifiers == "" {
+		notifiers = defaultNotifiers
+	}
+
+	for _, notifier := range strings.Split(notifiers, ",") {
+		notifier = strings.TrimSpace(notifier)
+		switch notifier {
+		case "log":
+		case "sms":
+			if cfg.SMSURL == "" {
+				return nil, fmt.Errorf("SMS_API_URL не задан")
+			}
+		case "email":
+			if cfg.SMTPAddr == "" || cfg.SMTPFrom == "" {
+				return nil, fmt.Errorf("SMTP_ADDR и SMTP_FROM должны быть заданы")
+			}
+		default:
+			return nil, fmt.Errorf("неизвестный канал уведомлений: %s", notifier)
+		}
+		cfg.Notifiers = append(cfg.Notifiers, notifier)
+	}
+
+	if strBefore := os.Getenv("NOTIFY_REMINDER_BEFORE"); strBefore != "" {
+		before, err := time.ParseDuration(strBefore)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка при парсинге NOTIFY_REMINDER_BEFORE: %w", err)
+		}
+		cfg.ReminderBefore = before
+	}
+
+	return cfg, nil
+}
+
+func newSchedulerConfig() (*SchedulerConfig, error) {
+	cfg := &SchedulerConfig{
+		Tick:             defaultSchedulerTick,
+		ExpireEvery:      defaultExpireEvery,
+		RemindEvery:      defaultRemindEvery,
+		CachePurgeEvery:  defaultCachePurgeEvery,
+		OutboxPurgeEvery: defaultOutboxPurgeEvery,
+		OutboxRetention:  defaultOutboxRetention,
+		ReturnListDir:    defaultReturnListDir,
+	}
+
+	durations := []struct {
+		env   string
+		value *time.Duration
+	}{
+		{"SCHEDULER_TICK", &cfg.Tick},
+		{"SCHEDULER_EXPIRE_EVERY", &cfg.ExpireEvery},
+		{"SCHEDULER_REMIND_EVERY", &cfg.RemindEvery},
+		{"SCHEDULER_CACHE_PURGE_EVERY", &cfg.CachePurgeEvery},
+		{"SCHEDULER_OUTBOX_PURGE_EVERY", &cfg.OutboxPurgeEvery},
+		{"SCHEDULER_OUTBOX_RETENTION", &cfg.OutboxRetention},
+	}
+	for _, d := range durations {
+		str := os.Getenv(d.env)
+		if str == "" {
+			continue
+		}
+		value, err := time.ParseDuration(str)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("%s должно быть положительной длительностью", d.env)
+		}
+		*d.value = value
+	}
+
+	returnListAt := os.Getenv("SCHEDULER_RETURN_LIST_AT")
+	if returnListAt == "" {
+		returnListAt = defaultReturnListAt
+	}
+	at, err := time.Parse("15:04", returnListAt)
+	if err != nil {
+		return nil, fmt.Errorf("SCHEDULER_RETURN_LIST_AT должно быть в формате ЧЧ:ММ")
+	}
+	cfg.ReturnListHour, cfg.ReturnListMinute = at.Hour(), at.Minute()
+
+	if dir := os.Getenv("SCHEDULER_RETURN_LIST_DIR"); dir != "" {
+		cfg.ReturnListDir = dir
+	}
+
+	return cfg, nil
+}
+
+func newPickupPointConfig() (*PickupPointConfig, error) {
+	cfg := &PickupPointConfig{ID: defaultPickupPointID}
+
+	if strID := os.Getenv("PICKUP_POINT_ID"); strID != "" {
+		id, err := strconv.Atoi(strID)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("PICKUP_POINT_ID должно быть положительным числом")
+		}
+		cfg.ID = id
+	}
+
+	// Tokens are listed as token:pointID separated by commas
+	if strTokens := os.Getenv("PICKUP_POINT_TOKENS"); strTokens != "" {
+		cfg.Tokens = make(map[string]int)
+		for _, pair := range strings.Split(strTokens, ",") {
+			token, strID, ok := strings.Cut(strings.TrimSpace(pair), ":")
+			id, err := strconv.Atoi(strID)
+			if !ok || token == "" || err != nil || id <= 0 {
+				return nil, fmt.Errorf("PICKUP_POINT_TOKENS должно быть в формате токен:ID,токен:ID")
+			}
+			cfg.Tokens[token] = id
+		}
+	}
+
+	return cfg, nil
+}
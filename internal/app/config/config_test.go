@@ -0,0 +1,209 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSinksConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           map[string]string
+		expectedModes []string
+		expectedError string
+	}{
+		{
+			name:          "stdout by default",
+			env:           map[string]string{},
+			expectedModes: []string{OutputStdout},
+		},
+		{
+			name:          "list of sinks",
+			env:           map[string]string{"OUTPUT_MODE": "kafka, file,kafka"},
+			expectedModes: []string{OutputKafka, OutputFile},
+		},
+		{
+			name:          "unknown sink",
+			env:           map[string]string{"OUTPUT_MODE": "kafka,pigeon"},
+			expectedError: "неизвестный OUTPUT_MODE: pigeon",
+		},
+		{
+			name:          "http sink without url",
+			env:           map[string]string{"OUTPUT_MODE": "http"},
+			expectedError: "EVENTS_HTTP_URL не задан",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			for _, key := range []string{"OUTPUT_MODE", "EVENTS_HTTP_URL"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			// act
+			cfg, err := newSinksConfig()
+
+			// assert
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedModes, cfg.Modes)
+		})
+	}
+}
+
+func TestNewSchedulerConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		env            map[string]string
+		expectedHour   int
+		expectedMinute int
+		expectedError  string
+	}{
+		{
+			name:         "defaults",
+			env:          map[string]string{},
+			expectedHour: 9,
+		},
+		{
+			name:           "return list time",
+			env:            map[string]string{"SCHEDULER_RETURN_LIST_AT": "18:30"},
+			expectedHour:   18,
+			expectedMinute: 30,
+		},
+		{
+			name:          "invalid return list time",
+			env:           map[string]string{"SCHEDULER_RETURN_LIST_AT": "25:00"},
+			expectedError: "SCHEDULER_RETURN_LIST_AT должно быть в формате ЧЧ:ММ",
+		},
+		{
+			name:          "non-positive interval",
+			env:           map[string]string{"SCHEDULER_EXPIRE_EVERY": "0s"},
+			expectedError: "SCHEDULER_EXPIRE_EVERY должно быть положительной длительностью",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			for _, key := range []string{"SCHEDULER_RETURN_LIST_AT", "SCHEDULER_EXPIRE_EVERY"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			// act
+			cfg, err := newSchedulerConfig()
+
+			// assert
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedHour, cfg.ReturnListHour)
+			assert.Equal(t, tt.expectedMinute, cfg.ReturnListMinute)
+		})
+	}
+}
+
+func TestNewPickupPointConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		env            map[string]string
+		expectedID     int
+		expectedTokens map[string]int
+		expectedError  string
+	}{
+		{
+			name:       "defaults",
+			env:        map[string]string{},
+			expectedID: 1,
+		},
+		{
+			name:           "point and tokens",
+			env:            map[string]string{"PICKUP_POINT_ID": "3", "PICKUP_POINT_TOKENS": "north:2, south:3"},
+			expectedID:     3,
+			expectedTokens: map[string]int{"north": 2, "south": 3},
+		},
+		{
+			name:          "invalid point",
+			env:           map[string]string{"PICKUP_POINT_ID": "0"},
+			expectedError: "PICKUP_POINT_ID должно быть положительным числом",
+		},
+		{
+			name:          "invalid tokens",
+			env:           map[string]string{"PICKUP_POINT_TOKENS": "north"},
+			expectedError: "PICKUP_POINT_TOKENS должно быть в формате токен:ID,токен:ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			for _, key := range []string{"PICKUP_POINT_ID", "PICKUP_POINT_TOKENS"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			// act
+			cfg, err := newPickupPointConfig()
+
+			// assert
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedID, cfg.ID)
+			assert.Equal(t, tt.expectedTokens, cfg.Tokens)
+		})
+	}
+}
+
+func TestNewServerConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           map[string]string
+		expected      ServerConfig
+		expectedError string
+	}{
+		{
+			name:     "defaults",
+			env:      map[string]string{},
+			expected: ServerConfig{GrpcPort: defaultGrpcPort},
+		},
+		{
+			name:     "tls",
+			env:      map[string]string{"GRPC_PORT": ":50052", "GRPC_TLS_CERT": "server.crt", "GRPC_TLS_KEY": "server.key"},
+			expected: ServerConfig{GrpcPort: ":50052", TLSCertFile: "server.crt", TLSKeyFile: "server.key"},
+		},
+		{
+			name:          "certificate without key",
+			env:           map[string]string{"GRPC_TLS_CERT": "server.crt"},
+			expectedError: "GRPC_TLS_CERT и GRPC_TLS_KEY должны быть заданы вместе",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			for _, key := range []string{"GRPC_PORT", "GRPC_TLS_CERT", "GRPC_TLS_KEY", "MANAGER_TOKEN"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			// act
+			cfg, err := newServerConfig()
+
+			// assert
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, *cfg)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"route/internal/app/models"
+	events "route/pkg/api/proto/events/v1/events/v1"
+)
+
+// SchemaVersion is the version of event messages, it must be increased on incompatible changes
+const SchemaVersion = 1
+
+// Kafka headers describing the event in the message value
+const (
+	HeaderEventType     = "event-type"
+	HeaderSchemaVersion = "schema-version"
+	HeaderEventID       = "event-id"
+)
+
+// New builds a serialized event of the given type with a snapshot of the order after the change
+func New(eventType models.EventType, order models.Order, packagingType models.PackageType, actor models.Actor) (*models.OutboxMessage, error) {
+	metadata := newMetadata(actor)
+	snapshot := orderToProto(order)
+
+	var msg proto.Message
+	switch eventType {
+	case models.OrderAccepted:
+		msg = &events.OrderAccepted{Metadata: metadata, Order: snapshot, PackagingType: string(packagingType)}
+	case models.OrderIssued:
+		msg = &events.OrderIssued{Metadata: metadata, Order: snapshot}
+	case models.OrderReturnedToCourier:
+		msg = &events.OrderReturnedToCourier{Metadata: metadata, Order: snapshot}
+	case models.ReturnAccepted:
+		msg = &events.ReturnAccepted{Metadata: metadata, Order: snapshot}
+	case models.OrderExpired:
+		msg = &events.OrderExpired{Metadata: metadata, Order: snapshot}
+	case models.OrderRefused:
+		msg = &events.OrderRefused{Metadata: metadata, Order: snapshot}
+	case models.OrderLocked:
+		msg = &events.OrderLocked{Metadata: metadata, Order: snapshot}
+	case models.OrderUnlocked:
+		msg = &events.OrderUnlocked{Metadata: metadata, Order: snapshot}
+	case models.OrderInTransit:
+		msg = &events.OrderInTransit{Metadata: metadata, Order: snapshot}
+	case models.OrderTransferred:
+		msg = &events.OrderTransferred{Metadata: metadata, Order: snapshot}
+	default:
+		return nil, fmt.Errorf("unknown event type %q", eventType)
+	}
+
+	return marshal(eventType, metadata, order.OrderID, msg)
+}
+
+// NewManifestAck builds a serialized acknowledgement or rejection of an order from a courier manifest
+func NewManifestAck(item models.ManifestItem, actor models.Actor) (*models.OutboxMessage, error) {
+	metadata := newMetadata(actor)
+	if !item.Accepted {
+		return marshal(models.ManifestItemRejected, metadata, item.OrderID, &events.ManifestItemRejected{
+			Metadata:   metadata,
+			ManifestId: item.ManifestID,
+			OrderId:    int32(item.OrderID),
+			Reason:     item.Reason,
+		})
+	}
+
+	return marshal(models.ManifestItemAccepted, metadata, item.OrderID, &events.ManifestItemAccepted{
+		Metadata:   metadata,
+		ManifestId: item.ManifestID,
+		OrderId:    int32(item.OrderID),
+	})
+}
+
+func newMetadata(actor models.Actor) *events.Metadata {
+	return &events.Metadata{
+		EventId:       uuid.New().String(),
+		SchemaVersion: SchemaVersion,
+		OccurredAt:    timestamppb.New(time.Now()),
+		Actor: &events.Actor{
+			Type: string(actor.Type),
+			Id:   actor.ID,
+		},
+	}
+}
+
+func marshal(eventType models.EventType, metadata *events.Metadata, orderID int, msg proto.Message) (*models.OutboxMessage, error) {
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OutboxMessage{
+		EventID:       metadata.GetEventId(),
+		EventType:     eventType,
+		SchemaVersion: SchemaVersion,
+		OrderID:       orderID,
+		Payload:       payload,
+	}, nil
+}
+
+// Decode deserializes an event of the given type
+func Decode(eventType models.EventType, payload []byte) (proto.Message, error) {
+	var msg proto.Message
+	switch eventType {
+	case models.OrderAccepted:
+		msg = &events.OrderAccepted{}
+	case models.OrderIssued:
+		msg = &events.OrderIssued{}
+	case models.OrderReturnedToCourier:
+		msg = &events.OrderReturnedToCourier{}
+	case models.ReturnAccepted:
+		msg = &events.ReturnAccepted{}
+	case models.OrderExpired:
+		msg = &events.OrderExpired{}
+	case models.OrderRefused:
+		msg = &events.OrderRefused{}
+	case models.OrderLocked:
+		msg = &events.OrderLocked{}
+	case models.OrderUnlocked:
+		msg = &events.OrderUnlocked{}
+	case models.OrderInTransit:
+		msg = &events.OrderInTransit{}
+	case models.OrderTransferred:
+		msg = &events.OrderTransferred{}
+	case models.CourierManifest:
+		msg = &events.CourierManifest{}
+	case models.ManifestItemAccepted:
+		msg = &events.ManifestItemAccepted{}
+	case models.ManifestItemRejected:
+		msg = &events.ManifestItemRejected{}
+	default:
+		return nil, fmt.Errorf("unknown event type %q", eventType)
+	}
+
+	if err := proto.Unmarshal(payload, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func orderToProto(order models.Order) *events.Order {
+	snapshot := &events.Order{
+		OrderId:         int32(order.OrderID),
+		UserId:          int32(order.UserID),
+		Deadline:        timestamppb.New(order.Deadline),
+		IssuedToUser:    order.IssuedToUser,
+		IsReturned:      order.IsReturned,
+		Cost:            order.Cost,
+		Weight:          order.Weight,
+		SessionId:       order.SessionID,
+		RefusalReason:   order.RefusalReason,
+		IssueOverrideBy: order.IssueOverrideBy,
+		ReceivedBy:      order.ReceivedBy,
+		AuthorizationId: order.AuthorizationID,
+		PickupPointId:   int32(order.PickupPointID),
+		TransferId:      order.TransferID,
+	}
+	if !order.IssuedAt.IsZero() {
+		snapshot.IssuedAt = timestamppb.New(order.IssuedAt)
+	}
+	if !order.ExpiredAt.IsZero() {
+		snapshot.ExpiredAt = timestamppb.New(order.ExpiredAt)
+	}
+	if !order.PickupLockedAt.IsZero() {
+		snapshot.PickupLockedAt = timestamppb.New(order.PickupLockedAt)
+	}
+	if !order.RefusedAt.IsZero() {
+		snapshot.RefusedAt = timestamppb.New(order.RefusedAt)
+	}
+	if !order.ReturnedToCourierAt.IsZero() {
+		snapshot.ReturnedToCourierAt = timestamppb.New(order.ReturnedToCourierAt)
+	}
+	return snapshot
+}
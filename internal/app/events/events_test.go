@@ -0,0 +1,67 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+	events "route/pkg/api/proto/events/v1/events/v1"
+)
+
+func TestNewAndDecode(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	order := models.Order{OrderID: 1, UserID: 2, Deadline: time.Now().Add(time.Hour), Cost: 105, Weight: 3}
+	actor := models.NewActor(models.ActorCLI, "manager")
+
+	// act
+	msg, err := New(models.OrderAccepted, order, models.Package, actor)
+	require.NoError(t, err)
+	decoded, err := Decode(msg.EventType, msg.Payload)
+	require.NoError(t, err)
+
+	// assert
+	assert.Equal(t, SchemaVersion, msg.SchemaVersion)
+	assert.Equal(t, 1, msg.OrderID)
+	accepted, ok := decoded.(*events.OrderAccepted)
+	require.True(t, ok)
+	assert.Equal(t, msg.EventID, accepted.GetMetadata().GetEventId())
+	assert.Equal(t, "cli", accepted.GetMetadata().GetActor().GetType())
+	assert.Equal(t, "manager", accepted.GetMetadata().GetActor().GetId())
+	assert.Equal(t, int32(2), accepted.GetOrder().GetUserId())
+	assert.Equal(t, string(models.Package), accepted.GetPackagingType())
+	assert.Nil(t, accepted.GetOrder().GetIssuedAt())
+}
+
+func TestNew_UnknownType(t *testing.T) {
+	t.Parallel()
+
+	_, err := New("OrderLost", models.Order{}, "", models.Actor{})
+
+	assert.EqualError(t, err, `unknown event type "OrderLost"`)
+}
+
+func TestNewManifestAck(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	item := models.ManifestItem{ManifestID: "m-1", OrderID: 7, Reason: "срок хранения не может быть в прошлом"}
+	actor := models.NewActor(models.ActorKafka, "courier-manifest")
+
+	// act
+	msg, err := NewManifestAck(item, actor)
+	require.NoError(t, err)
+	decoded, err := Decode(msg.EventType, msg.Payload)
+	require.NoError(t, err)
+
+	// assert
+	assert.Equal(t, models.ManifestItemRejected, msg.EventType)
+	rejected, ok := decoded.(*events.ManifestItemRejected)
+	require.True(t, ok)
+	assert.Equal(t, "m-1", rejected.GetManifestId())
+	assert.Equal(t, int32(7), rejected.GetOrderId())
+	assert.Equal(t, item.Reason, rejected.GetReason())
+}
@@ -0,0 +1,36 @@
+package exporter
+
+import (
+	"io"
+
+	"route/internal/app/models"
+	"route/internal/app/module"
+)
+
+// Exporter writes orders selected with the filters of list-orders and list-returns to files
+type Exporter struct {
+	mod module.Module
+}
+
+func New(mod module.Module) *Exporter {
+	return &Exporter{mod: mod}
+}
+
+// Export streams the orders matching the filter to w in the format and returns the number of exported orders
+func (e *Exporter) Export(w io.Writer, format Format, filter models.ExportFilter) (int, error) {
+	writer, err := NewWriter(w, format)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = e.mod.ExportOrders(filter, func(order models.Order) error {
+		count++
+		return writer.Write(order)
+	})
+	if err != nil {
+		return count, err
+	}
+
+	return count, writer.Close()
+}
@@ -0,0 +1,137 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"route/internal/app/models"
+	mockmodule "route/internal/app/module/mocks"
+)
+
+var exportedOrders = []models.Order{
+	{OrderID: 2, UserID: 7, PickupPointID: 1, Deadline: time.Date(2030, 1, 2, 15, 0, 0, 0, time.UTC), Weight: 1.5, Cost: 100,
+		Cell: "A-01"},
+	{OrderID: 1, UserID: 7, PickupPointID: 1, Deadline: time.Date(2030, 1, 2, 15, 0, 0, 0, time.UTC), Weight: 3, Cost: 50,
+		IssuedToUser: true, IssuedAt: time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC), ReceivedBy: "Иванов <И.И.>"},
+}
+
+func TestExporter_Export(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		format   Format
+		orders   []models.Order
+		expected string
+	}{
+		{
+			name:   "csv",
+			format: CSV,
+			orders: exportedOrders,
+			expected: "order_id,user_id,pickup_point_id,status,deadline,weight,cost,cell,issued_at,received_by,refused_at,refusal_reason\n" +
+				"2,7,1,stored,2030-01-02T15:00:00Z,1.5,100,A-01,,,,\n" +
+				"1,7,1,issued,2030-01-02T15:00:00Z,3,50,,2030-01-01T10:00:00Z,Иванов <И.И.>,,\n",
+		},
+		{
+			name:     "csv without orders has header",
+			format:   CSV,
+			expected: "order_id,user_id,pickup_point_id,status,deadline,weight,cost,cell,issued_at,received_by,refused_at,refusal_reason\n",
+		},
+		{
+			name:   "jsonl",
+			format: JSONL,
+			orders: exportedOrders[:1],
+			expected: `{"order_id":2,"user_id":7,"pickup_point_id":1,"status":"stored","deadline":"2030-01-02T15:00:00Z",` +
+				`"weight":1.5,"cost":100,"cell":"A-01","issued_at":"","received_by":"","refused_at":"","refusal_reason":""}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockModule := mockmodule.NewMockModule(ctrl)
+			filter := models.ExportFilter{Kind: models.OrdersExport, UserID: 7}
+			mockModule.EXPECT().ExportOrders(filter, gomock.Any()).DoAndReturn(
+				func(_ models.ExportFilter, fx func(order models.Order) error) error {
+					for _, order := range tt.orders {
+						if err := fx(order); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+			var out bytes.Buffer
+
+			// act
+			count, err := New(mockModule).Export(&out, tt.format, filter)
+
+			// assert
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.orders), count)
+			assert.Equal(t, tt.expected, out.String())
+		})
+	}
+}
+
+func TestExporter_ExportXLSX(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctrl := gomock.NewController(t)
+	mockModule := mockmodule.NewMockModule(ctrl)
+	mockModule.EXPECT().ExportOrders(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ models.ExportFilter, fx func(order models.Order) error) error {
+			return fx(exportedOrders[1])
+		})
+	var out bytes.Buffer
+
+	// act
+	count, err := New(mockModule).Export(&out, XLSX, models.ExportFilter{Kind: models.ReturnsExport})
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	names := make([]string, len(archive.File))
+	for i, f := range archive.File {
+		names[i] = f.Name
+	}
+	assert.Equal(t, []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", xlsxSheet}, names)
+	f, err := archive.Open(xlsxSheet)
+	require.NoError(t, err)
+	sheet, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Contains(t, string(sheet), `<row r="1"><c r="A1" t="inlineStr"><is><t>order_id</t></is></c>`)
+	assert.Contains(t, string(sheet), `<row r="2"><c r="A2"><v>1</v></c>`)
+	assert.Contains(t, string(sheet), `<c r="J2" t="inlineStr"><is><t>Иванов &lt;И.И.&gt;</t></is></c>`)
+	assert.Contains(t, string(sheet), `</row></sheetData></worksheet>`)
+}
+
+func TestExporter_ExportError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctrl := gomock.NewController(t)
+	mockModule := mockmodule.NewMockModule(ctrl)
+	mockModule.EXPECT().ExportOrders(gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+
+	// act
+	_, formatErr := New(mockModule).Export(io.Discard, "pdf", models.ExportFilter{})
+	_, err := New(mockModule).Export(io.Discard, CSV, models.ExportFilter{Kind: models.OrdersExport})
+
+	// assert
+	assert.EqualError(t, formatErr, "неподдерживаемый формат выгрузки: pdf")
+	assert.EqualError(t, err, "connection refused")
+}
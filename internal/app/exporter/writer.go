@@ -0,0 +1,161 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"route/internal/app/models"
+)
+
+type Format string
+
+const (
+	CSV   Format = "csv"
+	JSONL Format = "jsonl"
+	XLSX  Format = "xlsx"
+)
+
+// ParseFormat checks the format of an export file
+func ParseFormat(format string) (Format, error) {
+	switch Format(format) {
+	case CSV, JSONL, XLSX:
+		return Format(format), nil
+	}
+	return "", fmt.Errorf("неподдерживаемый формат выгрузки: %s", format)
+}
+
+// columns are the columns of CSV and XLSX files and the keys of JSON lines
+var columns = []string{"order_id", "user_id", "pickup_point_id", "status", "deadline", "weight", "cost", "cell",
+	"issued_at", "received_by", "refused_at", "refusal_reason"}
+
+// Row is an exported order
+type Row struct {
+	OrderID       int     `json:"order_id"`
+	UserID        int     `json:"user_id"`
+	PickupPointID int     `json:"pickup_point_id"`
+	Status        string  `json:"status"`
+	Deadline      string  `json:"deadline"`
+	Weight        float64 `json:"weight"`
+	Cost          float64 `json:"cost"`
+	Cell          string  `json:"cell"`
+	IssuedAt      string  `json:"issued_at"`
+	ReceivedBy    string  `json:"received_by"`
+	RefusedAt     string  `json:"refused_at"`
+	RefusalReason string  `json:"refusal_reason"`
+}
+
+func newRow(order models.Order) Row {
+	return Row{
+		OrderID:       order.OrderID,
+		UserID:        order.UserID,
+		PickupPointID: order.PickupPointID,
+		Status:        order.Status(),
+		Deadline:      formatTime(order.Deadline),
+		Weight:        order.Weight,
+		Cost:          order.Cost,
+		Cell:          order.Cell,
+		IssuedAt:      formatTime(order.IssuedAt),
+		ReceivedBy:    order.ReceivedBy,
+		RefusedAt:     formatTime(order.RefusedAt),
+		RefusalReason: order.RefusalReason,
+	}
+}
+
+// values returns the cells of the row in the order of columns, numbers are marked to be stored as numbers in XLSX
+func (r Row) values() []value {
+	return []value{
+		{text: strconv.Itoa(r.OrderID), number: true},
+		{text: strconv.Itoa(r.UserID), number: true},
+		{text: strconv.Itoa(r.PickupPointID), number: true},
+		{text: r.Status},
+		{text: r.Deadline},
+		{text: strconv.FormatFloat(r.Weight, 'f', -1, 64), number: true},
+		{text: strconv.FormatFloat(r.Cost, 'f', -1, 64), number: true},
+		{text: r.Cell},
+		{text: r.IssuedAt},
+		{text: r.ReceivedBy},
+		{text: r.RefusedAt},
+		{text: r.RefusalReason},
+	}
+}
+
+type value struct {
+	text   string
+	number bool
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Writer writes exported orders to a file, Close must be called to complete the file
+type Writer interface {
+	Write(order models.Order) error
+	Close() error
+}
+
+// NewWriter returns a writer of the format, the header is written with the first order or on Close
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case CSV:
+		return &csvWriter{writer: csv.NewWriter(w)}, nil
+	case JSONL:
+		return &jsonlWriter{encoder: json.NewEncoder(w)}, nil
+	case XLSX:
+		return newXLSXWriter(w), nil
+	}
+	return nil, fmt.Errorf("неподдерживаемый формат выгрузки: %s", format)
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+	header bool
+}
+
+func (c *csvWriter) writeHeader() error {
+	if c.header {
+		return nil
+	}
+	c.header = true
+	return c.writer.Write(columns)
+}
+
+func (c *csvWriter) Write(order models.Order) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	values := newRow(order).values()
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = v.text
+	}
+	return c.writer.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+type jsonlWriter struct {
+	encoder *json.Encoder
+}
+
+func (j *jsonlWriter) Write(order models.Order) error {
+	return j.encoder.Encode(newRow(order))
+}
+
+func (j *jsonlWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,123 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"route/internal/app/models"
+)
+
+// xlsxParts are the parts of a workbook with one sheet, the sheet itself is streamed
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="orders" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+const xlsxSheet = "xl/worksheets/sheet1.xml"
+
+// xlsxWriter writes a minimal workbook without a spreadsheet library. Rows are written to the zip archive
+// as they come, so large exports are not kept in memory
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	rows    int
+	err     error
+}
+
+func newXLSXWriter(w io.Writer) *xlsxWriter {
+	return &xlsxWriter{archive: zip.NewWriter(w)}
+}
+
+// start writes the static parts and the header row of the sheet
+func (x *xlsxWriter) start() error {
+	if x.sheet != nil || x.err != nil {
+		return x.err
+	}
+
+	for _, part := range xlsxParts {
+		f, err := x.archive.Create(part.name)
+		if err != nil {
+			return x.fail(err)
+		}
+		if _, err = io.WriteString(f, part.content); err != nil {
+			return x.fail(err)
+		}
+	}
+
+	f, err := x.archive.Create(xlsxSheet)
+	if err != nil {
+		return x.fail(err)
+	}
+	x.sheet = bufio.NewWriter(f)
+	x.sheet.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	header := make([]value, len(columns))
+	for i, column := range columns {
+		header[i] = value{text: column}
+	}
+	return x.writeRow(header)
+}
+
+func (x *xlsxWriter) fail(err error) error {
+	x.err = err
+	return err
+}
+
+func (x *xlsxWriter) writeRow(values []value) error {
+	x.rows++
+	fmt.Fprintf(x.sheet, `<row r="%d">`, x.rows)
+	for i, v := range values {
+		ref := fmt.Sprintf("%c%d", 'A'+i, x.rows)
+		if v.number {
+			fmt.Fprintf(x.sheet, `<c r="%s"><v>%s</v></c>`, ref, v.text)
+			continue
+		}
+		fmt.Fprintf(x.sheet, `<c r="%s" t="inlineStr"><is><t>`, ref)
+		if err := xml.EscapeText(x.sheet, []byte(v.text)); err != nil {
+			return x.fail(err)
+		}
+		x.sheet.WriteString(`</t></is></c>`)
+	}
+	_, err := x.sheet.WriteString(`</row>`)
+	if err != nil {
+		return x.fail(err)
+	}
+	return nil
+}
+
+func (x *xlsxWriter) Write(order models.Order) error {
+	if err := x.start(); err != nil {
+		return err
+	}
+	return x.writeRow(newRow(order).values())
+}
+
+func (x *xlsxWriter) Close() error {
+	if err := x.start(); err != nil {
+		return err
+	}
+	x.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := x.sheet.Flush(); err != nil {
+		return x.fail(err)
+	}
+	return x.archive.Close()
+}
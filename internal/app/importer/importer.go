@@ -0,0 +1,252 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"route/internal/app/models"
+	"route/internal/app/module"
+)
+
+// DefaultBatchSize is the number of rows accepted in one transaction
+const DefaultBatchSize = 100
+
+// Statuses of imported lines
+const (
+	StatusAccepted = "accepted"
+	StatusValid    = "valid"
+	StatusRejected = "rejected"
+)
+
+var resultColumns = []string{"line", "order_id", "status", "error", "cell"}
+
+var codeColumns = []string{"order_id", "pickup_code"}
+
+// Options of an import. Lines up to SkipLines were processed by an earlier run and are skipped.
+// Pickup codes of accepted orders are written to Codes only if it is set, the results never contain them
+type Options struct {
+	BatchSize int
+	DryRun    bool
+	SkipLines int
+	Codes     *CodeWriter
+}
+
+// Result is the outcome of one line of the import file
+type Result struct {
+	Line       int
+	OrderID    int
+	Status     string
+	Error      string
+	Cell       string
+	PickupCode string
+}
+
+// Summary counts processed lines of an import
+type Summary struct {
+	Processed int
+	Accepted  int
+	Rejected  int
+	LastLine  int
+}
+
+// Importer accepts orders from files. Every row is validated by OrderModule.AcceptOrders
+// with the checks of AcceptOrder, rows of a batch are stored in one transaction
+type Importer struct {
+	mod module.Module
+}
+
+func New(mod module.Module) *Importer {
+	return &Importer{mod: mod}
+}
+
+// Import streams rows from src and writes the result of every line to results.
+// Results are flushed after every batch, so an interrupted import can be resumed from the last written line
+func (i *Importer) Import(src io.Reader, format Format, results *ResultWriter, opts Options) (Summary, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	reader, err := newRowReader(src, format)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	var summary Summary
+	batch := make([]Result, 0, opts.BatchSize)
+	reqs := make([]models.AcceptRequest, 0, opts.BatchSize)
+	flush := func() error {
+		i.accept(batch, reqs, opts.DryRun)
+		for _, res := range batch {
+			summary.Processed++
+			summary.LastLine = res.Line
+			if res.Status == StatusRejected {
+				summary.Rejected++
+			} else {
+				summary.Accepted++
+			}
+		}
+		err := results.Write(batch)
+		if err == nil && opts.Codes != nil {
+			err = opts.Codes.Write(batch)
+		}
+		batch, reqs = batch[:0], reqs[:0]
+		return err
+	}
+
+	for {
+		line, row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var rowErr rowError
+		if err != nil && !errors.As(err, &rowErr) {
+			return summary, err
+		}
+		if line <= opts.SkipLines {
+			continue
+		}
+
+		res := Result{Line: line, OrderID: row.OrderID}
+		if err != nil {
+			res.Status, res.Error = StatusRejected, err.Error()
+		} else {
+			order := &models.Order{OrderID: row.OrderID, UserID: row.UserID, Deadline: row.Deadline, Cost: row.Cost, Weight: row.Weight}
+			reqs = append(reqs, models.AcceptRequest{Order: order, PackagingType: models.ToPackageType(row.PackagingType)})
+		}
+		batch = append(batch, res)
+
+		if len(reqs) == opts.BatchSize {
+			if err = flush(); err != nil {
+				return summary, err
+			}
+		}
+	}
+
+	if len(batch) > 0 {
+		if err = flush(); err != nil {
+			return summary, err
+		}
+	}
+	return summary, nil
+}
+
+// accept accepts the parsed rows of the batch and fills their results
+func (i *Importer) accept(batch []Result, reqs []models.AcceptRequest, dryRun bool) {
+	if len(reqs) == 0 {
+		return
+	}
+
+	errs := i.mod.AcceptOrders(reqs, dryRun)
+	k := 0
+	for j := range batch {
+		if batch[j].Status == StatusRejected {
+			continue
+		}
+
+		req := reqs[k]
+		switch {
+		case errs[k] != nil:
+			batch[j].Status, batch[j].Error = StatusRejected, errs[k].Error()
+		case dryRun:
+			batch[j].Status = StatusValid
+		default:
+			batch[j].Status, batch[j].Cell, batch[j].PickupCode = StatusAccepted, req.Order.Cell, req.Order.PickupCode
+		}
+		k++
+	}
+}
+
+// ResultWriter writes results of the import as CSV
+type ResultWriter struct {
+	writer *csv.Writer
+}
+
+// NewResultWriter writes results to w, the header is written unless the results are appended to an earlier run
+func NewResultWriter(w io.Writer, header bool) (*ResultWriter, error) {
+	writer := csv.NewWriter(w)
+	if header {
+		if err := writer.Write(resultColumns); err != nil {
+			return nil, err
+		}
+	}
+	return &ResultWriter{writer: writer}, nil
+}
+
+func (w *ResultWriter) Write(results []Result) error {
+	for _, res := range results {
+		err := w.writer.Write([]string{strconv.Itoa(res.Line), strconv.Itoa(res.OrderID), res.Status, res.Error, res.Cell})
+		if err != nil {
+			return err
+		}
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// CodeWriter writes pickup codes of accepted orders as CSV, the file must be readable by the operator only
+type CodeWriter struct {
+	writer *csv.Writer
+}
+
+// NewCodeWriter writes codes to w, the header is written unless the codes are appended to an earlier run
+func NewCodeWriter(w io.Writer, header bool) (*CodeWriter, error) {
+	writer := csv.NewWriter(w)
+	if header {
+		if err := writer.Write(codeColumns); err != nil {
+			return nil, err
+		}
+	}
+	return &CodeWriter{writer: writer}, nil
+}
+
+func (w *CodeWriter) Write(results []Result) error {
+	for _, res := range results {
+		if res.PickupCode == "" {
+			continue
+		}
+		if err := w.writer.Write([]string{strconv.Itoa(res.OrderID), res.PickupCode}); err != nil {
+			return err
+		}
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// LastLine returns the last line of the import file written to the results file, zero if there are no results yet
+func LastLine(path string) (int, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	last := 0
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return last, nil
+		}
+		// A line cut by an interruption ends the results
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return last, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("не удалось прочитать файл результатов: %v", err)
+		}
+
+		// The header is skipped
+		if line, err := strconv.Atoi(record[0]); err == nil && line > last {
+			last = line
+		}
+	}
+}
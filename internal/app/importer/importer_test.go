@@ -0,0 +1,144 @@
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"route/internal/app/models"
+	mockmodule "route/internal/app/module/mocks"
+)
+
+const csvFile = `order_id,user_id,deadline,packaging_type,weight,cost
+1,2,2030-01-02T15:00:00Z,коробка,3,100
+2,2,завтра,коробка,3,100
+3,2,2030-01-02T15:00:00Z,пакет,20,100
+`
+
+func TestImporter_Import(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		src             string
+		format          Format
+		opts            Options
+		setupMocks      func(mockModule *mockmodule.MockModule)
+		expectedResults string
+		expectedCodes   string
+		expectedSummary Summary
+	}{
+		{
+			name:   "csv rows are accepted",
+			src:    csvFile,
+			format: CSV,
+			opts:   Options{BatchSize: 10},
+			setupMocks: func(mockModule *mockmodule.MockModule) {
+				mockModule.EXPECT().AcceptOrders(gomock.Len(2), false).DoAndReturn(func(reqs []models.AcceptRequest, _ bool) []error {
+					reqs[0].Order.Cell, reqs[0].Order.PickupCode = "A-01", "123456"
+					return []error{nil, errors.New("вес заказа превышает допустимый для пакета: 20.000000")}
+				})
+			},
+			expectedResults: "line,order_id,status,error,cell\n" +
+				"2,1,accepted,,A-01\n" +
+				"3,2,rejected,неверный формат даты,\n" +
+				"4,3,rejected,вес заказа превышает допустимый для пакета: 20.000000,\n",
+			expectedCodes:   "order_id,pickup_code\n1,123456\n",
+			expectedSummary: Summary{Processed: 3, Accepted: 1, Rejected: 2, LastLine: 4},
+		},
+		{
+			name:   "resume skips processed lines",
+			src:    csvFile,
+			format: CSV,
+			opts:   Options{BatchSize: 10, SkipLines: 3},
+			setupMocks: func(mockModule *mockmodule.MockModule) {
+				mockModule.EXPECT().AcceptOrders(gomock.Len(1), false).Return([]error{nil})
+			},
+			expectedResults: "line,order_id,status,error,cell\n" +
+				"4,3,accepted,,\n",
+			expectedSummary: Summary{Processed: 1, Accepted: 1, LastLine: 4},
+		},
+		{
+			name:   "jsonl dry run in batches",
+			src:    "{\"order_id\":5,\"user_id\":1,\"deadline\":\"2030-01-02T15:00:00Z\",\"packaging_type\":\"пакет\",\"weight\":1,\"cost\":10}\n\n{oops}\n{\"order_id\":6,\"user_id\":1,\"deadline\":\"2030-01-02T15:00:00Z\",\"packaging_type\":\"пакет\",\"weight\":1,\"cost\":10}\n",
+			format: JSONL,
+			opts:   Options{BatchSize: 1, DryRun: true},
+			setupMocks: func(mockModule *mockmodule.MockModule) {
+				mockModule.EXPECT().AcceptOrders(gomock.Len(1), true).Return([]error{nil}).Times(2)
+			},
+			expectedResults: "line,order_id,status,error,cell\n" +
+				"1,5,valid,,\n" +
+				"3,0,rejected,некорректная строка JSON: invalid character 'o' looking for beginning of object key string,\n" +
+				"4,6,valid,,\n",
+			expectedSummary: Summary{Processed: 3, Accepted: 2, Rejected: 1, LastLine: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockModule := mockmodule.NewMockModule(ctrl)
+			tt.setupMocks(mockModule)
+			var out, codesOut bytes.Buffer
+			results, err := NewResultWriter(&out, true)
+			require.NoError(t, err)
+			opts := tt.opts
+			if tt.expectedCodes != "" {
+				opts.Codes, err = NewCodeWriter(&codesOut, true)
+				require.NoError(t, err)
+			}
+
+			// act
+			summary, err := New(mockModule).Import(strings.NewReader(tt.src), tt.format, results, opts)
+
+			// assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSummary, summary)
+			assert.Equal(t, tt.expectedResults, out.String(), "Results never contain pickup codes")
+			assert.Equal(t, tt.expectedCodes, codesOut.String())
+		})
+	}
+}
+
+func TestImporter_ImportBadHeader(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	results, err := NewResultWriter(&bytes.Buffer{}, true)
+	require.NoError(t, err)
+
+	// act
+	_, err = New(nil).Import(strings.NewReader("order_id,user_id\n1,2\n"), CSV, results, Options{})
+
+	// assert
+	assert.EqualError(t, err, "в заголовке CSV нет колонки deadline")
+}
+
+func TestLastLine(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.csv")
+	require.NoError(t, os.WriteFile(path, []byte("line,order_id,status,error,cell\n2,1,accepted,,,\n7,3,rejected,\"cut"), 0o644))
+
+	// act
+	last, err := LastLine(path)
+	missing, missingErr := LastLine(filepath.Join(dir, "missing.csv"))
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, last, "Line cut by an interruption is not counted")
+	require.NoError(t, missingErr)
+	assert.Zero(t, missing)
+}
@@ -0,0 +1,161 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	CSV   Format = "csv"
+	JSONL Format = "jsonl"
+)
+
+// csvColumns are the columns of the CSV header, their order in the file is free
+var csvColumns = []string{"order_id", "user_id", "deadline", "packaging_type", "weight", "cost"}
+
+// Row is an order read from the import file
+type Row struct {
+	OrderID       int       `json:"order_id"`
+	UserID        int       `json:"user_id"`
+	Deadline      time.Time `json:"deadline"`
+	PackagingType string    `json:"packaging_type"`
+	Weight        float64   `json:"weight"`
+	Cost          float64   `json:"cost"`
+}
+
+// rowError is an error of a single line, the import goes on with the next line
+type rowError struct {
+	msg string
+}
+
+func (e rowError) Error() string {
+	return e.msg
+}
+
+// rowReader reads rows with the numbers of their lines in the file, it returns io.EOF after the last row
+type rowReader interface {
+	Next() (int, Row, error)
+}
+
+func newRowReader(src io.Reader, format Format) (rowReader, error) {
+	switch format {
+	case CSV:
+		return newCSVReader(src)
+	case JSONL:
+		return &jsonlReader{scanner: bufio.NewScanner(src)}, nil
+	}
+	return nil, fmt.Errorf("неподдерживаемый формат файла: %s", format)
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVReader(src io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(src)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("пустой файл импорта")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать заголовок CSV: %v", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range csvColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("в заголовке CSV нет колонки %s", name)
+		}
+	}
+
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvReader) Next() (int, Row, error) {
+	record, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return 0, Row{}, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return parseErr.StartLine, Row{}, rowError{msg: fmt.Sprintf("некорректная строка CSV: %v", parseErr.Err)}
+	}
+	if err != nil {
+		return 0, Row{}, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	row, err := r.parse(record)
+	return line, row, err
+}
+
+func (r *csvReader) parse(record []string) (Row, error) {
+	field := func(name string) string {
+		if i := r.columns[name]; i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var row Row
+	var err error
+	if row.OrderID, err = strconv.Atoi(field("order_id")); err != nil {
+		return row, rowError{msg: "некорректный order_id"}
+	}
+	if row.UserID, err = strconv.Atoi(field("user_id")); err != nil {
+		return row, rowError{msg: "некорректный user_id"}
+	}
+	if row.Deadline, err = time.Parse(time.RFC3339, field("deadline")); err != nil {
+		return row, rowError{msg: "неверный формат даты"}
+	}
+	if row.Weight, err = strconv.ParseFloat(field("weight"), 64); err != nil {
+		return row, rowError{msg: "некорректный weight"}
+	}
+	if row.Cost, err = strconv.ParseFloat(field("cost"), 64); err != nil {
+		return row, rowError{msg: "некорректный cost"}
+	}
+	row.PackagingType = field("packaging_type")
+	return row, nil
+}
+
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (r *jsonlReader) Next() (int, Row, error) {
+	for r.scanner.Scan() {
+		r.line++
+		text := strings.TrimSpace(r.scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var row Row
+		if err := json.Unmarshal([]byte(text), &row); err != nil {
+			return r.line, row, rowError{msg: fmt.Sprintf("некорректная строка JSON: %v", err)}
+		}
+		return r.line, row, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return 0, Row{}, err
+	}
+	return 0, Row{}, io.EOF
+}
@@ -1,57 +1,268 @@
 package kafka
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"strconv"
 	"time"
 
 	"github.com/IBM/sarama"
+	"google.golang.org/protobuf/encoding/protojson"
 	"route/internal/app/config"
+	"route/internal/app/events"
+	"route/internal/app/models"
 )
 
+// Suffixes of the topics for failed messages
+const (
+	retryTopicSuffix = ".retry"
+	dlqTopicSuffix   = ".dlq"
+)
+
+// Headers added to failed messages
+const (
+	HeaderRetryAttempt      = "retry-attempt"
+	HeaderRetryAt           = "retry-at"
+	HeaderError             = "dlq-error"
+	HeaderOriginalTopic     = "dlq-original-topic"
+	HeaderOriginalOffset    = "dlq-original-offset"
+	HeaderOriginalPartition = "dlq-original-partition"
+)
+
+// Handler processes a message of the event type it is registered for
+type Handler func(ctx context.Context, msg *sarama.ConsumerMessage) error
+
+// Forwarder sends a consumed message to another topic
+type Forwarder interface {
+	Forward(topic string, msg *sarama.ConsumerMessage, extra ...sarama.RecordHeader) error
+}
+
+// Pauser stops and restarts fetching of partitions
+type Pauser interface {
+	Pause(partitions map[string][]int32)
+	Resume(partitions map[string][]int32)
+}
+
+// KafkaConsumer reads the topic as a member of a consumer group and dispatches
+// messages to handlers by the event type header. The offset of a message is
+// committed only after it was handled.
+//
+// A message whose handler failed is forwarded to <topic>.retry, which is consumed
+// by the same group after a backoff delay. While a partition of the retry topic waits for the delay
+// its fetching is paused, so it doesn't hold back other partitions fetched from the same broker.
+// After MaxAttempts failed attempts
+// the message is moved to <topic>.dlq with the error in headers.
 type KafkaConsumer struct {
-	brokers  []string
-	Consumer sarama.Consumer
-	topic    string
+	group     sarama.ConsumerGroup
+	topic     string
+	handlers  map[models.EventType]Handler
+	forwarder Forwarder
+	retry     RetryPolicy
+	pauser    Pauser
 }
 
-func NewKafkaConsumer(cfg config.KafkaConfig) (*KafkaConsumer, error) {
+// NewKafkaConsumer creates a consumer of the topic in the consumer group groupID
+func NewKafkaConsumer(cfg config.KafkaConfig, topic, groupID string, forwarder Forwarder) (*KafkaConsumer, error) {
 	consumerConfig := sarama.NewConfig()
-	consumerConfig.Consumer.Return.Errors = false
-	consumerConfig.Consumer.Offsets.AutoCommit.Enable = true
-	consumerConfig.Consumer.Offsets.AutoCommit.Interval = 5 * time.Second
+	consumerConfig.Consumer.Return.Errors = true
+	consumerConfig.Consumer.Offsets.AutoCommit.Enable = false
 
 	consumerConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
 
-	consumer, err := sarama.NewConsumer(cfg.BrokerList, consumerConfig)
+	group, err := sarama.NewConsumerGroup(cfg.BrokerList, groupID, consumerConfig)
 	if err != nil {
 		return nil, err
 	}
 
 	return &KafkaConsumer{
-		brokers:  cfg.BrokerList,
-		Consumer: consumer,
-		topic:    cfg.Topic,
-	}, err
+		group:     group,
+		topic:     topic,
+		handlers:  make(map[models.EventType]Handler),
+		forwarder: forwarder,
+		retry:     NewRetryPolicy(cfg.Retry),
+		pauser:    group,
+	}, nil
+}
+
+// Handle registers handler for messages of the event type, it must be called before Run
+func (kc *KafkaConsumer) Handle(eventType models.EventType, handler Handler) {
+	kc.handlers[eventType] = handler
+}
+
+// Run consumes the topic and its retry topic until ctx is cancelled, then leaves the group and closes it
+func (kc *KafkaConsumer) Run(ctx context.Context) error {
+	go func() {
+		for err := range kc.group.Errors() {
+			log.Printf("kafka consumer: %v", err)
+		}
+	}()
+
+	defer kc.group.Close()
+
+	topics := []string{kc.topic, kc.topic + retryTopicSuffix}
+	for {
+		// Consume returns on every rebalance, so it is called in a loop
+		err := kc.group.Consume(ctx, topics, kc)
+		if err != nil && !errors.Is(err, sarama.ErrClosedConsumerGroup) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// Setup is run at the beginning of a new session
+func (kc *KafkaConsumer) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// Cleanup is run at the end of a session
+func (kc *KafkaConsumer) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim handles messages of one partition until the session ends
+func (kc *KafkaConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case <-session.Context().Done():
+			return nil
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+
+			// Messages in the retry topic are handled not earlier than their backoff delay
+			if !kc.waitRetry(session.Context(), msg) {
+				return nil
+			}
+
+			if err := kc.dispatch(session.Context(), msg); err != nil {
+				// If the message can't be rescheduled, it is not committed and will be redelivered
+				if err = kc.reschedule(msg, err); err != nil {
+					return err
+				}
+			}
+
+			session.MarkMessage(msg, "")
+			session.Commit()
+		}
+	}
+}
+
+// dispatch passes the message to the handler registered for its event type
+func (kc *KafkaConsumer) dispatch(ctx context.Context, msg *sarama.ConsumerMessage) error {
+	eventType := models.EventType(header(msg, events.HeaderEventType))
+	handler, ok := kc.handlers[eventType]
+	if !ok {
+		log.Printf("kafka consumer: no handler for event type %q, offset %d skipped", eventType, msg.Offset)
+		return nil
+	}
+
+	return handler(ctx, msg)
+}
+
+// reschedule forwards the failed message to the retry topic or, after the last attempt, to the dead-letter topic
+func (kc *KafkaConsumer) reschedule(msg *sarama.ConsumerMessage, handleErr error) error {
+	attempt := 1
+	if strAttempt := header(msg, HeaderRetryAttempt); strAttempt != "" {
+		attempt, _ = strconv.Atoi(strAttempt)
+	}
+
+	if attempt < kc.retry.MaxAttempts {
+		retryAt := time.Now().Add(kc.retry.Backoff(attempt))
+		log.Printf("kafka consumer: attempt %d of message %s/%d failed, retry at %s: %v",
+			attempt, msg.Topic, msg.Offset, retryAt.Format(time.RFC3339), handleErr)
+
+		return kc.forwarder.Forward(kc.topic+retryTopicSuffix, msg,
+			sarama.RecordHeader{Key: []byte(HeaderRetryAttempt), Value: []byte(strconv.Itoa(attempt + 1))},
+			sarama.RecordHeader{Key: []byte(HeaderRetryAt), Value: []byte(strconv.FormatInt(retryAt.UnixMilli(), 10))},
+		)
+	}
+
+	log.Printf("kafka consumer: message %s/%d failed %d times, moved to dead-letter topic: %v",
+		msg.Topic, msg.Offset, attempt, handleErr)
+
+	originalTopic, originalPartition, originalOffset := msg.Topic, strconv.Itoa(int(msg.Partition)), strconv.FormatInt(msg.Offset, 10)
+	// Messages from the retry topic keep the position of the original message
+	if topic := header(msg, HeaderOriginalTopic); topic != "" {
+		originalTopic, originalPartition, originalOffset = topic, header(msg, HeaderOriginalPartition), header(msg, HeaderOriginalOffset)
+	}
+
+	return kc.forwarder.Forward(kc.topic+dlqTopicSuffix, msg,
+		sarama.RecordHeader{Key: []byte(HeaderRetryAttempt), Value: []byte(strconv.Itoa(attempt))},
+		sarama.RecordHeader{Key: []byte(HeaderError), Value: []byte(handleErr.Error())},
+		sarama.RecordHeader{Key: []byte(HeaderOriginalTopic), Value: []byte(originalTopic)},
+		sarama.RecordHeader{Key: []byte(HeaderOriginalPartition), Value: []byte(originalPartition)},
+		sarama.RecordHeader{Key: []byte(HeaderOriginalOffset), Value: []byte(originalOffset)},
+	)
 }
 
-func (kc *KafkaConsumer) ReadMessages() error {
-	partitionList, err := kc.Consumer.Partitions(kc.topic)
+// waitRetry waits until the time in the retry-at header with fetching of the message's partition paused,
+// it returns false if ctx is done first
+func (kc *KafkaConsumer) waitRetry(ctx context.Context, msg *sarama.ConsumerMessage) bool {
+	retryAt, err := strconv.ParseInt(header(msg, HeaderRetryAt), 10, 64)
 	if err != nil {
-		return err
+		return true
 	}
 
-	for _, partition := range partitionList {
-		pc, err := kc.Consumer.ConsumePartition(kc.topic, partition, sarama.OffsetNewest)
-		if err != nil {
-			return err
+	delay := time.Until(time.UnixMilli(retryAt))
+	if delay <= 0 {
+		return true
+	}
+
+	partition := map[string][]int32{msg.Topic: {msg.Partition}}
+	kc.pauser.Pause(partition)
+	defer kc.pauser.Resume(partition)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// header returns the value of the message header with the given key
+func header(msg *sarama.ConsumerMessage, key string) string {
+	for _, h := range msg.Headers {
+		if h != nil && string(h.Key) == key {
+			return string(h.Value)
 		}
+	}
+	return ""
+}
 
-		defer pc.Close()
+// mergeHeaders copies headers replacing the ones with the same keys as in extra
+func mergeHeaders(headers []*sarama.RecordHeader, extra []sarama.RecordHeader) []sarama.RecordHeader {
+	replaced := make(map[string]bool, len(extra))
+	for _, h := range extra {
+		replaced[string(h.Key)] = true
+	}
 
-		for message := range pc.Messages() {
-			fmt.Printf("Message received: %s\n", string(message.Value))
+	merged := make([]sarama.RecordHeader, 0, len(headers)+len(extra))
+	for _, h := range headers {
+		if h != nil && !replaced[string(h.Key)] {
+			merged = append(merged, *h)
 		}
 	}
+	return append(merged, extra...)
+}
+
+// LogHandler logs decoded order events
+func LogHandler(_ context.Context, msg *sarama.ConsumerMessage) error {
+	eventType := models.EventType(header(msg, events.HeaderEventType))
+	event, err := events.Decode(eventType, msg.Value)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %w", eventType, err)
+	}
 
+	log.Printf("Message received: %s v%s: %s", eventType, header(msg, events.HeaderSchemaVersion), protojson.Format(event))
 	return nil
 }
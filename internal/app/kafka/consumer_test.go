@@ -0,0 +1,286 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/events"
+	"route/internal/app/models"
+)
+
+type fakeSession struct {
+	ctx    context.Context
+	marked []int64
+}
+
+func (s *fakeSession) Claims() map[string][]int32               { return nil }
+func (s *fakeSession) MemberID() string                         { return "" }
+func (s *fakeSession) GenerationID() int32                      { return 0 }
+func (s *fakeSession) MarkOffset(string, int32, int64, string)  {}
+func (s *fakeSession) Commit()                                  {}
+func (s *fakeSession) ResetOffset(string, int32, int64, string) {}
+func (s *fakeSession) Context() context.Context                 { return s.ctx }
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	s.marked = append(s.marked, msg.Offset)
+}
+
+type fakeClaim struct {
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Topic() string                            { return "orders" }
+func (c *fakeClaim) Partition() int32                         { return 0 }
+func (c *fakeClaim) InitialOffset() int64                     { return 0 }
+func (c *fakeClaim) HighWaterMarkOffset() int64               { return 0 }
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+type forwarded struct {
+	topic   string
+	offset  int64
+	headers map[string]string
+}
+
+type fakeForwarder struct {
+	forwarded []forwarded
+	err       error
+}
+
+func (f *fakeForwarder) Forward(topic string, msg *sarama.ConsumerMessage, extra ...sarama.RecordHeader) error {
+	if f.err != nil {
+		return f.err
+	}
+	headers := make(map[string]string)
+	for _, h := range mergeHeaders(msg.Headers, extra) {
+		headers[string(h.Key)] = string(h.Value)
+	}
+	f.forwarded = append(f.forwarded, forwarded{topic: topic, offset: msg.Offset, headers: headers})
+	return nil
+}
+
+func newMessage(offset int64, eventType models.EventType, headers ...*sarama.RecordHeader) *sarama.ConsumerMessage {
+	return &sarama.ConsumerMessage{
+		Topic:  "orders",
+		Offset: offset,
+		Headers: append([]*sarama.RecordHeader{
+			{Key: []byte(events.HeaderEventType), Value: []byte(eventType)},
+		}, headers...),
+	}
+}
+
+type fakePauser struct {
+	paused  []map[string][]int32
+	resumed []map[string][]int32
+}
+
+func (p *fakePauser) Pause(partitions map[string][]int32) {
+	p.paused = append(p.paused, partitions)
+}
+
+func (p *fakePauser) Resume(partitions map[string][]int32) {
+	p.resumed = append(p.resumed, partitions)
+}
+
+func newTestConsumer(forwarder Forwarder) *KafkaConsumer {
+	return &KafkaConsumer{
+		topic:     "orders",
+		handlers:  make(map[models.EventType]Handler),
+		forwarder: forwarder,
+		retry:     RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Second},
+		pauser:    &fakePauser{},
+	}
+}
+
+func TestKafkaConsumer_ConsumeClaim(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	forwarder := &fakeForwarder{}
+	kc := newTestConsumer(forwarder)
+	var handled []string
+	kc.Handle(models.OrderAccepted, func(_ context.Context, msg *sarama.ConsumerMessage) error {
+		handled = append(handled, "accepted")
+		return nil
+	})
+	kc.Handle(models.OrderIssued, func(_ context.Context, msg *sarama.ConsumerMessage) error {
+		handled = append(handled, "issued")
+		return errors.New("handler error")
+	})
+
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, 3)}
+	claim.messages <- newMessage(1, models.OrderAccepted)
+	claim.messages <- newMessage(2, models.OrderIssued)
+	claim.messages <- newMessage(3, models.ReturnAccepted)
+	close(claim.messages)
+	session := &fakeSession{ctx: context.Background()}
+
+	// act
+	err := kc.ConsumeClaim(session, claim)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"accepted", "issued"}, handled)
+	assert.Equal(t, []int64{1, 2, 3}, session.marked)
+	require.Len(t, forwarder.forwarded, 1)
+	assert.Equal(t, "orders.retry", forwarder.forwarded[0].topic)
+	assert.Equal(t, "2", forwarder.forwarded[0].headers[HeaderRetryAttempt])
+}
+
+func TestKafkaConsumer_Reschedule(t *testing.T) {
+	t.Parallel()
+
+	handleErr := errors.New("handler error")
+
+	t.Run("last attempt goes to dead-letter topic", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		forwarder := &fakeForwarder{}
+		kc := newTestConsumer(forwarder)
+		msg := newMessage(7, models.OrderIssued, &sarama.RecordHeader{Key: []byte(HeaderRetryAttempt), Value: []byte("3")})
+
+		// act
+		err := kc.reschedule(msg, handleErr)
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, forwarder.forwarded, 1)
+		dead := forwarder.forwarded[0]
+		assert.Equal(t, "orders.dlq", dead.topic)
+		assert.Equal(t, "handler error", dead.headers[HeaderError])
+		assert.Equal(t, "3", dead.headers[HeaderRetryAttempt])
+		assert.Equal(t, "orders", dead.headers[HeaderOriginalTopic])
+		assert.Equal(t, "7", dead.headers[HeaderOriginalOffset])
+		assert.Equal(t, string(models.OrderIssued), dead.headers[events.HeaderEventType])
+	})
+
+	t.Run("message is not committed if it can't be rescheduled", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		kc := newTestConsumer(&fakeForwarder{err: errors.New("kafka is down")})
+		kc.Handle(models.OrderIssued, func(context.Context, *sarama.ConsumerMessage) error {
+			return handleErr
+		})
+		claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, 1)}
+		claim.messages <- newMessage(1, models.OrderIssued)
+		session := &fakeSession{ctx: context.Background()}
+
+		// act
+		err := kc.ConsumeClaim(session, claim)
+
+		// assert
+		assert.EqualError(t, err, "kafka is down")
+		assert.Empty(t, session.marked)
+	})
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	t.Parallel()
+
+	policy := RetryPolicy{MaxAttempts: 10, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	assert.Equal(t, 100*time.Millisecond, policy.Backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.Backoff(2))
+	assert.Equal(t, 800*time.Millisecond, policy.Backoff(4))
+	assert.Equal(t, time.Second, policy.Backoff(5))
+	assert.Equal(t, time.Second, policy.Backoff(100))
+}
+
+func TestRetryPolicy_Do(t *testing.T) {
+	t.Parallel()
+
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	calls := 0
+
+	err := policy.Do(func() error {
+		calls++
+		return errors.New("failed")
+	})
+
+	assert.EqualError(t, err, "failed")
+	assert.Equal(t, 3, calls)
+}
+
+func TestKafkaConsumer_ConsumeClaimStopsOnCancel(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	kc := newTestConsumer(&fakeForwarder{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	session := &fakeSession{ctx: ctx}
+
+	// act
+	err := kc.ConsumeClaim(session, &fakeClaim{messages: make(chan *sarama.ConsumerMessage)})
+
+	// assert
+	require.NoError(t, err)
+	assert.Empty(t, session.marked)
+}
+
+func TestKafkaConsumer_WaitRetry(t *testing.T) {
+	t.Parallel()
+
+	// Retry times are taken when the subtest runs, parallel subtests may start late
+	retryIn := func(delay time.Duration) func() *sarama.RecordHeader {
+		return func() *sarama.RecordHeader {
+			at := time.Now().Add(delay)
+			return &sarama.RecordHeader{Key: []byte(HeaderRetryAt), Value: []byte(strconv.FormatInt(at.UnixMilli(), 10))}
+		}
+	}
+
+	tests := []struct {
+		name           string
+		header         func() *sarama.RecordHeader
+		expectedPaused bool
+	}{
+		{
+			name: "not a retry",
+			header: func() *sarama.RecordHeader {
+				return &sarama.RecordHeader{Key: []byte(HeaderRetryAttempt), Value: []byte("2")}
+			},
+		},
+		{
+			name:   "due retry",
+			header: retryIn(-time.Second),
+		},
+		{
+			name:           "delayed retry pauses its partition",
+			header:         retryIn(500 * time.Millisecond),
+			expectedPaused: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			kc := newTestConsumer(&fakeForwarder{})
+			pauser := &fakePauser{}
+			kc.pauser = pauser
+			msg := newMessage(1, models.OrderIssued, tt.header())
+			msg.Topic, msg.Partition = "orders.retry", 2
+
+			// act
+			ok := kc.waitRetry(context.Background(), msg)
+
+			// assert
+			assert.True(t, ok)
+			if !tt.expectedPaused {
+				assert.Empty(t, pauser.paused)
+				return
+			}
+			partition := map[string][]int32{"orders.retry": {2}}
+			assert.Equal(t, []map[string][]int32{partition}, pauser.paused)
+			assert.Equal(t, []map[string][]int32{partition}, pauser.resumed, "Partition is resumed after the delay")
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package kafka
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+	"route/internal/app/config"
+	"route/internal/app/events"
+	"route/internal/app/models"
+)
+
+// defaultReadTimeout limits reading of dead letters, a message may be removed by retention
+// after the offsets of the partition have been read
+const defaultReadTimeout = 10 * time.Second
+
+// DeadLetterQueue reads dead-letter topics of the consumed topics and replays their messages
+// to the topics they came from
+type DeadLetterQueue struct {
+	client    sarama.Client
+	consumer  sarama.Consumer
+	forwarder Forwarder
+	// topics are the consumed topics, the first one is the events topic
+	topics      []string
+	readTimeout time.Duration
+}
+
+func NewDeadLetterQueue(cfg config.KafkaConfig, forwarder Forwarder) (*DeadLetterQueue, error) {
+	client, err := sarama.NewClient(cfg.BrokerList, sarama.NewConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return nil, err
+	}
+
+	topics := []string{cfg.Topic}
+	if cfg.ManifestTopic != "" {
+		topics = append(topics, cfg.ManifestTopic)
+	}
+
+	return &DeadLetterQueue{
+		client:      client,
+		consumer:    consumer,
+		forwarder:   forwarder,
+		topics:      topics,
+		readTimeout: defaultReadTimeout,
+	}, nil
+}
+
+// dlqTopic returns the dead-letter topic of the consumed topic, empty topic means the events topic
+func (q *DeadLetterQueue) dlqTopic(topic string) (string, error) {
+	if topic == "" {
+		topic = q.topics[0]
+	}
+	for _, consumed := range q.topics {
+		if consumed == topic {
+			return topic + dlqTopicSuffix, nil
+		}
+	}
+	return "", fmt.Errorf("топик %s не читается сервисом", topic)
+}
+
+// List returns up to limit most recent messages of the dead-letter topic of the consumed topic,
+// limit is from 1 to models.MaxDeadLetters
+func (q *DeadLetterQueue) List(topic string, limit int) ([]models.DeadLetter, error) {
+	if limit <= 0 || limit > models.MaxDeadLetters {
+		return nil, fmt.Errorf("параметр limit должен быть от 1 до %d", models.MaxDeadLetters)
+	}
+
+	dlqTopic, err := q.dlqTopic(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	partitions, err := q.client.Partitions(dlqTopic)
+	if err != nil {
+		return nil, err
+	}
+
+	var letters []models.DeadLetter
+	for _, partition := range partitions {
+		oldest, newest, err := q.offsets(dlqTopic, partition)
+		if err != nil {
+			return nil, err
+		}
+		if newest == oldest {
+			continue
+		}
+
+		// Only the last limit messages of every partition can get to the result
+		from := max(oldest, newest-int64(limit))
+		messages, err := q.read(dlqTopic, partition, from, newest)
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range messages {
+			letters = append(letters, toDeadLetter(msg))
+		}
+	}
+
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i].Time.After(letters[j].Time)
+	})
+	if len(letters) > limit {
+		letters = letters[:limit]
+	}
+
+	return letters, nil
+}
+
+// Replay sends the dead-letter message at the given position back to the topic it came from
+// with a reset attempt counter. The message itself stays in the dead-letter topic.
+func (q *DeadLetterQueue) Replay(topic string, partition int32, offset int64) error {
+	dlqTopic, err := q.dlqTopic(topic)
+	if err != nil {
+		return err
+	}
+
+	oldest, newest, err := q.offsets(dlqTopic, partition)
+	if err != nil {
+		return err
+	}
+	if offset < oldest || offset >= newest {
+		return fmt.Errorf("сообщение %d/%d не найдено в %s", partition, offset, dlqTopic)
+	}
+
+	messages, err := q.read(dlqTopic, partition, offset, offset+1)
+	if err != nil {
+		return err
+	}
+	// The message could be removed after the offsets were read
+	if len(messages) == 0 || messages[0].Offset != offset {
+		return fmt.Errorf("сообщение %d/%d не найдено в %s", partition, offset, dlqTopic)
+	}
+
+	originalTopic := header(messages[0], HeaderOriginalTopic)
+	if originalTopic == "" {
+		originalTopic = dlqTopic[:len(dlqTopic)-len(dlqTopicSuffix)]
+	}
+	return q.forwarder.Forward(originalTopic, messages[0],
+		sarama.RecordHeader{Key: []byte(HeaderRetryAttempt), Value: []byte("1")},
+		sarama.RecordHeader{Key: []byte(HeaderRetryAt), Value: []byte("0")},
+	)
+}
+
+// offsets returns the oldest available and the next to be written offsets of the partition
+func (q *DeadLetterQueue) offsets(topic string, partition int32) (int64, int64, error) {
+	oldest, err := q.client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return 0, 0, err
+	}
+	newest, err := q.client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return 0, 0, err
+	}
+	return oldest, newest, nil
+}
+
+// read returns messages of the partition in [from, to). Reading stops after the read timeout,
+// messages removed by retention meanwhile are never received
+func (q *DeadLetterQueue) read(topic string, partition int32, from, to int64) ([]*sarama.ConsumerMessage, error) {
+	pc, err := q.consumer.ConsumePartition(topic, partition, from)
+	if err != nil {
+		return nil, err
+	}
+	defer pc.Close()
+
+	timeout := time.NewTimer(q.readTimeout)
+	defer timeout.Stop()
+
+	var messages []*sarama.ConsumerMessage
+	for {
+		select {
+		case msg, ok := <-pc.Messages():
+			if !ok {
+				return messages, nil
+			}
+			messages = append(messages, msg)
+			if msg.Offset >= to-1 {
+				return messages, nil
+			}
+		case <-timeout.C:
+			if len(messages) > 0 {
+				return messages, nil
+			}
+			return nil, fmt.Errorf("не удалось прочитать %s/%d с offset %d за %s", topic, partition, from, q.readTimeout)
+		}
+	}
+}
+
+func toDeadLetter(msg *sarama.ConsumerMessage) models.DeadLetter {
+	attempts, _ := strconv.Atoi(header(msg, HeaderRetryAttempt))
+	return models.DeadLetter{
+		Partition:     msg.Partition,
+		Offset:        msg.Offset,
+		Time:          msg.Timestamp,
+		Key:           string(msg.Key),
+		EventType:     models.EventType(header(msg, events.HeaderEventType)),
+		Error:         header(msg, HeaderError),
+		Attempts:      attempts,
+		OriginalTopic: header(msg, HeaderOriginalTopic),
+	}
+}
@@ -0,0 +1,109 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+)
+
+// newTestDeadLetterQueue reads the partition 0 of manifests.dlq served by a mock broker,
+// the partition has messages with offsets [0, newest) but only the first one can be fetched
+func newTestDeadLetterQueue(t *testing.T, forwarder Forwarder, newest int64) *DeadLetterQueue {
+	t.Helper()
+
+	broker := sarama.NewMockBroker(t, 1)
+	t.Cleanup(broker.Close)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("manifests.dlq", 0, broker.BrokerID()),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).
+			SetOffset("manifests.dlq", 0, sarama.OffsetOldest, 0).
+			SetOffset("manifests.dlq", 0, sarama.OffsetNewest, newest),
+		"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+			SetMessage("manifests.dlq", 0, 0, sarama.StringEncoder("manifest")).
+			SetHighWaterMark("manifests.dlq", 0, newest),
+	})
+
+	client, err := sarama.NewClient([]string{broker.Addr()}, sarama.NewConfig())
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	consumer, err := sarama.NewConsumerFromClient(client)
+	require.NoError(t, err)
+
+	return &DeadLetterQueue{
+		client:      client,
+		consumer:    consumer,
+		forwarder:   forwarder,
+		topics:      []string{"orders", "manifests"},
+		readTimeout: 200 * time.Millisecond,
+	}
+}
+
+func TestDeadLetterQueue_Replay(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	forwarder := &fakeForwarder{}
+	dlq := newTestDeadLetterQueue(t, forwarder, 1)
+
+	// act
+	err := dlq.Replay("manifests", 0, 0)
+	unknownErr := dlq.Replay("payments", 0, 0)
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, forwarder.forwarded, 1)
+	assert.Equal(t, "manifests", forwarder.forwarded[0].topic, "Dead letter is replayed to the topic it came from")
+	assert.Equal(t, "1", forwarder.forwarded[0].headers[HeaderRetryAttempt])
+	assert.EqualError(t, unknownErr, "топик payments не читается сервисом")
+}
+
+func TestDeadLetterQueue_ListInvalidLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		limit int
+	}{
+		{name: "zero", limit: 0},
+		{name: "negative", limit: -1},
+		{name: "over maximum", limit: models.MaxDeadLetters + 1},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			dlq := &DeadLetterQueue{topics: []string{"orders"}}
+
+			// act
+			letters, err := dlq.List("orders", tt.limit)
+
+			// assert
+			assert.EqualError(t, err, "параметр limit должен быть от 1 до 1000")
+			assert.Empty(t, letters)
+		})
+	}
+}
+
+func TestDeadLetterQueue_ListStopsOnTimeout(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	dlq := newTestDeadLetterQueue(t, &fakeForwarder{}, 3)
+
+	// act
+	letters, err := dlq.List("manifests", 10)
+
+	// assert
+	require.NoError(t, err, "Messages removed after the offsets were read don't block the list")
+	require.Len(t, letters, 1)
+	assert.Equal(t, int64(0), letters[0].Offset)
+}
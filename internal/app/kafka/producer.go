@@ -1,21 +1,27 @@
 package kafka
 
 import (
+	"strconv"
+
 	"github.com/IBM/sarama"
 	"github.com/pkg/errors"
 	"route/internal/app/config"
+	"route/internal/app/events"
+	"route/internal/app/models"
 )
 
 type KafkaProducer struct {
 	brokers  []string
 	producer sarama.SyncProducer
 	topic    string
+	retry    RetryPolicy
 }
 
 func NewKafkaProducer(cfg config.KafkaConfig) (*KafkaProducer, error) {
 	producerConfig := sarama.NewConfig()
 
-	producerConfig.Producer.Partitioner = sarama.NewRandomPartitioner
+	// Messages are keyed by order ID, so events of one order keep their order within a partition
+	producerConfig.Producer.Partitioner = sarama.NewHashPartitioner
 
 	producerConfig.Producer.RequiredAcks = sarama.WaitForLocal
 
@@ -38,14 +44,43 @@ func NewKafkaProducer(cfg config.KafkaConfig) (*KafkaProducer, error) {
 		brokers:  cfg.BrokerList,
 		producer: syncProducer,
 		topic:    cfg.Topic,
+		retry:    NewRetryPolicy(cfg.Retry),
 	}, nil
 }
 
-func (kp *KafkaProducer) SendMessage(message []byte) error {
+// SendEvent sends an order event keyed by order ID with its type and schema version in headers
+func (kp *KafkaProducer) SendEvent(event models.OutboxMessage) error {
 	msg := &sarama.ProducerMessage{
 		Topic: kp.topic,
-		Value: sarama.ByteEncoder(message),
+		Key:   sarama.StringEncoder(strconv.Itoa(event.OrderID)),
+		Value: sarama.ByteEncoder(event.Payload),
+		Headers: []sarama.RecordHeader{
+			{Key: []byte(events.HeaderEventType), Value: []byte(event.EventType)},
+			{Key: []byte(events.HeaderSchemaVersion), Value: []byte(strconv.Itoa(event.SchemaVersion))},
+			{Key: []byte(events.HeaderEventID), Value: []byte(event.EventID)},
+		},
+	}
+	return kp.send(msg)
+}
+
+// Forward sends a consumed message to the topic keeping its key, value and headers.
+// Headers with the same keys as in extra are replaced.
+func (kp *KafkaProducer) Forward(topic string, message *sarama.ConsumerMessage, extra ...sarama.RecordHeader) error {
+	msg := &sarama.ProducerMessage{
+		Topic:   topic,
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: mergeHeaders(message.Headers, extra),
+	}
+	if message.Key != nil {
+		msg.Key = sarama.ByteEncoder(message.Key)
 	}
-	_, _, err := kp.producer.SendMessage(msg)
-	return err
+	return kp.send(msg)
+}
+
+// send sends the message retrying with exponential backoff
+func (kp *KafkaProducer) send(msg *sarama.ProducerMessage) error {
+	return kp.retry.Do(func() error {
+		_, _, err := kp.producer.SendMessage(msg)
+		return err
+	})
 }
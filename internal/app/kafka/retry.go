@@ -0,0 +1,49 @@
+package kafka
+
+import (
+	"time"
+
+	"route/internal/app/config"
+)
+
+// RetryPolicy is an exponential backoff policy: the delay before the n-th retry
+// is InitialBackoff * 2^(n-1), but not more than MaxBackoff
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func NewRetryPolicy(cfg config.RetryConfig) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    cfg.MaxAttempts,
+		InitialBackoff: cfg.InitialBackoff,
+		MaxBackoff:     cfg.MaxBackoff,
+	}
+}
+
+// Backoff returns the delay after the given failed attempt, attempts start from 1
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if backoff >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return min(backoff, p.MaxBackoff)
+}
+
+// Do calls fx until it succeeds or MaxAttempts is reached, it returns the last error
+func (p RetryPolicy) Do(fx func() error) error {
+	var err error
+	for attempt := 1; attempt <= p.MaxAttempts; attempt++ {
+		if err = fx(); err == nil {
+			return nil
+		}
+		if attempt < p.MaxAttempts {
+			time.Sleep(p.Backoff(attempt))
+		}
+	}
+	return err
+}
@@ -0,0 +1,125 @@
+package manifest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"route/internal/app/models"
+)
+
+// Formats of exported return manifests
+const (
+	FormatText = "text"
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+type returnManifestJSON struct {
+	ID          int64             `json:"id"`
+	CourierID   int               `json:"courier_id"`
+	CreatedAt   time.Time         `json:"created_at"`
+	TotalWeight float64           `json:"total_weight"`
+	Orders      []returnOrderJSON `json:"orders"`
+}
+
+type returnOrderJSON struct {
+	OrderID  int       `json:"order_id"`
+	UserID   int       `json:"user_id"`
+	Deadline time.Time `json:"deadline"`
+	Weight   float64   `json:"weight"`
+	Cost     float64   `json:"cost"`
+}
+
+// ExportReturnManifest writes the manifest in the given format,
+// the text format is a handover act for the courier to sign
+func ExportReturnManifest(w io.Writer, manifest models.ReturnManifest, format string) error {
+	switch format {
+	case FormatText:
+		return exportText(w, manifest)
+	case FormatCSV:
+		return exportCSV(w, manifest)
+	case FormatJSON:
+		return exportJSON(w, manifest)
+	default:
+		return fmt.Errorf("неизвестный формат: %s", format)
+	}
+}
+
+func totalWeight(manifest models.ReturnManifest) float64 {
+	total := 0.0
+	for _, order := range manifest.Orders {
+		total += order.Weight
+	}
+	return total
+}
+
+func exportText(w io.Writer, manifest models.ReturnManifest) error {
+	fmt.Fprintf(w, "АКТ ВОЗВРАТА ЗАКАЗОВ КУРЬЕРУ № %d\n", manifest.ID)
+	fmt.Fprintf(w, "Дата: %s\n", manifest.CreatedAt.Format("02.01.2006 15:04"))
+	fmt.Fprintf(w, "ID курьера: %d\n\n", manifest.CourierID)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "№\tID заказа\tID клиента\tСрок хранения\tВес, кг\tСтоимость")
+	for i, order := range manifest.Orders {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%s\t%.2f\t%.2f\n",
+			i+1, order.OrderID, order.UserID, order.Deadline.Format("02.01.2006"), order.Weight, order.Cost)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nВсего заказов: %d, общий вес: %.2f кг\n\n", len(manifest.Orders), totalWeight(manifest))
+	_, err := fmt.Fprintln(w, "Сдал: ____________________    Принял курьер: ____________________")
+	return err
+}
+
+func exportCSV(w io.Writer, manifest models.ReturnManifest) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"manifest_id", "courier_id", "order_id", "user_id", "deadline", "weight", "cost"}); err != nil {
+		return err
+	}
+	for _, order := range manifest.Orders {
+		err := cw.Write([]string{
+			strconv.FormatInt(manifest.ID, 10),
+			strconv.Itoa(manifest.CourierID),
+			strconv.Itoa(order.OrderID),
+			strconv.Itoa(order.UserID),
+			order.Deadline.Format(time.RFC3339),
+			strconv.FormatFloat(order.Weight, 'f', -1, 64),
+			strconv.FormatFloat(order.Cost, 'f', -1, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func exportJSON(w io.Writer, manifest models.ReturnManifest) error {
+	out := returnManifestJSON{
+		ID:          manifest.ID,
+		CourierID:   manifest.CourierID,
+		CreatedAt:   manifest.CreatedAt,
+		TotalWeight: totalWeight(manifest),
+		Orders:      make([]returnOrderJSON, 0, len(manifest.Orders)),
+	}
+	for _, order := range manifest.Orders {
+		out.Orders = append(out.Orders, returnOrderJSON{
+			OrderID:  order.OrderID,
+			UserID:   order.UserID,
+			Deadline: order.Deadline,
+			Weight:   order.Weight,
+			Cost:     order.Cost,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,90 @@
+package manifest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+)
+
+func TestExportReturnManifest(t *testing.T) {
+	t.Parallel()
+
+	deadline := time.Date(2024, 7, 27, 0, 0, 0, 0, time.UTC)
+	returnManifest := models.ReturnManifest{
+		ID:        7,
+		CourierID: 3,
+		CreatedAt: time.Date(2024, 7, 29, 10, 0, 0, 0, time.UTC),
+		Orders: []models.Order{
+			{OrderID: 1, UserID: 10, Deadline: deadline, Weight: 1.5, Cost: 100},
+			{OrderID: 2, UserID: 11, Deadline: deadline, Weight: 2, Cost: 50},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		format        string
+		expected      []string
+		expectedError string
+	}{
+		{
+			name:   "csv",
+			format: FormatCSV,
+			expected: []string{
+				"manifest_id,courier_id,order_id,user_id,deadline,weight,cost",
+				"7,3,1,10,2024-07-27T00:00:00Z,1.5,100",
+				"7,3,2,11,2024-07-27T00:00:00Z,2,50",
+			},
+		},
+		{
+			name:   "json",
+			format: FormatJSON,
+			expected: []string{
+				`"id": 7,`,
+				`"total_weight": 3.5,`,
+				`"order_id": 2,`,
+			},
+		},
+		{
+			name:   "text",
+			format: FormatText,
+			expected: []string{
+				"АКТ ВОЗВРАТА ЗАКАЗОВ КУРЬЕРУ № 7",
+				"Всего заказов: 2, общий вес: 3.50 кг",
+				"Принял курьер:",
+			},
+		},
+		{
+			name:          "unknown format",
+			format:        "pdf",
+			expectedError: "неизвестный формат: pdf",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			var buf bytes.Buffer
+
+			// act
+			err := ExportReturnManifest(&buf, returnManifest, tt.format)
+
+			// assert
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			for _, line := range tt.expected {
+				assert.True(t, strings.Contains(buf.String(), line), "expected %q in\n%s", line, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"google.golang.org/protobuf/proto"
+	"route/internal/app/events"
+	"route/internal/app/models"
+	"route/internal/app/module"
+	"route/internal/app/repository"
+	"route/internal/app/repository/postgresql"
+	eventspb "route/pkg/api/proto/events/v1/events/v1"
+)
+
+type OrderReader interface {
+	GetOrderByID(orderID int) (*models.Order, error)
+}
+
+type AckPublisher interface {
+	SendEvent(msg models.OutboxMessage) error
+}
+
+// Ingestor accepts orders from courier manifests published by the marketplace.
+// Every item is validated by OrderModule.AcceptOrder and acknowledged with
+// a ManifestItemAccepted or ManifestItemRejected event.
+//
+// The result of every item is recorded, so a redelivered manifest is acknowledged
+// again with the same results instead of accepting its orders twice.
+type Ingestor struct {
+	mod       module.Module
+	orders    OrderReader
+	repo      repository.ManifestRepository
+	publisher AckPublisher
+	actor     models.Actor
+}
+
+func NewIngestor(mod module.Module, orders OrderReader, repo repository.ManifestRepository, publisher AckPublisher) *Ingestor {
+	return &Ingestor{
+		mod:       mod,
+		orders:    orders,
+		repo:      repo,
+		publisher: publisher,
+		actor:     models.NewActor(models.ActorKafka, "courier-manifest"),
+	}
+}
+
+// Handle is a Kafka handler for CourierManifest messages
+func (i *Ingestor) Handle(_ context.Context, msg *sarama.ConsumerMessage) error {
+	var manifest eventspb.CourierManifest
+	if err := proto.Unmarshal(msg.Value, &manifest); err != nil {
+		return fmt.Errorf("failed to decode manifest: %w", err)
+	}
+
+	return i.Ingest(&manifest)
+}
+
+// Ingest processes all items of the manifest, it stops on the first infrastructure error
+// so the manifest can be redelivered
+func (i *Ingestor) Ingest(manifest *eventspb.CourierManifest) error {
+	if manifest.GetManifestId() == "" {
+		return errors.New("manifest without ID")
+	}
+
+	for _, item := range manifest.GetItems() {
+		if err := i.ingestItem(manifest.GetManifestId(), item); err != nil {
+			return fmt.Errorf("manifest %s, order %d: %w", manifest.GetManifestId(), item.GetOrderId(), err)
+		}
+	}
+
+	return nil
+}
+
+func (i *Ingestor) ingestItem(manifestID string, item *eventspb.ManifestItem) error {
+	processed, err := i.repo.GetManifestItem(manifestID, int(item.GetOrderId()))
+	if err != nil && !errors.Is(err, postgresql.ErrManifestItemNotFound) {
+		return err
+	}
+
+	if processed == nil {
+		processed, err = i.accept(manifestID, item)
+		if err != nil {
+			return err
+		}
+
+		if err = i.repo.SaveManifestItem(*processed); err != nil {
+			return err
+		}
+	}
+
+	ack, err := events.NewManifestAck(*processed, i.actor)
+	if err != nil {
+		return err
+	}
+
+	return i.publisher.SendEvent(*ack)
+}
+
+// accept accepts the order from the manifest item, business rule violations reject the item
+func (i *Ingestor) accept(manifestID string, item *eventspb.ManifestItem) (*models.ManifestItem, error) {
+	order := &models.Order{
+		OrderID:  int(item.GetOrderId()),
+		UserID:   int(item.GetUserId()),
+		Deadline: item.GetDeadline().AsTime(),
+		Weight:   item.GetWeight(),
+		Cost:     item.GetCost(),
+	}
+	result := &models.ManifestItem{ManifestID: manifestID, OrderID: order.OrderID, Accepted: true}
+
+	err := i.mod.AcceptOrder(order, models.ToPackageType(item.GetPackagingType()))
+	if err == nil {
+		return result, nil
+	}
+
+	var validationErr module.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil, err
+	}
+
+	// The order may be accepted by a previous delivery of the manifest which failed before its result was recorded
+	existing, getErr := i.orders.GetOrderByID(order.OrderID)
+	if getErr == nil && existing != nil && existing.UserID == order.UserID {
+		return result, nil
+	}
+
+	result.Accepted = false
+	result.Reason = err.Error()
+	return result, nil
+}
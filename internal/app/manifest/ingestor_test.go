@@ -0,0 +1,148 @@
+package manifest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"route/internal/app/models"
+	"route/internal/app/module"
+	mockrepository "route/internal/app/repository/mocks"
+	"route/internal/app/repository/postgresql"
+	eventspb "route/pkg/api/proto/events/v1/events/v1"
+)
+
+type fakePublisher struct {
+	sent []models.OutboxMessage
+}
+
+func (p *fakePublisher) SendEvent(msg models.OutboxMessage) error {
+	p.sent = append(p.sent, msg)
+	return nil
+}
+
+func testManifest(deadline time.Time) *eventspb.CourierManifest {
+	return &eventspb.CourierManifest{
+		ManifestId: "m-1",
+		Items: []*eventspb.ManifestItem{{
+			OrderId:       1,
+			UserId:        2,
+			Deadline:      timestamppb.New(deadline),
+			Weight:        3,
+			Cost:          100,
+			PackagingType: string(models.Box),
+		}},
+	}
+}
+
+func TestIngest(t *testing.T) {
+	t.Parallel()
+
+	infraErr := errors.New("connection refused")
+	future := time.Now().Add(24 * time.Hour)
+	past := time.Now().Add(-24 * time.Hour)
+
+	tests := []struct {
+		name         string
+		deadline     time.Time
+		setup        func(orders *mockrepository.MockRepository, repo *mockrepository.MockManifestRepository)
+		expectedErr  error
+		expectedType models.EventType
+	}{
+		{
+			name:     "new item is accepted",
+			deadline: future,
+			setup: func(orders *mockrepository.MockRepository, repo *mockrepository.MockManifestRepository) {
+				repo.EXPECT().GetManifestItem("m-1", 1).Return(nil, postgresql.ErrManifestItemNotFound)
+				orders.EXPECT().GetOrderByID(1).Return(nil, postgresql.ErrOrderNotFound)
+				orders.EXPECT().AcceptOrder(gomock.Any(), gomock.Any()).Return(nil)
+				repo.EXPECT().SaveManifestItem(models.ManifestItem{ManifestID: "m-1", OrderID: 1, Accepted: true}).Return(nil)
+			},
+			expectedType: models.ManifestItemAccepted,
+		},
+		{
+			name:     "validation error rejects item",
+			deadline: past,
+			setup: func(orders *mockrepository.MockRepository, repo *mockrepository.MockManifestRepository) {
+				repo.EXPECT().GetManifestItem("m-1", 1).Return(nil, postgresql.ErrManifestItemNotFound)
+				orders.EXPECT().GetOrderByID(1).Return(nil, postgresql.ErrOrderNotFound).Times(2)
+				repo.EXPECT().SaveManifestItem(models.ManifestItem{
+					ManifestID: "m-1",
+					OrderID:    1,
+					Reason:     "срок хранения не может быть в прошлом",
+				}).Return(nil)
+			},
+			expectedType: models.ManifestItemRejected,
+		},
+		{
+			name:     "processed item is acknowledged again",
+			deadline: future,
+			setup: func(_ *mockrepository.MockRepository, repo *mockrepository.MockManifestRepository) {
+				repo.EXPECT().GetManifestItem("m-1", 1).Return(&models.ManifestItem{ManifestID: "m-1", OrderID: 1, Accepted: true}, nil)
+			},
+			expectedType: models.ManifestItemAccepted,
+		},
+		{
+			name:     "order accepted by interrupted delivery",
+			deadline: future,
+			setup: func(orders *mockrepository.MockRepository, repo *mockrepository.MockManifestRepository) {
+				repo.EXPECT().GetManifestItem("m-1", 1).Return(nil, postgresql.ErrManifestItemNotFound)
+				orders.EXPECT().GetOrderByID(1).Return(&models.Order{OrderID: 1, UserID: 2}, nil).Times(2)
+				repo.EXPECT().SaveManifestItem(models.ManifestItem{ManifestID: "m-1", OrderID: 1, Accepted: true}).Return(nil)
+			},
+			expectedType: models.ManifestItemAccepted,
+		},
+		{
+			name:     "infrastructure error stops manifest",
+			deadline: future,
+			setup: func(orders *mockrepository.MockRepository, repo *mockrepository.MockManifestRepository) {
+				repo.EXPECT().GetManifestItem("m-1", 1).Return(nil, postgresql.ErrManifestItemNotFound)
+				orders.EXPECT().GetOrderByID(1).Return(nil, infraErr)
+			},
+			expectedErr: infraErr,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			orders := mockrepository.NewMockRepository(ctrl)
+			repo := mockrepository.NewMockManifestRepository(ctrl)
+			publisher := &fakePublisher{}
+			tt.setup(orders, repo)
+			ingestor := NewIngestor(module.New(orders), orders, repo, publisher)
+
+			// act
+			err := ingestor.Ingest(testManifest(tt.deadline))
+
+			// assert
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Empty(t, publisher.sent)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, publisher.sent, 1)
+			assert.Equal(t, tt.expectedType, publisher.sent[0].EventType)
+			assert.Equal(t, 1, publisher.sent[0].OrderID)
+		})
+	}
+}
+
+func TestIngest_WithoutID(t *testing.T) {
+	t.Parallel()
+
+	ingestor := NewIngestor(nil, nil, nil, &fakePublisher{})
+
+	err := ingestor.Ingest(&eventspb.CourierManifest{})
+
+	assert.EqualError(t, err, "manifest without ID")
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Authorization is a power of attorney: the client authorizes another person
+// to collect one order or all their orders until ValidUntil
+type Authorization struct {
+	ID         int64
+	UserID     int
+	Recipient  string
+	OrderID    int // zero if all orders of the client are authorized
+	ValidUntil time.Time
+	CreatedAt  time.Time
+}
+
+// Allows checks whether the authorization allows the recipient to collect the order at the moment
+func (a Authorization) Allows(order Order, recipient string, at time.Time) bool {
+	return a.UserID == order.UserID &&
+		(a.OrderID == 0 || a.OrderID == order.OrderID) &&
+		strings.EqualFold(strings.TrimSpace(a.Recipient), strings.TrimSpace(recipient)) &&
+		at.Before(a.ValidUntil)
+}
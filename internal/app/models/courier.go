@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Courier is a person delivering orders to the pickup point on behalf of a delivery company
+type Courier struct {
+	ID        int
+	Name      string
+	Company   string
+	CreatedAt time.Time
+}
+
+// AcceptanceSession is a delivery of orders by a courier, orders accepted during it are linked to it.
+// ExpectedCount is the number of orders declared by the courier, zero if unknown
+type AcceptanceSession struct {
+	ID            int64
+	CourierID     int
+	ExpectedCount int
+	OpenedAt      time.Time
+	ClosedAt      time.Time
+}
+
+// Closed reports whether no more orders can be accepted in the session
+func (s AcceptanceSession) Closed() bool {
+	return !s.ClosedAt.IsZero()
+}
+
+// SessionRejection is an order of the session that failed acceptance checks
+type SessionRejection struct {
+	OrderID int
+	Reason  string
+}
+
+// SessionSummary sums up a closed session
+type SessionSummary struct {
+	Session     AcceptanceSession
+	Accepted    int
+	TotalWeight float64
+	Rejections  []SessionRejection
+}
+
+// Missing returns how many declared orders were not accepted, negative if more were accepted than declared
+func (s SessionSummary) Missing() int {
+	if s.Session.ExpectedCount == 0 {
+		return 0
+	}
+	return s.Session.ExpectedCount - s.Accepted
+}
+
+// HasDiscrepancies reports whether the accepted orders differ from the declared ones
+func (s SessionSummary) HasDiscrepancies() bool {
+	return s.Missing() != 0 || len(s.Rejections) > 0
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// MaxDeadLetters is the largest number of dead letters listed at once
+const MaxDeadLetters = 1000
+
+// DeadLetter is a message that could not be handled and was moved to the dead-letter topic
+type DeadLetter struct {
+	Partition     int32
+	Offset        int64
+	Time          time.Time
+	Key           string
+	EventType     EventType
+	Error         string
+	Attempts      int
+	OriginalTopic string
+}
@@ -1,21 +1,55 @@
 package models
 
-import (
-	"strings"
-	"time"
+// EventType is a type of change of an order
+type EventType string
+
+const (
+	OrderAccepted          EventType = "OrderAccepted"
+	OrderIssued            EventType = "OrderIssued"
+	OrderReturnedToCourier EventType = "OrderReturnedToCourier"
+	ReturnAccepted         EventType = "ReturnAccepted"
+	OrderExpired           EventType = "OrderExpired"
+	OrderRefused           EventType = "OrderRefused"
+	OrderLocked            EventType = "OrderLocked"
+	OrderUnlocked          EventType = "OrderUnlocked"
+	OrderInTransit         EventType = "OrderInTransit"
+	OrderTransferred       EventType = "OrderTransferred"
+
+	CourierManifest      EventType = "CourierManifest"
+	ManifestItemAccepted EventType = "ManifestItemAccepted"
+	ManifestItemRejected EventType = "ManifestItemRejected"
 )
 
-// Event содержит информацию о событии
-type Event struct {
-	Time    string `json:"time"`
-	Command string `json:"command"`
-	Args    string `json:"args"`
+// ActorType is a type of the initiator of a change
+type ActorType string
+
+const (
+	ActorCLI    ActorType = "cli"
+	ActorGRPC   ActorType = "grpc"
+	ActorKafka  ActorType = "kafka"
+	ActorSystem ActorType = "system"
+)
+
+// Actor is the initiator of a change of an order
+type Actor struct {
+	Type ActorType
+	ID   string
 }
 
-func NewEvent(commandName string, args []string) *Event {
-	return &Event{
-		Time:    time.Now().Format(time.RFC3339),
-		Command: commandName,
-		Args:    strings.Join(args, " "),
+func NewActor(actorType ActorType, id string) Actor {
+	return Actor{
+		Type: actorType,
+		ID:   id,
 	}
 }
+
+// OutboxMessage is a serialized event stored in the outbox table until it is published
+type OutboxMessage struct {
+	ID            int64
+	EventID       string
+	EventType     EventType
+	SchemaVersion int
+	OrderID       int
+	Payload       []byte
+	Attempts      int
+}
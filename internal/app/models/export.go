@@ -0,0 +1,26 @@
+package models
+
+// ExportKind is the list of orders to export
+type ExportKind string
+
+const (
+	// OrdersExport exports the most recent orders like list-orders
+	OrdersExport ExportKind = "orders"
+	// ReturnsExport exports returned orders like list-returns
+	ReturnsExport ExportKind = "returns"
+)
+
+// Valid reports whether the kind is one of the known export kinds
+func (k ExportKind) Valid() bool {
+	return k == OrdersExport || k == ReturnsExport
+}
+
+// ExportFilter selects orders to export with the same filters as listing.
+// Zero UserID exports orders of all users, zero LastN or PageSize exports all matching orders
+type ExportFilter struct {
+	Kind     ExportKind
+	UserID   int
+	LastN    int
+	Page     int
+	PageSize int
+}
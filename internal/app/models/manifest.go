@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// ManifestItem is a processed order from a courier manifest
+type ManifestItem struct {
+	ManifestID string
+	OrderID    int
+	Accepted   bool
+	Reason     string
+}
+
+// ReturnManifest is a batch of expired orders handed over to a courier for return
+type ReturnManifest struct {
+	ID        int64
+	CourierID int
+	CreatedAt time.Time
+	Orders    []Order
+}
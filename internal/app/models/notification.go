@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// NotificationKind is a reason to notify a client about an order
+type NotificationKind string
+
+const (
+	OrderArrived         NotificationKind = "order_arrived"
+	ExpiryReminder       NotificationKind = "expiry_reminder"
+	ReturnAcceptedNotice NotificationKind = "return_accepted"
+)
+
+// Locales of notification templates
+const (
+	LocaleRu = "ru"
+	LocaleEn = "en"
+)
+
+// Contact is how a client is notified, empty fields are not used
+type Contact struct {
+	UserID int
+	Phone  string
+	Email  string
+	Locale string
+}
+
+// Notification is a recorded notification sent to a client through a channel
+type Notification struct {
+	OrderID   int
+	UserID    int
+	Kind      NotificationKind
+	Channel   string
+	Error     string
+	CreatedAt time.Time
+}
@@ -15,9 +15,45 @@ type Order struct {
 	ReceivedFromCourier bool
 	IsReturned          bool
 	IssuedAt            time.Time
-	Hash                string
-	Cost                float64
-	Weight              float64
+	ExpiredAt           time.Time
+	ReturnedToCourierAt time.Time
+	SessionID           int64
+	RefusedAt           time.Time
+	RefusalReason       string
+	PickupCodeHash      string
+	PickupFailures      int
+	PickupLastFailureAt time.Time
+	PickupLockedAt      time.Time
+	IssueOverrideBy     string
+	ReceivedBy          string
+	AuthorizationID     int64
+	PickupPointID       int
+	TransferID          int64 // transfer the order is in transit with, zero if it is not moved
+	CellID              int   // storage cell of the order, zero if the point has no cells or the order left the shelf
+	Cell                string
+
+	// PickupCode is the plain pickup code, it is set only right after acceptance and never stored
+	PickupCode string
+	Hash       string
+	Cost       float64
+	Weight     float64
+}
+
+// Status is the state of the order: stored, in_transit, issued, returned, refused or returned_to_courier
+func (o Order) Status() string {
+	switch {
+	case !o.ReturnedToCourierAt.IsZero():
+		return "returned_to_courier"
+	case !o.RefusedAt.IsZero():
+		return "refused"
+	case o.IsReturned:
+		return "returned"
+	case o.IssuedToUser:
+		return "issued"
+	case o.TransferID != 0:
+		return "in_transit"
+	}
+	return "stored"
 }
 
 func NewOrder(orderID, userID int, deadline time.Time, cost float64, weight float64) *Order {
@@ -35,3 +71,26 @@ func NewOrder(orderID, userID int, deadline time.Time, cost float64, weight floa
 		Weight:              weight,
 	}
 }
+
+// AcceptRequest is an order to accept with its packaging, e.g. a row of an imported file
+type AcceptRequest struct {
+	Order         *Order
+	PackagingType PackageType
+}
+
+// IssueRequest is a request to issue the order to the client who presented the pickup code.
+// A manager can issue the order without the code, OverrideBy is the manager then.
+// Recipient is the person collecting the order by the client's authorization, empty if the client collects it
+type IssueRequest struct {
+	OrderID    int
+	PickupCode string
+	OverrideBy string
+	Recipient  string
+}
+
+// IssueRecord is who issued and who received the order, it is stored with the issued order
+type IssueRecord struct {
+	OverrideBy      string
+	ReceivedBy      string
+	AuthorizationID int64
+}
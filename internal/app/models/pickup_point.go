@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// DefaultPickupPointID is the pickup point of orders accepted without a point
+const DefaultPickupPointID = 1
+
+// PickupPoint is a pickup point of the network
+type PickupPoint struct {
+	ID        int
+	Name      string
+	Address   string
+	CreatedAt time.Time
+}
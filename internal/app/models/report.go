@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Surcharges are the packaging surcharges included in the cost of accepted orders
+var Surcharges = map[PackageType]float64{
+	Package: PackageCost,
+	Box:     BoxCost,
+	Film:    FilmCost,
+}
+
+// ReportRow aggregates orders of one day and packaging type. Surcharges are earned when orders are issued,
+// refunds are the costs of orders returned by clients
+type ReportRow struct {
+	Day              time.Time
+	PackagingType    PackageType
+	Accepted         int
+	Issued           int
+	Returned         int
+	SurchargeRevenue float64
+	Refunds          float64
+}
+
+// Report is the shift report of the pickup point for the days from From to To inclusive
+type Report struct {
+	PickupPointID int
+	From          time.Time
+	To            time.Time
+	Rows          []ReportRow
+}
+
+// Total sums up all rows of the report
+func (r Report) Total() ReportRow {
+	var total ReportRow
+	for _, row := range r.Rows {
+		total.Accepted += row.Accepted
+		total.Issued += row.Issued
+		total.Returned += row.Returned
+		total.SurchargeRevenue += row.SurchargeRevenue
+		total.Refunds += row.Refunds
+	}
+	return total
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// DiscrepancyKind is the kind of mismatch between the shelves and the database found by a stocktake
+type DiscrepancyKind string
+
+const (
+	// Missing order is stored at the point per the database, but was not scanned
+	Missing DiscrepancyKind = "missing"
+	// Unexpected order was scanned, but is not stored at the point per the database
+	Unexpected DiscrepancyKind = "unexpected"
+	// WronglyIssued order was scanned, but is issued to the client per the database
+	WronglyIssued DiscrepancyKind = "wrongly_issued"
+)
+
+// Discrepancy is an order that doesn't match the database
+type Discrepancy struct {
+	OrderID int
+	Kind    DiscrepancyKind
+}
+
+// Stocktake is a report comparing orders scanned on the shelves of the pickup point with the database
+type Stocktake struct {
+	ID            int64
+	PickupPointID int
+	Scanned       int
+	Stored        int
+	CreatedAt     time.Time
+	Discrepancies []Discrepancy
+}
+
+// Count returns the number of discrepancies of the kind
+func (s Stocktake) Count(kind DiscrepancyKind) int {
+	count := 0
+	for _, d := range s.Discrepancies {
+		if d.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// CellSize is the size class of a storage cell, a larger cell fits everything a smaller one does
+type CellSize int
+
+const (
+	CellSmall CellSize = iota + 1
+	CellMedium
+	CellLarge
+)
+
+func (s CellSize) String() string {
+	switch s {
+	case CellSmall:
+		return "S"
+	case CellMedium:
+		return "M"
+	case CellLarge:
+		return "L"
+	}
+	return ""
+}
+
+// ToCellSize parses the size class written as S, M or L, it returns zero for an unknown size
+func ToCellSize(s string) CellSize {
+	for _, size := range []CellSize{CellSmall, CellMedium, CellLarge} {
+		if size.String() == s {
+			return size
+		}
+	}
+	return 0
+}
+
+// CellSizeFor returns the smallest cell size the order in the packaging fits into
+func CellSizeFor(packagingType PackageType) CellSize {
+	switch packagingType {
+	case Box:
+		return CellLarge
+	case Film:
+		return CellMedium
+	}
+	return CellSmall
+}
+
+// Rack is a rack of storage cells at a pickup point
+type Rack struct {
+	ID            int
+	PickupPointID int
+	Name          string
+	CreatedAt     time.Time
+	Cells         []StorageCell
+}
+
+// StorageCell is a place on a rack for one order
+type StorageCell struct {
+	ID        int
+	RackID    int
+	Code      string
+	Size      CellSize
+	MaxWeight float64
+	OrderID   int // order stored in the cell, zero if the cell is free
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Transfer is a move of orders from one pickup point to another.
+// Orders are in transit from creation of the transfer until it is received
+type Transfer struct {
+	ID          int64
+	FromPointID int
+	ToPointID   int
+	CreatedAt   time.Time
+	ReceivedAt  time.Time
+	Orders      []Order
+}
+
+// Received reports whether the orders have arrived at the destination point
+func (t Transfer) Received() bool {
+	return !t.ReceivedAt.IsZero()
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// WebhookSubscription is a partner endpoint that receives order events of the listed types
+type WebhookSubscription struct {
+	ID         int64
+	URL        string
+	EventTypes []EventType
+	Secret     string
+	CreatedAt  time.Time
+}
+
+// WebhookDelivery is an event queued for delivery to a subscription
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int64
+	URL            string
+	Secret         string
+	EventID        string
+	EventType      EventType
+	Body           []byte
+	Attempts       int
+}
+
+// WebhookAttempt is the result of one delivery attempt. A failed delivery is retried
+// at NextAttemptAt unless GiveUp is set.
+type WebhookAttempt struct {
+	StatusCode    int
+	Error         string
+	Duration      time.Duration
+	Delivered     bool
+	GiveUp        bool
+	NextAttemptAt time.Time
+}
+
+// WebhookDeliveryLog is a recorded delivery attempt
+type WebhookDeliveryLog struct {
+	DeliveryID     int64
+	SubscriptionID int64
+	EventID        string
+	EventType      EventType
+	Attempt        int
+	StatusCode     int
+	Error          string
+	Duration       time.Duration
+	CreatedAt      time.Time
+}
@@ -0,0 +1,81 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"route/internal/app/models"
+	"route/internal/app/repository"
+	"route/internal/app/repository/postgresql"
+)
+
+var errAuthorizationsNotConfigured = errors.New("доверенности не настроены")
+
+// WithAuthorizations returns a copy of the module that issues orders to persons authorized by clients
+func (m OrderModule) WithAuthorizations(authorizations repository.AuthorizationRepository) *OrderModule {
+	m.authorizations = authorizations
+	return &m
+}
+
+// CreateAuthorization saves the power of attorney of the client, an authorization for one order
+// is checked against the order's client
+func (m OrderModule) CreateAuthorization(auth models.Authorization) (*models.Authorization, error) {
+	if m.authorizations == nil {
+		return nil, errAuthorizationsNotConfigured
+	}
+
+	auth.Recipient = strings.TrimSpace(auth.Recipient)
+	if auth.UserID <= 0 {
+		return nil, newValidationError("некорректный ID клиента: %d", auth.UserID)
+	}
+	if auth.Recipient == "" {
+		return nil, newValidationError("не указан получатель по доверенности")
+	}
+	if !auth.ValidUntil.After(time.Now()) {
+		return nil, newValidationError("срок действия доверенности не может быть в прошлом")
+	}
+
+	if auth.OrderID != 0 {
+		order, err := m.repo.GetOrderByID(auth.OrderID)
+		if err != nil && !errors.Is(err, postgresql.ErrOrderNotFound) {
+			return nil, err
+		}
+		if order == nil || order.UserID != auth.UserID {
+			return nil, fmt.Errorf("заказ с ID %d не найден", auth.OrderID)
+		}
+	}
+
+	return m.authorizations.CreateAuthorization(auth)
+}
+
+// ListAuthorizations returns powers of attorney of the client
+func (m OrderModule) ListAuthorizations(userID int) ([]models.Authorization, error) {
+	if m.authorizations == nil {
+		return nil, errAuthorizationsNotConfigured
+	}
+
+	return m.authorizations.ListAuthorizations(userID)
+}
+
+// findAuthorization finds a valid authorization of the recipient to collect the order
+func (m OrderModule) findAuthorization(order *models.Order, recipient string) (*models.Authorization, error) {
+	if m.authorizations == nil {
+		return nil, errAuthorizationsNotConfigured
+	}
+
+	auths, err := m.authorizations.ListAuthorizations(order.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, auth := range auths {
+		if auth.Allows(*order, recipient, now) {
+			return &auth, nil
+		}
+	}
+
+	return nil, newValidationError("у получателя %s нет действующей доверенности на заказ с ID %d", recipient, order.OrderID)
+}
@@ -0,0 +1,160 @@
+package module
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"route/internal/app/models"
+	"route/internal/app/pickup"
+	mockrepository "route/internal/app/repository/mocks"
+)
+
+func TestModule_IssueOrderToRecipient(t *testing.T) {
+	t.Parallel()
+
+	futureTime := time.Now().Add(24 * time.Hour)
+	order := &models.Order{OrderID: 8, UserID: 2, ReceivedFromCourier: true, Deadline: futureTime, PickupCodeHash: pickup.HashCode(8, "123456")}
+	req := models.IssueRequest{OrderID: 8, PickupCode: "123456", Recipient: " Петров Петр "}
+
+	tests := []struct {
+		name          string
+		auths         []models.Authorization
+		setupMocks    func(mockRepo *mockrepository.MockRepository)
+		expectedError string
+	}{
+		{
+			name:          "no authorization",
+			setupMocks:    func(mockRepo *mockrepository.MockRepository) {},
+			expectedError: "у получателя Петров Петр нет действующей доверенности на заказ с ID 8",
+		},
+		{
+			name: "expired authorization",
+			auths: []models.Authorization{
+				{ID: 1, UserID: 2, Recipient: "Петров Петр", ValidUntil: time.Now().Add(-time.Hour)},
+			},
+			setupMocks:    func(mockRepo *mockrepository.MockRepository) {},
+			expectedError: "у получателя Петров Петр нет действующей доверенности на заказ с ID 8",
+		},
+		{
+			name: "authorization for another order",
+			auths: []models.Authorization{
+				{ID: 2, UserID: 2, Recipient: "Петров Петр", OrderID: 9, ValidUntil: futureTime},
+			},
+			setupMocks:    func(mockRepo *mockrepository.MockRepository) {},
+			expectedError: "у получателя Петров Петр нет действующей доверенности на заказ с ID 8",
+		},
+		{
+			name: "authorization for all orders",
+			auths: []models.Authorization{
+				{ID: 3, UserID: 2, Recipient: "петров петр", ValidUntil: futureTime},
+			},
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().IssueOrder(8, gomock.Any(), models.IssueRecord{ReceivedBy: "Петров Петр", AuthorizationID: 3}).Return(nil)
+			},
+		},
+		{
+			name: "authorization for the order",
+			auths: []models.Authorization{
+				{ID: 4, UserID: 2, Recipient: "Петров Петр", OrderID: 8, ValidUntil: futureTime},
+			},
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().IssueOrder(8, gomock.Any(), models.IssueRecord{ReceivedBy: "Петров Петр", AuthorizationID: 4}).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockRepository(ctrl)
+			mockAuths := mockrepository.NewMockAuthorizationRepository(ctrl)
+			mod := New(mockRepo).WithAuthorizations(mockAuths)
+			mockRepo.EXPECT().GetOrderByID(8).Return(order, nil)
+			mockAuths.EXPECT().ListAuthorizations(2).Return(tt.auths, nil)
+			tt.setupMocks(mockRepo)
+
+			// act
+			err := mod.IssueOrder(req)
+
+			// assert
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestModule_CreateAuthorization(t *testing.T) {
+	t.Parallel()
+
+	futureTime := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		auth          models.Authorization
+		setupMocks    func(mockRepo *mockrepository.MockRepository, mockAuths *mockrepository.MockAuthorizationRepository)
+		expectedError string
+	}{
+		{
+			name:          "empty recipient",
+			auth:          models.Authorization{UserID: 1, Recipient: " ", ValidUntil: futureTime},
+			setupMocks:    func(*mockrepository.MockRepository, *mockrepository.MockAuthorizationRepository) {},
+			expectedError: "не указан получатель по доверенности",
+		},
+		{
+			name:          "validity in the past",
+			auth:          models.Authorization{UserID: 1, Recipient: "Петров", ValidUntil: time.Now().Add(-time.Hour)},
+			setupMocks:    func(*mockrepository.MockRepository, *mockrepository.MockAuthorizationRepository) {},
+			expectedError: "срок действия доверенности не может быть в прошлом",
+		},
+		{
+			name: "order of another client",
+			auth: models.Authorization{UserID: 1, Recipient: "Петров", OrderID: 5, ValidUntil: futureTime},
+			setupMocks: func(mockRepo *mockrepository.MockRepository, _ *mockrepository.MockAuthorizationRepository) {
+				mockRepo.EXPECT().GetOrderByID(5).Return(&models.Order{OrderID: 5, UserID: 2}, nil)
+			},
+			expectedError: "заказ с ID 5 не найден",
+		},
+		{
+			name: "authorization for all orders",
+			auth: models.Authorization{UserID: 1, Recipient: " Петров ", ValidUntil: futureTime},
+			setupMocks: func(_ *mockrepository.MockRepository, mockAuths *mockrepository.MockAuthorizationRepository) {
+				mockAuths.EXPECT().CreateAuthorization(models.Authorization{UserID: 1, Recipient: "Петров", ValidUntil: futureTime}).
+					Return(&models.Authorization{ID: 1}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockRepository(ctrl)
+			mockAuths := mockrepository.NewMockAuthorizationRepository(ctrl)
+			mod := New(mockRepo).WithAuthorizations(mockAuths)
+			tt.setupMocks(mockRepo, mockAuths)
+
+			// act
+			_, err := mod.CreateAuthorization(tt.auth)
+
+			// assert
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
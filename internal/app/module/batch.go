@@ -0,0 +1,72 @@
+package module
+
+import (
+	"sync"
+
+	"route/internal/app/models"
+)
+
+// batchWorkers is the number of orders of a batch checked at the same time, whatever the size of the batch
+const batchWorkers = 8
+
+// AcceptOrders accepts a batch of orders with the checks of AcceptOrder and stores them in one transaction.
+// The error of every request is returned at its index, nil if the order is accepted. With dryRun the orders
+// are only checked. If storing the batch fails, the orders are accepted one by one, so only the failing ones are rejected
+func (m OrderModule) AcceptOrders(reqs []models.AcceptRequest, dryRun bool) []error {
+	errs := make([]error, len(reqs))
+	stored := make([]*models.Order, len(reqs))
+	pts := make([]*models.PackagingType, len(reqs))
+	codes := make([]string, len(reqs))
+
+	// Orders of the batch are checked by batchWorkers workers, a repeated order ID is rejected before the checks
+	seen := make(map[int]bool, len(reqs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(batchWorkers, len(reqs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				stored[i], pts[i], codes[i], errs[i] = m.prepareOrder(reqs[i].Order, reqs[i].PackagingType)
+			}
+		}()
+	}
+	for i, req := range reqs {
+		if seen[req.Order.OrderID] {
+			errs[i] = newValidationError("заказ с ID %d уже существует", req.Order.OrderID)
+			continue
+		}
+		seen[req.Order.OrderID] = true
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var batch []int
+	for i, err := range errs {
+		if err == nil {
+			batch = append(batch, i)
+		}
+	}
+	if dryRun || len(batch) == 0 {
+		return errs
+	}
+
+	orders := make([]*models.Order, len(batch))
+	packagingTypes := make([]*models.PackagingType, len(batch))
+	for k, i := range batch {
+		orders[k], packagingTypes[k] = stored[i], pts[i]
+	}
+
+	if err := m.repo.AcceptOrders(orders, packagingTypes); err != nil {
+		for _, i := range batch {
+			errs[i] = m.acceptOrder(reqs[i].Order, reqs[i].PackagingType)
+		}
+		return errs
+	}
+
+	for _, i := range batch {
+		m.accepted(reqs[i].Order, stored[i], codes[i])
+	}
+	return errs
+}
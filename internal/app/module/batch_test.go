@@ -0,0 +1,90 @@
+package module
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"route/internal/app/models"
+	mockrepository "route/internal/app/repository/mocks"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestModule_AcceptOrders(t *testing.T) {
+	t.Parallel()
+
+	deadline := time.Now().Add(24 * time.Hour)
+	newReqs := func() []models.AcceptRequest {
+		return []models.AcceptRequest{
+			{Order: &models.Order{OrderID: 1, UserID: 1, Deadline: deadline, Weight: 1, Cost: 10}, PackagingType: models.Package},
+			{Order: &models.Order{OrderID: 1, UserID: 1, Deadline: deadline, Weight: 1, Cost: 10}, PackagingType: models.Package},
+			{Order: &models.Order{OrderID: 2, UserID: 1, Deadline: deadline, Weight: 50, Cost: 10}, PackagingType: models.Package},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		dryRun         bool
+		setupMocks     func(mockRepo *mockrepository.MockRepository)
+		expectedErrors []string
+	}{
+		{
+			name:   "dry run only checks orders",
+			dryRun: true,
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(1).Return(nil, postgresql.ErrOrderNotFound)
+				mockRepo.EXPECT().GetOrderByID(2).Return(nil, postgresql.ErrOrderNotFound)
+			},
+			expectedErrors: []string{"", "заказ с ID 1 уже существует", "вес заказа превышает допустимый для пакета: 50.000000"},
+		},
+		{
+			name: "valid orders are stored in one batch",
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(1).Return(nil, postgresql.ErrOrderNotFound)
+				mockRepo.EXPECT().GetOrderByID(2).Return(nil, postgresql.ErrOrderNotFound)
+				mockRepo.EXPECT().AcceptOrders(gomock.Len(1), gomock.Len(1)).Return(nil)
+			},
+			expectedErrors: []string{"", "заказ с ID 1 уже существует", "вес заказа превышает допустимый для пакета: 50.000000"},
+		},
+		{
+			name: "failed batch is accepted one by one",
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(1).Return(nil, postgresql.ErrOrderNotFound).Times(2)
+				mockRepo.EXPECT().GetOrderByID(2).Return(nil, postgresql.ErrOrderNotFound)
+				mockRepo.EXPECT().AcceptOrders(gomock.Len(1), gomock.Len(1)).Return(errors.New("could not serialize access"))
+				mockRepo.EXPECT().AcceptOrder(gomock.Any(), gomock.Any()).Return(postgresql.ErrNoFreeCell)
+			},
+			expectedErrors: []string{"в пункте выдачи нет свободной ячейки для заказа с ID 1", "заказ с ID 1 уже существует",
+				"вес заказа превышает допустимый для пакета: 50.000000"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockRepository(ctrl)
+			mod := New(mockRepo)
+			tt.setupMocks(mockRepo)
+
+			// act
+			errs := mod.AcceptOrders(newReqs(), tt.dryRun)
+
+			// assert
+			require.Len(t, errs, len(tt.expectedErrors))
+			for i, expected := range tt.expectedErrors {
+				if expected == "" {
+					assert.NoError(t, errs[i])
+					continue
+				}
+				assert.EqualError(t, errs[i], expected)
+			}
+		})
+	}
+}
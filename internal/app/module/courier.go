@@ -0,0 +1,115 @@
+package module
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"route/internal/app/models"
+	"route/internal/app/repository"
+	"route/internal/app/repository/postgresql"
+)
+
+var errSessionsNotConfigured = errors.New("сессии приемки не настроены")
+
+// WithCouriers returns a copy of the module that accepts orders in acceptance sessions of couriers
+func (m OrderModule) WithCouriers(couriers repository.CourierRepository) *OrderModule {
+	m.couriers = couriers
+	return &m
+}
+
+// CreateCourier registers a courier of a delivery company
+func (m OrderModule) CreateCourier(name, company string) (*models.Courier, error) {
+	if m.couriers == nil {
+		return nil, errSessionsNotConfigured
+	}
+
+	courier := models.Courier{Name: strings.TrimSpace(name), Company: strings.TrimSpace(company)}
+	if courier.Name == "" {
+		return nil, newValidationError("не указано имя курьера")
+	}
+	if courier.Company == "" {
+		return nil, newValidationError("не указана компания курьера")
+	}
+
+	id, err := m.couriers.CreateCourier(courier)
+	if err != nil {
+		return nil, err
+	}
+
+	courier.ID = id
+	return &courier, nil
+}
+
+// OpenSession opens an acceptance session for the courier,
+// expectedCount is the number of orders in the courier's waybill, zero if unknown
+func (m OrderModule) OpenSession(courierID, expectedCount int) (*models.AcceptanceSession, error) {
+	if m.couriers == nil {
+		return nil, errSessionsNotConfigured
+	}
+
+	if expectedCount < 0 {
+		return nil, newValidationError("ожидаемое количество заказов не может быть отрицательным")
+	}
+
+	if _, err := m.getCourier(courierID); err != nil {
+		return nil, err
+	}
+
+	return m.couriers.OpenSession(courierID, expectedCount)
+}
+
+// CloseSession closes the session and returns its summary
+func (m OrderModule) CloseSession(sessionID int64) (*models.SessionSummary, error) {
+	if m.couriers == nil {
+		return nil, errSessionsNotConfigured
+	}
+
+	summary, err := m.couriers.CloseSession(sessionID)
+	if errors.Is(err, postgresql.ErrSessionNotFound) {
+		return nil, newValidationError("сессия приемки %d не найдена", sessionID)
+	}
+	if errors.Is(err, postgresql.ErrSessionClosed) {
+		return nil, newValidationError("сессия приемки %d уже закрыта", sessionID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+func (m OrderModule) getCourier(courierID int) (*models.Courier, error) {
+	courier, err := m.couriers.GetCourier(courierID)
+	if errors.Is(err, postgresql.ErrCourierNotFound) {
+		return nil, newValidationError("курьер с ID %d не найден", courierID)
+	}
+	return courier, err
+}
+
+func (m OrderModule) checkSessionOpen(sessionID int64) error {
+	if m.couriers == nil {
+		return errSessionsNotConfigured
+	}
+
+	session, err := m.couriers.GetSession(sessionID)
+	if errors.Is(err, postgresql.ErrSessionNotFound) {
+		return newValidationError("сессия приемки %d не найдена", sessionID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if session.Closed() {
+		return newValidationError("сессия приемки %d закрыта", sessionID)
+	}
+	return nil
+}
+
+// recordRejection records the failed order for the session summary, a failure doesn't fail the acceptance
+func (m OrderModule) recordRejection(sessionID int64, orderID int, reason error) {
+	rejection := models.SessionRejection{OrderID: orderID, Reason: reason.Error()}
+	if err := m.couriers.RecordRejection(sessionID, rejection); err != nil {
+		log.Printf("failed to record rejection of order %d in session %d: %v", orderID, sessionID, err)
+	}
+}
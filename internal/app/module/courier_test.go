@@ -0,0 +1,209 @@
+package module
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"route/internal/app/models"
+	mockrepository "route/internal/app/repository/mocks"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestModule_AcceptOrderInSession(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		setupMocks    func(mockRepo *mockrepository.MockRepository, mockCouriers *mockrepository.MockCourierRepository)
+		expectedError string
+	}{
+		{
+			name: "session not found",
+			setupMocks: func(mockRepo *mockrepository.MockRepository, mockCouriers *mockrepository.MockCourierRepository) {
+				mockCouriers.EXPECT().GetSession(int64(1)).Return(nil, postgresql.ErrSessionNotFound)
+			},
+			expectedError: "сессия приемки 1 не найдена",
+		},
+		{
+			name: "session closed",
+			setupMocks: func(mockRepo *mockrepository.MockRepository, mockCouriers *mockrepository.MockCourierRepository) {
+				mockCouriers.EXPECT().GetSession(int64(1)).Return(&models.AcceptanceSession{ID: 1, ClosedAt: time.Now()}, nil)
+			},
+			expectedError: "сессия приемки 1 закрыта",
+		},
+		{
+			name: "rejected order is recorded",
+			setupMocks: func(mockRepo *mockrepository.MockRepository, mockCouriers *mockrepository.MockCourierRepository) {
+				mockCouriers.EXPECT().GetSession(int64(1)).Return(&models.AcceptanceSession{ID: 1}, nil)
+				mockRepo.EXPECT().GetOrderByID(10).Return(&models.Order{OrderID: 10}, nil)
+				mockCouriers.EXPECT().RecordRejection(int64(1), models.SessionRejection{
+					OrderID: 10,
+					Reason:  "заказ с ID 10 уже существует",
+				}).Return(nil)
+			},
+			expectedError: "заказ с ID 10 уже существует",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockRepository(ctrl)
+			mockCouriers := mockrepository.NewMockCourierRepository(ctrl)
+			mod := New(mockRepo).WithCouriers(mockCouriers)
+			tt.setupMocks(mockRepo, mockCouriers)
+
+			// act
+			err := mod.AcceptOrder(&models.Order{OrderID: 10, UserID: 1, SessionID: 1}, models.Box)
+
+			// assert
+			require.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestModule_OpenSession(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		courierID     int
+		expected      int
+		setupMocks    func(mockCouriers *mockrepository.MockCourierRepository)
+		expectedError string
+	}{
+		{
+			name:          "negative expected count",
+			courierID:     1,
+			expected:      -1,
+			setupMocks:    func(mockCouriers *mockrepository.MockCourierRepository) {},
+			expectedError: "ожидаемое количество заказов не может быть отрицательным",
+		},
+		{
+			name:      "courier not found",
+			courierID: 2,
+			setupMocks: func(mockCouriers *mockrepository.MockCourierRepository) {
+				mockCouriers.EXPECT().GetCourier(2).Return(nil, postgresql.ErrCourierNotFound)
+			},
+			expectedError: "курьер с ID 2 не найден",
+		},
+		{
+			name:      "session opened",
+			courierID: 1,
+			expected:  5,
+			setupMocks: func(mockCouriers *mockrepository.MockCourierRepository) {
+				mockCouriers.EXPECT().GetCourier(1).Return(&models.Courier{ID: 1}, nil)
+				mockCouriers.EXPECT().OpenSession(1, 5).Return(&models.AcceptanceSession{ID: 3, CourierID: 1, ExpectedCount: 5}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockCouriers := mockrepository.NewMockCourierRepository(ctrl)
+			mod := New(mockrepository.NewMockRepository(ctrl)).WithCouriers(mockCouriers)
+			tt.setupMocks(mockCouriers)
+
+			// act
+			session, err := mod.OpenSession(tt.courierID, tt.expected)
+
+			// assert
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(3), session.ID)
+		})
+	}
+}
+
+func TestModule_CloseSession(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		repoErr       error
+		expectedError string
+	}{
+		{
+			name:          "session not found",
+			repoErr:       postgresql.ErrSessionNotFound,
+			expectedError: "сессия приемки 1 не найдена",
+		},
+		{
+			name:          "session already closed",
+			repoErr:       postgresql.ErrSessionClosed,
+			expectedError: "сессия приемки 1 уже закрыта",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockCouriers := mockrepository.NewMockCourierRepository(ctrl)
+			mod := New(mockrepository.NewMockRepository(ctrl)).WithCouriers(mockCouriers)
+			mockCouriers.EXPECT().CloseSession(int64(1)).Return(nil, tt.repoErr)
+
+			// act
+			_, err := mod.CloseSession(1)
+
+			// assert
+			require.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestSessionSummary_Discrepancies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		summary         models.SessionSummary
+		expectedMissing int
+		expectedFound   bool
+	}{
+		{
+			name:    "no waybill",
+			summary: models.SessionSummary{Accepted: 3},
+		},
+		{
+			name:            "missing orders",
+			summary:         models.SessionSummary{Session: models.AcceptanceSession{ExpectedCount: 5}, Accepted: 3},
+			expectedMissing: 2,
+			expectedFound:   true,
+		},
+		{
+			name:          "rejected orders",
+			summary:       models.SessionSummary{Accepted: 3, Rejections: []models.SessionRejection{{OrderID: 1}}},
+			expectedFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// assert
+			assert.Equal(t, tt.expectedMissing, tt.summary.Missing())
+			assert.Equal(t, tt.expectedFound, tt.summary.HasDiscrepancies())
+		})
+	}
+}
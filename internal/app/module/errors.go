@@ -0,0 +1,31 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+
+	"route/internal/app/repository/postgresql"
+)
+
+// ValidationError is returned when an operation violates business rules,
+// as opposed to infrastructure errors which may succeed on retry
+type ValidationError struct {
+	msg string
+}
+
+func (e ValidationError) Error() string {
+	return e.msg
+}
+
+func newValidationError(format string, args ...any) error {
+	return ValidationError{msg: fmt.Sprintf(format, args...)}
+}
+
+// orderChangedError reports a guarded update which found the order changed by another request
+// after the module had checked it, other errors are returned as is
+func orderChangedError(err error, orderID int) error {
+	if errors.Is(err, postgresql.ErrOrderStateChanged) {
+		return newValidationError("заказ с ID %d изменен другим запросом, повторите операцию", orderID)
+	}
+	return err
+}
@@ -12,11 +12,12 @@ package mock_module
 import (
 	reflect "reflect"
 	models "route/internal/app/models"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
 
-// MockModule is a mock of mod interface.
+// MockModule is a mock of Module interface.
 type MockModule struct {
 	ctrl     *gomock.Controller
 	recorder *MockModuleMockRecorder
@@ -53,6 +54,20 @@ func (mr *MockModuleMockRecorder) AcceptOrder(order, packagingType any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptOrder", reflect.TypeOf((*MockModule)(nil).AcceptOrder), order, packagingType)
 }
 
+// AcceptOrders mocks base method.
+func (m *MockModule) AcceptOrders(reqs []models.AcceptRequest, dryRun bool) []error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptOrders", reqs, dryRun)
+	ret0, _ := ret[0].([]error)
+	return ret0
+}
+
+// AcceptOrders indicates an expected call of AcceptOrders.
+func (mr *MockModuleMockRecorder) AcceptOrders(reqs, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptOrders", reflect.TypeOf((*MockModule)(nil).AcceptOrders), reqs, dryRun)
+}
+
 // AcceptReturn mocks base method.
 func (m *MockModule) AcceptReturn(orderID, userID int) error {
 	m.ctrl.T.Helper()
@@ -67,18 +82,167 @@ func (mr *MockModuleMockRecorder) AcceptReturn(orderID, userID any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptReturn", reflect.TypeOf((*MockModule)(nil).AcceptReturn), orderID, userID)
 }
 
+// CloseSession mocks base method.
+func (m *MockModule) CloseSession(sessionID int64) (*models.SessionSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSession", sessionID)
+	ret0, _ := ret[0].(*models.SessionSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseSession indicates an expected call of CloseSession.
+func (mr *MockModuleMockRecorder) CloseSession(sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSession", reflect.TypeOf((*MockModule)(nil).CloseSession), sessionID)
+}
+
+// CreateAuthorization mocks base method.
+func (m *MockModule) CreateAuthorization(auth models.Authorization) (*models.Authorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuthorization", auth)
+	ret0, _ := ret[0].(*models.Authorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuthorization indicates an expected call of CreateAuthorization.
+func (mr *MockModuleMockRecorder) CreateAuthorization(auth any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthorization", reflect.TypeOf((*MockModule)(nil).CreateAuthorization), auth)
+}
+
+// CreateCourier mocks base method.
+func (m *MockModule) CreateCourier(name, company string) (*models.Courier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCourier", name, company)
+	ret0, _ := ret[0].(*models.Courier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCourier indicates an expected call of CreateCourier.
+func (mr *MockModuleMockRecorder) CreateCourier(name, company any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCourier", reflect.TypeOf((*MockModule)(nil).CreateCourier), name, company)
+}
+
+// CreatePickupPoint mocks base method.
+func (m *MockModule) CreatePickupPoint(name, address string) (*models.PickupPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePickupPoint", name, address)
+	ret0, _ := ret[0].(*models.PickupPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePickupPoint indicates an expected call of CreatePickupPoint.
+func (mr *MockModuleMockRecorder) CreatePickupPoint(name, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePickupPoint", reflect.TypeOf((*MockModule)(nil).CreatePickupPoint), name, address)
+}
+
+// CreateRack mocks base method.
+func (m *MockModule) CreateRack(name string, cellCount int, size models.CellSize, maxWeight float64) (*models.Rack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRack", name, cellCount, size, maxWeight)
+	ret0, _ := ret[0].(*models.Rack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRack indicates an expected call of CreateRack.
+func (mr *MockModuleMockRecorder) CreateRack(name, cellCount, size, maxWeight any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRack", reflect.TypeOf((*MockModule)(nil).CreateRack), name, cellCount, size, maxWeight)
+}
+
+// CreateReturnManifest mocks base method.
+func (m *MockModule) CreateReturnManifest(courierID int) (*models.ReturnManifest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReturnManifest", courierID)
+	ret0, _ := ret[0].(*models.ReturnManifest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReturnManifest indicates an expected call of CreateReturnManifest.
+func (mr *MockModuleMockRecorder) CreateReturnManifest(courierID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReturnManifest", reflect.TypeOf((*MockModule)(nil).CreateReturnManifest), courierID)
+}
+
+// CreateTransfer mocks base method.
+func (m *MockModule) CreateTransfer(toPointID int, orderIDs []int) (*models.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransfer", toPointID, orderIDs)
+	ret0, _ := ret[0].(*models.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransfer indicates an expected call of CreateTransfer.
+func (mr *MockModuleMockRecorder) CreateTransfer(toPointID, orderIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockModule)(nil).CreateTransfer), toPointID, orderIDs)
+}
+
+// ExportOrders mocks base method.
+func (m *MockModule) ExportOrders(filter models.ExportFilter, fx func(models.Order) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportOrders", filter, fx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportOrders indicates an expected call of ExportOrders.
+func (mr *MockModuleMockRecorder) ExportOrders(filter, fx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportOrders", reflect.TypeOf((*MockModule)(nil).ExportOrders), filter, fx)
+}
+
 // IssueOrder mocks base method.
-func (m *MockModule) IssueOrder(orderID int) error {
+func (m *MockModule) IssueOrder(req models.IssueRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IssueOrder", orderID)
+	ret := m.ctrl.Call(m, "IssueOrder", req)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // IssueOrder indicates an expected call of IssueOrder.
-func (mr *MockModuleMockRecorder) IssueOrder(orderID any) *gomock.Call {
+func (mr *MockModuleMockRecorder) IssueOrder(req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueOrder", reflect.TypeOf((*MockModule)(nil).IssueOrder), req)
+}
+
+// ListAuthorizations mocks base method.
+func (m *MockModule) ListAuthorizations(userID int) ([]models.Authorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuthorizations", userID)
+	ret0, _ := ret[0].([]models.Authorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuthorizations indicates an expected call of ListAuthorizations.
+func (mr *MockModuleMockRecorder) ListAuthorizations(userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueOrder", reflect.TypeOf((*MockModule)(nil).IssueOrder), orderID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuthorizations", reflect.TypeOf((*MockModule)(nil).ListAuthorizations), userID)
+}
+
+// ListCells mocks base method.
+func (m *MockModule) ListCells() ([]models.StorageCell, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCells")
+	ret0, _ := ret[0].([]models.StorageCell)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCells indicates an expected call of ListCells.
+func (mr *MockModuleMockRecorder) ListCells() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCells", reflect.TypeOf((*MockModule)(nil).ListCells))
 }
 
 // ListOrders mocks base method.
@@ -96,6 +260,21 @@ func (mr *MockModuleMockRecorder) ListOrders(userID, lastN any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockModule)(nil).ListOrders), userID, lastN)
 }
 
+// ListPickupPoints mocks base method.
+func (m *MockModule) ListPickupPoints() ([]models.PickupPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPickupPoints")
+	ret0, _ := ret[0].([]models.PickupPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPickupPoints indicates an expected call of ListPickupPoints.
+func (mr *MockModuleMockRecorder) ListPickupPoints() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPickupPoints", reflect.TypeOf((*MockModule)(nil).ListPickupPoints))
+}
+
 // ListReturns mocks base method.
 func (m *MockModule) ListReturns(page, pageSize int) ([]models.Order, error) {
 	m.ctrl.T.Helper()
@@ -111,6 +290,65 @@ func (mr *MockModuleMockRecorder) ListReturns(page, pageSize any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReturns", reflect.TypeOf((*MockModule)(nil).ListReturns), page, pageSize)
 }
 
+// OpenSession mocks base method.
+func (m *MockModule) OpenSession(courierID, expectedCount int) (*models.AcceptanceSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenSession", courierID, expectedCount)
+	ret0, _ := ret[0].(*models.AcceptanceSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenSession indicates an expected call of OpenSession.
+func (mr *MockModuleMockRecorder) OpenSession(courierID, expectedCount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenSession", reflect.TypeOf((*MockModule)(nil).OpenSession), courierID, expectedCount)
+}
+
+// ReceiveTransfer mocks base method.
+func (m *MockModule) ReceiveTransfer(transferID int64) (*models.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReceiveTransfer", transferID)
+	ret0, _ := ret[0].(*models.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReceiveTransfer indicates an expected call of ReceiveTransfer.
+func (mr *MockModuleMockRecorder) ReceiveTransfer(transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiveTransfer", reflect.TypeOf((*MockModule)(nil).ReceiveTransfer), transferID)
+}
+
+// RefuseOrder mocks base method.
+func (m *MockModule) RefuseOrder(orderID int, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefuseOrder", orderID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefuseOrder indicates an expected call of RefuseOrder.
+func (mr *MockModuleMockRecorder) RefuseOrder(orderID, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefuseOrder", reflect.TypeOf((*MockModule)(nil).RefuseOrder), orderID, reason)
+}
+
+// Report mocks base method.
+func (m *MockModule) Report(from, to time.Time) (*models.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Report", from, to)
+	ret0, _ := ret[0].(*models.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockModuleMockRecorder) Report(from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockModule)(nil).Report), from, to)
+}
+
 // ReturnOrder mocks base method.
 func (m *MockModule) ReturnOrder(orderID int) error {
 	m.ctrl.T.Helper()
@@ -124,3 +362,69 @@ func (mr *MockModuleMockRecorder) ReturnOrder(orderID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnOrder", reflect.TypeOf((*MockModule)(nil).ReturnOrder), orderID)
 }
+
+// Stocktake mocks base method.
+func (m *MockModule) Stocktake(scanned []int) (*models.Stocktake, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stocktake", scanned)
+	ret0, _ := ret[0].(*models.Stocktake)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stocktake indicates an expected call of Stocktake.
+func (mr *MockModuleMockRecorder) Stocktake(scanned any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stocktake", reflect.TypeOf((*MockModule)(nil).Stocktake), scanned)
+}
+
+// UnlockOrder mocks base method.
+func (m *MockModule) UnlockOrder(orderID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockOrder", orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockOrder indicates an expected call of UnlockOrder.
+func (mr *MockModuleMockRecorder) UnlockOrder(orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockOrder", reflect.TypeOf((*MockModule)(nil).UnlockOrder), orderID)
+}
+
+// MockNotifications is a mock of Notifications interface.
+type MockNotifications struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationsMockRecorder
+}
+
+// MockNotificationsMockRecorder is the mock recorder for MockNotifications.
+type MockNotificationsMockRecorder struct {
+	mock *MockNotifications
+}
+
+// NewMockNotifications creates a new mock instance.
+func NewMockNotifications(ctrl *gomock.Controller) *MockNotifications {
+	mock := &MockNotifications{ctrl: ctrl}
+	mock.recorder = &MockNotificationsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifications) EXPECT() *MockNotificationsMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockNotifications) Notify(kind models.NotificationKind, order models.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", kind, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockNotificationsMockRecorder) Notify(kind, order any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotifications)(nil).Notify), kind, order)
+}
@@ -2,14 +2,45 @@
 
 package module
 
-import "route/internal/app/models"
+import (
+	"time"
+
+	"route/internal/app/models"
+)
 
 // Module is an interface for module
 type Module interface {
 	AcceptOrder(order *models.Order, packagingType models.PackageType) error
+	AcceptOrders(reqs []models.AcceptRequest, dryRun bool) []error
 	ReturnOrder(orderID int) error
-	IssueOrder(orderID int) error
+	IssueOrder(req models.IssueRequest) error
+	UnlockOrder(orderID int) error
+	RefuseOrder(orderID int, reason string) error
 	ListOrders(userID, lastN int) ([]models.Order, error)
 	AcceptReturn(orderID, userID int) error
 	ListReturns(page, pageSize int) ([]models.Order, error)
+	ExportOrders(filter models.ExportFilter, fx func(order models.Order) error) error
+	CreateReturnManifest(courierID int) (*models.ReturnManifest, error)
+
+	CreateCourier(name, company string) (*models.Courier, error)
+	OpenSession(courierID, expectedCount int) (*models.AcceptanceSession, error)
+	CloseSession(sessionID int64) (*models.SessionSummary, error)
+
+	CreateAuthorization(auth models.Authorization) (*models.Authorization, error)
+	ListAuthorizations(userID int) ([]models.Authorization, error)
+
+	CreatePickupPoint(name, address string) (*models.PickupPoint, error)
+	ListPickupPoints() ([]models.PickupPoint, error)
+	CreateTransfer(toPointID int, orderIDs []int) (*models.Transfer, error)
+	ReceiveTransfer(transferID int64) (*models.Transfer, error)
+
+	CreateRack(name string, cellCount int, size models.CellSize, maxWeight float64) (*models.Rack, error)
+	ListCells() ([]models.StorageCell, error)
+	Stocktake(scanned []int) (*models.Stocktake, error)
+	Report(from, to time.Time) (*models.Report, error)
+}
+
+// Notifications notify clients about changes of their orders
+type Notifications interface {
+	Notify(kind models.NotificationKind, order models.Order) error
 }
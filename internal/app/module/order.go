@@ -3,61 +3,109 @@ package module
 import (
 	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
-	"github.com/prometheus/client_golang/prometheus"
+	"route/internal/app/metrics"
 	"route/internal/app/models"
+	"route/internal/app/pickup"
 	"route/internal/app/repository"
 	"route/internal/app/repository/postgresql"
 	"route/pkg/hash"
 )
 
-type IMCache[K comparable, V any] interface {
-	Set(key K, value V, now time.Time)
-	Get(key K) (V, bool)
-	Delete(key K)
-}
-
 type OrderModule struct {
-	repo                repository.Repository
-	cache               IMCache[int, models.Order]
-	issuedOrdersCounter prometheus.Counter
+	repo           repository.Repository
+	notifications  Notifications
+	couriers       repository.CourierRepository
+	authorizations repository.AuthorizationRepository
+	points         repository.PickupPointRepository
+	storage        repository.StorageRepository
+	stocktakes     repository.StocktakeRepository
+	reports        repository.ReportRepository
+
+	// pointID is the pickup point served by the module, zero if the module serves all points
+	pointID int
 }
 
-func New(repo repository.Repository, cache IMCache[int, models.Order]) *OrderModule {
+// New is a constructor for OrderModule, caching is done by the repository
+func New(repo repository.Repository) *OrderModule {
 	return &OrderModule{
-		repo:  repo,
-		cache: cache,
+		repo: repo,
 	}
 }
 
+// WithNotifications returns a copy of the module that notifies clients after changes of their orders
+func (m OrderModule) WithNotifications(notifications Notifications) *OrderModule {
+	m.notifications = notifications
+	return &m
+}
+
+// notify notifies the client, a failed notification doesn't fail the operation
+func (m OrderModule) notify(kind models.NotificationKind, order models.Order) {
+	if m.notifications == nil {
+		return
+	}
+
+	if err := m.notifications.Notify(kind, order); err != nil {
+		log.Printf("failed to notify user %d about order %d: %v", order.UserID, order.OrderID, err)
+	}
+}
+
+// AcceptOrder accepts the order from the courier. An order accepted in an acceptance session
+// is linked to it, and if it fails the checks its rejection is recorded for the session summary
 func (m OrderModule) AcceptOrder(order *models.Order, packagingType models.PackageType) error {
-	// Check if the order is already in cache
-	_, ok := m.cache.Get(order.OrderID)
-	if ok {
-		// If the order is in cache, return an error
-		return fmt.Errorf("заказ с ID %d уже существует", order.OrderID)
+	if order.SessionID != 0 {
+		if err := m.checkSessionOpen(order.SessionID); err != nil {
+			return err
+		}
+	}
+
+	err := m.acceptOrder(order, packagingType)
+	var validationErr ValidationError
+	if order.SessionID != 0 && errors.As(err, &validationErr) {
+		m.recordRejection(order.SessionID, order.OrderID, err)
+	}
+	return err
+}
+
+func (m OrderModule) acceptOrder(order *models.Order, packagingType models.PackageType) error {
+	modifiedOrder, pt, code, err := m.prepareOrder(order, packagingType)
+	if err != nil {
+		return err
+	}
+
+	err = m.repo.AcceptOrder(modifiedOrder, pt)
+	if err != nil {
+		return acceptError(order, err)
 	}
 
+	m.accepted(order, modifiedOrder, code)
+	return nil
+}
+
+// prepareOrder checks the order and returns the order to store with its packaging and the plain pickup code
+func (m OrderModule) prepareOrder(order *models.Order, packagingType models.PackageType) (*models.Order, *models.PackagingType, string, error) {
 	foundOrder, err := m.repo.GetOrderByID(order.OrderID)
 	if err != nil && !errors.Is(err, postgresql.ErrOrderNotFound) {
-		return err
+		return nil, nil, "", err
 	}
 
 	if foundOrder != nil {
 		// Order with orderID already exists, return an error
-		return fmt.Errorf("заказ с ID %d уже существует", order.OrderID)
+		return nil, nil, "", newValidationError("заказ с ID %d уже существует", order.OrderID)
 	}
 
 	// Check that deadline is not in the past
 	if order.Deadline.Before(time.Now()) {
-		return errors.New("срок хранения не может быть в прошлом")
+		return nil, nil, "", newValidationError("срок хранения не может быть в прошлом")
 	}
 
 	// Check the packaging type and get the packaging type struct
 	pt, err := checkPackagingType(packagingType, order.Weight)
 	if err != nil {
-		return err
+		return nil, nil, "", err
 	}
 
 	// Sum total cost by adding cost to its additional cost
@@ -65,29 +113,41 @@ func (m OrderModule) AcceptOrder(order *models.Order, packagingType models.Packa
 
 	// Create a new order and increase cost by additional cost
 	modifiedOrder := models.NewOrder(order.OrderID, order.UserID, order.Deadline, totalCost, order.Weight)
+	modifiedOrder.SessionID = order.SessionID
 
-	res := m.repo.AcceptOrder(modifiedOrder, pt)
-	if res == nil {
-		// Set the order to cache
-		m.cache.Set(order.OrderID, *modifiedOrder, time.Now())
+	// Only the hash of the pickup code is stored, the code itself is given to the client
+	code, err := pickup.GenerateCode()
+	if err != nil {
+		return nil, nil, "", err
 	}
-	return res
+	modifiedOrder.PickupCodeHash = pickup.HashCode(order.OrderID, code)
+
+	return modifiedOrder, pt, code, nil
 }
 
-func (m OrderModule) ReturnOrder(orderID int) error {
-	// Check if the order is already in cache
-	cachedOrder, found := m.cache.Get(orderID)
-	if found {
-		cond := processReturnOrderCondition(&cachedOrder)
-		if cond != nil {
-			return cond
-		}
-		res := m.repo.ReturnOrder(orderID)
-		// If the order is successfully returned, delete it from cache
-		m.cache.Delete(orderID)
-		return res
+// acceptError converts errors of storing the order to the messages for staff
+func acceptError(order *models.Order, err error) error {
+	if errors.Is(err, postgresql.ErrSessionClosed) {
+		return newValidationError("сессия приемки %d закрыта", order.SessionID)
+	}
+	if errors.Is(err, postgresql.ErrNoFreeCell) {
+		return newValidationError("в пункте выдачи нет свободной ячейки для заказа с ID %d", order.OrderID)
 	}
+	return err
+}
+
+// accepted gives the cell and the pickup code of the stored order to the caller and notifies the client
+func (m OrderModule) accepted(order, stored *models.Order, code string) {
+	// Staff puts the order to the cell chosen by the repository
+	order.CellID, order.Cell = stored.CellID, stored.Cell
 
+	// The code is set after the order is stored so that it never gets to the cache
+	order.PickupCode = code
+	stored.PickupCode = code
+	m.notify(models.OrderArrived, *stored)
+}
+
+func (m OrderModule) ReturnOrder(orderID int) error {
 	order, err := m.repo.GetOrderByID(orderID)
 	if err != nil && !errors.Is(err, postgresql.ErrOrderNotFound) {
 		return err
@@ -101,7 +161,7 @@ func (m OrderModule) ReturnOrder(orderID int) error {
 		return cond
 	}
 
-	return m.repo.ReturnOrder(orderID)
+	return orderChangedError(m.repo.ReturnOrder(orderID), orderID)
 }
 
 func processReturnOrderCondition(order *models.Order) error {
@@ -110,22 +170,29 @@ func processReturnOrderCondition(order *models.Order) error {
 		return fmt.Errorf("заказ с ID %d уже был выдан клиенту", order.OrderID)
 	}
 
-	// If order is not expired, return an error
-	if order.Deadline.After(time.Now()) {
+	// If order is already handed over in a return manifest, return an error
+	if !order.ReturnedToCourierAt.IsZero() {
+		return fmt.Errorf("заказ с ID %d уже передан курьеру", order.OrderID)
+	}
+
+	// If order is moved to another pickup point, return an error
+	if order.TransferID != 0 {
+		return fmt.Errorf("заказ с ID %d в пути в другой пункт выдачи", order.OrderID)
+	}
+
+	// If order is not expired and not refused by client, return an error
+	if order.RefusedAt.IsZero() && order.Deadline.After(time.Now()) {
 		return fmt.Errorf("заказ с ID %d еще не просрочен", order.OrderID)
 	}
 
 	return nil
 }
 
-func (m OrderModule) IssueOrder(orderID int) error {
-
-	// Attempt to retrieve the order from the cache
-	cachedOrder, found := m.cache.Get(orderID)
-	if found {
-		return processIssueCondition(&cachedOrder)
-	}
-
+// IssueOrder issues the order to the client who presented its pickup code.
+// A manager can issue the order without the code, even if it is locked.
+// The order can be collected by another person if the client authorized them
+func (m OrderModule) IssueOrder(req models.IssueRequest) error {
+	orderID := req.OrderID
 	order, err := m.repo.GetOrderByID(orderID)
 	if err != nil && !errors.Is(err, postgresql.ErrOrderNotFound) {
 		return err
@@ -140,25 +207,28 @@ func (m OrderModule) IssueOrder(orderID int) error {
 		return cond
 	}
 
-	err = m.repo.IssueOrder(orderID, hash.GenerateHash())
-	if err != nil {
-		return err
+	record := models.IssueRecord{OverrideBy: strings.TrimSpace(req.OverrideBy), ReceivedBy: strings.TrimSpace(req.Recipient)}
+	if record.ReceivedBy != "" {
+		auth, err := m.findAuthorization(order, record.ReceivedBy)
+		if err != nil {
+			return err
+		}
+		record.AuthorizationID = auth.ID
 	}
 
-	// Get the updated order from the database
-	updatedOrder, err := m.repo.GetOrderByID(orderID)
-	if err != nil {
-		return err
-	}
-	if updatedOrder == nil {
-		return fmt.Errorf("не удалось получить обновленные данные заказа с ID %d", orderID)
+	if record.OverrideBy == "" {
+		if err = m.checkPickupCode(order, req.PickupCode); err != nil {
+			return err
+		}
 	}
 
-	// Set the updated order to cache
-	m.cache.Set(orderID, *updatedOrder, time.Now())
+	err = m.repo.IssueOrder(orderID, hash.GenerateHash(), record)
+	if err != nil {
+		return orderChangedError(err, orderID)
+	}
 
 	// Increment counter
-	m.issuedOrdersCounter.Inc()
+	metrics.IssuedOrdersCounter.Inc()
 
 	return nil
 }
@@ -169,11 +239,21 @@ func processIssueCondition(order *models.Order) error {
 		return fmt.Errorf("заказ с ID %d уже был выдан клиенту", order.OrderID)
 	}
 
+	// If order is moved to another pickup point, return an error
+	if order.TransferID != 0 {
+		return fmt.Errorf("заказ с ID %d в пути в другой пункт выдачи", order.OrderID)
+	}
+
 	// If order is not received from courier, return an error
 	if !order.ReceivedFromCourier {
 		return fmt.Errorf("заказ с ID %d не был получен курьером", order.OrderID)
 	}
 
+	// If order is refused by client, return an error
+	if !order.RefusedAt.IsZero() {
+		return fmt.Errorf("заказ с ID %d отклонен клиентом", order.OrderID)
+	}
+
 	// If order is expired, return an error
 	if order.Deadline.Before(time.Now()) {
 		return fmt.Errorf("заказ с ID %d просрочен", order.OrderID)
@@ -182,25 +262,55 @@ func processIssueCondition(order *models.Order) error {
 	return nil
 }
 
+// RefuseOrder records that the client refused the order at pickup. The order is not issued
+// and goes back to the courier with the next return manifest
+func (m OrderModule) RefuseOrder(orderID int, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return newValidationError("не указана причина отказа")
+	}
+
+	order, err := m.repo.GetOrderByID(orderID)
+	if err != nil && !errors.Is(err, postgresql.ErrOrderNotFound) {
+		return err
+	}
+
+	if order == nil {
+		return fmt.Errorf("заказ с ID %d не найден", orderID)
+	}
+
+	// Only an order that could be issued can be refused
+	if !order.RefusedAt.IsZero() {
+		return fmt.Errorf("заказ с ID %d уже отклонен клиентом", orderID)
+	}
+	if !order.ReturnedToCourierAt.IsZero() {
+		return fmt.Errorf("заказ с ID %d уже передан курьеру", orderID)
+	}
+	if err = processIssueCondition(order); err != nil {
+		return err
+	}
+
+	return orderChangedError(m.repo.RefuseOrder(orderID, reason), orderID)
+}
+
 func (m OrderModule) ListOrders(userID, lastN int) ([]models.Order, error) {
 	return m.repo.ListOrders(userID, lastN)
 }
 
 func (m OrderModule) AcceptReturn(orderID, userID int) error {
-	// Check if the order is already in cache
-	cachedOrder, found := m.cache.Get(orderID)
-	if found && cachedOrder.UserID == userID {
-		return processAcceptReturnCondition(&cachedOrder)
-	}
-
 	order, err := m.repo.GetOrderByID(orderID)
-	if err != nil {
+	if err != nil && !errors.Is(err, postgresql.ErrOrderNotFound) {
 		return err
 	}
 	if order == nil || order.UserID != userID {
 		return fmt.Errorf("заказ с ID %d не найден", orderID)
 	}
 
+	// Only orders issued from our pickup point are returned to it
+	if m.pointID != 0 && order.PickupPointID != m.pointID {
+		return newValidationError("заказ с ID %d выдан в другом пункте выдачи", orderID)
+	}
+
 	err = processAcceptReturnCondition(order)
 	if err != nil {
 		return err
@@ -209,21 +319,10 @@ func (m OrderModule) AcceptReturn(orderID, userID int) error {
 
 	err = m.repo.AcceptReturn(*order)
 	if err != nil {
-		return err
+		return orderChangedError(err, orderID)
 	}
 
-	// Get the updated order from the database
-	updatedOrder, err := m.repo.GetOrderByID(orderID)
-	if err != nil {
-		return err
-	}
-	if updatedOrder == nil {
-		return fmt.Errorf("не удалось получить обновленные данные заказа с ID %d", orderID)
-	}
-
-	// Set the updated order to cache
-	m.cache.Set(orderID, *updatedOrder, time.Now())
-
+	m.notify(models.ReturnAcceptedNotice, *order)
 	return nil
 }
 
@@ -247,18 +346,55 @@ func (m OrderModule) ListReturns(page, pageSize int) ([]models.Order, error) {
 	return m.repo.ListReturns(page, pageSize)
 }
 
+// ExportOrders passes the orders or returns matching the filter to fx one by one
+func (m OrderModule) ExportOrders(filter models.ExportFilter, fx func(order models.Order) error) error {
+	if !filter.Kind.Valid() {
+		return newValidationError("неизвестный тип выгрузки: %s", filter.Kind)
+	}
+	if filter.UserID < 0 || filter.LastN < 0 || filter.Page < 0 || filter.PageSize < 0 {
+		return newValidationError("параметры выгрузки не могут быть отрицательными")
+	}
+
+	return m.repo.ExportOrders(filter, fx)
+}
+
+// CreateReturnManifest hands over expired orders not issued to clients to the courier,
+// orders brought in sessions of couriers of other companies are not handed over
+func (m OrderModule) CreateReturnManifest(courierID int) (*models.ReturnManifest, error) {
+	if courierID <= 0 {
+		return nil, newValidationError("некорректный ID курьера: %d", courierID)
+	}
+
+	// Couriers are checked only if the module knows them
+	if m.couriers != nil {
+		if _, err := m.getCourier(courierID); err != nil {
+			return nil, err
+		}
+	}
+
+	manifest, err := m.repo.CreateReturnManifest(courierID)
+	if errors.Is(err, postgresql.ErrNoOrdersToReturn) {
+		return nil, newValidationError("нет просроченных заказов для возврата курьеру")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
 func checkPackagingType(packagingType models.PackageType, weight float64) (*models.PackagingType, error) {
 	// Check if the provided packaging type is allowed
 	switch packagingType {
 	case models.Package:
 		if weight >= models.PackageWeightLimit {
-			return nil, fmt.Errorf("вес заказа превышает допустимый для пакета: %f", weight)
+			return nil, newValidationError("вес заказа превышает допустимый для пакета: %f", weight)
 		}
 		return models.NewPackagingType(models.Package, models.PackageCost), nil
 
 	case models.Box:
 		if weight >= models.BoxWeightLimit {
-			return nil, fmt.Errorf("вес заказа превышает допустимый для коробки: %f", weight)
+			return nil, newValidationError("вес заказа превышает допустимый для коробки: %f", weight)
 		}
 		return models.NewPackagingType(models.Box, models.BoxCost), nil
 
@@ -267,6 +403,6 @@ func checkPackagingType(packagingType models.PackageType, weight float64) (*mode
 
 	default:
 		// If the packaging type is not allowed, return an error
-		return nil, fmt.Errorf("недопустимый тип упаковки: %s", packagingType)
+		return nil, newValidationError("недопустимый тип упаковки: %s", packagingType)
 	}
 }
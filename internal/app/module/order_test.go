@@ -6,12 +6,14 @@ import (
 	"testing"
 	"time"
 
-	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"route/internal/app/models"
+	mockmodule "route/internal/app/module/mocks"
+	"route/internal/app/pickup"
 	mockrepository "route/internal/app/repository/mocks"
+	"route/internal/app/repository/postgresql"
 )
 
 type OrderMatcher struct {
@@ -97,6 +99,7 @@ func TestCheckPackagingType(t *testing.T) {
 	}
 
 	for _, tt := range tests {
+		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
@@ -122,11 +125,6 @@ func TestCheckPackagingType(t *testing.T) {
 
 func TestModule_AcceptOrder(t *testing.T) {
 	t.Parallel()
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockRepo := mockrepository.NewMockRepository(ctrl)
-	module := New(mockRepo)
 
 	order := &models.Order{
 		OrderID:  1,
@@ -137,64 +135,112 @@ func TestModule_AcceptOrder(t *testing.T) {
 	}
 
 	expectedOrder := &models.Order{
-		OrderID: order.OrderID,
-		UserID:  order.UserID,
-		Weight:  order.Weight,
-		Cost:    order.Cost,
+		OrderID:             order.OrderID,
+		UserID:              order.UserID,
+		Weight:              order.Weight,
+		Cost:                order.Cost + models.PackageCost,
+		ReceivedFromCourier: true,
 	}
 
-	t.Run("order already exists", func(t *testing.T) {
-		t.Parallel()
-		mockRepo.EXPECT().GetOrderByID(order.OrderID).Return(&models.Order{}, nil)
-
-		err := module.AcceptOrder(order, models.Package)
-		assert.EqualError(t, err, "заказ с ID 1 уже существует")
-	})
-
-	t.Run("deadline in the past", func(t *testing.T) {
-		t.Parallel()
-		pastOrder := *order
-		pastOrder.Deadline = time.Now().Add(-24 * time.Hour) // Past deadline
-
-		mockRepo.EXPECT().GetOrderByID(pastOrder.OrderID).Return(nil, pgx.ErrNoRows)
-
-		err := module.AcceptOrder(&pastOrder, models.Package)
-		assert.EqualError(t, err, "срок хранения не может быть в прошлом")
-	})
-
-	t.Run("invalid packaging type", func(t *testing.T) {
-		t.Parallel()
-		mockRepo.EXPECT().GetOrderByID(order.OrderID).Return(nil, pgx.ErrNoRows)
-
-		err := module.AcceptOrder(order, "invalid")
-		assert.EqualError(t, err, "недопустимый тип упаковки: invalid")
-	})
-
-	t.Run("successful order acceptance", func(t *testing.T) {
-		t.Parallel()
-		mockRepo.EXPECT().GetOrderByID(order.OrderID).Return(nil, pgx.ErrNoRows)
-		mockRepo.EXPECT().AcceptOrder(EqOrder(expectedOrder), gomock.Any()).Return(nil)
+	pastOrder := *order
+	pastOrder.Deadline = time.Now().Add(-24 * time.Hour) // Past deadline
 
-		err := module.AcceptOrder(order, models.Package)
-		assert.NoError(t, err)
-	})
+	tests := []struct {
+		name          string
+		order         *models.Order
+		packagingType models.PackageType
+		setupMocks    func(mockRepo *mockrepository.MockRepository)
+		expectedError string
+	}{
+		{
+			name:          "order already exists",
+			order:         order,
+			packagingType: models.Package,
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(order.OrderID).Return(&models.Order{}, nil)
+			},
+			expectedError: "заказ с ID 1 уже существует",
+		},
+		{
+			name:          "deadline in the past",
+			order:         &pastOrder,
+			packagingType: models.Package,
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(pastOrder.OrderID).Return(nil, postgresql.ErrOrderNotFound)
+			},
+			expectedError: "срок хранения не может быть в прошлом",
+		},
+		{
+			name:          "invalid packaging type",
+			order:         order,
+			packagingType: "invalid",
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(order.OrderID).Return(nil, postgresql.ErrOrderNotFound)
+			},
+			expectedError: "недопустимый тип упаковки: invalid",
+		},
+		{
+			name:          "successful order acceptance",
+			order:         order,
+			packagingType: models.Package,
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(order.OrderID).Return(nil, postgresql.ErrOrderNotFound)
+				mockRepo.EXPECT().AcceptOrder(EqOrder(expectedOrder), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:          "no free storage cell",
+			order:         order,
+			packagingType: models.Package,
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(order.OrderID).Return(nil, postgresql.ErrOrderNotFound)
+				mockRepo.EXPECT().AcceptOrder(EqOrder(expectedOrder), gomock.Any()).Return(postgresql.ErrNoFreeCell)
+			},
+			expectedError: "в пункте выдачи нет свободной ячейки для заказа с ID 1",
+		},
+		{
+			name:          "repository error on GetOrderByID",
+			order:         order,
+			packagingType: models.Package,
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(order.OrderID).Return(nil, errors.New("database error"))
+			},
+			expectedError: "database error",
+		},
+		{
+			name:          "repository error on AcceptOrder",
+			order:         order,
+			packagingType: models.Package,
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(order.OrderID).Return(nil, postgresql.ErrOrderNotFound)
+				mockRepo.EXPECT().AcceptOrder(EqOrder(expectedOrder), gomock.Any()).Return(errors.New("database error"))
+			},
+			expectedError: "database error",
+		},
+	}
 
-	t.Run("repository error on GetOrderByID", func(t *testing.T) {
-		t.Parallel()
-		mockRepo.EXPECT().GetOrderByID(order.OrderID).Return(nil, errors.New("database error"))
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
 
-		err := module.AcceptOrder(order, models.Package)
-		assert.EqualError(t, err, "database error")
-	})
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockRepository(ctrl)
+			mod := New(mockRepo)
+			tt.setupMocks(mockRepo)
 
-	t.Run("repository error on AcceptOrder", func(t *testing.T) {
-		t.Parallel()
-		mockRepo.EXPECT().GetOrderByID(order.OrderID).Return(nil, pgx.ErrNoRows)
-		mockRepo.EXPECT().AcceptOrder(EqOrder(expectedOrder), gomock.Any()).Return("database error")
+			// act
+			err := mod.AcceptOrder(tt.order, tt.packagingType)
 
-		err := module.AcceptOrder(order, models.Package)
-		assert.EqualError(t, err, "database error")
-	})
+			// assert
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
 }
 
 func TestModule_ReturnOrder(t *testing.T) {
@@ -219,7 +265,7 @@ func TestModule_ReturnOrder(t *testing.T) {
 			name:    "order not found",
 			orderID: 1,
 			setupMocks: func() {
-				mockRepo.EXPECT().GetOrderByID(1).Return(nil, pgx.ErrNoRows)
+				mockRepo.EXPECT().GetOrderByID(1).Return(nil, postgresql.ErrOrderNotFound)
 			},
 			expectedError: fmt.Sprintf("заказ с ID %d не найден", 1),
 		},
@@ -239,6 +285,23 @@ func TestModule_ReturnOrder(t *testing.T) {
 			},
 			expectedError: fmt.Sprintf("заказ с ID %d еще не просрочен", 3),
 		},
+		{
+			name:    "order already handed over to courier",
+			orderID: 5,
+			setupMocks: func() {
+				mockRepo.EXPECT().GetOrderByID(5).Return(&models.Order{OrderID: 5, Deadline: pastTime, ReturnedToCourierAt: currentTime}, nil)
+			},
+			expectedError: fmt.Sprintf("заказ с ID %d уже передан курьеру", 5),
+		},
+		{
+			name:    "refused order returned before deadline",
+			orderID: 6,
+			setupMocks: func() {
+				mockRepo.EXPECT().GetOrderByID(6).Return(&models.Order{OrderID: 6, Deadline: futureTime, RefusedAt: currentTime}, nil)
+				mockRepo.EXPECT().ReturnOrder(6).Return(nil)
+			},
+			expectedError: "",
+		},
 		{
 			name:    "successful order return",
 			orderID: 4,
@@ -251,6 +314,7 @@ func TestModule_ReturnOrder(t *testing.T) {
 	}
 
 	for _, tt := range tests {
+		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
@@ -288,6 +352,7 @@ func TestModule_IssueOrder(t *testing.T) {
 	tests := []struct {
 		name          string
 		orderID       int
+		code          string
 		setupMocks    func()
 		expectedError string
 	}{
@@ -295,7 +360,7 @@ func TestModule_IssueOrder(t *testing.T) {
 			name:    "order not found",
 			orderID: 1,
 			setupMocks: func() {
-				mockRepo.EXPECT().GetOrderByID(1).Return(nil, pgx.ErrNoRows)
+				mockRepo.EXPECT().GetOrderByID(1).Return(nil, postgresql.ErrOrderNotFound)
 			},
 			expectedError: fmt.Sprintf("заказ с ID %d не найден", 1),
 		},
@@ -315,25 +380,47 @@ func TestModule_IssueOrder(t *testing.T) {
 			},
 			expectedError: fmt.Sprintf("заказ с ID %d не был получен курьером", 3),
 		},
+		{
+			name:    "order refused by user",
+			orderID: 5,
+			setupMocks: func() {
+				mockRepo.EXPECT().GetOrderByID(5).Return(&models.Order{OrderID: 5, ReceivedFromCourier: true, Deadline: futureTime, RefusedAt: currentTime}, nil)
+			},
+			expectedError: fmt.Sprintf("заказ с ID %d отклонен клиентом", 5),
+		},
+		{
+			name:    "order issued by another request",
+			orderID: 6,
+			code:    "123456",
+			setupMocks: func() {
+				mockRepo.EXPECT().GetOrderByID(6).Return(&models.Order{OrderID: 6, ReceivedFromCourier: true, Deadline: futureTime,
+					PickupCodeHash: pickup.HashCode(6, "123456")}, nil)
+				mockRepo.EXPECT().IssueOrder(6, gomock.Any(), models.IssueRecord{}).Return(postgresql.ErrOrderStateChanged)
+			},
+			expectedError: fmt.Sprintf("заказ с ID %d изменен другим запросом, повторите операцию", 6),
+		},
 		{
 			name:    "successful order issue",
 			orderID: 4,
+			code:    "123456",
 			setupMocks: func() {
-				mockRepo.EXPECT().GetOrderByID(4).Return(&models.Order{OrderID: 4, IssuedToUser: false, ReceivedFromCourier: true, Deadline: futureTime}, nil)
-				mockRepo.EXPECT().IssueOrder(4, gomock.Any()).Return(nil)
+				mockRepo.EXPECT().GetOrderByID(4).Return(&models.Order{OrderID: 4, IssuedToUser: false, ReceivedFromCourier: true, Deadline: futureTime,
+					PickupCodeHash: pickup.HashCode(4, "123456")}, nil)
+				mockRepo.EXPECT().IssueOrder(4, gomock.Any(), models.IssueRecord{}).Return(nil)
 			},
 			expectedError: "",
 		},
 	}
 
 	for _, tt := range tests {
+		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
 			tt.setupMocks()
 
 			// act
-			err := mod.IssueOrder(tt.orderID)
+			err := mod.IssueOrder(models.IssueRequest{OrderID: tt.orderID, PickupCode: tt.code})
 
 			// assert
 			if tt.expectedError == "" {
@@ -349,6 +436,88 @@ func TestModule_IssueOrder(t *testing.T) {
 	}
 }
 
+func TestModule_RefuseOrder(t *testing.T) {
+	t.Parallel()
+
+	currentTime := time.Now()
+	futureTime := currentTime.Add(24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		orderID       int
+		reason        string
+		setupMocks    func(mockRepo *mockrepository.MockRepository)
+		expectedError string
+	}{
+		{
+			name:          "empty reason",
+			orderID:       1,
+			reason:        " ",
+			setupMocks:    func(mockRepo *mockrepository.MockRepository) {},
+			expectedError: "не указана причина отказа",
+		},
+		{
+			name:    "order not found",
+			orderID: 1,
+			reason:  "поврежден",
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(1).Return(nil, postgresql.ErrOrderNotFound)
+			},
+			expectedError: "заказ с ID 1 не найден",
+		},
+		{
+			name:    "order already issued",
+			orderID: 2,
+			reason:  "поврежден",
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(2).Return(&models.Order{OrderID: 2, IssuedToUser: true}, nil)
+			},
+			expectedError: "заказ с ID 2 уже был выдан клиенту",
+		},
+		{
+			name:    "order already refused",
+			orderID: 3,
+			reason:  "поврежден",
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(3).Return(&models.Order{OrderID: 3, RefusedAt: currentTime}, nil)
+			},
+			expectedError: "заказ с ID 3 уже отклонен клиентом",
+		},
+		{
+			name:    "successful refusal",
+			orderID: 4,
+			reason:  "не подошел размер",
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(4).Return(&models.Order{OrderID: 4, ReceivedFromCourier: true, Deadline: futureTime}, nil)
+				mockRepo.EXPECT().RefuseOrder(4, "не подошел размер").Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockRepository(ctrl)
+			mod := New(mockRepo)
+			tt.setupMocks(mockRepo)
+
+			// act
+			err := mod.RefuseOrder(tt.orderID, tt.reason)
+
+			// assert
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestModule_ListOrders(t *testing.T) {
 	t.Parallel()
 
@@ -451,6 +620,129 @@ func TestModule_ListReturns(t *testing.T) {
 	})
 }
 
+func TestModule_ExportOrders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		filter        models.ExportFilter
+		setupMocks    func(mockRepo *mockrepository.MockRepository)
+		expectedError string
+	}{
+		{
+			name:   "orders are streamed from the repository",
+			filter: models.ExportFilter{Kind: models.ReturnsExport, Page: 2, PageSize: 10},
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().ExportOrders(models.ExportFilter{Kind: models.ReturnsExport, Page: 2, PageSize: 10}, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:          "unknown kind",
+			filter:        models.ExportFilter{Kind: "parcels"},
+			setupMocks:    func(mockRepo *mockrepository.MockRepository) {},
+			expectedError: "неизвестный тип выгрузки: parcels",
+		},
+		{
+			name:          "negative filter",
+			filter:        models.ExportFilter{Kind: models.OrdersExport, LastN: -1},
+			setupMocks:    func(mockRepo *mockrepository.MockRepository) {},
+			expectedError: "параметры выгрузки не могут быть отрицательными",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockRepository(ctrl)
+			mod := New(mockRepo)
+			tt.setupMocks(mockRepo)
+
+			// act
+			err := mod.ExportOrders(tt.filter, func(order models.Order) error { return nil })
+
+			// assert
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestModule_CreateReturnManifest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		courierID     int
+		setupMocks    func(mockRepo *mockrepository.MockRepository)
+		expectedCount int
+		expectedError string
+	}{
+		{
+			name:          "invalid courier",
+			courierID:     -1,
+			setupMocks:    func(mockRepo *mockrepository.MockRepository) {},
+			expectedError: "некорректный ID курьера: -1",
+		},
+		{
+			name:      "no orders to return",
+			courierID: 1,
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().CreateReturnManifest(1).Return(nil, postgresql.ErrNoOrdersToReturn)
+			},
+			expectedError: "нет просроченных заказов для возврата курьеру",
+		},
+		{
+			name:      "database error",
+			courierID: 1,
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().CreateReturnManifest(1).Return(nil, errors.New("database error"))
+			},
+			expectedError: "database error",
+		},
+		{
+			name:      "orders handed over",
+			courierID: 1,
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().CreateReturnManifest(1).Return(&models.ReturnManifest{
+					ID: 1, CourierID: 1, Orders: []models.Order{{OrderID: 1}, {OrderID: 2}},
+				}, nil)
+			},
+			expectedCount: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockRepository(ctrl)
+			mod := New(mockRepo)
+			tt.setupMocks(mockRepo)
+
+			// act
+			manifest, err := mod.CreateReturnManifest(tt.courierID)
+
+			// assert
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, manifest.Orders, tt.expectedCount)
+		})
+	}
+}
+
 func TestModule_AcceptReturn(t *testing.T) {
 	t.Parallel()
 
@@ -474,7 +766,7 @@ func TestModule_AcceptReturn(t *testing.T) {
 			orderID: 1,
 			userID:  1,
 			setupMocks: func() {
-				mockRepo.EXPECT().GetAllOrders().Return([]models.Order{}, nil)
+				mockRepo.EXPECT().GetOrderByID(1).Return(nil, postgresql.ErrOrderNotFound)
 			},
 			expectedError: fmt.Sprintf("заказ с ID %d не найден", 1),
 		},
@@ -483,8 +775,7 @@ func TestModule_AcceptReturn(t *testing.T) {
 			orderID: 2,
 			userID:  1,
 			setupMocks: func() {
-				mockRepo.EXPECT().GetAllOrders().Return([]models.Order{
-					{OrderID: 2, UserID: 1, IsReturned: true}}, nil)
+				mockRepo.EXPECT().GetOrderByID(2).Return(&models.Order{OrderID: 2, UserID: 1, IsReturned: true}, nil)
 			},
 			expectedError: fmt.Sprintf("заказ с ID %d уже был возвращен", 2),
 		},
@@ -493,8 +784,7 @@ func TestModule_AcceptReturn(t *testing.T) {
 			orderID: 3,
 			userID:  1,
 			setupMocks: func() {
-				mockRepo.EXPECT().GetAllOrders().Return([]models.Order{
-					{OrderID: 3, UserID: 1, IssuedToUser: false}}, nil)
+				mockRepo.EXPECT().GetOrderByID(3).Return(&models.Order{OrderID: 3, UserID: 1, IssuedToUser: false}, nil)
 			},
 			expectedError: fmt.Sprintf("заказ с ID %d не был выдан клиенту", 3),
 		},
@@ -503,8 +793,7 @@ func TestModule_AcceptReturn(t *testing.T) {
 			orderID: 4,
 			userID:  1,
 			setupMocks: func() {
-				mockRepo.EXPECT().GetAllOrders().Return([]models.Order{
-					{OrderID: 4, UserID: 1, IssuedToUser: true, IssuedAt: currentTime.Add(-49 * time.Hour)}}, nil)
+				mockRepo.EXPECT().GetOrderByID(4).Return(&models.Order{OrderID: 4, UserID: 1, IssuedToUser: true, IssuedAt: currentTime.Add(-49 * time.Hour)}, nil)
 			},
 			expectedError: fmt.Sprintf("заказ с ID %d не может быть возвращен, так как прошло более двух дней с момента его выдачи", 4),
 		},
@@ -513,8 +802,7 @@ func TestModule_AcceptReturn(t *testing.T) {
 			orderID: 5,
 			userID:  1,
 			setupMocks: func() {
-				mockRepo.EXPECT().GetAllOrders().Return([]models.Order{
-					{OrderID: 5, UserID: 1, IssuedToUser: true, IssuedAt: currentTime}}, nil)
+				mockRepo.EXPECT().GetOrderByID(5).Return(&models.Order{OrderID: 5, UserID: 1, IssuedToUser: true, IssuedAt: currentTime}, nil)
 				mockRepo.EXPECT().AcceptReturn(gomock.Any()).Return(nil)
 			},
 			expectedError: "",
@@ -522,6 +810,7 @@ func TestModule_AcceptReturn(t *testing.T) {
 	}
 
 	for _, tt := range tests {
+		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			tt.setupMocks()
@@ -542,3 +831,73 @@ func TestModule_AcceptReturn(t *testing.T) {
 		})
 	}
 }
+
+func TestModule_Notifications(t *testing.T) {
+	t.Parallel()
+
+	order := &models.Order{
+		OrderID:  1,
+		UserID:   1,
+		Deadline: time.Now().Add(24 * time.Hour),
+		Weight:   5,
+		Cost:     100,
+	}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(mockRepo *mockrepository.MockRepository, mockNotifications *mockmodule.MockNotifications)
+		expectedError string
+	}{
+		{
+			name: "client is notified about arrival",
+			setupMocks: func(mockRepo *mockrepository.MockRepository, mockNotifications *mockmodule.MockNotifications) {
+				mockRepo.EXPECT().GetOrderByID(order.OrderID).Return(nil, postgresql.ErrOrderNotFound)
+				mockRepo.EXPECT().AcceptOrder(gomock.Any(), gomock.Any()).Return(nil)
+				mockNotifications.EXPECT().Notify(models.OrderArrived, gomock.Any()).DoAndReturn(func(_ models.NotificationKind, o models.Order) error {
+					assert.Equal(t, order.Cost+models.PackageCost, o.Cost)
+					return nil
+				})
+			},
+		},
+		{
+			name: "failed notification doesn't fail acceptance",
+			setupMocks: func(mockRepo *mockrepository.MockRepository, mockNotifications *mockmodule.MockNotifications) {
+				mockRepo.EXPECT().GetOrderByID(order.OrderID).Return(nil, postgresql.ErrOrderNotFound)
+				mockRepo.EXPECT().AcceptOrder(gomock.Any(), gomock.Any()).Return(nil)
+				mockNotifications.EXPECT().Notify(models.OrderArrived, gomock.Any()).Return(errors.New("sms provider is down"))
+			},
+		},
+		{
+			name: "client is not notified if acceptance failed",
+			setupMocks: func(mockRepo *mockrepository.MockRepository, _ *mockmodule.MockNotifications) {
+				mockRepo.EXPECT().GetOrderByID(order.OrderID).Return(nil, postgresql.ErrOrderNotFound)
+				mockRepo.EXPECT().AcceptOrder(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+			},
+			expectedError: "database error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockRepository(ctrl)
+			mockNotifications := mockmodule.NewMockNotifications(ctrl)
+			mod := New(mockRepo).WithNotifications(mockNotifications)
+			tt.setupMocks(mockRepo, mockNotifications)
+
+			// act
+			err := mod.AcceptOrder(order, models.Package)
+
+			// assert
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
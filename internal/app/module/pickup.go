@@ -0,0 +1,72 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"route/internal/app/models"
+	"route/internal/app/pickup"
+	"route/internal/app/repository/postgresql"
+)
+
+const (
+	// maxPickupFailures is the number of wrong pickup codes in a row after which the order is locked
+	maxPickupFailures = 5
+	// pickupRetryDelay is the minimal delay between attempts after a wrong pickup code
+	pickupRetryDelay = 10 * time.Second
+)
+
+// checkPickupCode checks the pickup code presented by the client. A wrong code is counted,
+// and the order is locked after maxPickupFailures wrong codes until a manager unlocks it
+func (m OrderModule) checkPickupCode(order *models.Order, code string) error {
+	if !order.PickupLockedAt.IsZero() {
+		return newValidationError("заказ с ID %d заблокирован после %d неверных кодов получения, обратитесь к менеджеру",
+			order.OrderID, maxPickupFailures)
+	}
+
+	// Orders accepted before pickup codes were introduced have no code
+	if order.PickupCodeHash == "" {
+		return newValidationError("у заказа с ID %d нет кода получения, выдача возможна только менеджером", order.OrderID)
+	}
+
+	if code == "" {
+		return newValidationError("не указан код получения заказа с ID %d", order.OrderID)
+	}
+
+	if wait := time.Until(order.PickupLastFailureAt.Add(pickupRetryDelay)); order.PickupFailures > 0 && wait > 0 {
+		return newValidationError("слишком частые попытки ввода кода, повторите через %d с", int(math.Ceil(wait.Seconds())))
+	}
+
+	if pickup.VerifyCode(order.OrderID, code, order.PickupCodeHash) {
+		return nil
+	}
+
+	updated, err := m.repo.RecordPickupFailure(order.OrderID, maxPickupFailures)
+	if err != nil {
+		return err
+	}
+	if !updated.PickupLockedAt.IsZero() {
+		return newValidationError("неверный код получения, заказ с ID %d заблокирован", order.OrderID)
+	}
+	return newValidationError("неверный код получения, осталось попыток: %d", maxPickupFailures-updated.PickupFailures)
+}
+
+// UnlockOrder unlocks the order locked after wrong pickup codes
+func (m OrderModule) UnlockOrder(orderID int) error {
+	order, err := m.repo.GetOrderByID(orderID)
+	if err != nil && !errors.Is(err, postgresql.ErrOrderNotFound) {
+		return err
+	}
+
+	if order == nil {
+		return fmt.Errorf("заказ с ID %d не найден", orderID)
+	}
+
+	if order.PickupLockedAt.IsZero() && order.PickupFailures == 0 {
+		return newValidationError("заказ с ID %d не заблокирован", orderID)
+	}
+
+	return m.repo.UnlockPickup(orderID)
+}
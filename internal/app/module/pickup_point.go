@@ -0,0 +1,48 @@
+package module
+
+import (
+	"errors"
+	"strings"
+
+	"route/internal/app/models"
+	"route/internal/app/repository"
+)
+
+var errPickupPointsNotConfigured = errors.New("пункты выдачи не настроены")
+
+// WithPickupPoints returns a copy of the module that manages pickup points of the network
+func (m OrderModule) WithPickupPoints(points repository.PickupPointRepository) *OrderModule {
+	m.points = points
+	return &m
+}
+
+// ForPoint returns a copy of the module serving the pickup point, all orders
+// it reads and changes belong to the point
+func (m OrderModule) ForPoint(pointID int) *OrderModule {
+	m.repo = m.repo.ForPoint(pointID)
+	m.pointID = pointID
+	return &m
+}
+
+// CreatePickupPoint adds a pickup point to the network
+func (m OrderModule) CreatePickupPoint(name, address string) (*models.PickupPoint, error) {
+	if m.points == nil {
+		return nil, errPickupPointsNotConfigured
+	}
+
+	point := models.PickupPoint{Name: strings.TrimSpace(name), Address: strings.TrimSpace(address)}
+	if point.Name == "" {
+		return nil, newValidationError("не указано название пункта выдачи")
+	}
+
+	return m.points.CreatePickupPoint(point)
+}
+
+// ListPickupPoints returns pickup points of the network
+func (m OrderModule) ListPickupPoints() ([]models.PickupPoint, error) {
+	if m.points == nil {
+		return nil, errPickupPointsNotConfigured
+	}
+
+	return m.points.ListPickupPoints()
+}
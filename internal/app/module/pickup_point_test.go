@@ -0,0 +1,98 @@
+package module
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"route/internal/app/models"
+	mockrepository "route/internal/app/repository/mocks"
+)
+
+func TestModule_AcceptReturnAtPoint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		order         *models.Order
+		setupMocks    func(mockRepo *mockrepository.MockRepository)
+		expectedError string
+	}{
+		{
+			name:          "order issued at another point",
+			order:         &models.Order{OrderID: 4, UserID: 1, IssuedToUser: true, IssuedAt: time.Now(), PickupPointID: 3},
+			setupMocks:    func(*mockrepository.MockRepository) {},
+			expectedError: "заказ с ID 4 выдан в другом пункте выдачи",
+		},
+		{
+			name:  "order issued at our point",
+			order: &models.Order{OrderID: 4, UserID: 1, IssuedToUser: true, IssuedAt: time.Now(), PickupPointID: 2},
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().AcceptReturn(gomock.Any()).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockRepository(ctrl)
+			mockRepo.EXPECT().ForPoint(2).Return(mockRepo)
+			mockRepo.EXPECT().GetOrderByID(4).Return(tt.order, nil)
+			tt.setupMocks(mockRepo)
+			mod := New(mockRepo).ForPoint(2)
+
+			// act
+			err := mod.AcceptReturn(4, 1)
+
+			// assert
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestModule_CreatePickupPoint(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty name", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		ctrl := gomock.NewController(t)
+		mod := New(mockrepository.NewMockRepository(ctrl)).WithPickupPoints(mockrepository.NewMockPickupPointRepository(ctrl))
+
+		// act
+		_, err := mod.CreatePickupPoint(" ", "Ленина, 1")
+
+		// assert
+		assert.EqualError(t, err, "не указано название пункта выдачи")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		ctrl := gomock.NewController(t)
+		mockPoints := mockrepository.NewMockPickupPointRepository(ctrl)
+		mockPoints.EXPECT().CreatePickupPoint(models.PickupPoint{Name: "Северный", Address: "Ленина, 1"}).
+			Return(&models.PickupPoint{ID: 2, Name: "Северный", Address: "Ленина, 1"}, nil)
+		mod := New(mockrepository.NewMockRepository(ctrl)).WithPickupPoints(mockPoints)
+
+		// act
+		point, err := mod.CreatePickupPoint(" Северный ", "Ленина, 1")
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 2, point.ID)
+	})
+}
@@ -0,0 +1,182 @@
+package module
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"route/internal/app/models"
+	"route/internal/app/pickup"
+	mockrepository "route/internal/app/repository/mocks"
+)
+
+func TestModule_IssueOrderPickupCode(t *testing.T) {
+	t.Parallel()
+
+	const orderID = 7
+	futureTime := time.Now().Add(24 * time.Hour)
+	codeHash := pickup.HashCode(orderID, "123456")
+	newOrder := func() *models.Order {
+		return &models.Order{OrderID: orderID, ReceivedFromCourier: true, Deadline: futureTime, PickupCodeHash: codeHash}
+	}
+
+	tests := []struct {
+		name          string
+		req           models.IssueRequest
+		setupMocks    func(mockRepo *mockrepository.MockRepository)
+		expectedError string
+	}{
+		{
+			name: "missing code",
+			req:  models.IssueRequest{OrderID: orderID},
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(orderID).Return(newOrder(), nil)
+			},
+			expectedError: "не указан код получения заказа с ID 7",
+		},
+		{
+			name: "wrong code is counted",
+			req:  models.IssueRequest{OrderID: orderID, PickupCode: "000000"},
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(orderID).Return(newOrder(), nil)
+				mockRepo.EXPECT().RecordPickupFailure(orderID, maxPickupFailures).Return(&models.Order{OrderID: orderID, PickupFailures: 2}, nil)
+			},
+			expectedError: "неверный код получения, осталось попыток: 3",
+		},
+		{
+			name: "last wrong code locks order",
+			req:  models.IssueRequest{OrderID: orderID, PickupCode: "000000"},
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(orderID).Return(newOrder(), nil)
+				mockRepo.EXPECT().RecordPickupFailure(orderID, maxPickupFailures).Return(&models.Order{OrderID: orderID,
+					PickupFailures: maxPickupFailures, PickupLockedAt: time.Now()}, nil)
+			},
+			expectedError: "неверный код получения, заказ с ID 7 заблокирован",
+		},
+		{
+			name: "attempt right after wrong code",
+			req:  models.IssueRequest{OrderID: orderID, PickupCode: "123456"},
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				order := newOrder()
+				order.PickupFailures = 1
+				order.PickupLastFailureAt = time.Now()
+				mockRepo.EXPECT().GetOrderByID(orderID).Return(order, nil)
+			},
+			expectedError: "слишком частые попытки ввода кода, повторите через 10 с",
+		},
+		{
+			name: "locked order",
+			req:  models.IssueRequest{OrderID: orderID, PickupCode: "123456"},
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				order := newOrder()
+				order.PickupLockedAt = time.Now()
+				mockRepo.EXPECT().GetOrderByID(orderID).Return(order, nil)
+			},
+			expectedError: "заказ с ID 7 заблокирован после 5 неверных кодов получения, обратитесь к менеджеру",
+		},
+		{
+			name: "order without code",
+			req:  models.IssueRequest{OrderID: orderID, PickupCode: "123456"},
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				order := newOrder()
+				order.PickupCodeHash = ""
+				mockRepo.EXPECT().GetOrderByID(orderID).Return(order, nil)
+			},
+			expectedError: "у заказа с ID 7 нет кода получения, выдача возможна только менеджером",
+		},
+		{
+			name: "right code after earlier failure",
+			req:  models.IssueRequest{OrderID: orderID, PickupCode: "123456"},
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				order := newOrder()
+				order.PickupFailures = 1
+				order.PickupLastFailureAt = time.Now().Add(-time.Minute)
+				mockRepo.EXPECT().GetOrderByID(orderID).Return(order, nil)
+				mockRepo.EXPECT().IssueOrder(orderID, gomock.Any(), models.IssueRecord{}).Return(nil)
+			},
+		},
+		{
+			name: "manager overrides locked order",
+			req:  models.IssueRequest{OrderID: orderID, OverrideBy: "Иванов"},
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				order := newOrder()
+				order.PickupLockedAt = time.Now()
+				mockRepo.EXPECT().GetOrderByID(orderID).Return(order, nil)
+				mockRepo.EXPECT().IssueOrder(orderID, gomock.Any(), models.IssueRecord{OverrideBy: "Иванов"}).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockRepository(ctrl)
+			mod := New(mockRepo)
+			tt.setupMocks(mockRepo)
+
+			// act
+			err := mod.IssueOrder(tt.req)
+
+			// assert
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestModule_UnlockOrder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		setupMocks    func(mockRepo *mockrepository.MockRepository)
+		expectedError string
+	}{
+		{
+			name: "order is not locked",
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(3).Return(&models.Order{OrderID: 3}, nil)
+			},
+			expectedError: "заказ с ID 3 не заблокирован",
+		},
+		{
+			name: "locked order is unlocked",
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(3).Return(&models.Order{OrderID: 3, PickupFailures: 5, PickupLockedAt: time.Now()}, nil)
+				mockRepo.EXPECT().UnlockPickup(3).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockRepository(ctrl)
+			mod := New(mockRepo)
+			tt.setupMocks(mockRepo)
+
+			// act
+			err := mod.UnlockOrder(3)
+
+			// assert
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package module
+
+import (
+	"errors"
+	"time"
+
+	"route/internal/app/models"
+	"route/internal/app/repository"
+)
+
+// maxReportDays limits the period of a report
+const maxReportDays = 366
+
+var errReportsNotConfigured = errors.New("отчеты не настроены")
+
+// WithReports returns a copy of the module that builds shift and revenue reports
+func (m OrderModule) WithReports(reports repository.ReportRepository) *OrderModule {
+	m.reports = reports
+	return &m
+}
+
+// Report aggregates orders of the served pickup point, or of all points, for the days from from to to inclusive.
+// Only dates of from and to are used
+func (m OrderModule) Report(from, to time.Time) (*models.Report, error) {
+	if m.reports == nil {
+		return nil, errReportsNotConfigured
+	}
+
+	from = truncateDay(from)
+	to = truncateDay(to)
+	if from.After(to) {
+		return nil, newValidationError("начало периода отчета позже его конца")
+	}
+	if to.Sub(from) >= maxReportDays*24*time.Hour {
+		return nil, newValidationError("период отчета не может быть больше %d дней", maxReportDays)
+	}
+
+	rows, err := m.reports.GetReport(m.pointID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Report{PickupPointID: m.pointID, From: from, To: to, Rows: rows}, nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,79 @@
+package module
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"route/internal/app/models"
+	mockrepository "route/internal/app/repository/mocks"
+)
+
+func TestModule_Report(t *testing.T) {
+	t.Parallel()
+
+	day := func(d int) time.Time {
+		return time.Date(2030, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+	rows := []models.ReportRow{{Day: day(2), PackagingType: models.Box, Accepted: 2, Issued: 1, SurchargeRevenue: 20}}
+
+	tests := []struct {
+		name           string
+		from, to       time.Time
+		setupMocks     func(mockReports *mockrepository.MockReportRepository)
+		expectedReport *models.Report
+		expectedError  string
+	}{
+		{
+			name: "dates are truncated to days and the end is inclusive",
+			from: day(1).Add(15 * time.Hour),
+			to:   day(3).Add(9 * time.Hour),
+			setupMocks: func(mockReports *mockrepository.MockReportRepository) {
+				mockReports.EXPECT().GetReport(2, day(1), day(4)).Return(rows, nil)
+			},
+			expectedReport: &models.Report{PickupPointID: 2, From: day(1), To: day(3), Rows: rows},
+		},
+		{
+			name:          "start after end",
+			from:          day(3),
+			to:            day(1),
+			setupMocks:    func(*mockrepository.MockReportRepository) {},
+			expectedError: "начало периода отчета позже его конца",
+		},
+		{
+			name:          "period is too long",
+			from:          day(1),
+			to:            day(1).AddDate(1, 0, 1),
+			setupMocks:    func(*mockrepository.MockReportRepository) {},
+			expectedError: "период отчета не может быть больше 366 дней",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockRepository(ctrl)
+			mockReports := mockrepository.NewMockReportRepository(ctrl)
+			mockRepo.EXPECT().ForPoint(2).Return(mockRepo)
+			mod := New(mockRepo).WithReports(mockReports).ForPoint(2)
+			tt.setupMocks(mockReports)
+
+			// act
+			report, err := mod.Report(tt.from, tt.to)
+
+			// assert
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedReport, report)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package module
+
+import (
+	"errors"
+	"sort"
+
+	"route/internal/app/models"
+	"route/internal/app/repository"
+	"route/internal/app/repository/postgresql"
+)
+
+var errStocktakesNotConfigured = errors.New("инвентаризация не настроена")
+
+// WithStocktakes returns a copy of the module that saves stocktake reports
+func (m OrderModule) WithStocktakes(stocktakes repository.StocktakeRepository) *OrderModule {
+	m.stocktakes = stocktakes
+	return &m
+}
+
+// Stocktake compares order IDs scanned on the shelves with orders kept at the pickup point per the database
+// and saves the report of discrepancies. Repeated scans of an order are counted once
+func (m OrderModule) Stocktake(scanned []int) (*models.Stocktake, error) {
+	if m.stocktakes == nil {
+		return nil, errStocktakesNotConfigured
+	}
+
+	seen := make(map[int]bool, len(scanned))
+	for _, orderID := range scanned {
+		if orderID <= 0 {
+			return nil, newValidationError("некорректный ID заказа: %d", orderID)
+		}
+		seen[orderID] = true
+	}
+
+	present, err := m.repo.ListPresentOrders()
+	if err != nil {
+		return nil, err
+	}
+
+	stocktake := models.Stocktake{PickupPointID: m.servedPoint(), Scanned: len(seen), Stored: len(present)}
+	isPresent := make(map[int]bool, len(present))
+	for _, order := range present {
+		isPresent[order.OrderID] = true
+		if !seen[order.OrderID] {
+			stocktake.Discrepancies = append(stocktake.Discrepancies, models.Discrepancy{OrderID: order.OrderID, Kind: models.Missing})
+		}
+	}
+
+	extra := make([]int, 0)
+	for orderID := range seen {
+		if !isPresent[orderID] {
+			extra = append(extra, orderID)
+		}
+	}
+	sort.Ints(extra)
+
+	// Orders on the shelves that shouldn't be there are either issued by mistake or unknown to the point
+	for _, orderID := range extra {
+		order, err := m.repo.GetOrderByID(orderID)
+		if err != nil && !errors.Is(err, postgresql.ErrOrderNotFound) {
+			return nil, err
+		}
+
+		kind := models.Unexpected
+		if order != nil && order.IssuedToUser && !order.IsReturned {
+			kind = models.WronglyIssued
+		}
+		stocktake.Discrepancies = append(stocktake.Discrepancies, models.Discrepancy{OrderID: orderID, Kind: kind})
+	}
+
+	return m.stocktakes.SaveStocktake(stocktake)
+}
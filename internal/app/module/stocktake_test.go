@@ -0,0 +1,79 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"route/internal/app/models"
+	mockrepository "route/internal/app/repository/mocks"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestModule_Stocktake(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		scanned       []int
+		setupMocks    func(mockRepo *mockrepository.MockRepository, mockStocktakes *mockrepository.MockStocktakeRepository)
+		expectedError string
+	}{
+		{
+			name:          "invalid order ID",
+			scanned:       []int{1, -2},
+			setupMocks:    func(*mockrepository.MockRepository, *mockrepository.MockStocktakeRepository) {},
+			expectedError: "некорректный ID заказа: -2",
+		},
+		{
+			name:    "no discrepancies",
+			scanned: []int{2, 1, 2},
+			setupMocks: func(mockRepo *mockrepository.MockRepository, mockStocktakes *mockrepository.MockStocktakeRepository) {
+				mockRepo.EXPECT().ListPresentOrders().Return([]models.Order{{OrderID: 1}, {OrderID: 2}}, nil)
+				mockStocktakes.EXPECT().SaveStocktake(models.Stocktake{PickupPointID: 2, Scanned: 2, Stored: 2}).
+					Return(&models.Stocktake{ID: 1}, nil)
+			},
+		},
+		{
+			name:    "discrepancies are reported",
+			scanned: []int{9, 1, 5},
+			setupMocks: func(mockRepo *mockrepository.MockRepository, mockStocktakes *mockrepository.MockStocktakeRepository) {
+				mockRepo.EXPECT().ListPresentOrders().Return([]models.Order{{OrderID: 1}, {OrderID: 2}}, nil)
+				mockRepo.EXPECT().GetOrderByID(5).Return(&models.Order{OrderID: 5, IssuedToUser: true}, nil)
+				mockRepo.EXPECT().GetOrderByID(9).Return(nil, postgresql.ErrOrderNotFound)
+				mockStocktakes.EXPECT().SaveStocktake(models.Stocktake{PickupPointID: 2, Scanned: 3, Stored: 2,
+					Discrepancies: []models.Discrepancy{
+						{OrderID: 2, Kind: models.Missing},
+						{OrderID: 5, Kind: models.WronglyIssued},
+						{OrderID: 9, Kind: models.Unexpected},
+					}}).Return(&models.Stocktake{ID: 1}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockRepository(ctrl)
+			mockStocktakes := mockrepository.NewMockStocktakeRepository(ctrl)
+			mockRepo.EXPECT().ForPoint(2).Return(mockRepo)
+			mod := New(mockRepo).WithStocktakes(mockStocktakes).ForPoint(2)
+			tt.setupMocks(mockRepo, mockStocktakes)
+
+			// act
+			_, err := mod.Stocktake(tt.scanned)
+
+			// assert
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
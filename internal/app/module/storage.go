@@ -0,0 +1,67 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"route/internal/app/models"
+	"route/internal/app/repository"
+)
+
+// maxRackCells limits the number of cells of one rack
+const maxRackCells = 100
+
+var errStorageNotConfigured = errors.New("ячейки хранения не настроены")
+
+// WithStorage returns a copy of the module that manages storage cells of the pickup point
+func (m OrderModule) WithStorage(storage repository.StorageRepository) *OrderModule {
+	m.storage = storage
+	return &m
+}
+
+// CreateRack adds a rack of identical cells to the pickup point, cells are numbered within the rack
+func (m OrderModule) CreateRack(name string, cellCount int, size models.CellSize, maxWeight float64) (*models.Rack, error) {
+	if m.storage == nil {
+		return nil, errStorageNotConfigured
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, newValidationError("не указано название стеллажа")
+	}
+	if cellCount <= 0 || cellCount > maxRackCells {
+		return nil, newValidationError("количество ячеек стеллажа должно быть от 1 до %d", maxRackCells)
+	}
+	if size.String() == "" {
+		return nil, newValidationError("недопустимый размер ячейки, допустимы S, M и L")
+	}
+	if maxWeight <= 0 {
+		return nil, newValidationError("допустимый вес ячейки должен быть положительным")
+	}
+
+	rack := models.Rack{PickupPointID: m.servedPoint(), Name: name, Cells: make([]models.StorageCell, cellCount)}
+	for i := range rack.Cells {
+		rack.Cells[i] = models.StorageCell{Code: fmt.Sprintf("%s-%02d", name, i+1), Size: size, MaxWeight: maxWeight}
+	}
+
+	return m.storage.CreateRack(rack)
+}
+
+// ListCells returns storage cells of the pickup point with the orders stored in them
+func (m OrderModule) ListCells() ([]models.StorageCell, error) {
+	if m.storage == nil {
+		return nil, errStorageNotConfigured
+	}
+
+	return m.storage.ListCells(m.servedPoint())
+}
+
+// servedPoint is the pickup point the module works with,
+// a module of all points works with the default point like it accepts orders to it
+func (m OrderModule) servedPoint() int {
+	if m.pointID == 0 {
+		return models.DefaultPickupPointID
+	}
+	return m.pointID
+}
@@ -0,0 +1,124 @@
+package module
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"route/internal/app/models"
+	mockrepository "route/internal/app/repository/mocks"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestModule_CreateRack(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		rackName      string
+		cellCount     int
+		size          models.CellSize
+		maxWeight     float64
+		setupMocks    func(mockStorage *mockrepository.MockStorageRepository)
+		expectedError string
+	}{
+		{
+			name:          "empty name",
+			rackName:      " ",
+			cellCount:     2,
+			size:          models.CellSmall,
+			maxWeight:     5,
+			setupMocks:    func(*mockrepository.MockStorageRepository) {},
+			expectedError: "не указано название стеллажа",
+		},
+		{
+			name:          "too many cells",
+			rackName:      "A",
+			cellCount:     maxRackCells + 1,
+			size:          models.CellSmall,
+			maxWeight:     5,
+			setupMocks:    func(*mockrepository.MockStorageRepository) {},
+			expectedError: "количество ячеек стеллажа должно быть от 1 до 100",
+		},
+		{
+			name:          "unknown size",
+			rackName:      "A",
+			cellCount:     2,
+			size:          models.ToCellSize("XL"),
+			maxWeight:     5,
+			setupMocks:    func(*mockrepository.MockStorageRepository) {},
+			expectedError: "недопустимый размер ячейки, допустимы S, M и L",
+		},
+		{
+			name:          "zero weight",
+			rackName:      "A",
+			cellCount:     2,
+			size:          models.CellSmall,
+			setupMocks:    func(*mockrepository.MockStorageRepository) {},
+			expectedError: "допустимый вес ячейки должен быть положительным",
+		},
+		{
+			name:      "cells are numbered",
+			rackName:  " A ",
+			cellCount: 2,
+			size:      models.CellLarge,
+			maxWeight: 30,
+			setupMocks: func(mockStorage *mockrepository.MockStorageRepository) {
+				mockStorage.EXPECT().CreateRack(models.Rack{PickupPointID: 3, Name: "A", Cells: []models.StorageCell{
+					{Code: "A-01", Size: models.CellLarge, MaxWeight: 30},
+					{Code: "A-02", Size: models.CellLarge, MaxWeight: 30},
+				}}).Return(&models.Rack{ID: 1}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockRepository(ctrl)
+			mockStorage := mockrepository.NewMockStorageRepository(ctrl)
+			mockRepo.EXPECT().ForPoint(3).Return(mockRepo)
+			mod := New(mockRepo).WithStorage(mockStorage).ForPoint(3)
+			tt.setupMocks(mockStorage)
+
+			// act
+			_, err := mod.CreateRack(tt.rackName, tt.cellCount, tt.size, tt.maxWeight)
+
+			// assert
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestModule_AcceptOrderToCell(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctrl := gomock.NewController(t)
+	mockRepo := mockrepository.NewMockRepository(ctrl)
+	mod := New(mockRepo)
+	order := &models.Order{OrderID: 1, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), Weight: 5, Cost: 100}
+	mockRepo.EXPECT().GetOrderByID(1).Return(nil, postgresql.ErrOrderNotFound)
+	mockRepo.EXPECT().AcceptOrder(gomock.Any(), gomock.Any()).DoAndReturn(func(o *models.Order, _ *models.PackagingType) error {
+		o.CellID, o.Cell = 7, "B-03"
+		return nil
+	})
+
+	// act
+	err := mod.AcceptOrder(order, models.Box)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 7, order.CellID)
+	assert.Equal(t, "B-03", order.Cell)
+}
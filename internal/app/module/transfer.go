@@ -0,0 +1,118 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+
+	"route/internal/app/models"
+	"route/internal/app/repository/postgresql"
+)
+
+// CreateTransfer sends orders stored at the module's pickup point to another point of the network.
+// The orders stay in transit and can't be issued until the transfer is received
+func (m OrderModule) CreateTransfer(toPointID int, orderIDs []int) (*models.Transfer, error) {
+	if m.pointID == 0 {
+		return nil, newValidationError("не указан пункт выдачи отправителя")
+	}
+	if toPointID <= 0 {
+		return nil, newValidationError("некорректный ID пункта выдачи: %d", toPointID)
+	}
+	if toPointID == m.pointID {
+		return nil, newValidationError("заказы уже находятся в пункте выдачи %d", toPointID)
+	}
+	if len(orderIDs) == 0 {
+		return nil, newValidationError("не указаны заказы для перемещения")
+	}
+
+	// Pickup points are checked only if the module knows them
+	if m.points != nil {
+		_, err := m.points.GetPickupPoint(toPointID)
+		if errors.Is(err, postgresql.ErrPickupPointNotFound) {
+			return nil, newValidationError("пункт выдачи с ID %d не найден", toPointID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	seen := make(map[int]bool, len(orderIDs))
+	for _, orderID := range orderIDs {
+		if seen[orderID] {
+			return nil, newValidationError("заказ с ID %d указан несколько раз", orderID)
+		}
+		seen[orderID] = true
+
+		if err := m.checkTransferable(orderID); err != nil {
+			return nil, err
+		}
+	}
+
+	transfer, err := m.repo.CreateTransfer(toPointID, orderIDs)
+	if errors.Is(err, postgresql.ErrOrdersNotTransferable) {
+		// Some order changed after the check, e.g. it was issued meanwhile
+		return nil, newValidationError("состав заказов изменился, повторите перемещение")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// checkTransferable checks that the order is stored at the pickup point and can be moved
+func (m OrderModule) checkTransferable(orderID int) error {
+	order, err := m.repo.GetOrderByID(orderID)
+	if err != nil && !errors.Is(err, postgresql.ErrOrderNotFound) {
+		return err
+	}
+	if order == nil {
+		return fmt.Errorf("заказ с ID %d не найден", orderID)
+	}
+
+	switch {
+	case order.TransferID != 0:
+		return newValidationError("заказ с ID %d уже в пути", orderID)
+	case order.IssuedToUser:
+		return newValidationError("заказ с ID %d уже был выдан клиенту", orderID)
+	case order.IsReturned:
+		return newValidationError("заказ с ID %d уже был возвращен", orderID)
+	case !order.ReturnedToCourierAt.IsZero():
+		return newValidationError("заказ с ID %d уже передан курьеру", orderID)
+	}
+
+	return nil
+}
+
+// ReceiveTransfer receives orders sent to the module's pickup point, they can be issued from it afterwards
+func (m OrderModule) ReceiveTransfer(transferID int64) (*models.Transfer, error) {
+	if m.pointID == 0 {
+		return nil, newValidationError("не указан пункт выдачи получателя")
+	}
+
+	transfer, err := m.repo.GetTransfer(transferID)
+	if errors.Is(err, postgresql.ErrTransferNotFound) {
+		return nil, newValidationError("перемещение %d не найдено", transferID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if transfer.ToPointID != m.pointID {
+		return nil, newValidationError("перемещение %d направлено в пункт выдачи %d", transferID, transfer.ToPointID)
+	}
+	if transfer.Received() {
+		return nil, newValidationError("перемещение %d уже получено", transferID)
+	}
+
+	transfer, err = m.repo.ReceiveTransfer(transferID)
+	if errors.Is(err, postgresql.ErrTransferReceived) {
+		return nil, newValidationError("перемещение %d уже получено", transferID)
+	}
+	if errors.Is(err, postgresql.ErrNoFreeCell) {
+		return nil, newValidationError("в пункте выдачи нет свободных ячеек для заказов перемещения %d", transferID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
@@ -0,0 +1,185 @@
+package module
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"route/internal/app/models"
+	mockrepository "route/internal/app/repository/mocks"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestModule_CreateTransfer(t *testing.T) {
+	t.Parallel()
+
+	stored := &models.Order{OrderID: 1, PickupPointID: 1, ReceivedFromCourier: true, Deadline: time.Now().Add(24 * time.Hour)}
+
+	tests := []struct {
+		name          string
+		toPointID     int
+		orderIDs      []int
+		setupMocks    func(mockRepo *mockrepository.MockRepository)
+		expectedError string
+	}{
+		{
+			name:          "same pickup point",
+			toPointID:     1,
+			orderIDs:      []int{1},
+			setupMocks:    func(mockRepo *mockrepository.MockRepository) {},
+			expectedError: "заказы уже находятся в пункте выдачи 1",
+		},
+		{
+			name:          "no orders",
+			toPointID:     2,
+			setupMocks:    func(mockRepo *mockrepository.MockRepository) {},
+			expectedError: "не указаны заказы для перемещения",
+		},
+		{
+			name:      "duplicate order",
+			toPointID: 2,
+			orderIDs:  []int{1, 1},
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(1).Return(stored, nil)
+			},
+			expectedError: "заказ с ID 1 указан несколько раз",
+		},
+		{
+			name:      "order of another point",
+			toPointID: 2,
+			orderIDs:  []int{5},
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(5).Return(nil, postgresql.ErrOrderNotFound)
+			},
+			expectedError: "заказ с ID 5 не найден",
+		},
+		{
+			name:      "issued order",
+			toPointID: 2,
+			orderIDs:  []int{2},
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(2).Return(&models.Order{OrderID: 2, IssuedToUser: true}, nil)
+			},
+			expectedError: "заказ с ID 2 уже был выдан клиенту",
+		},
+		{
+			name:      "order already in transit",
+			toPointID: 2,
+			orderIDs:  []int{3},
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(3).Return(&models.Order{OrderID: 3, TransferID: 4}, nil)
+			},
+			expectedError: "заказ с ID 3 уже в пути",
+		},
+		{
+			name:      "order changed after check",
+			toPointID: 2,
+			orderIDs:  []int{1},
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(1).Return(stored, nil)
+				mockRepo.EXPECT().CreateTransfer(2, []int{1}).Return(nil, postgresql.ErrOrdersNotTransferable)
+			},
+			expectedError: "состав заказов изменился, повторите перемещение",
+		},
+		{
+			name:      "orders are sent",
+			toPointID: 2,
+			orderIDs:  []int{1},
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetOrderByID(1).Return(stored, nil)
+				mockRepo.EXPECT().CreateTransfer(2, []int{1}).Return(&models.Transfer{ID: 1, FromPointID: 1, ToPointID: 2}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockRepository(ctrl)
+			mockRepo.EXPECT().ForPoint(1).Return(mockRepo)
+			mod := New(mockRepo).ForPoint(1)
+			tt.setupMocks(mockRepo)
+
+			// act
+			_, err := mod.CreateTransfer(tt.toPointID, tt.orderIDs)
+
+			// assert
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestModule_ReceiveTransfer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		setupMocks    func(mockRepo *mockrepository.MockRepository)
+		expectedError string
+	}{
+		{
+			name: "unknown transfer",
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetTransfer(int64(9)).Return(nil, postgresql.ErrTransferNotFound)
+			},
+			expectedError: "перемещение 9 не найдено",
+		},
+		{
+			name: "transfer to another point",
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetTransfer(int64(9)).Return(&models.Transfer{ID: 9, FromPointID: 2, ToPointID: 3}, nil)
+			},
+			expectedError: "перемещение 9 направлено в пункт выдачи 3",
+		},
+		{
+			name: "transfer already received",
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetTransfer(int64(9)).Return(&models.Transfer{ID: 9, FromPointID: 1, ToPointID: 2,
+					ReceivedAt: time.Now()}, nil)
+			},
+			expectedError: "перемещение 9 уже получено",
+		},
+		{
+			name: "transfer is received",
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().GetTransfer(int64(9)).Return(&models.Transfer{ID: 9, FromPointID: 1, ToPointID: 2}, nil)
+				mockRepo.EXPECT().ReceiveTransfer(int64(9)).Return(&models.Transfer{ID: 9, FromPointID: 1, ToPointID: 2,
+					ReceivedAt: time.Now()}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockRepository(ctrl)
+			mockRepo.EXPECT().ForPoint(2).Return(mockRepo)
+			mod := New(mockRepo).ForPoint(2)
+			tt.setupMocks(mockRepo)
+
+			// act
+			_, err := mod.ReceiveTransfer(9)
+
+			// assert
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+
+	"route/internal/app/models"
+)
+
+// ErrNoAddress is returned by a notifier if the contact has no address for its channel
+var ErrNoAddress = errors.New("contact has no address for the channel")
+
+// Notifier sends a message to a client through a channel
+type Notifier interface {
+	Channel() string
+	Send(contact models.Contact, msg Message) error
+}
+
+// SMS sends messages through the HTTP API of an SMS provider
+type SMS struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func NewSMS(url, token string, timeout time.Duration) *SMS {
+	return &SMS{url: url, token: token, client: &http.Client{Timeout: timeout}}
+}
+
+func (s *SMS) Channel() string {
+	return "sms"
+}
+
+func (s *SMS) Send(contact models.Contact, msg Message) error {
+	if contact.Phone == "" {
+		return ErrNoAddress
+	}
+
+	body, err := json.Marshal(struct {
+		Phone string `json:"phone"`
+		Text  string `json:"text"`
+	}{contact.Phone, msg.Body})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sms provider responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Email sends messages through an SMTP server
+type Email struct {
+	addr     string
+	from     string
+	auth     smtp.Auth
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewEmail(addr, from, username, password string) *Email {
+	var auth smtp.Auth
+	if username != "" {
+		host, _, _ := strings.Cut(addr, ":")
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &Email{addr: addr, from: from, auth: auth, sendMail: smtp.SendMail}
+}
+
+func (e *Email) Channel() string {
+	return "email"
+}
+
+func (e *Email) Send(contact models.Contact, msg Message) error {
+	if contact.Email == "" {
+		return ErrNoAddress
+	}
+
+	var letter bytes.Buffer
+	fmt.Fprintf(&letter, "From: %s\r\n", e.from)
+	fmt.Fprintf(&letter, "To: %s\r\n", contact.Email)
+	fmt.Fprintf(&letter, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", msg.Subject))
+	letter.WriteString("MIME-Version: 1.0\r\n")
+	letter.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	letter.WriteString(msg.Body)
+
+	return e.sendMail(e.addr, e.auth, e.from, []string{contact.Email}, letter.Bytes())
+}
+
+// Log writes messages to a log instead of sending them, every client is notified.
+// Pickup codes are not written, the log keeps the redacted body.
+type Log struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewLog(w io.Writer) *Log {
+	return &Log{w: w}
+}
+
+func (l *Log) Channel() string {
+	return "log"
+}
+
+func (l *Log) Send(contact models.Contact, msg Message) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, err := fmt.Fprintf(l.w, "%s user=%d phone=%q email=%q subject=%q body=%q\n",
+		time.Now().Format(time.RFC3339), contact.UserID, contact.Phone, contact.Email, msg.Subject, msg.RedactedBody)
+	return err
+}
@@ -0,0 +1,144 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"route/internal/app/config"
+	"route/internal/app/models"
+	"route/internal/app/repository"
+	"route/internal/app/repository/postgresql"
+)
+
+// Service notifies clients about their orders through every notifier their contact has an address for.
+// Each sent notification is recorded, so a client is reminded about an order only once.
+type Service struct {
+	repo      repository.NotificationRepository
+	templates *Templates
+	notifiers []Notifier
+}
+
+// New creates the service with notifiers listed in the config
+func New(cfg config.NotificationConfig, repo repository.NotificationRepository) (*Service, error) {
+	templates, err := NewTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	notifiers := make([]Notifier, 0, len(cfg.Notifiers))
+	for _, name := range cfg.Notifiers {
+		switch name {
+		case "sms":
+			notifiers = append(notifiers, NewSMS(cfg.SMSURL, cfg.SMSToken, cfg.Timeout))
+		case "email":
+			notifiers = append(notifiers, NewEmail(cfg.SMTPAddr, cfg.SMTPFrom, cfg.SMTPUser, cfg.SMTPPassword))
+		case "log":
+			var w io.Writer = os.Stdout
+			if cfg.LogPath != "" {
+				file, err := os.OpenFile(cfg.LogPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+				if err != nil {
+					return nil, err
+				}
+				w = file
+			}
+			notifiers = append(notifiers, NewLog(w))
+		default:
+			return nil, fmt.Errorf("unknown notifier %q", name)
+		}
+	}
+
+	return NewService(repo, templates, notifiers...), nil
+}
+
+func NewService(repo repository.NotificationRepository, templates *Templates, notifiers ...Notifier) *Service {
+	return &Service{
+		repo:      repo,
+		templates: templates,
+		notifiers: notifiers,
+	}
+}
+
+// SetContact saves contact data of the client
+func (s *Service) SetContact(contact models.Contact) error {
+	if contact.UserID <= 0 {
+		return errors.New("не указан ID пользователя")
+	}
+	if contact.Phone == "" && contact.Email == "" {
+		return errors.New("необходимо указать телефон или email")
+	}
+	if contact.Locale == "" {
+		contact.Locale = models.LocaleRu
+	}
+	if _, ok := dateLayouts[contact.Locale]; !ok {
+		return fmt.Errorf("неподдерживаемый язык уведомлений: %s", contact.Locale)
+	}
+
+	return s.repo.SaveContact(contact)
+}
+
+// Notify sends the notification about the order to its client. Clients without contact data are skipped.
+// It returns an error only if no notifier could deliver the message.
+func (s *Service) Notify(kind models.NotificationKind, order models.Order) error {
+	contact, err := s.repo.GetContact(order.UserID)
+	if errors.Is(err, postgresql.ErrContactNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	msg, err := s.templates.Render(kind, contact.Locale, order)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	delivered := false
+	for _, notifier := range s.notifiers {
+		sendErr := notifier.Send(*contact, *msg)
+		if errors.Is(sendErr, ErrNoAddress) {
+			continue
+		}
+
+		notification := models.Notification{OrderID: order.OrderID, UserID: order.UserID, Kind: kind, Channel: notifier.Channel()}
+		if sendErr != nil {
+			notification.Error = sendErr.Error()
+			errs = append(errs, fmt.Errorf("%s: %w", notifier.Channel(), sendErr))
+		} else {
+			delivered = true
+		}
+
+		if err = s.repo.SaveNotification(notification); err != nil {
+			return err
+		}
+	}
+
+	if delivered {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// SendReminders reminds clients about orders expiring within the given period,
+// it returns the number of orders clients were reminded about
+func (s *Service) SendReminders(within time.Duration) (int, error) {
+	orders, err := s.repo.ListOrdersToRemind(time.Now().Add(within))
+	if err != nil {
+		return 0, err
+	}
+
+	reminded := 0
+	for _, order := range orders {
+		if err = s.Notify(models.ExpiryReminder, order); err != nil {
+			log.Printf("failed to remind about order %d: %v", order.OrderID, err)
+			continue
+		}
+		reminded++
+	}
+
+	return reminded, nil
+}
@@ -0,0 +1,267 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"route/internal/app/models"
+	mockrepository "route/internal/app/repository/mocks"
+	"route/internal/app/repository/postgresql"
+)
+
+type fakeNotifier struct {
+	channel string
+	err     error
+	sent    []Message
+}
+
+func (f *fakeNotifier) Channel() string {
+	return f.channel
+}
+
+func (f *fakeNotifier) Send(_ models.Contact, msg Message) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func TestService_Notify(t *testing.T) {
+	t.Parallel()
+
+	order := models.Order{OrderID: 3, UserID: 7, Deadline: time.Now().Add(time.Hour), Cost: 50}
+	contact := &models.Contact{UserID: 7, Phone: "+79990000000", Locale: models.LocaleEn}
+
+	tests := []struct {
+		name          string
+		smsErr        error
+		emailErr      error
+		setupMocks    func(mockRepo *mockrepository.MockNotificationRepository)
+		expectedSent  int
+		expectedError string
+	}{
+		{
+			name: "sent through channels with address",
+			// The contact has no email, so the email notifier is skipped
+			emailErr: ErrNoAddress,
+			setupMocks: func(mockRepo *mockrepository.MockNotificationRepository) {
+				mockRepo.EXPECT().GetContact(7).Return(contact, nil)
+				mockRepo.EXPECT().SaveNotification(models.Notification{OrderID: 3, UserID: 7, Kind: models.OrderArrived, Channel: "sms"}).Return(nil)
+			},
+			expectedSent: 1,
+		},
+		{
+			name:     "all channels failed",
+			smsErr:   errors.New("sms provider is down"),
+			emailErr: errors.New("smtp is down"),
+			setupMocks: func(mockRepo *mockrepository.MockNotificationRepository) {
+				mockRepo.EXPECT().GetContact(7).Return(contact, nil)
+				mockRepo.EXPECT().SaveNotification(gomock.Any()).Return(nil).Times(2)
+			},
+			expectedError: "sms: sms provider is down\nemail: smtp is down",
+		},
+		{
+			name:     "one channel failed",
+			emailErr: errors.New("smtp is down"),
+			setupMocks: func(mockRepo *mockrepository.MockNotificationRepository) {
+				mockRepo.EXPECT().GetContact(7).Return(contact, nil)
+				mockRepo.EXPECT().SaveNotification(gomock.Any()).Return(nil).Times(2)
+			},
+			expectedSent: 1,
+		},
+		{
+			name: "client without contact",
+			setupMocks: func(mockRepo *mockrepository.MockNotificationRepository) {
+				mockRepo.EXPECT().GetContact(7).Return(nil, postgresql.ErrContactNotFound)
+			},
+		},
+	}
+
+	templates, err := NewTemplates()
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockNotificationRepository(ctrl)
+			sms := &fakeNotifier{channel: "sms", err: tt.smsErr}
+			email := &fakeNotifier{channel: "email", err: tt.emailErr}
+			service := NewService(mockRepo, templates, sms, email)
+			tt.setupMocks(mockRepo)
+
+			// act
+			err := service.Notify(models.OrderArrived, order)
+
+			// assert
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Len(t, append(sms.sent, email.sent...), tt.expectedSent)
+		})
+	}
+}
+
+func TestService_SendReminders(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctrl := gomock.NewController(t)
+	mockRepo := mockrepository.NewMockNotificationRepository(ctrl)
+	templates, err := NewTemplates()
+	require.NoError(t, err)
+	notifier := &fakeNotifier{channel: "log"}
+	service := NewService(mockRepo, templates, notifier)
+	orders := []models.Order{{OrderID: 1, UserID: 1}, {OrderID: 2, UserID: 2}}
+	mockRepo.EXPECT().ListOrdersToRemind(gomock.Any()).Return(orders, nil)
+	mockRepo.EXPECT().GetContact(1).Return(&models.Contact{UserID: 1}, nil)
+	mockRepo.EXPECT().GetContact(2).Return(nil, errors.New("database error"))
+	mockRepo.EXPECT().SaveNotification(models.Notification{OrderID: 1, UserID: 1, Kind: models.ExpiryReminder, Channel: "log"}).Return(nil)
+
+	// act
+	reminded, err := service.SendReminders(24 * time.Hour)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, reminded)
+	require.Len(t, notifier.sent, 1)
+	assert.Contains(t, notifier.sent[0].Subject, "1")
+}
+
+func TestSMS_Send(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var received struct {
+		Phone string `json:"phone"`
+		Text  string `json:"text"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+	sms := NewSMS(server.URL, "token", time.Second)
+
+	// act
+	err := sms.Send(models.Contact{Phone: "+79990000000"}, Message{Subject: "subject", Body: "body"})
+	noAddressErr := sms.Send(models.Contact{Email: "client@example.com"}, Message{})
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "+79990000000", received.Phone)
+	assert.Equal(t, "body", received.Text)
+	assert.ErrorIs(t, noAddressErr, ErrNoAddress)
+}
+
+func TestEmail_Send(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var sentTo []string
+	var letter string
+	email := NewEmail("smtp.example.com:25", "pvz@example.com", "", "")
+	email.sendMail = func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Equal(t, "smtp.example.com:25", addr)
+		assert.Equal(t, "pvz@example.com", from)
+		sentTo = to
+		letter = string(msg)
+		return nil
+	}
+
+	// act
+	err := email.Send(models.Contact{Email: "client@example.com"}, Message{Subject: "Заказ 1", Body: "Ваш заказ прибыл"})
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"client@example.com"}, sentTo)
+	assert.True(t, strings.HasSuffix(letter, "\r\n\r\nВаш заказ прибыл"))
+	assert.Contains(t, letter, "Subject: =?UTF-8?q?")
+}
+
+func TestLog_Send(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	templates, err := NewTemplates()
+	require.NoError(t, err)
+	msg, err := templates.Render(models.OrderArrived, models.LocaleRu, models.Order{OrderID: 1, PickupCode: "042917"})
+	require.NoError(t, err)
+	var out bytes.Buffer
+
+	// act
+	err = NewLog(&out).Send(models.Contact{UserID: 2, Phone: "+79990000000"}, *msg)
+
+	// assert
+	require.NoError(t, err)
+	assert.Contains(t, msg.Body, "042917")
+	assert.NotContains(t, out.String(), "042917", "Pickup code is not written to the log")
+	assert.Contains(t, out.String(), "Код получения: ******.")
+}
+
+func TestService_SetContact(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		contact       models.Contact
+		expectedSaved *models.Contact
+		expectedError string
+	}{
+		{
+			name:          "default locale",
+			contact:       models.Contact{UserID: 1, Phone: "+79990000000"},
+			expectedSaved: &models.Contact{UserID: 1, Phone: "+79990000000", Locale: models.LocaleRu},
+		},
+		{
+			name:          "no address",
+			contact:       models.Contact{UserID: 1, Locale: models.LocaleEn},
+			expectedError: "необходимо указать телефон или email",
+		},
+		{
+			name:          "unsupported locale",
+			contact:       models.Contact{UserID: 1, Email: "client@example.com", Locale: "de"},
+			expectedError: "неподдерживаемый язык уведомлений: de",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockNotificationRepository(ctrl)
+			if tt.expectedSaved != nil {
+				mockRepo.EXPECT().SaveContact(*tt.expectedSaved).Return(nil)
+			}
+
+			// act
+			err := NewService(mockRepo, nil).SetContact(tt.contact)
+
+			// assert
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
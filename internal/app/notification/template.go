@@ -0,0 +1,87 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"strconv"
+	"text/template"
+	"time"
+
+	"route/internal/app/models"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// dateLayouts are deadline formats of each locale
+var dateLayouts = map[string]string{
+	models.LocaleRu: "02.01.2006 15:04",
+	models.LocaleEn: "Jan 2, 2006 15:04",
+}
+
+// redactedCode replaces the pickup code in messages written to logs
+const redactedCode = "******"
+
+// Message is a rendered notification. RedactedBody is the body without the pickup code,
+// it is used by notifiers which persist messages.
+type Message struct {
+	Subject      string
+	Body         string
+	RedactedBody string
+}
+
+// Templates render notifications in the client's locale, unknown locales fall back to Russian
+type Templates struct {
+	locales map[string]*template.Template
+}
+
+func NewTemplates() (*Templates, error) {
+	locales := make(map[string]*template.Template, len(dateLayouts))
+	for locale := range dateLayouts {
+		tmpl, err := template.ParseFS(templateFiles, "templates/"+locale+".tmpl")
+		if err != nil {
+			return nil, err
+		}
+		locales[locale] = tmpl
+	}
+
+	return &Templates{locales: locales}, nil
+}
+
+// Render renders the notification about the order
+func (t *Templates) Render(kind models.NotificationKind, locale string, order models.Order) (*Message, error) {
+	tmpl, ok := t.locales[locale]
+	if !ok {
+		locale = models.LocaleRu
+		tmpl = t.locales[locale]
+	}
+
+	data := struct {
+		OrderID    int
+		Deadline   string
+		Cost       string
+		PickupCode string
+	}{
+		OrderID:    order.OrderID,
+		Deadline:   order.Deadline.In(time.Local).Format(dateLayouts[locale]),
+		Cost:       strconv.FormatFloat(order.Cost, 'f', 2, 64),
+		PickupCode: order.PickupCode,
+	}
+
+	var subject, body, redacted bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, string(kind)+".subject", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s notification: %w", kind, err)
+	}
+	if err := tmpl.ExecuteTemplate(&body, string(kind)+".body", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s notification: %w", kind, err)
+	}
+	if data.PickupCode != "" {
+		data.PickupCode = redactedCode
+	}
+	if err := tmpl.ExecuteTemplate(&redacted, string(kind)+".body", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s notification: %w", kind, err)
+	}
+
+	return &Message{Subject: subject.String(), Body: body.String(), RedactedBody: redacted.String()}, nil
+}
@@ -0,0 +1,97 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+)
+
+func TestTemplates_Render(t *testing.T) {
+	t.Parallel()
+
+	order := models.Order{OrderID: 12, Deadline: time.Date(2024, 7, 30, 18, 0, 0, 0, time.Local), Cost: 105}
+
+	tests := []struct {
+		name            string
+		kind            models.NotificationKind
+		locale          string
+		order           models.Order
+		expectedSubject string
+		expectedBody    string
+		expectedLogBody string
+	}{
+		{
+			name:            "arrival in russian",
+			kind:            models.OrderArrived,
+			locale:          models.LocaleRu,
+			expectedSubject: "Заказ 12 ждет вас в пункте выдачи",
+			expectedBody:    "Ваш заказ 12 прибыл в пункт выдачи. Заберите его до 30.07.2024 18:00. К оплате: 105.00 руб.",
+		},
+		{
+			name:            "arrival with pickup code in english",
+			kind:            models.OrderArrived,
+			locale:          models.LocaleEn,
+			order:           models.Order{OrderID: 12, Deadline: order.Deadline, Cost: 105, PickupCode: "042917"},
+			expectedSubject: "Order 12 is waiting for you at the pickup point",
+			expectedBody:    "Your order 12 has arrived at the pickup point. Please collect it by Jul 30, 2024 18:00. Amount due: 105.00 RUB. Pickup code: 042917.",
+			expectedLogBody: "Your order 12 has arrived at the pickup point. Please collect it by Jul 30, 2024 18:00. Amount due: 105.00 RUB. Pickup code: ******.",
+		},
+		{
+			name:            "reminder in english",
+			kind:            models.ExpiryReminder,
+			locale:          models.LocaleEn,
+			expectedSubject: "Storage of order 12 is about to expire",
+			expectedBody:    "Storage of order 12 expires on Jul 30, 2024 18:00. After that the order will be returned to the courier.",
+		},
+		{
+			name:            "unknown locale falls back to russian",
+			kind:            models.ReturnAcceptedNotice,
+			locale:          "de",
+			expectedSubject: "Возврат заказа 12 принят",
+			expectedBody:    "Мы приняли возврат заказа 12. Деньги поступят на ваш счет в ближайшее время.",
+		},
+	}
+
+	templates, err := NewTemplates()
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			or := order
+			if tt.order.OrderID != 0 {
+				or = tt.order
+			}
+
+			// act
+			msg, err := templates.Render(tt.kind, tt.locale, or)
+
+			// assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSubject, msg.Subject)
+			assert.Equal(t, tt.expectedBody, msg.Body)
+			expectedLogBody := tt.expectedLogBody
+			if expectedLogBody == "" {
+				expectedLogBody = tt.expectedBody
+			}
+			assert.Equal(t, expectedLogBody, msg.RedactedBody)
+		})
+	}
+}
+
+func TestTemplates_RenderUnknownKind(t *testing.T) {
+	t.Parallel()
+
+	templates, err := NewTemplates()
+	require.NoError(t, err)
+
+	_, err = templates.Render("order_lost", models.LocaleRu, models.Order{})
+
+	assert.Error(t, err)
+}
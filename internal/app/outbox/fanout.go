@@ -0,0 +1,55 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"route/internal/app/repository"
+	"route/internal/app/sink"
+)
+
+// FanOut publishes the outbox to several sinks. Every sink has its own relay
+// and delivery progress, so a failing sink doesn't hold back the others
+// and catches up when it recovers.
+type FanOut struct {
+	relays []*Relay
+}
+
+func NewFanOut(repo repository.OutboxRepository, sinks []sink.Named) *FanOut {
+	relays := make([]*Relay, 0, len(sinks))
+	for _, s := range sinks {
+		relays = append(relays, NewRelay(repo, s))
+	}
+
+	return &FanOut{relays: relays}
+}
+
+// Run runs relays of all sinks until ctx is cancelled
+func (f *FanOut) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, relay := range f.relays {
+		wg.Add(1)
+		go func(relay *Relay) {
+			defer wg.Done()
+			relay.Run(ctx)
+		}(relay)
+	}
+	wg.Wait()
+}
+
+// Flush publishes pending messages to every sink, errors of all sinks are returned together
+func (f *FanOut) Flush() (int, error) {
+	total := 0
+	var errs []error
+	for _, relay := range f.relays {
+		sent, err := relay.Flush()
+		total += sent
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", relay.sink.Name, err))
+		}
+	}
+
+	return total, errors.Join(errs...)
+}
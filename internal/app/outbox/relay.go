@@ -0,0 +1,86 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"route/internal/app/models"
+	"route/internal/app/repository"
+	"route/internal/app/sink"
+)
+
+const (
+	defaultBatchSize    = 100
+	defaultPollInterval = time.Second
+	maxBackoff          = time.Minute
+)
+
+// Relay publishes events written to the outbox by the repository to a sink.
+// Messages are marked delivered only after the sink accepted them, which gives
+// at-least-once delivery of events for committed changes only.
+type Relay struct {
+	repo         repository.OutboxRepository
+	sink         sink.Named
+	batchSize    int
+	pollInterval time.Duration
+}
+
+func NewRelay(repo repository.OutboxRepository, sink sink.Named) *Relay {
+	return &Relay{
+		repo:         repo,
+		sink:         sink,
+		batchSize:    defaultBatchSize,
+		pollInterval: defaultPollInterval,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled. After a failed publish
+// the poll interval is doubled up to maxBackoff and reset on success.
+func (r *Relay) Run(ctx context.Context) {
+	delay := r.pollInterval
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		_, err := r.Flush()
+		if err != nil {
+			log.Printf("outbox relay %s: %v", r.sink.Name, err)
+			delay = min(delay*2, maxBackoff)
+		} else {
+			delay = r.pollInterval
+		}
+		timer.Reset(delay)
+	}
+}
+
+// Flush publishes pending messages until the outbox is drained or publishing fails
+func (r *Relay) Flush() (int, error) {
+	total := 0
+	for {
+		var publishErr error
+		var failedID int64
+		sent, err := r.repo.ProcessPending(r.sink.Name, r.batchSize, func(msg models.OutboxMessage) error {
+			publishErr = r.sink.SendEvent(msg)
+			failedID = msg.ID
+			return publishErr
+		})
+		total += sent
+		if err != nil {
+			return total, err
+		}
+		if publishErr != nil {
+			return total, fmt.Errorf("failed to publish outbox message %d: %w", failedID, publishErr)
+		}
+		if sent < r.batchSize {
+			return total, nil
+		}
+	}
+}
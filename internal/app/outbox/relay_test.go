@@ -0,0 +1,127 @@
+package outbox
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"route/internal/app/models"
+	mockrepository "route/internal/app/repository/mocks"
+	"route/internal/app/sink"
+)
+
+type fakePublisher struct {
+	sent [][]byte
+	err  error
+}
+
+func (p *fakePublisher) SendEvent(msg models.OutboxMessage) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.sent = append(p.sent, msg.Payload)
+	return nil
+}
+
+// processMessages emulates OutboxRepository.ProcessPending over the given messages
+func processMessages(messages []models.OutboxMessage) func(string, int, func(models.OutboxMessage) error) (int, error) {
+	return func(_ string, _ int, fx func(models.OutboxMessage) error) (int, error) {
+		sent := 0
+		for _, msg := range messages {
+			if err := fx(msg); err != nil {
+				return sent, nil
+			}
+			sent++
+		}
+		return sent, nil
+	}
+}
+
+func TestRelay_Flush(t *testing.T) {
+	t.Parallel()
+
+	messages := []models.OutboxMessage{
+		{ID: 1, EventType: models.OrderAccepted, OrderID: 1, Payload: []byte("first")},
+		{ID: 2, EventType: models.OrderIssued, OrderID: 1, Payload: []byte("second")},
+	}
+
+	t.Run("publishes pending messages in order", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mockrepository.NewMockOutboxRepository(ctrl)
+		publisher := &fakePublisher{}
+		relay := NewRelay(mockRepo, sink.Named{Name: "test", EventSink: publisher})
+		mockRepo.EXPECT().ProcessPending("test", defaultBatchSize, gomock.Any()).DoAndReturn(processMessages(messages))
+
+		// act
+		sent, err := relay.Flush()
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 2, sent)
+		assert.Equal(t, [][]byte{[]byte("first"), []byte("second")}, publisher.sent)
+	})
+
+	t.Run("publisher error", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mockrepository.NewMockOutboxRepository(ctrl)
+		publisher := &fakePublisher{err: errors.New("kafka is down")}
+		relay := NewRelay(mockRepo, sink.Named{Name: "test", EventSink: publisher})
+		mockRepo.EXPECT().ProcessPending("test", defaultBatchSize, gomock.Any()).DoAndReturn(processMessages(messages))
+
+		// act
+		sent, err := relay.Flush()
+
+		// assert
+		assert.EqualError(t, err, "failed to publish outbox message 1: kafka is down")
+		assert.Zero(t, sent)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mockrepository.NewMockOutboxRepository(ctrl)
+		relay := NewRelay(mockRepo, sink.Named{Name: "test", EventSink: &fakePublisher{}})
+		mockRepo.EXPECT().ProcessPending("test", defaultBatchSize, gomock.Any()).Return(0, errors.New("database error"))
+
+		// act
+		_, err := relay.Flush()
+
+		// assert
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestFanOut_Flush(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	messages := []models.OutboxMessage{
+		{ID: 1, EventType: models.OrderAccepted, OrderID: 1, Payload: []byte("first")},
+		{ID: 2, EventType: models.OrderIssued, OrderID: 1, Payload: []byte("second")},
+	}
+	ctrl := gomock.NewController(t)
+	mockRepo := mockrepository.NewMockOutboxRepository(ctrl)
+	kafka := &fakePublisher{err: errors.New("kafka is down")}
+	file := &fakePublisher{}
+	fanOut := NewFanOut(mockRepo, []sink.Named{{Name: "kafka", EventSink: kafka}, {Name: "file", EventSink: file}})
+	mockRepo.EXPECT().ProcessPending("kafka", defaultBatchSize, gomock.Any()).DoAndReturn(processMessages(messages))
+	mockRepo.EXPECT().ProcessPending("file", defaultBatchSize, gomock.Any()).DoAndReturn(processMessages(messages))
+
+	// act
+	sent, err := fanOut.Flush()
+
+	// assert
+	assert.EqualError(t, err, "kafka: failed to publish outbox message 1: kafka is down")
+	assert.Equal(t, 2, sent)
+	assert.Equal(t, [][]byte{[]byte("first"), []byte("second")}, file.sent)
+}
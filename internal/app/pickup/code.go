@@ -0,0 +1,69 @@
+package pickup
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// CodeLength is the number of digits in a pickup code
+const CodeLength = 6
+
+// qrPrefix starts QR payloads of pickup codes
+const qrPrefix = "PVZ"
+
+var ErrInvalidQRPayload = errors.New("invalid QR payload")
+
+// GenerateCode returns a random numeric pickup code
+func GenerateCode() (string, error) {
+	limit := big.NewInt(1)
+	for i := 0; i < CodeLength; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", CodeLength, n), nil
+}
+
+// HashCode hashes the code of the order, only the hash is stored.
+// The order ID salts the hash, so equal codes of different orders have different hashes
+func HashCode(orderID int, code string) string {
+	sum := sha256.Sum256([]byte(strconv.Itoa(orderID) + ":" + strings.TrimSpace(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyCode reports whether the code matches the stored hash of the order's code
+func VerifyCode(orderID int, code, hash string) bool {
+	if hash == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(HashCode(orderID, code)), []byte(hash)) == 1
+}
+
+// QRPayload returns the payload of a QR code the client shows at pickup instead of typing the code
+func QRPayload(orderID int, code string) string {
+	return fmt.Sprintf("%s:%d:%s", qrPrefix, orderID, code)
+}
+
+// ParseQRPayload returns the order ID and the pickup code from a QR payload
+func ParseQRPayload(payload string) (int, string, error) {
+	parts := strings.Split(strings.TrimSpace(payload), ":")
+	if len(parts) != 3 || parts[0] != qrPrefix || parts[2] == "" {
+		return 0, "", ErrInvalidQRPayload
+	}
+
+	orderID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, "", ErrInvalidQRPayload
+	}
+	return orderID, parts[2], nil
+}
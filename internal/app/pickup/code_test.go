@@ -0,0 +1,88 @@
+package pickup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateCode(t *testing.T) {
+	t.Parallel()
+
+	// act
+	code, err := GenerateCode()
+
+	// assert
+	require.NoError(t, err)
+	assert.Len(t, code, CodeLength)
+	assert.Regexp(t, "^[0-9]+$", code)
+}
+
+func TestVerifyCode(t *testing.T) {
+	t.Parallel()
+
+	hash := HashCode(1, "123456")
+
+	tests := []struct {
+		name     string
+		orderID  int
+		code     string
+		hash     string
+		expected bool
+	}{
+		{name: "valid code", orderID: 1, code: "123456", hash: hash, expected: true},
+		{name: "wrong code", orderID: 1, code: "654321", hash: hash},
+		{name: "code of another order", orderID: 2, code: "123456", hash: hash},
+		{name: "order without code", orderID: 1, code: "", hash: ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			valid := VerifyCode(tt.orderID, tt.code, tt.hash)
+
+			// assert
+			assert.Equal(t, tt.expected, valid)
+		})
+	}
+}
+
+func TestParseQRPayload(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		payload         string
+		expectedOrderID int
+		expectedCode    string
+		expectedError   error
+	}{
+		{name: "valid payload", payload: QRPayload(42, "123456"), expectedOrderID: 42, expectedCode: "123456"},
+		{name: "unknown prefix", payload: "ABC:42:123456", expectedError: ErrInvalidQRPayload},
+		{name: "invalid order", payload: "PVZ:x:123456", expectedError: ErrInvalidQRPayload},
+		{name: "no code", payload: "PVZ:42:", expectedError: ErrInvalidQRPayload},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			orderID, code, err := ParseQRPayload(tt.payload)
+
+			// assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedOrderID, orderID)
+			assert.Equal(t, tt.expectedCode, code)
+		})
+	}
+}
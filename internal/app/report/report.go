@@ -0,0 +1,112 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"route/internal/app/models"
+)
+
+// Formats of printed reports
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+)
+
+const dayLayout = "2006-01-02"
+
+// View is the report as it is marshaled to JSON and YAML
+type View struct {
+	PickupPointID int       `json:"pickup_point_id" yaml:"pickup_point_id"`
+	From          string    `json:"from" yaml:"from"`
+	To            string    `json:"to" yaml:"to"`
+	Rows          []RowView `json:"rows" yaml:"rows"`
+	Total         RowView   `json:"total" yaml:"total"`
+}
+
+// RowView is a report row, the total row has no day and packaging type
+type RowView struct {
+	Day              string  `json:"day,omitempty" yaml:"day,omitempty"`
+	PackagingType    string  `json:"packaging_type,omitempty" yaml:"packaging_type,omitempty"`
+	Accepted         int     `json:"accepted" yaml:"accepted"`
+	Issued           int     `json:"issued" yaml:"issued"`
+	Returned         int     `json:"returned" yaml:"returned"`
+	SurchargeRevenue float64 `json:"surcharge_revenue" yaml:"surcharge_revenue"`
+	Refunds          float64 `json:"refunds" yaml:"refunds"`
+}
+
+// Write prints the report in the given format
+func Write(w io.Writer, report models.Report, format string) error {
+	switch format {
+	case FormatTable:
+		return writeTable(w, report)
+	case FormatJSON:
+		return writeJSON(w, report)
+	default:
+		return fmt.Errorf("неизвестный формат: %s", format)
+	}
+}
+
+// packagingTypeName names orders accepted without packaging
+func packagingTypeName(packagingType models.PackageType) string {
+	if packagingType == "" {
+		return "без упаковки"
+	}
+	return string(packagingType)
+}
+
+func writeTable(w io.Writer, report models.Report) error {
+	point := "все пункты"
+	if report.PickupPointID != 0 {
+		point = fmt.Sprintf("пункт %d", report.PickupPointID)
+	}
+	fmt.Fprintf(w, "Отчет за %s - %s, %s\n\n", report.From.Format("02.01.2006"), report.To.Format("02.01.2006"), point)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Дата\tУпаковка\tПринято\tВыдано\tВозвращено\tДоплаты за упаковку\tВозвраты денег")
+	for _, row := range report.Rows {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%.2f\t%.2f\n", row.Day.Format("02.01.2006"), packagingTypeName(row.PackagingType),
+			row.Accepted, row.Issued, row.Returned, row.SurchargeRevenue, row.Refunds)
+	}
+	total := report.Total()
+	fmt.Fprintf(tw, "Итого\t\t%d\t%d\t%d\t%.2f\t%.2f\n", total.Accepted, total.Issued, total.Returned, total.SurchargeRevenue, total.Refunds)
+	return tw.Flush()
+}
+
+func newRowView(row models.ReportRow) RowView {
+	out := RowView{
+		PackagingType:    string(row.PackagingType),
+		Accepted:         row.Accepted,
+		Issued:           row.Issued,
+		Returned:         row.Returned,
+		SurchargeRevenue: row.SurchargeRevenue,
+		Refunds:          row.Refunds,
+	}
+	if !row.Day.IsZero() {
+		out.Day = row.Day.Format(dayLayout)
+	}
+	return out
+}
+
+// NewView converts the report to its marshaled view
+func NewView(report models.Report) View {
+	out := View{
+		PickupPointID: report.PickupPointID,
+		From:          report.From.Format(dayLayout),
+		To:            report.To.Format(dayLayout),
+		Rows:          make([]RowView, 0, len(report.Rows)),
+		Total:         newRowView(report.Total()),
+	}
+	for _, row := range report.Rows {
+		out.Rows = append(out.Rows, newRowView(row))
+	}
+	return out
+}
+
+func writeJSON(w io.Writer, report models.Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(NewView(report))
+}
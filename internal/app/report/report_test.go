@@ -0,0 +1,88 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)
+	shiftReport := models.Report{
+		PickupPointID: 1,
+		From:          day,
+		To:            day,
+		Rows: []models.ReportRow{
+			{Day: day, Accepted: 1},
+			{Day: day, PackagingType: models.Box, Accepted: 2, Issued: 1, Returned: 1, SurchargeRevenue: 20, Refunds: 120},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		format        string
+		expected      []string
+		expectedError string
+	}{
+		{
+			name:   "table",
+			format: FormatTable,
+			expected: []string{
+				"Отчет за 02.01.2030 - 02.01.2030, пункт 1",
+				"02.01.2030  без упаковки  1        0       0           0.00                 0.00",
+				"02.01.2030  коробка       2        1       1           20.00                120.00",
+				"Итого                     3        1       1           20.00                120.00",
+			},
+		},
+		{
+			name:   "json",
+			format: FormatJSON,
+			expected: []string{
+				`"from": "2030-01-02",`,
+				`"packaging_type": "коробка",`,
+				`"total": {
+    "accepted": 3,
+    "issued": 1,
+    "returned": 1,
+    "surcharge_revenue": 20,
+    "refunds": 120
+  }`,
+			},
+		},
+		{
+			name:          "unknown format",
+			format:        "xml",
+			expectedError: "неизвестный формат: xml",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			var buf bytes.Buffer
+
+			// act
+			err := Write(&buf, shiftReport, tt.format)
+
+			// assert
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			for _, line := range tt.expected {
+				assert.True(t, strings.Contains(buf.String(), line), "expected %q in\n%s", line, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package cached
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"route/internal/app/models"
+	"route/internal/app/repository"
+	"route/internal/app/repository/postgresql"
+)
+
+type IMCache[K comparable, V any] interface {
+	Set(key K, value V, now time.Time)
+	Get(key K) (V, bool)
+	Delete(key K)
+}
+
+// Repo is a read-through/write-through cache decorator around repository.Repository.
+// Reads of a single order are served from the cache and concurrent misses for the same
+// order are collapsed into one database query. Every mutation is always sent to the
+// underlying repository first, and the cache is updated only if it succeeds. A row read
+// from the database is not cached if the order was changed while it was read.
+// Repos of different pickup points share the cache, an order cached for one point
+// is not returned to another one.
+type Repo struct {
+	repo     repository.Repository
+	cache    IMCache[int, models.Order]
+	group    *singleflight.Group
+	versions *versions
+	pointID  int
+}
+
+func New(repo repository.Repository, cache IMCache[int, models.Order]) *Repo {
+	return &Repo{
+		repo:     repo,
+		cache:    cache,
+		group:    &singleflight.Group{},
+		versions: &versions{},
+	}
+}
+
+// ForPoint returns a copy of the repo limited to orders of the pickup point, zero means all points
+func (r *Repo) ForPoint(pointID int) repository.Repository {
+	return &Repo{
+		repo:     r.repo.ForPoint(pointID),
+		cache:    r.cache,
+		group:    r.group,
+		versions: r.versions,
+		pointID:  pointID,
+	}
+}
+
+// AcceptOrder adds a new order to the database and puts it to the cache
+func (r *Repo) AcceptOrder(order *models.Order, packagingType *models.PackagingType) error {
+	if err := r.repo.AcceptOrder(order, packagingType); err != nil {
+		return err
+	}
+
+	r.set(*order, time.Now())
+	return nil
+}
+
+// AcceptOrders adds a batch of orders to the database and puts them to the cache
+func (r *Repo) AcceptOrders(orders []*models.Order, packagingTypes []*models.PackagingType) error {
+	if err := r.repo.AcceptOrders(orders, packagingTypes); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, order := range orders {
+		r.set(*order, now)
+	}
+	return nil
+}
+
+// ReturnOrder removes an order from the database and from the cache
+func (r *Repo) ReturnOrder(orderID int) error {
+	if err := r.repo.ReturnOrder(orderID); err != nil {
+		r.dropStale(orderID, err)
+		return err
+	}
+
+	r.invalidate(orderID)
+	return nil
+}
+
+// IssueOrder marks an order issued in the database and invalidates its cache entry,
+// because issue time is set by the database
+func (r *Repo) IssueOrder(orderID int, hash string, record models.IssueRecord) error {
+	if err := r.repo.IssueOrder(orderID, hash, record); err != nil {
+		r.dropStale(orderID, err)
+		return err
+	}
+
+	r.invalidate(orderID)
+	return nil
+}
+
+// RecordPickupFailure counts a wrong pickup code in the database and updates the order in the cache
+func (r *Repo) RecordPickupFailure(orderID int, maxFailures int) (*models.Order, error) {
+	order, err := r.repo.RecordPickupFailure(orderID, maxFailures)
+	if err != nil {
+		return nil, err
+	}
+
+	r.set(*order, time.Now())
+	return order, nil
+}
+
+// UnlockPickup unlocks the order in the database and invalidates its cache entry
+func (r *Repo) UnlockPickup(orderID int) error {
+	if err := r.repo.UnlockPickup(orderID); err != nil {
+		return err
+	}
+
+	r.invalidate(orderID)
+	return nil
+}
+
+// RefuseOrder records the refusal in the database and invalidates the order's cache entry
+func (r *Repo) RefuseOrder(orderID int, reason string) error {
+	if err := r.repo.RefuseOrder(orderID, reason); err != nil {
+		r.dropStale(orderID, err)
+		return err
+	}
+
+	r.invalidate(orderID)
+	return nil
+}
+
+// set caches the order written by a mutation
+func (r *Repo) set(order models.Order, now time.Time) {
+	r.versions.change(order.OrderID, func() { r.cache.Set(order.OrderID, order, now) })
+}
+
+// invalidate drops the order changed by a mutation from the cache
+func (r *Repo) invalidate(orderID int) {
+	r.versions.change(orderID, func() { r.cache.Delete(orderID) })
+}
+
+// dropStale invalidates the cache entry if the mutation failed because the order had been changed
+// by another request, the entry the module checked is stale then
+func (r *Repo) dropStale(orderID int, err error) {
+	if errors.Is(err, postgresql.ErrOrderStateChanged) {
+		r.invalidate(orderID)
+	}
+}
+
+// ListOrders returns a list of the user's most recent orders from the database
+func (r *Repo) ListOrders(userID, lastN int) ([]models.Order, error) {
+	return r.repo.ListOrders(userID, lastN)
+}
+
+// AcceptReturn marks an order returned in the database and updates it in the cache
+func (r *Repo) AcceptReturn(order models.Order) error {
+	if err := r.repo.AcceptReturn(order); err != nil {
+		r.dropStale(order.OrderID, err)
+		return err
+	}
+
+	order.IsReturned = true
+	r.set(order, time.Now())
+	return nil
+}
+
+// ListReturns returns a list of returned orders from the database
+func (r *Repo) ListReturns(page, pageSize int) ([]models.Order, error) {
+	return r.repo.ListReturns(page, pageSize)
+}
+
+// ExportOrders streams orders matching the filter from the database
+func (r *Repo) ExportOrders(filter models.ExportFilter, fx func(order models.Order) error) error {
+	return r.repo.ExportOrders(filter, fx)
+}
+
+// GetAllOrders returns a list of all orders from the database
+func (r *Repo) GetAllOrders() ([]models.Order, error) {
+	return r.repo.GetAllOrders()
+}
+
+// ListStoredOrders returns orders still stored at the pickup point from the database
+func (r *Repo) ListStoredOrders() ([]models.Order, error) {
+	return r.repo.ListStoredOrders()
+}
+
+// ListPresentOrders returns orders kept at the pickup point from the database
+func (r *Repo) ListPresentOrders() ([]models.Order, error) {
+	return r.repo.ListPresentOrders()
+}
+
+// MarkExpired marks expired orders in the database and invalidates their cache entries
+func (r *Repo) MarkExpired() ([]models.Order, error) {
+	orders, err := r.repo.MarkExpired()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, order := range orders {
+		r.invalidate(order.OrderID)
+	}
+	return orders, nil
+}
+
+// ListExpiredOrders returns expired orders still stored at the pickup point from the database
+func (r *Repo) ListExpiredOrders() ([]models.Order, error) {
+	return r.repo.ListExpiredOrders()
+}
+
+// CreateReturnManifest hands over expired orders not brought by other companies to the courier in the database and invalidates their cache entries
+func (r *Repo) CreateReturnManifest(courierID int) (*models.ReturnManifest, error) {
+	manifest, err := r.repo.CreateReturnManifest(courierID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, order := range manifest.Orders {
+		r.invalidate(order.OrderID)
+	}
+	return manifest, nil
+}
+
+// CreateTransfer marks the orders in transit in the database and invalidates their cache entries
+func (r *Repo) CreateTransfer(toPointID int, orderIDs []int) (*models.Transfer, error) {
+	transfer, err := r.repo.CreateTransfer(toPointID, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, order := range transfer.Orders {
+		r.invalidate(order.OrderID)
+	}
+	return transfer, nil
+}
+
+// GetTransfer returns the transfer from the database
+func (r *Repo) GetTransfer(transferID int64) (*models.Transfer, error) {
+	return r.repo.GetTransfer(transferID)
+}
+
+// ReceiveTransfer moves the orders to the destination point in the database and invalidates their cache entries
+func (r *Repo) ReceiveTransfer(transferID int64) (*models.Transfer, error) {
+	transfer, err := r.repo.ReceiveTransfer(transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, order := range transfer.Orders {
+		r.invalidate(order.OrderID)
+	}
+	return transfer, nil
+}
+
+// WarmUp preloads orders still stored at the pickup point into the cache,
+// so the first pickups after a restart don't hit the database
+func (r *Repo) WarmUp() (int, error) {
+	loaded := r.versions.snapshot()
+	orders, err := r.repo.ListStoredOrders()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	for _, order := range orders {
+		order := order
+		r.versions.load(order.OrderID, loaded[stripe(order.OrderID)], func() { r.cache.Set(order.OrderID, order, now) })
+	}
+
+	return len(orders), nil
+}
+
+// Reload caches the orders with the given IDs read from the database, e.g. the keys of a cache snapshot.
+// Orders that no longer exist are skipped. It returns the number of cached orders
+func (r *Repo) Reload(orderIDs []int) (int, error) {
+	reloaded := 0
+	for _, orderID := range orderIDs {
+		order, err := r.GetOrderByID(orderID)
+		if errors.Is(err, postgresql.ErrOrderNotFound) {
+			continue
+		}
+		if err != nil {
+			return reloaded, err
+		}
+		if order != nil {
+			reloaded++
+		}
+	}
+
+	return reloaded, nil
+}
+
+// GetOrderByID returns the order with the given ID from the cache,
+// falling back to the database on a miss
+func (r *Repo) GetOrderByID(orderID int) (*models.Order, error) {
+	if order, ok := r.cache.Get(orderID); ok && (r.pointID == 0 || order.PickupPointID == r.pointID) {
+		return &order, nil
+	}
+
+	// Only one query per order ID and pickup point is in flight, other callers wait for its result
+	res, err, _ := r.group.Do(strconv.Itoa(r.pointID)+":"+strconv.Itoa(orderID), func() (interface{}, error) {
+		loaded := r.versions.get(orderID)
+		order, err := r.repo.GetOrderByID(orderID)
+		if err != nil {
+			return nil, err
+		}
+		if order == nil {
+			return nil, nil
+		}
+
+		// A mutation during the load may have replaced the row, then it is not cached
+		r.versions.load(orderID, loaded, func() { r.cache.Set(orderID, *order, time.Now()) })
+		return *order, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+
+	// Every caller gets its own copy of the shared result
+	order := res.(models.Order)
+	return &order, nil
+}
@@ -0,0 +1,306 @@
+package cached
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"route/internal/app/cache"
+	"route/internal/app/models"
+	mockrepository "route/internal/app/repository/mocks"
+	"route/internal/app/repository/postgresql"
+)
+
+func newTestRepo(t *testing.T) (*Repo, *mockrepository.MockRepository, *cache.IMCache[int, models.Order]) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockRepo := mockrepository.NewMockRepository(ctrl)
+	imCache := cache.NewIMCache[int, models.Order](time.Minute)
+	return New(mockRepo, imCache), mockRepo, imCache
+}
+
+func TestRepo_MutationsReachDatabase(t *testing.T) {
+	t.Parallel()
+
+	order := models.Order{OrderID: 1, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), ReceivedFromCourier: true}
+
+	tests := []struct {
+		name       string
+		setupMocks func(mockRepo *mockrepository.MockRepository)
+		mutate     func(repo *Repo) error
+		wantCached bool
+	}{
+		{
+			name: "accept order",
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().AcceptOrder(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			mutate: func(repo *Repo) error {
+				o := order
+				return repo.AcceptOrder(&o, models.NewPackagingType(models.Box, models.BoxCost))
+			},
+			wantCached: true,
+		},
+		{
+			name: "issue order",
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().IssueOrder(order.OrderID, "hash", models.IssueRecord{}).Return(nil)
+			},
+			mutate: func(repo *Repo) error {
+				return repo.IssueOrder(order.OrderID, "hash", models.IssueRecord{})
+			},
+		},
+		{
+			name: "unlock pickup",
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().UnlockPickup(order.OrderID).Return(nil)
+			},
+			mutate: func(repo *Repo) error {
+				return repo.UnlockPickup(order.OrderID)
+			},
+		},
+		{
+			name: "return order",
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().ReturnOrder(order.OrderID).Return(nil)
+			},
+			mutate: func(repo *Repo) error {
+				return repo.ReturnOrder(order.OrderID)
+			},
+		},
+		{
+			name: "accept return",
+			setupMocks: func(mockRepo *mockrepository.MockRepository) {
+				mockRepo.EXPECT().AcceptReturn(order).Return(nil)
+			},
+			mutate: func(repo *Repo) error {
+				return repo.AcceptReturn(order)
+			},
+			wantCached: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			repo, mockRepo, imCache := newTestRepo(t)
+			// The order is already cached, so the mutation must not be short-circuited by a hit
+			imCache.Set(order.OrderID, order, time.Now())
+			tt.setupMocks(mockRepo)
+
+			// act
+			err := tt.mutate(repo)
+
+			// assert
+			require.NoError(t, err)
+			_, ok := imCache.Get(order.OrderID)
+			assert.Equal(t, tt.wantCached, ok)
+		})
+	}
+}
+
+func TestRepo_FailedMutationKeepsCache(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	repo, mockRepo, imCache := newTestRepo(t)
+	order := models.Order{OrderID: 1, UserID: 1}
+	imCache.Set(order.OrderID, order, time.Now())
+	mockRepo.EXPECT().IssueOrder(order.OrderID, gomock.Any(), models.IssueRecord{}).Return(errors.New("database error"))
+
+	// act
+	err := repo.IssueOrder(order.OrderID, "hash", models.IssueRecord{})
+
+	// assert
+	require.EqualError(t, err, "database error")
+	cached, ok := imCache.Get(order.OrderID)
+	require.True(t, ok)
+	assert.False(t, cached.IssuedToUser)
+}
+
+func TestRepo_ChangedOrderIsDropped(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	repo, mockRepo, imCache := newTestRepo(t)
+	order := models.Order{OrderID: 1, UserID: 1}
+	imCache.Set(order.OrderID, order, time.Now())
+	mockRepo.EXPECT().IssueOrder(order.OrderID, gomock.Any(), models.IssueRecord{}).Return(postgresql.ErrOrderStateChanged)
+
+	// act
+	err := repo.IssueOrder(order.OrderID, "hash", models.IssueRecord{})
+
+	// assert
+	require.ErrorIs(t, err, postgresql.ErrOrderStateChanged)
+	_, ok := imCache.Get(order.OrderID)
+	assert.False(t, ok, "Stale entry is not served again")
+}
+
+func TestRepo_GetOrderByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("read-through on miss", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		repo, mockRepo, _ := newTestRepo(t)
+		mockRepo.EXPECT().GetOrderByID(1).Return(&models.Order{OrderID: 1, UserID: 2}, nil).Times(1)
+
+		// act
+		first, err := repo.GetOrderByID(1)
+		require.NoError(t, err)
+		second, err := repo.GetOrderByID(1)
+		require.NoError(t, err)
+
+		// assert
+		assert.Equal(t, 2, first.UserID)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("error is not cached", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		repo, mockRepo, _ := newTestRepo(t)
+		mockRepo.EXPECT().GetOrderByID(1).Return(nil, errors.New("database error")).Times(2)
+
+		// act
+		_, err1 := repo.GetOrderByID(1)
+		_, err2 := repo.GetOrderByID(1)
+
+		// assert
+		assert.EqualError(t, err1, "database error")
+		assert.EqualError(t, err2, "database error")
+	})
+
+	t.Run("load racing a mutation is not cached", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		repo, mockRepo, imCache := newTestRepo(t)
+		loading, release := make(chan struct{}), make(chan struct{})
+		mockRepo.EXPECT().GetOrderByID(1).DoAndReturn(func(orderID int) (*models.Order, error) {
+			close(loading)
+			<-release
+			return &models.Order{OrderID: orderID}, nil
+		})
+		mockRepo.EXPECT().IssueOrder(1, "hash", models.IssueRecord{}).Return(nil)
+
+		// act
+		done := make(chan *models.Order)
+		go func() {
+			order, _ := repo.GetOrderByID(1)
+			done <- order
+		}()
+		<-loading
+		err := repo.IssueOrder(1, "hash", models.IssueRecord{})
+		close(release)
+		loaded := <-done
+
+		// assert
+		require.NoError(t, err)
+		require.NotNil(t, loaded)
+		_, ok := imCache.Get(1)
+		assert.False(t, ok, "Row read before the mutation is not put back to the cache")
+	})
+
+	t.Run("concurrent misses are collapsed", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		repo, mockRepo, _ := newTestRepo(t)
+		release := make(chan struct{})
+		mockRepo.EXPECT().GetOrderByID(1).DoAndReturn(func(orderID int) (*models.Order, error) {
+			<-release
+			return &models.Order{OrderID: orderID}, nil
+		}).Times(1)
+
+		// act
+		const callers = 10
+		var wg sync.WaitGroup
+		results := make([]*models.Order, callers)
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i], _ = repo.GetOrderByID(1)
+			}(i)
+		}
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		// assert
+		for _, res := range results {
+			require.NotNil(t, res)
+			assert.Equal(t, 1, res.OrderID)
+		}
+	})
+}
+
+func TestRepo_ForPoint(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	repo, mockRepo, imCache := newTestRepo(t)
+	imCache.Set(1, models.Order{OrderID: 1, PickupPointID: 1}, time.Now())
+	mockRepo.EXPECT().ForPoint(2).Return(mockRepo)
+	mockRepo.EXPECT().GetOrderByID(1).Return(nil, postgresql.ErrOrderNotFound)
+	pointRepo := repo.ForPoint(2)
+
+	// act
+	_, otherErr := pointRepo.GetOrderByID(1)
+	own, err := repo.GetOrderByID(1)
+
+	// assert
+	assert.ErrorIs(t, otherErr, postgresql.ErrOrderNotFound, "Order cached for another point is read from the database")
+	require.NoError(t, err)
+	assert.Equal(t, 1, own.PickupPointID)
+}
+
+func TestRepo_WarmUp(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	repo, mockRepo, imCache := newTestRepo(t)
+	mockRepo.EXPECT().ListStoredOrders().Return([]models.Order{{OrderID: 1}, {OrderID: 2}}, nil)
+
+	// act
+	n, err := repo.WarmUp()
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	_, ok := imCache.Get(1)
+	assert.True(t, ok)
+	_, ok = imCache.Get(2)
+	assert.True(t, ok)
+}
+
+func TestRepo_Reload(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	repo, mockRepo, imCache := newTestRepo(t)
+	mockRepo.EXPECT().GetOrderByID(1).Return(&models.Order{OrderID: 1, Cost: 200}, nil)
+	mockRepo.EXPECT().GetOrderByID(2).Return(nil, postgresql.ErrOrderNotFound)
+
+	// act
+	n, err := repo.Reload([]int{1, 2})
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "Orders deleted while the service was down are skipped")
+	order, ok := imCache.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, 200.0, order.Cost, "Order is cached as it is in the database now")
+	_, ok = imCache.Get(2)
+	assert.False(t, ok)
+}
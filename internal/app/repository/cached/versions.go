@@ -0,0 +1,59 @@
+package cached
+
+import "sync"
+
+// versionStripes is the number of version counters, orders share a counter if their IDs are equal modulo it
+const versionStripes = 256
+
+// versions count changes of cached orders. A value read from the database is cached only if no order
+// of its stripe changed while it was read, otherwise a load started before a mutation could put back
+// the row the mutation has just replaced. Orders sharing a stripe only make such loads skip the cache
+type versions struct {
+	mu      [versionStripes]sync.Mutex
+	counter [versionStripes]uint64
+}
+
+func stripe(orderID int) int {
+	return int(uint(orderID) % versionStripes)
+}
+
+// get returns the version of the order's stripe
+func (v *versions) get(orderID int) uint64 {
+	i := stripe(orderID)
+	v.mu[i].Lock()
+	defer v.mu[i].Unlock()
+
+	return v.counter[i]
+}
+
+// snapshot returns versions of all stripes
+func (v *versions) snapshot() [versionStripes]uint64 {
+	var snapshot [versionStripes]uint64
+	for i := range v.counter {
+		v.mu[i].Lock()
+		snapshot[i] = v.counter[i]
+		v.mu[i].Unlock()
+	}
+	return snapshot
+}
+
+// change runs the change of the cached order and increments the version of its stripe
+func (v *versions) change(orderID int, fx func()) {
+	i := stripe(orderID)
+	v.mu[i].Lock()
+	defer v.mu[i].Unlock()
+
+	v.counter[i]++
+	fx()
+}
+
+// load runs fx only if the order's stripe still has the version read before the load
+func (v *versions) load(orderID int, version uint64, fx func()) {
+	i := stripe(orderID)
+	v.mu[i].Lock()
+	defer v.mu[i].Unlock()
+
+	if v.counter[i] == version {
+		fx()
+	}
+}
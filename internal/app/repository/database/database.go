@@ -17,6 +17,7 @@ type DBops interface {
 
 type TransactionManager interface {
 	RunRepeatableRead(ctx context.Context, fx func(context.Context) error) error
+	RunReadCommitted(ctx context.Context, fx func(context.Context) error) error
 	GetQueryEngine(ctx context.Context) DBops
 }
 
@@ -41,6 +42,16 @@ var key = &contextKey{}
 // If the function returns error, the transaction is rolled back
 // Otherwise, the transaction is committed
 func (d Database) RunRepeatableRead(ctx context.Context, fx func(context.Context) error) error {
+	return d.runTx(ctx, pgx.RepeatableRead, fx)
+}
+
+// RunReadCommitted runs function within a read committed transaction,
+// every statement sees the changes committed before it starts, e.g. while it waited for a lock
+func (d Database) RunReadCommitted(ctx context.Context, fx func(context.Context) error) error {
+	return d.runTx(ctx, pgx.ReadCommitted, fx)
+}
+
+func (d Database) runTx(ctx context.Context, isoLevel pgx.TxIsoLevel, fx func(context.Context) error) error {
 	conn, err := d.pool.Acquire(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to acquire connection: %w", err)
@@ -48,7 +59,7 @@ func (d Database) RunRepeatableRead(ctx context.Context, fx func(context.Context
 	defer conn.Release()
 
 	tx, err := conn.BeginTx(ctx, pgx.TxOptions{
-		IsoLevel:   pgx.RepeatableRead,
+		IsoLevel:   isoLevel,
 		AccessMode: pgx.ReadWrite,
 	})
 	if err != nil {
@@ -12,6 +12,8 @@ package mock_repository
 import (
 	reflect "reflect"
 	models "route/internal/app/models"
+	repository "route/internal/app/repository"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -53,6 +55,20 @@ func (mr *MockRepositoryMockRecorder) AcceptOrder(order, packagingType any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptOrder", reflect.TypeOf((*MockRepository)(nil).AcceptOrder), order, packagingType)
 }
 
+// AcceptOrders mocks base method.
+func (m *MockRepository) AcceptOrders(orders []*models.Order, packagingTypes []*models.PackagingType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptOrders", orders, packagingTypes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcceptOrders indicates an expected call of AcceptOrders.
+func (mr *MockRepositoryMockRecorder) AcceptOrders(orders, packagingTypes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptOrders", reflect.TypeOf((*MockRepository)(nil).AcceptOrders), orders, packagingTypes)
+}
+
 // AcceptReturn mocks base method.
 func (m *MockRepository) AcceptReturn(order models.Order) error {
 	m.ctrl.T.Helper()
@@ -67,6 +83,64 @@ func (mr *MockRepositoryMockRecorder) AcceptReturn(order any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptReturn", reflect.TypeOf((*MockRepository)(nil).AcceptReturn), order)
 }
 
+// CreateReturnManifest mocks base method.
+func (m *MockRepository) CreateReturnManifest(courierID int) (*models.ReturnManifest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReturnManifest", courierID)
+	ret0, _ := ret[0].(*models.ReturnManifest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReturnManifest indicates an expected call of CreateReturnManifest.
+func (mr *MockRepositoryMockRecorder) CreateReturnManifest(courierID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReturnManifest", reflect.TypeOf((*MockRepository)(nil).CreateReturnManifest), courierID)
+}
+
+// CreateTransfer mocks base method.
+func (m *MockRepository) CreateTransfer(toPointID int, orderIDs []int) (*models.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransfer", toPointID, orderIDs)
+	ret0, _ := ret[0].(*models.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransfer indicates an expected call of CreateTransfer.
+func (mr *MockRepositoryMockRecorder) CreateTransfer(toPointID, orderIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockRepository)(nil).CreateTransfer), toPointID, orderIDs)
+}
+
+// ExportOrders mocks base method.
+func (m *MockRepository) ExportOrders(filter models.ExportFilter, fx func(models.Order) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportOrders", filter, fx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportOrders indicates an expected call of ExportOrders.
+func (mr *MockRepositoryMockRecorder) ExportOrders(filter, fx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportOrders", reflect.TypeOf((*MockRepository)(nil).ExportOrders), filter, fx)
+}
+
+// ForPoint mocks base method.
+func (m *MockRepository) ForPoint(pointID int) repository.Repository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForPoint", pointID)
+	ret0, _ := ret[0].(repository.Repository)
+	return ret0
+}
+
+// ForPoint indicates an expected call of ForPoint.
+func (mr *MockRepositoryMockRecorder) ForPoint(pointID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForPoint", reflect.TypeOf((*MockRepository)(nil).ForPoint), pointID)
+}
+
 // GetAllOrders mocks base method.
 func (m *MockRepository) GetAllOrders() ([]models.Order, error) {
 	m.ctrl.T.Helper()
@@ -97,18 +171,48 @@ func (mr *MockRepositoryMockRecorder) GetOrderByID(orderID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByID", reflect.TypeOf((*MockRepository)(nil).GetOrderByID), orderID)
 }
 
+// GetTransfer mocks base method.
+func (m *MockRepository) GetTransfer(transferID int64) (*models.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", transferID)
+	ret0, _ := ret[0].(*models.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockRepositoryMockRecorder) GetTransfer(transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockRepository)(nil).GetTransfer), transferID)
+}
+
 // IssueOrder mocks base method.
-func (m *MockRepository) IssueOrder(orderID int, hash string) error {
+func (m *MockRepository) IssueOrder(orderID int, hash string, record models.IssueRecord) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IssueOrder", orderID, hash)
+	ret := m.ctrl.Call(m, "IssueOrder", orderID, hash, record)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // IssueOrder indicates an expected call of IssueOrder.
-func (mr *MockRepositoryMockRecorder) IssueOrder(orderID, hash any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) IssueOrder(orderID, hash, record any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueOrder", reflect.TypeOf((*MockRepository)(nil).IssueOrder), orderID, hash)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueOrder", reflect.TypeOf((*MockRepository)(nil).IssueOrder), orderID, hash, record)
+}
+
+// ListExpiredOrders mocks base method.
+func (m *MockRepository) ListExpiredOrders() ([]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredOrders")
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredOrders indicates an expected call of ListExpiredOrders.
+func (mr *MockRepositoryMockRecorder) ListExpiredOrders() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredOrders", reflect.TypeOf((*MockRepository)(nil).ListExpiredOrders))
 }
 
 // ListOrders mocks base method.
@@ -126,6 +230,21 @@ func (mr *MockRepositoryMockRecorder) ListOrders(userID, lastN any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockRepository)(nil).ListOrders), userID, lastN)
 }
 
+// ListPresentOrders mocks base method.
+func (m *MockRepository) ListPresentOrders() ([]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPresentOrders")
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPresentOrders indicates an expected call of ListPresentOrders.
+func (mr *MockRepositoryMockRecorder) ListPresentOrders() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPresentOrders", reflect.TypeOf((*MockRepository)(nil).ListPresentOrders))
+}
+
 // ListReturns mocks base method.
 func (m *MockRepository) ListReturns(page, pageSize int) ([]models.Order, error) {
 	m.ctrl.T.Helper()
@@ -141,6 +260,80 @@ func (mr *MockRepositoryMockRecorder) ListReturns(page, pageSize any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReturns", reflect.TypeOf((*MockRepository)(nil).ListReturns), page, pageSize)
 }
 
+// ListStoredOrders mocks base method.
+func (m *MockRepository) ListStoredOrders() ([]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStoredOrders")
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStoredOrders indicates an expected call of ListStoredOrders.
+func (mr *MockRepositoryMockRecorder) ListStoredOrders() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStoredOrders", reflect.TypeOf((*MockRepository)(nil).ListStoredOrders))
+}
+
+// MarkExpired mocks base method.
+func (m *MockRepository) MarkExpired() ([]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkExpired")
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkExpired indicates an expected call of MarkExpired.
+func (mr *MockRepositoryMockRecorder) MarkExpired() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkExpired", reflect.TypeOf((*MockRepository)(nil).MarkExpired))
+}
+
+// ReceiveTransfer mocks base method.
+func (m *MockRepository) ReceiveTransfer(transferID int64) (*models.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReceiveTransfer", transferID)
+	ret0, _ := ret[0].(*models.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReceiveTransfer indicates an expected call of ReceiveTransfer.
+func (mr *MockRepositoryMockRecorder) ReceiveTransfer(transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiveTransfer", reflect.TypeOf((*MockRepository)(nil).ReceiveTransfer), transferID)
+}
+
+// RecordPickupFailure mocks base method.
+func (m *MockRepository) RecordPickupFailure(orderID, maxFailures int) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordPickupFailure", orderID, maxFailures)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordPickupFailure indicates an expected call of RecordPickupFailure.
+func (mr *MockRepositoryMockRecorder) RecordPickupFailure(orderID, maxFailures any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPickupFailure", reflect.TypeOf((*MockRepository)(nil).RecordPickupFailure), orderID, maxFailures)
+}
+
+// RefuseOrder mocks base method.
+func (m *MockRepository) RefuseOrder(orderID int, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefuseOrder", orderID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefuseOrder indicates an expected call of RefuseOrder.
+func (mr *MockRepositoryMockRecorder) RefuseOrder(orderID, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefuseOrder", reflect.TypeOf((*MockRepository)(nil).RefuseOrder), orderID, reason)
+}
+
 // ReturnOrder mocks base method.
 func (m *MockRepository) ReturnOrder(orderID int) error {
 	m.ctrl.T.Helper()
@@ -154,3 +347,700 @@ func (mr *MockRepositoryMockRecorder) ReturnOrder(orderID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnOrder", reflect.TypeOf((*MockRepository)(nil).ReturnOrder), orderID)
 }
+
+// UnlockPickup mocks base method.
+func (m *MockRepository) UnlockPickup(orderID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockPickup", orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockPickup indicates an expected call of UnlockPickup.
+func (mr *MockRepositoryMockRecorder) UnlockPickup(orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockPickup", reflect.TypeOf((*MockRepository)(nil).UnlockPickup), orderID)
+}
+
+// MockPickupPointRepository is a mock of PickupPointRepository interface.
+type MockPickupPointRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPickupPointRepositoryMockRecorder
+}
+
+// MockPickupPointRepositoryMockRecorder is the mock recorder for MockPickupPointRepository.
+type MockPickupPointRepositoryMockRecorder struct {
+	mock *MockPickupPointRepository
+}
+
+// NewMockPickupPointRepository creates a new mock instance.
+func NewMockPickupPointRepository(ctrl *gomock.Controller) *MockPickupPointRepository {
+	mock := &MockPickupPointRepository{ctrl: ctrl}
+	mock.recorder = &MockPickupPointRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPickupPointRepository) EXPECT() *MockPickupPointRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreatePickupPoint mocks base method.
+func (m *MockPickupPointRepository) CreatePickupPoint(point models.PickupPoint) (*models.PickupPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePickupPoint", point)
+	ret0, _ := ret[0].(*models.PickupPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePickupPoint indicates an expected call of CreatePickupPoint.
+func (mr *MockPickupPointRepositoryMockRecorder) CreatePickupPoint(point any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePickupPoint", reflect.TypeOf((*MockPickupPointRepository)(nil).CreatePickupPoint), point)
+}
+
+// GetPickupPoint mocks base method.
+func (m *MockPickupPointRepository) GetPickupPoint(id int) (*models.PickupPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPickupPoint", id)
+	ret0, _ := ret[0].(*models.PickupPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPickupPoint indicates an expected call of GetPickupPoint.
+func (mr *MockPickupPointRepositoryMockRecorder) GetPickupPoint(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPickupPoint", reflect.TypeOf((*MockPickupPointRepository)(nil).GetPickupPoint), id)
+}
+
+// ListPickupPoints mocks base method.
+func (m *MockPickupPointRepository) ListPickupPoints() ([]models.PickupPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPickupPoints")
+	ret0, _ := ret[0].([]models.PickupPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPickupPoints indicates an expected call of ListPickupPoints.
+func (mr *MockPickupPointRepositoryMockRecorder) ListPickupPoints() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPickupPoints", reflect.TypeOf((*MockPickupPointRepository)(nil).ListPickupPoints))
+}
+
+// MockStorageRepository is a mock of StorageRepository interface.
+type MockStorageRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageRepositoryMockRecorder
+}
+
+// MockStorageRepositoryMockRecorder is the mock recorder for MockStorageRepository.
+type MockStorageRepositoryMockRecorder struct {
+	mock *MockStorageRepository
+}
+
+// NewMockStorageRepository creates a new mock instance.
+func NewMockStorageRepository(ctrl *gomock.Controller) *MockStorageRepository {
+	mock := &MockStorageRepository{ctrl: ctrl}
+	mock.recorder = &MockStorageRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorageRepository) EXPECT() *MockStorageRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateRack mocks base method.
+func (m *MockStorageRepository) CreateRack(rack models.Rack) (*models.Rack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRack", rack)
+	ret0, _ := ret[0].(*models.Rack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRack indicates an expected call of CreateRack.
+func (mr *MockStorageRepositoryMockRecorder) CreateRack(rack any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRack", reflect.TypeOf((*MockStorageRepository)(nil).CreateRack), rack)
+}
+
+// ListCells mocks base method.
+func (m *MockStorageRepository) ListCells(pointID int) ([]models.StorageCell, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCells", pointID)
+	ret0, _ := ret[0].([]models.StorageCell)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCells indicates an expected call of ListCells.
+func (mr *MockStorageRepositoryMockRecorder) ListCells(pointID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCells", reflect.TypeOf((*MockStorageRepository)(nil).ListCells), pointID)
+}
+
+// MockStocktakeRepository is a mock of StocktakeRepository interface.
+type MockStocktakeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStocktakeRepositoryMockRecorder
+}
+
+// MockStocktakeRepositoryMockRecorder is the mock recorder for MockStocktakeRepository.
+type MockStocktakeRepositoryMockRecorder struct {
+	mock *MockStocktakeRepository
+}
+
+// NewMockStocktakeRepository creates a new mock instance.
+func NewMockStocktakeRepository(ctrl *gomock.Controller) *MockStocktakeRepository {
+	mock := &MockStocktakeRepository{ctrl: ctrl}
+	mock.recorder = &MockStocktakeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStocktakeRepository) EXPECT() *MockStocktakeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// SaveStocktake mocks base method.
+func (m *MockStocktakeRepository) SaveStocktake(stocktake models.Stocktake) (*models.Stocktake, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveStocktake", stocktake)
+	ret0, _ := ret[0].(*models.Stocktake)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveStocktake indicates an expected call of SaveStocktake.
+func (mr *MockStocktakeRepositoryMockRecorder) SaveStocktake(stocktake any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveStocktake", reflect.TypeOf((*MockStocktakeRepository)(nil).SaveStocktake), stocktake)
+}
+
+// MockReportRepository is a mock of ReportRepository interface.
+type MockReportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportRepositoryMockRecorder
+}
+
+// MockReportRepositoryMockRecorder is the mock recorder for MockReportRepository.
+type MockReportRepositoryMockRecorder struct {
+	mock *MockReportRepository
+}
+
+// NewMockReportRepository creates a new mock instance.
+func NewMockReportRepository(ctrl *gomock.Controller) *MockReportRepository {
+	mock := &MockReportRepository{ctrl: ctrl}
+	mock.recorder = &MockReportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportRepository) EXPECT() *MockReportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetReport mocks base method.
+func (m *MockReportRepository) GetReport(pointID int, from, to time.Time) ([]models.ReportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReport", pointID, from, to)
+	ret0, _ := ret[0].([]models.ReportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReport indicates an expected call of GetReport.
+func (mr *MockReportRepositoryMockRecorder) GetReport(pointID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReport", reflect.TypeOf((*MockReportRepository)(nil).GetReport), pointID, from, to)
+}
+
+// MockCourierRepository is a mock of CourierRepository interface.
+type MockCourierRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCourierRepositoryMockRecorder
+}
+
+// MockCourierRepositoryMockRecorder is the mock recorder for MockCourierRepository.
+type MockCourierRepositoryMockRecorder struct {
+	mock *MockCourierRepository
+}
+
+// NewMockCourierRepository creates a new mock instance.
+func NewMockCourierRepository(ctrl *gomock.Controller) *MockCourierRepository {
+	mock := &MockCourierRepository{ctrl: ctrl}
+	mock.recorder = &MockCourierRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCourierRepository) EXPECT() *MockCourierRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CloseSession mocks base method.
+func (m *MockCourierRepository) CloseSession(id int64) (*models.SessionSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSession", id)
+	ret0, _ := ret[0].(*models.SessionSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseSession indicates an expected call of CloseSession.
+func (mr *MockCourierRepositoryMockRecorder) CloseSession(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSession", reflect.TypeOf((*MockCourierRepository)(nil).CloseSession), id)
+}
+
+// CreateCourier mocks base method.
+func (m *MockCourierRepository) CreateCourier(courier models.Courier) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCourier", courier)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCourier indicates an expected call of CreateCourier.
+func (mr *MockCourierRepositoryMockRecorder) CreateCourier(courier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCourier", reflect.TypeOf((*MockCourierRepository)(nil).CreateCourier), courier)
+}
+
+// GetCourier mocks base method.
+func (m *MockCourierRepository) GetCourier(id int) (*models.Courier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCourier", id)
+	ret0, _ := ret[0].(*models.Courier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCourier indicates an expected call of GetCourier.
+func (mr *MockCourierRepositoryMockRecorder) GetCourier(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourier", reflect.TypeOf((*MockCourierRepository)(nil).GetCourier), id)
+}
+
+// GetSession mocks base method.
+func (m *MockCourierRepository) GetSession(id int64) (*models.AcceptanceSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", id)
+	ret0, _ := ret[0].(*models.AcceptanceSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockCourierRepositoryMockRecorder) GetSession(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockCourierRepository)(nil).GetSession), id)
+}
+
+// OpenSession mocks base method.
+func (m *MockCourierRepository) OpenSession(courierID, expectedCount int) (*models.AcceptanceSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenSession", courierID, expectedCount)
+	ret0, _ := ret[0].(*models.AcceptanceSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenSession indicates an expected call of OpenSession.
+func (mr *MockCourierRepositoryMockRecorder) OpenSession(courierID, expectedCount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenSession", reflect.TypeOf((*MockCourierRepository)(nil).OpenSession), courierID, expectedCount)
+}
+
+// RecordRejection mocks base method.
+func (m *MockCourierRepository) RecordRejection(sessionID int64, rejection models.SessionRejection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordRejection", sessionID, rejection)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordRejection indicates an expected call of RecordRejection.
+func (mr *MockCourierRepositoryMockRecorder) RecordRejection(sessionID, rejection any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordRejection", reflect.TypeOf((*MockCourierRepository)(nil).RecordRejection), sessionID, rejection)
+}
+
+// MockAuthorizationRepository is a mock of AuthorizationRepository interface.
+type MockAuthorizationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorizationRepositoryMockRecorder
+}
+
+// MockAuthorizationRepositoryMockRecorder is the mock recorder for MockAuthorizationRepository.
+type MockAuthorizationRepositoryMockRecorder struct {
+	mock *MockAuthorizationRepository
+}
+
+// NewMockAuthorizationRepository creates a new mock instance.
+func NewMockAuthorizationRepository(ctrl *gomock.Controller) *MockAuthorizationRepository {
+	mock := &MockAuthorizationRepository{ctrl: ctrl}
+	mock.recorder = &MockAuthorizationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorizationRepository) EXPECT() *MockAuthorizationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateAuthorization mocks base method.
+func (m *MockAuthorizationRepository) CreateAuthorization(auth models.Authorization) (*models.Authorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuthorization", auth)
+	ret0, _ := ret[0].(*models.Authorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuthorization indicates an expected call of CreateAuthorization.
+func (mr *MockAuthorizationRepositoryMockRecorder) CreateAuthorization(auth any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthorization", reflect.TypeOf((*MockAuthorizationRepository)(nil).CreateAuthorization), auth)
+}
+
+// ListAuthorizations mocks base method.
+func (m *MockAuthorizationRepository) ListAuthorizations(userID int) ([]models.Authorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuthorizations", userID)
+	ret0, _ := ret[0].([]models.Authorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuthorizations indicates an expected call of ListAuthorizations.
+func (mr *MockAuthorizationRepositoryMockRecorder) ListAuthorizations(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuthorizations", reflect.TypeOf((*MockAuthorizationRepository)(nil).ListAuthorizations), userID)
+}
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ProcessPending mocks base method.
+func (m *MockOutboxRepository) ProcessPending(sink string, limit int, fx func(models.OutboxMessage) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessPending", sink, limit, fx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessPending indicates an expected call of ProcessPending.
+func (mr *MockOutboxRepositoryMockRecorder) ProcessPending(sink, limit, fx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessPending", reflect.TypeOf((*MockOutboxRepository)(nil).ProcessPending), sink, limit, fx)
+}
+
+// MockManifestRepository is a mock of ManifestRepository interface.
+type MockManifestRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockManifestRepositoryMockRecorder
+}
+
+// MockManifestRepositoryMockRecorder is the mock recorder for MockManifestRepository.
+type MockManifestRepositoryMockRecorder struct {
+	mock *MockManifestRepository
+}
+
+// NewMockManifestRepository creates a new mock instance.
+func NewMockManifestRepository(ctrl *gomock.Controller) *MockManifestRepository {
+	mock := &MockManifestRepository{ctrl: ctrl}
+	mock.recorder = &MockManifestRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockManifestRepository) EXPECT() *MockManifestRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetManifestItem mocks base method.
+func (m *MockManifestRepository) GetManifestItem(manifestID string, orderID int) (*models.ManifestItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetManifestItem", manifestID, orderID)
+	ret0, _ := ret[0].(*models.ManifestItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetManifestItem indicates an expected call of GetManifestItem.
+func (mr *MockManifestRepositoryMockRecorder) GetManifestItem(manifestID, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetManifestItem", reflect.TypeOf((*MockManifestRepository)(nil).GetManifestItem), manifestID, orderID)
+}
+
+// SaveManifestItem mocks base method.
+func (m *MockManifestRepository) SaveManifestItem(item models.ManifestItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveManifestItem", item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveManifestItem indicates an expected call of SaveManifestItem.
+func (mr *MockManifestRepositoryMockRecorder) SaveManifestItem(item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveManifestItem", reflect.TypeOf((*MockManifestRepository)(nil).SaveManifestItem), item)
+}
+
+// MockWebhookRepository is a mock of WebhookRepository interface.
+type MockWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookRepositoryMockRecorder
+}
+
+// MockWebhookRepositoryMockRecorder is the mock recorder for MockWebhookRepository.
+type MockWebhookRepositoryMockRecorder struct {
+	mock *MockWebhookRepository
+}
+
+// NewMockWebhookRepository creates a new mock instance.
+func NewMockWebhookRepository(ctrl *gomock.Controller) *MockWebhookRepository {
+	mock := &MockWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookRepository) EXPECT() *MockWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateSubscription mocks base method.
+func (m *MockWebhookRepository) CreateSubscription(sub models.WebhookSubscription) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscription", sub)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSubscription indicates an expected call of CreateSubscription.
+func (mr *MockWebhookRepositoryMockRecorder) CreateSubscription(sub any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockWebhookRepository)(nil).CreateSubscription), sub)
+}
+
+// DeleteSubscription mocks base method.
+func (m *MockWebhookRepository) DeleteSubscription(id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscription", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubscription indicates an expected call of DeleteSubscription.
+func (mr *MockWebhookRepositoryMockRecorder) DeleteSubscription(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockWebhookRepository)(nil).DeleteSubscription), id)
+}
+
+// EnqueueDeliveries mocks base method.
+func (m *MockWebhookRepository) EnqueueDeliveries(msg models.OutboxMessage, body []byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueDeliveries", msg, body)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueDeliveries indicates an expected call of EnqueueDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) EnqueueDeliveries(msg, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).EnqueueDeliveries), msg, body)
+}
+
+// ListDeliveryLogs mocks base method.
+func (m *MockWebhookRepository) ListDeliveryLogs(subscriptionID int64, limit int) ([]models.WebhookDeliveryLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveryLogs", subscriptionID, limit)
+	ret0, _ := ret[0].([]models.WebhookDeliveryLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveryLogs indicates an expected call of ListDeliveryLogs.
+func (mr *MockWebhookRepositoryMockRecorder) ListDeliveryLogs(subscriptionID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveryLogs", reflect.TypeOf((*MockWebhookRepository)(nil).ListDeliveryLogs), subscriptionID, limit)
+}
+
+// ListSubscriptions mocks base method.
+func (m *MockWebhookRepository) ListSubscriptions() ([]models.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptions")
+	ret0, _ := ret[0].([]models.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptions indicates an expected call of ListSubscriptions.
+func (mr *MockWebhookRepositoryMockRecorder) ListSubscriptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptions", reflect.TypeOf((*MockWebhookRepository)(nil).ListSubscriptions))
+}
+
+// ProcessDueDeliveries mocks base method.
+func (m *MockWebhookRepository) ProcessDueDeliveries(limit int, fx func(models.WebhookDelivery) models.WebhookAttempt) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessDueDeliveries", limit, fx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessDueDeliveries indicates an expected call of ProcessDueDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) ProcessDueDeliveries(limit, fx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessDueDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).ProcessDueDeliveries), limit, fx)
+}
+
+// MockNotificationRepository is a mock of NotificationRepository interface.
+type MockNotificationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationRepositoryMockRecorder
+}
+
+// MockNotificationRepositoryMockRecorder is the mock recorder for MockNotificationRepository.
+type MockNotificationRepositoryMockRecorder struct {
+	mock *MockNotificationRepository
+}
+
+// NewMockNotificationRepository creates a new mock instance.
+func NewMockNotificationRepository(ctrl *gomock.Controller) *MockNotificationRepository {
+	mock := &MockNotificationRepository{ctrl: ctrl}
+	mock.recorder = &MockNotificationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationRepository) EXPECT() *MockNotificationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetContact mocks base method.
+func (m *MockNotificationRepository) GetContact(userID int) (*models.Contact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContact", userID)
+	ret0, _ := ret[0].(*models.Contact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContact indicates an expected call of GetContact.
+func (mr *MockNotificationRepositoryMockRecorder) GetContact(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContact", reflect.TypeOf((*MockNotificationRepository)(nil).GetContact), userID)
+}
+
+// ListOrdersToRemind mocks base method.
+func (m *MockNotificationRepository) ListOrdersToRemind(deadlineBefore time.Time) ([]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrdersToRemind", deadlineBefore)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrdersToRemind indicates an expected call of ListOrdersToRemind.
+func (mr *MockNotificationRepositoryMockRecorder) ListOrdersToRemind(deadlineBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrdersToRemind", reflect.TypeOf((*MockNotificationRepository)(nil).ListOrdersToRemind), deadlineBefore)
+}
+
+// SaveContact mocks base method.
+func (m *MockNotificationRepository) SaveContact(contact models.Contact) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveContact", contact)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveContact indicates an expected call of SaveContact.
+func (mr *MockNotificationRepositoryMockRecorder) SaveContact(contact any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveContact", reflect.TypeOf((*MockNotificationRepository)(nil).SaveContact), contact)
+}
+
+// SaveNotification mocks base method.
+func (m *MockNotificationRepository) SaveNotification(notification models.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveNotification", notification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveNotification indicates an expected call of SaveNotification.
+func (mr *MockNotificationRepositoryMockRecorder) SaveNotification(notification any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveNotification", reflect.TypeOf((*MockNotificationRepository)(nil).SaveNotification), notification)
+}
+
+// MockSchedulerRepository is a mock of SchedulerRepository interface.
+type MockSchedulerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchedulerRepositoryMockRecorder
+}
+
+// MockSchedulerRepositoryMockRecorder is the mock recorder for MockSchedulerRepository.
+type MockSchedulerRepositoryMockRecorder struct {
+	mock *MockSchedulerRepository
+}
+
+// NewMockSchedulerRepository creates a new mock instance.
+func NewMockSchedulerRepository(ctrl *gomock.Controller) *MockSchedulerRepository {
+	mock := &MockSchedulerRepository{ctrl: ctrl}
+	mock.recorder = &MockSchedulerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSchedulerRepository) EXPECT() *MockSchedulerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// RunLocked mocks base method.
+func (m *MockSchedulerRepository) RunLocked(job string, fx func(time.Time) (bool, error)) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunLocked", job, fx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunLocked indicates an expected call of RunLocked.
+func (mr *MockSchedulerRepositoryMockRecorder) RunLocked(job, fx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunLocked", reflect.TypeOf((*MockSchedulerRepository)(nil).RunLocked), job, fx)
+}
@@ -0,0 +1,56 @@
+package postgresql
+
+import (
+	"context"
+
+	"route/internal/app/models"
+	"route/internal/app/repository/database"
+)
+
+type AuthorizationRepo struct {
+	tm database.TransactionManager
+}
+
+func NewAuthorization(tm database.TransactionManager) *AuthorizationRepo {
+	return &AuthorizationRepo{tm: tm}
+}
+
+// CreateAuthorization saves the power of attorney
+func (r *AuthorizationRepo) CreateAuthorization(auth models.Authorization) (*models.Authorization, error) {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	err := qe.QueryRow(ctx,
+		"INSERT INTO pickup_authorizations (user_id, recipient, order_id, valid_until) VALUES ($1, $2, NULLIF($3, 0), $4) RETURNING id, created_at",
+		auth.UserID, auth.Recipient, auth.OrderID, auth.ValidUntil).Scan(&auth.ID, &auth.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth, nil
+}
+
+// ListAuthorizations returns powers of attorney of the client, the newest first
+func (r *AuthorizationRepo) ListAuthorizations(userID int) ([]models.Authorization, error) {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	rows, err := qe.Query(ctx,
+		"SELECT id, user_id, recipient, COALESCE(order_id, 0), valid_until, created_at FROM pickup_authorizations WHERE user_id = $1 ORDER BY id DESC",
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var auths []models.Authorization
+	for rows.Next() {
+		var auth models.Authorization
+		if err = rows.Scan(&auth.ID, &auth.UserID, &auth.Recipient, &auth.OrderID, &auth.ValidUntil, &auth.CreatedAt); err != nil {
+			return nil, err
+		}
+		auths = append(auths, auth)
+	}
+
+	return auths, rows.Err()
+}
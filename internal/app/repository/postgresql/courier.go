@@ -0,0 +1,159 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"route/internal/app/models"
+	"route/internal/app/repository/database"
+)
+
+var (
+	ErrCourierNotFound = errors.New("courier not found")
+	ErrSessionNotFound = errors.New("acceptance session not found")
+	ErrSessionClosed   = errors.New("acceptance session is closed")
+)
+
+type CourierRepo struct {
+	tm database.TransactionManager
+}
+
+func NewCourier(tm database.TransactionManager) *CourierRepo {
+	return &CourierRepo{tm: tm}
+}
+
+// CreateCourier saves the courier and returns its ID
+func (r *CourierRepo) CreateCourier(courier models.Courier) (int, error) {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	var id int
+	err := qe.QueryRow(ctx, "INSERT INTO couriers (name, company) VALUES ($1, $2) RETURNING id",
+		courier.Name, courier.Company).Scan(&id)
+	return id, err
+}
+
+// GetCourier returns the courier with the given ID
+func (r *CourierRepo) GetCourier(id int) (*models.Courier, error) {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	courier := models.Courier{ID: id}
+	err := qe.QueryRow(ctx, "SELECT name, company, created_at FROM couriers WHERE id = $1", id).
+		Scan(&courier.Name, &courier.Company, &courier.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCourierNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &courier, nil
+}
+
+// OpenSession opens an acceptance session of the courier
+func (r *CourierRepo) OpenSession(courierID, expectedCount int) (*models.AcceptanceSession, error) {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	session := models.AcceptanceSession{CourierID: courierID, ExpectedCount: expectedCount}
+	err := qe.QueryRow(ctx, "INSERT INTO acceptance_sessions (courier_id, expected_count) VALUES ($1, $2) RETURNING id, opened_at",
+		courierID, expectedCount).Scan(&session.ID, &session.OpenedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// GetSession returns the acceptance session with the given ID
+func (r *CourierRepo) GetSession(id int64) (*models.AcceptanceSession, error) {
+	ctx := context.Background()
+	return getSession(ctx, r.tm.GetQueryEngine(ctx), id, "")
+}
+
+// RecordRejection records an order of the session that failed acceptance checks
+func (r *CourierRepo) RecordRejection(sessionID int64, rejection models.SessionRejection) error {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	_, err := qe.Exec(ctx, "INSERT INTO session_rejections (session_id, order_id, reason) VALUES ($1, $2, $3)",
+		sessionID, rejection.OrderID, rejection.Reason)
+	return err
+}
+
+// CloseSession closes the session and saves its totals. The session row is locked,
+// so no order can be linked to the session while it is being closed. Totals are counted
+// under read committed, so they include orders linked while the lock was awaited
+func (r *CourierRepo) CloseSession(id int64) (*models.SessionSummary, error) {
+	var summary models.SessionSummary
+	err := r.tm.RunReadCommitted(context.Background(), func(ctx context.Context) error {
+		qe := r.tm.GetQueryEngine(ctx)
+
+		session, err := getSession(ctx, qe, id, "FOR UPDATE")
+		if err != nil {
+			return err
+		}
+		if session.Closed() {
+			return ErrSessionClosed
+		}
+
+		err = qe.QueryRow(ctx, "SELECT COUNT(*), COALESCE(SUM(weight), 0) FROM orders WHERE session_id = $1", id).
+			Scan(&summary.Accepted, &summary.TotalWeight)
+		if err != nil {
+			return err
+		}
+
+		rows, err := qe.Query(ctx, "SELECT order_id, reason FROM session_rejections WHERE session_id = $1 ORDER BY id", id)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var rejection models.SessionRejection
+			if err = rows.Scan(&rejection.OrderID, &rejection.Reason); err != nil {
+				return err
+			}
+			summary.Rejections = append(summary.Rejections, rejection)
+		}
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		err = qe.QueryRow(ctx,
+			"UPDATE acceptance_sessions SET closed_at = NOW(), accepted_count = $2, total_weight = $3 WHERE id = $1 RETURNING closed_at",
+			id, summary.Accepted, summary.TotalWeight).Scan(&session.ClosedAt)
+		if err != nil {
+			return err
+		}
+
+		summary.Session = *session
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+func getSession(ctx context.Context, qe database.DBops, id int64, lock string) (*models.AcceptanceSession, error) {
+	session := models.AcceptanceSession{ID: id}
+	var closedAt *time.Time
+	err := qe.QueryRow(ctx, "SELECT courier_id, expected_count, opened_at, closed_at FROM acceptance_sessions WHERE id = $1 "+lock, id).
+		Scan(&session.CourierID, &session.ExpectedCount, &session.OpenedAt, &closedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if closedAt != nil {
+		session.ClosedAt = *closedAt
+	}
+	return &session, nil
+}
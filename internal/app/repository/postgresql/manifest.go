@@ -0,0 +1,50 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"route/internal/app/models"
+	"route/internal/app/repository/database"
+)
+
+var ErrManifestItemNotFound = errors.New("manifest item not found")
+
+type ManifestRepo struct {
+	tm database.TransactionManager
+}
+
+func NewManifest(tm database.TransactionManager) *ManifestRepo {
+	return &ManifestRepo{tm: tm}
+}
+
+// GetManifestItem returns the result of processing the order from the manifest
+func (r *ManifestRepo) GetManifestItem(manifestID string, orderID int) (*models.ManifestItem, error) {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	item := models.ManifestItem{ManifestID: manifestID, OrderID: orderID}
+	err := qe.QueryRow(ctx, "SELECT accepted, reason FROM manifest_items WHERE manifest_id = $1 AND order_id = $2",
+		manifestID, orderID).Scan(&item.Accepted, &item.Reason)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrManifestItemNotFound
+		}
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// SaveManifestItem records the result of processing the order from the manifest,
+// the first recorded result wins
+func (r *ManifestRepo) SaveManifestItem(item models.ManifestItem) error {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	_, err := qe.Exec(ctx,
+		"INSERT INTO manifest_items (manifest_id, order_id, accepted, reason) VALUES ($1, $2, $3, $4) ON CONFLICT (manifest_id, order_id) DO NOTHING",
+		item.ManifestID, item.OrderID, item.Accepted, item.Reason)
+	return err
+}
@@ -0,0 +1,77 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"route/internal/app/models"
+	"route/internal/app/repository/database"
+)
+
+var ErrContactNotFound = errors.New("contact not found")
+
+type NotificationRepo struct {
+	tm database.TransactionManager
+}
+
+func NewNotification(tm database.TransactionManager) *NotificationRepo {
+	return &NotificationRepo{tm: tm}
+}
+
+// GetContact returns contact data of the client
+func (r *NotificationRepo) GetContact(userID int) (*models.Contact, error) {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	contact := models.Contact{UserID: userID}
+	err := qe.QueryRow(ctx, "SELECT phone, email, locale FROM user_contacts WHERE user_id = $1", userID).
+		Scan(&contact.Phone, &contact.Email, &contact.Locale)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrContactNotFound
+		}
+		return nil, err
+	}
+
+	return &contact, nil
+}
+
+// SaveContact creates or replaces contact data of the client
+func (r *NotificationRepo) SaveContact(contact models.Contact) error {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	_, err := qe.Exec(ctx,
+		`INSERT INTO user_contacts (user_id, phone, email, locale) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE SET phone = EXCLUDED.phone, email = EXCLUDED.email, locale = EXCLUDED.locale, updated_at = NOW()`,
+		contact.UserID, contact.Phone, contact.Email, contact.Locale)
+	return err
+}
+
+// SaveNotification records a notification sent through a channel
+func (r *NotificationRepo) SaveNotification(notification models.Notification) error {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	_, err := qe.Exec(ctx, "INSERT INTO notifications (order_id, user_id, kind, channel, error) VALUES ($1, $2, $3, $4, $5)",
+		notification.OrderID, notification.UserID, string(notification.Kind), notification.Channel, notification.Error)
+	return err
+}
+
+// ListOrdersToRemind returns stored orders expiring before the given time
+// whose clients have not been reminded yet, failed reminders are sent again.
+// Orders in transit or handed over to the courier are no longer at the point and are skipped
+func (r *NotificationRepo) ListOrdersToRemind(deadlineBefore time.Time) ([]models.Order, error) {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	return queryOrders(ctx, qe,
+		`SELECT `+orderColumns+` FROM orders o
+		WHERE issued_to_user = false AND is_returned = false AND refused_at IS NULL AND deadline > NOW() AND deadline <= $1
+		AND transfer_id IS NULL AND returned_to_courier_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM notifications n WHERE n.order_id = o.id AND n.kind = $2 AND n.error = '')
+		ORDER BY deadline`,
+		deadlineBefore, string(models.ExpiryReminder))
+}
@@ -3,98 +3,215 @@ package postgresql
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/jackc/pgx/v4"
 	"route/internal/app/models"
+	"route/internal/app/repository"
 	"route/internal/app/repository/database"
 )
 
-var ErrOrderNotFound = errors.New("order not found")
+var (
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrOrderStateChanged is returned when the order was changed by another request after it had been checked
+	ErrOrderStateChanged = errors.New("order state has changed")
+)
+
+const (
+	// The conditions checked again by updates, so an order read from a stale cache is not changed twice
+	issuableCondition   = "issued_to_user = false AND is_returned = false AND refused_at IS NULL AND returned_to_courier_at IS NULL AND transfer_id IS NULL"
+	returnableCondition = "is_returned = false"
+	removableCondition  = "issued_to_user = false AND returned_to_courier_at IS NULL AND transfer_id IS NULL"
+)
 
+// Repo works with orders of one pickup point, or of all points if pointID is zero
 type Repo struct {
-	tm database.TransactionManager
+	tm      database.TransactionManager
+	actor   models.Actor
+	pointID int
 }
 
 func New(tm database.TransactionManager) *Repo {
-	return &Repo{tm: tm}
+	return &Repo{
+		tm:    tm,
+		actor: models.NewActor(models.ActorSystem, ""),
+	}
+}
+
+// WithActor returns a copy of the repo that attributes events of all changes to actor
+func (r *Repo) WithActor(actor models.Actor) *Repo {
+	return &Repo{
+		tm:      r.tm,
+		actor:   actor,
+		pointID: r.pointID,
+	}
+}
+
+// ForPoint returns a copy of the repo limited to orders of the pickup point, zero means all points
+func (r *Repo) ForPoint(pointID int) repository.Repository {
+	return &Repo{
+		tm:      r.tm,
+		actor:   r.actor,
+		pointID: pointID,
+	}
 }
 
-// AcceptOrder adds a new order to the database
+// pointScope is the condition limiting a query to orders of the repo's pickup point,
+// arg is the number of the query argument holding the point ID
+func pointScope(arg int) string {
+	return fmt.Sprintf("pickup_point_id = COALESCE(NULLIF($%d::int, 0), pickup_point_id)", arg)
+}
+
+// AcceptOrder adds a new order to the database and places it to a free storage cell of the point
 func (r *Repo) AcceptOrder(order *models.Order, packagingType *models.PackagingType) error {
-	return r.tm.RunRepeatableRead(context.Background(), func(ctx context.Context) error {
-		qe := r.tm.GetQueryEngine(ctx)
+	return runPlacingOrders(r.tm, func(ctx context.Context) error {
+		return r.acceptOrder(ctx, r.tm.GetQueryEngine(ctx), order, packagingType)
+	})
+}
 
-		// Insert the packaging type and get its ID
-		var packagingTypeID int
-		err := qe.QueryRow(ctx, "INSERT INTO packaging_types (type) VALUES ($1) RETURNING id",
-			string(packagingType.Type)).Scan(&packagingTypeID)
-		if err != nil {
-			return err
+// AcceptOrders adds a batch of orders in one transaction, either all of them are stored or none.
+// packagingTypes[i] is the packaging of orders[i]
+func (r *Repo) AcceptOrders(orders []*models.Order, packagingTypes []*models.PackagingType) error {
+	if len(orders) != len(packagingTypes) {
+		return errors.New("orders and packaging types don't match")
+	}
+
+	return runPlacingOrders(r.tm, func(ctx context.Context) error {
+		qe := r.tm.GetQueryEngine(ctx)
+		for i, order := range orders {
+			if err := r.acceptOrder(ctx, qe, order, packagingTypes[i]); err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+}
 
-		_, err = qe.Exec(ctx,
-			"INSERT INTO orders (id, user_id, deadline, issued_at, hash, packaging_type_id, cost, weight) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
-			order.OrderID, order.UserID, order.Deadline, order.IssuedAt, order.Hash, packagingTypeID, order.Cost, order.Weight)
+// acceptOrder stores the order with its packaging and event, it must be called within a transaction
+func (r *Repo) acceptOrder(ctx context.Context, qe database.DBops, order *models.Order, packagingType *models.PackagingType) error {
+	// The session can't be closed until the order is linked to it
+	if order.SessionID != 0 {
+		session, err := getSession(ctx, qe, order.SessionID, "FOR SHARE")
 		if err != nil {
 			return err
 		}
-		return nil
-	})
+		if session.Closed() {
+			return ErrSessionClosed
+		}
+	}
+
+	// Insert the packaging type and get its ID
+	var packagingTypeID int
+	err := qe.QueryRow(ctx, "INSERT INTO packaging_types (type) VALUES ($1) RETURNING id",
+		string(packagingType.Type)).Scan(&packagingTypeID)
+	if err != nil {
+		return err
+	}
+
+	// A repo of all points accepts orders to the default point
+	order.PickupPointID = r.pointID
+	if order.PickupPointID == 0 {
+		order.PickupPointID = models.DefaultPickupPointID
+	}
+
+	order.CellID, order.Cell, err = allocateCell(ctx, qe, order.PickupPointID, packagingType.Type, order.Weight)
+	if err != nil {
+		return err
+	}
+
+	_, err = qe.Exec(ctx,
+		`INSERT INTO orders (id, user_id, deadline, issued_at, hash, packaging_type_id, cost, weight, session_id, pickup_code_hash, pickup_point_id, cell_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, 0), $10, $11, NULLIF($12, 0))`,
+		order.OrderID, order.UserID, order.Deadline, order.IssuedAt, order.Hash, packagingTypeID, order.Cost, order.Weight, order.SessionID,
+		order.PickupCodeHash, order.PickupPointID, order.CellID)
+	if err != nil {
+		return err
+	}
+	return r.insertEvent(ctx, qe, models.OrderAccepted, *order, packagingType.Type)
 }
 
 // ReturnOrder removes an order from the database.
 func (r *Repo) ReturnOrder(orderID int) error {
 	return r.tm.RunRepeatableRead(context.Background(), func(ctx context.Context) error {
 		qe := r.tm.GetQueryEngine(ctx)
-		_, err := qe.Exec(ctx, "DELETE FROM orders WHERE id = $1", orderID)
+		order, err := r.scanGuardedOrder(ctx, qe, qe.QueryRow(ctx,
+			"DELETE FROM orders WHERE id = $1 AND "+pointScope(2)+" AND "+removableCondition+" RETURNING "+orderColumns,
+			orderID, r.pointID), orderID)
 		if err != nil {
 			return err
 		}
-		return nil
+		return r.insertEvent(ctx, qe, models.OrderReturnedToCourier, order, "")
 	})
 }
 
-// IssueOrder updates an order in the database, marking it issued
-func (r *Repo) IssueOrder(orderID int, hash string) error {
+// IssueOrder updates an order in the database, marking it issued with the record of who issued and received it
+func (r *Repo) IssueOrder(orderID int, hash string, record models.IssueRecord) error {
 	return r.tm.RunRepeatableRead(context.Background(), func(ctx context.Context) error {
 		qe := r.tm.GetQueryEngine(ctx)
-		_, err := qe.Exec(ctx, "UPDATE orders SET issued_to_user = true, issued_at = NOW(), hash = $1 WHERE id = $2", hash, orderID)
+		order, err := r.scanGuardedOrder(ctx, qe, qe.QueryRow(ctx,
+			`UPDATE orders SET issued_to_user = true, issued_at = NOW(), hash = $1, issue_override_by = $2, cell_id = NULL,
+			received_by = $3, authorization_id = NULLIF($4, 0) WHERE id = $5 AND `+pointScope(6)+` AND `+issuableCondition+`
+			RETURNING `+orderColumns,
+			hash, record.OverrideBy, record.ReceivedBy, record.AuthorizationID, orderID, r.pointID), orderID)
 		if err != nil {
 			return err
 		}
-		return nil
+		return r.insertEvent(ctx, qe, models.OrderIssued, order, "")
 	})
 }
 
-// ListOrders returns a list of the user's most recent orders from the database
-func (r *Repo) ListOrders(userID, lastN int) ([]models.Order, error) {
-	var orders []models.Order
-	ctx := context.Background()
+// RecordPickupFailure counts a wrong pickup code, the order is locked after maxFailures wrong codes in a row.
+// It returns the order after the update
+func (r *Repo) RecordPickupFailure(orderID int, maxFailures int) (*models.Order, error) {
+	var order models.Order
+	err := r.tm.RunRepeatableRead(context.Background(), func(ctx context.Context) error {
+		qe := r.tm.GetQueryEngine(ctx)
+
+		var err error
+		order, err = scanOrder(qe.QueryRow(ctx,
+			`UPDATE orders SET pickup_failures = pickup_failures + 1, pickup_last_failure_at = NOW(),
+			pickup_locked_at = CASE WHEN pickup_failures + 1 >= $1 THEN COALESCE(pickup_locked_at, NOW()) ELSE pickup_locked_at END
+			WHERE id = $2 AND `+pointScope(3)+` RETURNING `+orderColumns,
+			maxFailures, orderID, r.pointID))
+		if err != nil {
+			return err
+		}
+
+		// The lock is published once, when the limit is reached
+		if order.PickupFailures == maxFailures {
+			return r.insertEvent(ctx, qe, models.OrderLocked, order, "")
+		}
+		return nil
+	})
 
-	qe := r.tm.GetQueryEngine(ctx)
-	rows, err := qe.Query(ctx,
-		"SELECT id, user_id, deadline, is_returned, is_at_pickup_point, issued_to_user, issued_at, received_from_courier, hash, cost, weight FROM orders WHERE user_id = $1 ORDER BY id DESC LIMIT $2",
-		userID, lastN)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var order models.Order
-		err = rows.Scan(&order.OrderID, &order.UserID, &order.Deadline, &order.IsReturned, &order.IsAtPickupPoint,
-			&order.IssuedToUser, &order.IssuedAt, &order.ReceivedFromCourier, &order.Hash, &order.Cost, &order.Weight)
+	return &order, nil
+}
+
+// UnlockPickup unlocks the order and resets its wrong pickup codes
+func (r *Repo) UnlockPickup(orderID int) error {
+	return r.tm.RunRepeatableRead(context.Background(), func(ctx context.Context) error {
+		qe := r.tm.GetQueryEngine(ctx)
+		order, err := scanOrder(qe.QueryRow(ctx,
+			"UPDATE orders SET pickup_failures = 0, pickup_last_failure_at = NULL, pickup_locked_at = NULL WHERE id = $1 AND "+pointScope(2)+
+				" RETURNING "+orderColumns,
+			orderID, r.pointID))
 		if err != nil {
-			return nil, err
+			return err
 		}
-		orders = append(orders, order)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, err
-	}
+		return r.insertEvent(ctx, qe, models.OrderUnlocked, order, "")
+	})
+}
 
-	return orders, nil
+// ListOrders returns a list of the user's most recent orders from the database
+func (r *Repo) ListOrders(userID, lastN int) ([]models.Order, error) {
+	ctx := context.Background()
+	return queryOrders(ctx, r.tm.GetQueryEngine(ctx),
+		"SELECT "+orderColumns+" FROM orders WHERE user_id = $1 AND "+pointScope(3)+" ORDER BY id DESC LIMIT $2",
+		userID, lastN, r.pointID)
 }
 
 // AcceptReturn updates an order in the database, marking it returned
@@ -103,12 +220,13 @@ func (r *Repo) AcceptReturn(order models.Order) error {
 		qe := r.tm.GetQueryEngine(ctx)
 
 		// Prepared statement for better performance
-		sql := "UPDATE orders SET is_returned = true, hash = $1 WHERE id = $2"
-		_, err := qe.Exec(ctx, sql, order.Hash, order.OrderID)
+		sql := "UPDATE orders SET is_returned = true, hash = $1 WHERE id = $2 AND " + pointScope(3) + " AND " + returnableCondition +
+			" RETURNING " + orderColumns
+		returned, err := r.scanGuardedOrder(ctx, qe, qe.QueryRow(ctx, sql, order.Hash, order.OrderID, r.pointID), order.OrderID)
 		if err != nil {
 			return err
 		}
-		return nil
+		return r.insertEvent(ctx, qe, models.ReturnAccepted, returned, "")
 	})
 }
 
@@ -116,90 +234,181 @@ func (r *Repo) AcceptReturn(order models.Order) error {
 func (r *Repo) ListReturns(page, pageSize int) ([]models.Order, error) {
 	var orders []models.Order
 	err := r.tm.RunRepeatableRead(context.Background(), func(ctx context.Context) error {
-		qe := r.tm.GetQueryEngine(ctx)
-		rows, err := qe.Query(ctx,
-			"SELECT id, user_id, deadline, is_returned, is_at_pickup_point, issued_to_user, issued_at, received_from_courier, hash, cost, weight FROM orders WHERE is_returned = true ORDER BY id DESC LIMIT $1 OFFSET $2",
-			pageSize, (page-1)*pageSize)
+		var err error
+		orders, err = queryOrders(ctx, r.tm.GetQueryEngine(ctx),
+			"SELECT "+orderColumns+" FROM orders WHERE is_returned = true AND refused_at IS NULL AND "+pointScope(3)+
+				" ORDER BY id DESC LIMIT $1 OFFSET $2",
+			pageSize, (page-1)*pageSize, r.pointID)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// ExportOrders reads the orders matching the filter in one transaction and passes them to fx one by one,
+// so the export sees a consistent snapshot. An error of fx stops the export
+func (r *Repo) ExportOrders(filter models.ExportFilter, fx func(order models.Order) error) error {
+	var sql string
+	var args []interface{}
+	switch filter.Kind {
+	case models.OrdersExport:
+		sql = "SELECT " + orderColumns + " FROM orders WHERE user_id = COALESCE(NULLIF($1::int, 0), user_id) AND " + pointScope(3) +
+			" ORDER BY id DESC LIMIT NULLIF($2::int, 0)"
+		args = []interface{}{filter.UserID, filter.LastN, r.pointID}
+	case models.ReturnsExport:
+		offset := 0
+		if filter.PageSize > 0 && filter.Page > 1 {
+			offset = (filter.Page - 1) * filter.PageSize
+		}
+		sql = "SELECT " + orderColumns + " FROM orders WHERE is_returned = true AND refused_at IS NULL AND " + pointScope(3) +
+			" ORDER BY id DESC LIMIT NULLIF($1::int, 0) OFFSET $2"
+		args = []interface{}{filter.PageSize, offset, r.pointID}
+	default:
+		return fmt.Errorf("unknown export kind %q", filter.Kind)
+	}
+
+	return r.tm.RunRepeatableRead(context.Background(), func(ctx context.Context) error {
+		rows, err := r.tm.GetQueryEngine(ctx).Query(ctx, sql, args...)
 		if err != nil {
 			return err
 		}
 		defer rows.Close()
 
 		for rows.Next() {
-			var order models.Order
-			err = rows.Scan(&order.OrderID, &order.UserID, &order.Deadline, &order.IsReturned, &order.IsAtPickupPoint, &order.IssuedToUser,
-				&order.IssuedAt, &order.ReceivedFromCourier, &order.Hash, &order.Cost, &order.Weight)
+			order, err := scanOrder(rows)
 			if err != nil {
 				return err
 			}
-			orders = append(orders, order)
+			if err = fx(order); err != nil {
+				return err
+			}
 		}
+		return rows.Err()
+	})
+}
 
-		if err = rows.Err(); err != nil {
-			return err
-		}
+// GetAllOrders returns a list of all orders from the database
+func (r *Repo) GetAllOrders() ([]models.Order, error) {
+	ctx := context.Background()
+	return queryOrders(ctx, r.tm.GetQueryEngine(ctx), "SELECT "+orderColumns+" FROM orders WHERE "+pointScope(1)+" ORDER BY id DESC",
+		r.pointID)
+}
 
-		return nil
+// GetOrderByID returns the order with the given ID from the database
+func (r *Repo) GetOrderByID(orderID int) (*models.Order, error) {
+	var order models.Order
+	err := r.tm.RunRepeatableRead(context.Background(), func(ctx context.Context) error {
+		var err error
+		order, err = scanOrder(r.tm.GetQueryEngine(ctx).QueryRow(ctx, "SELECT "+orderColumns+" FROM orders WHERE id = $1 AND "+pointScope(2),
+			orderID, r.pointID))
+		return err
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	return orders, nil
+	return &order, nil
 }
 
-// GetAllOrders returns a list of all orders from the database
-func (r *Repo) GetAllOrders() ([]models.Order, error) {
-	var orders []models.Order
+// ListStoredOrders returns orders that are still stored at the pickup point,
+// i.e. not issued, not returned and with a deadline in the future
+func (r *Repo) ListStoredOrders() ([]models.Order, error) {
 	ctx := context.Background()
-	qe := r.tm.GetQueryEngine(ctx)
-	rows, err := qe.Query(ctx,
-		"SELECT id, user_id, deadline, is_returned, is_at_pickup_point, issued_to_user, issued_at, received_from_courier, hash, cost, weight FROM orders ORDER BY id DESC")
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+	return queryOrders(ctx, r.tm.GetQueryEngine(ctx),
+		"SELECT "+orderColumns+" FROM orders WHERE issued_to_user = false AND is_returned = false AND refused_at IS NULL AND deadline > NOW() "+
+			"AND "+pointScope(1)+" ORDER BY id",
+		r.pointID)
+}
+
+// ListPresentOrders returns orders that are physically kept at the pickup point: not issued or returned by the client,
+// and neither handed over to a courier nor in transit to another point. Expired and refused orders are kept until handed over
+func (r *Repo) ListPresentOrders() ([]models.Order, error) {
+	ctx := context.Background()
+	return queryOrders(ctx, r.tm.GetQueryEngine(ctx),
+		"SELECT "+orderColumns+" FROM orders WHERE (issued_to_user = false OR is_returned = true) "+
+			"AND returned_to_courier_at IS NULL AND transfer_id IS NULL AND "+pointScope(1)+" ORDER BY id",
+		r.pointID)
+}
+
+// MarkExpired marks orders whose deadline has passed and that were neither issued nor returned as expired.
+// Every expired order gets an OrderExpired event in the same transaction. It returns the marked orders
+func (r *Repo) MarkExpired() ([]models.Order, error) {
+	var orders []models.Order
+	err := r.tm.RunRepeatableRead(context.Background(), func(ctx context.Context) error {
+		qe := r.tm.GetQueryEngine(ctx)
 
-	for rows.Next() {
-		var order models.Order
-		err = rows.Scan(&order.OrderID, &order.UserID, &order.Deadline, &order.IsReturned, &order.IsAtPickupPoint, &order.IssuedToUser,
-			&order.IssuedAt, &order.ReceivedFromCourier, &order.Hash, &order.Cost, &order.Weight)
+		var err error
+		orders, err = queryOrders(ctx, qe,
+			`UPDATE orders SET expired_at = NOW()
+			WHERE deadline <= NOW() AND expired_at IS NULL AND issued_to_user = false AND is_returned = false
+			AND returned_to_courier_at IS NULL AND refused_at IS NULL AND `+pointScope(1)+`
+			RETURNING `+orderColumns,
+			r.pointID)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		orders = append(orders, order)
-	}
 
-	if err = rows.Err(); err != nil {
+		for _, order := range orders {
+			if err = r.insertEvent(ctx, qe, models.OrderExpired, order, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
 		return nil, err
 	}
 
 	return orders, nil
 }
 
-// GetOrderByID returns the order with the given ID from the database
-func (r *Repo) GetOrderByID(orderID int) (*models.Order, error) {
-	var order models.Order
-	err := r.tm.RunRepeatableRead(context.Background(), func(ctx context.Context) error {
+// ListExpiredOrders returns expired and refused orders that are still stored at the pickup point
+// and have to be returned to the courier
+func (r *Repo) ListExpiredOrders() ([]models.Order, error) {
+	ctx := context.Background()
+	return queryOrders(ctx, r.tm.GetQueryEngine(ctx),
+		"SELECT "+orderColumns+" FROM orders WHERE (expired_at IS NOT NULL OR refused_at IS NOT NULL) "+
+			"AND issued_to_user = false AND is_returned = false AND returned_to_courier_at IS NULL AND transfer_id IS NULL AND "+pointScope(1)+" ORDER BY deadline, id",
+		r.pointID)
+}
+
+// RefuseOrder records that the client refused the order at pickup, the order stays not issued
+// and waits to be returned to the courier
+func (r *Repo) RefuseOrder(orderID int, reason string) error {
+	return r.tm.RunRepeatableRead(context.Background(), func(ctx context.Context) error {
 		qe := r.tm.GetQueryEngine(ctx)
-		row := qe.QueryRow(ctx,
-			"SELECT id, user_id, deadline, is_returned, is_at_pickup_point, issued_to_user, issued_at, received_from_courier, hash, cost, weight FROM orders WHERE id = $1",
-			orderID)
-		err := row.Scan(&order.OrderID, &order.UserID, &order.Deadline, &order.IsReturned, &order.IsAtPickupPoint, &order.IssuedToUser,
-			&order.IssuedAt, &order.ReceivedFromCourier, &order.Hash, &order.Cost, &order.Weight)
+		order, err := r.scanGuardedOrder(ctx, qe, qe.QueryRow(ctx,
+			"UPDATE orders SET refused_at = NOW(), refusal_reason = $1 WHERE id = $2 AND "+pointScope(3)+" AND "+issuableCondition+
+				" RETURNING "+orderColumns,
+			reason, orderID, r.pointID), orderID)
 		if err != nil {
-			if errors.Is(err, pgx.ErrNoRows) {
-				return ErrOrderNotFound
-			}
 			return err
 		}
-		return nil
+		return r.insertEvent(ctx, qe, models.OrderRefused, order, "")
 	})
+}
 
-	if err != nil {
-		return nil, err
+// scanGuardedOrder scans the order changed by a guarded update. If no row is changed
+// but the order exists, it no longer satisfies the guard
+func (r *Repo) scanGuardedOrder(ctx context.Context, qe database.DBops, row pgx.Row, orderID int) (models.Order, error) {
+	order, err := scanOrder(row)
+	if !errors.Is(err, ErrOrderNotFound) {
+		return order, err
 	}
 
-	return &order, nil
+	var exists bool
+	if err = qe.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM orders WHERE id = $1 AND "+pointScope(2)+")", orderID, r.pointID).
+		Scan(&exists); err != nil {
+		return order, err
+	}
+	if exists {
+		return order, ErrOrderStateChanged
+	}
+	return order, ErrOrderNotFound
 }
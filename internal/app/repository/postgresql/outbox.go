@@ -0,0 +1,245 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"route/internal/app/events"
+	"route/internal/app/models"
+	"route/internal/app/repository/database"
+)
+
+// orderColumns are the columns scanned by scanOrder
+const orderColumns = "id, user_id, deadline, is_returned, is_at_pickup_point, issued_to_user, issued_at, received_from_courier, hash, cost, weight, expired_at, returned_to_courier_at, session_id, refused_at, refusal_reason, " +
+	"pickup_code_hash, pickup_failures, pickup_last_failure_at, pickup_locked_at, issue_override_by, " +
+	"received_by, authorization_id, pickup_point_id, transfer_id, cell_id, COALESCE((SELECT code FROM storage_cells WHERE storage_cells.id = cell_id), '')"
+
+// scanOrder scans a row selected or returned with orderColumns
+func scanOrder(row pgx.Row) (models.Order, error) {
+	var order models.Order
+	// issued_at is nullable for orders created outside of AcceptOrder
+	var issuedAt, expiredAt, returnedToCourierAt, refusedAt, lastFailureAt, lockedAt *time.Time
+	var sessionID, authorizationID, transferID *int64
+	var cellID *int
+	err := row.Scan(&order.OrderID, &order.UserID, &order.Deadline, &order.IsReturned, &order.IsAtPickupPoint, &order.IssuedToUser,
+		&issuedAt, &order.ReceivedFromCourier, &order.Hash, &order.Cost, &order.Weight, &expiredAt, &returnedToCourierAt, &sessionID,
+		&refusedAt, &order.RefusalReason, &order.PickupCodeHash, &order.PickupFailures, &lastFailureAt, &lockedAt, &order.IssueOverrideBy,
+		&order.ReceivedBy, &authorizationID, &order.PickupPointID, &transferID, &cellID, &order.Cell)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return order, ErrOrderNotFound
+	}
+	if issuedAt != nil {
+		order.IssuedAt = *issuedAt
+	}
+	if expiredAt != nil {
+		order.ExpiredAt = *expiredAt
+	}
+	if returnedToCourierAt != nil {
+		order.ReturnedToCourierAt = *returnedToCourierAt
+	}
+	if sessionID != nil {
+		order.SessionID = *sessionID
+	}
+	if refusedAt != nil {
+		order.RefusedAt = *refusedAt
+	}
+	if lastFailureAt != nil {
+		order.PickupLastFailureAt = *lastFailureAt
+	}
+	if lockedAt != nil {
+		order.PickupLockedAt = *lockedAt
+	}
+	if authorizationID != nil {
+		order.AuthorizationID = *authorizationID
+	}
+	if transferID != nil {
+		order.TransferID = *transferID
+	}
+	if cellID != nil {
+		order.CellID = *cellID
+	}
+	return order, err
+}
+
+// queryOrders runs a query selecting orderColumns and scans all rows
+func queryOrders(ctx context.Context, qe database.DBops, sql string, args ...interface{}) ([]models.Order, error) {
+	rows, err := qe.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []models.Order
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, rows.Err()
+}
+
+// insertEvent writes an event with the order snapshot to the outbox,
+// it must be called within the transaction of the order mutation
+func (r *Repo) insertEvent(ctx context.Context, qe database.DBops, eventType models.EventType, order models.Order, packagingType models.PackageType) error {
+	msg, err := events.New(eventType, order, packagingType, r.actor)
+	if err != nil {
+		return err
+	}
+
+	_, err = qe.Exec(ctx, "INSERT INTO outbox (event_id, event_type, schema_version, order_id, payload) VALUES ($1, $2, $3, $4, $5)",
+		msg.EventID, string(msg.EventType), msg.SchemaVersion, msg.OrderID, msg.Payload)
+	if err != nil {
+		return err
+	}
+	return insertReportEvent(ctx, qe, eventType, order.OrderID)
+}
+
+// defaultClaimTTL is how long the messages of a sink stay claimed by a replica that stopped before releasing them
+const defaultClaimTTL = 5 * time.Minute
+
+type OutboxRepo struct {
+	tm       database.TransactionManager
+	claimTTL time.Duration
+}
+
+func NewOutbox(tm database.TransactionManager) *OutboxRepo {
+	return &OutboxRepo{tm: tm, claimTTL: defaultClaimTTL}
+}
+
+// ProcessPending passes up to limit messages not yet delivered to the sink to fx in order of creation.
+// The messages are claimed in a short transaction and sent after it is committed, so a slow sink
+// doesn't hold a transaction open. A delivery is recorded if fx succeeds. On the first failure its attempt
+// is recorded and the rest of the batch is left for the next call, so per-order ordering is kept.
+// Every sink progresses independently, a sink is processed by one replica at a time, the one holding its claim.
+// A sink seen for the first time starts after the messages already delivered to other sinks.
+// It returns the number of sent messages.
+func (r *OutboxRepo) ProcessPending(sink string, limit int, fx func(msg models.OutboxMessage) error) (int, error) {
+	ctx := context.Background()
+	token := uuid.New().String()
+	messages, err := r.claim(ctx, sink, token, limit)
+	if err != nil || messages == nil {
+		return 0, err
+	}
+
+	sent, err := r.send(ctx, sink, messages, fx)
+	_, releaseErr := r.tm.GetQueryEngine(ctx).Exec(ctx,
+		"UPDATE outbox_sinks SET claim_token = NULL, claimed_until = NULL WHERE sink = $1 AND claim_token = $2",
+		sink, token)
+	if err != nil {
+		return sent, err
+	}
+	return sent, releaseErr
+}
+
+// claim returns the pending messages of the sink claimed with the token,
+// nil if the sink is claimed by another replica or has no pending messages
+func (r *OutboxRepo) claim(ctx context.Context, sink, token string, limit int) ([]models.OutboxMessage, error) {
+	var messages []models.OutboxMessage
+	err := r.tm.RunRepeatableRead(ctx, func(ctx context.Context) error {
+		qe := r.tm.GetQueryEngine(ctx)
+
+		var locked bool
+		err := qe.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock(hashtext('outbox:' || $1))", sink).Scan(&locked)
+		if err != nil || !locked {
+			return err
+		}
+
+		_, err = qe.Exec(ctx,
+			`INSERT INTO outbox_sinks (sink, start_id)
+			SELECT $1, GREATEST(
+				(SELECT COALESCE(MAX(outbox_id), 0) FROM outbox_deliveries WHERE sent_at IS NOT NULL),
+				(SELECT COALESCE(MAX(start_id), 0) FROM outbox_sinks))
+			ON CONFLICT (sink) DO NOTHING`,
+			sink)
+		if err != nil {
+			return err
+		}
+
+		tag, err := qe.Exec(ctx,
+			`UPDATE outbox_sinks SET claim_token = $2, claimed_until = NOW() + $3 * INTERVAL '1 millisecond'
+			WHERE sink = $1 AND (claimed_until IS NULL OR claimed_until < NOW())`,
+			sink, token, r.claimTTL.Milliseconds())
+		if err != nil || tag.RowsAffected() == 0 {
+			return err
+		}
+
+		rows, err := qe.Query(ctx,
+			`SELECT o.id, o.event_id, o.event_type, o.schema_version, o.order_id, o.payload, COALESCE(d.attempts, 0)
+			FROM outbox o
+			JOIN outbox_sinks s ON s.sink = $1
+			LEFT JOIN outbox_deliveries d ON d.outbox_id = o.id AND d.sink = s.sink
+			WHERE o.id > s.start_id AND d.sent_at IS NULL
+			ORDER BY o.id LIMIT $2`,
+			sink, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var msg models.OutboxMessage
+			var eventType string
+			err = rows.Scan(&msg.ID, &msg.EventID, &eventType, &msg.SchemaVersion, &msg.OrderID, &msg.Payload, &msg.Attempts)
+			if err != nil {
+				return err
+			}
+			msg.EventType = models.EventType(eventType)
+			messages = append(messages, msg)
+		}
+
+		return rows.Err()
+	})
+
+	return messages, err
+}
+
+// send passes the claimed messages to fx and records every attempt as soon as it is made
+func (r *OutboxRepo) send(ctx context.Context, sink string, messages []models.OutboxMessage, fx func(msg models.OutboxMessage) error) (int, error) {
+	qe := r.tm.GetQueryEngine(ctx)
+	sent := 0
+	for _, msg := range messages {
+		if sendErr := fx(msg); sendErr != nil {
+			_, err := qe.Exec(ctx,
+				`INSERT INTO outbox_deliveries (sink, outbox_id, attempts, last_error) VALUES ($1, $2, 1, $3)
+				ON CONFLICT (sink, outbox_id) DO UPDATE SET attempts = outbox_deliveries.attempts + 1, last_error = EXCLUDED.last_error`,
+				sink, msg.ID, sendErr.Error())
+			return sent, err
+		}
+
+		_, err := qe.Exec(ctx,
+			`INSERT INTO outbox_deliveries (sink, outbox_id, attempts, sent_at) VALUES ($1, $2, 1, NOW())
+			ON CONFLICT (sink, outbox_id) DO UPDATE SET attempts = outbox_deliveries.attempts + 1, last_error = NULL, sent_at = NOW()`,
+			sink, msg.ID)
+		if err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// PurgeDelivered deletes messages created more than retention ago that are delivered to every sink,
+// messages still pending for a sink are kept whatever their age. It returns the number of deleted messages.
+// Reports don't depend on the outbox, they are built from report_events
+func (r *OutboxRepo) PurgeDelivered(retention time.Duration) (int, error) {
+	ctx := context.Background()
+	tag, err := r.tm.GetQueryEngine(ctx).Exec(ctx,
+		`DELETE FROM outbox o
+		WHERE o.created_at < NOW() - $1 * INTERVAL '1 millisecond'
+		AND NOT EXISTS (
+			SELECT 1 FROM outbox_sinks s
+			WHERE o.id > s.start_id AND NOT EXISTS (
+				SELECT 1 FROM outbox_deliveries d WHERE d.sink = s.sink AND d.outbox_id = o.id AND d.sent_at IS NOT NULL))`,
+		retention.Milliseconds())
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,75 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"route/internal/app/models"
+	"route/internal/app/repository/database"
+)
+
+var ErrPickupPointNotFound = errors.New("pickup point not found")
+
+type PickupPointRepo struct {
+	tm database.TransactionManager
+}
+
+func NewPickupPoint(tm database.TransactionManager) *PickupPointRepo {
+	return &PickupPointRepo{tm: tm}
+}
+
+// CreatePickupPoint saves the pickup point
+func (r *PickupPointRepo) CreatePickupPoint(point models.PickupPoint) (*models.PickupPoint, error) {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	err := qe.QueryRow(ctx, "INSERT INTO pickup_points (name, address) VALUES ($1, $2) RETURNING id, created_at",
+		point.Name, point.Address).Scan(&point.ID, &point.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &point, nil
+}
+
+// GetPickupPoint returns the pickup point with the given ID
+func (r *PickupPointRepo) GetPickupPoint(id int) (*models.PickupPoint, error) {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	point := models.PickupPoint{ID: id}
+	err := qe.QueryRow(ctx, "SELECT name, address, created_at FROM pickup_points WHERE id = $1", id).
+		Scan(&point.Name, &point.Address, &point.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPickupPointNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &point, nil
+}
+
+// ListPickupPoints returns all pickup points of the network
+func (r *PickupPointRepo) ListPickupPoints() ([]models.PickupPoint, error) {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	rows, err := qe.Query(ctx, "SELECT id, name, address, created_at FROM pickup_points ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []models.PickupPoint
+	for rows.Next() {
+		var point models.PickupPoint
+		if err = rows.Scan(&point.ID, &point.Name, &point.Address, &point.CreatedAt); err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+
+	return points, rows.Err()
+}
@@ -0,0 +1,94 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"route/internal/app/models"
+	"route/internal/app/repository/database"
+)
+
+type ReportRepo struct {
+	tm database.TransactionManager
+}
+
+func NewReport(tm database.TransactionManager) *ReportRepo {
+	return &ReportRepo{tm: tm}
+}
+
+// reportDayLayout formats days of the report history, days are dates in the time zone of the service
+const reportDayLayout = "2006-01-02"
+
+// reportEvents are the events kept in the report history
+var reportEvents = map[models.EventType]bool{
+	models.OrderAccepted:  true,
+	models.OrderIssued:    true,
+	models.ReturnAccepted: true,
+}
+
+// insertReportEvent records the event of the order in the report history with the point, packaging and amounts
+// the order has now, it must be called within the transaction of the order mutation after the order is changed
+func insertReportEvent(ctx context.Context, qe database.DBops, eventType models.EventType, orderID int) error {
+	if !reportEvents[eventType] {
+		return nil
+	}
+
+	types, surcharges := surchargeArrays()
+	_, err := qe.Exec(ctx,
+		`INSERT INTO report_events (event_type, order_id, pickup_point_id, packaging_type, cost, surcharge, day)
+		SELECT $1, o.id, o.pickup_point_id, COALESCE(p.type, ''), o.cost, COALESCE(s.surcharge, 0), $3::date
+		FROM orders o
+		LEFT JOIN packaging_types p ON p.id = o.packaging_type_id
+		LEFT JOIN unnest($4::text[], $5::float8[]) AS s(type, surcharge) ON s.type = p.type
+		WHERE o.id = $2`,
+		string(eventType), orderID, time.Now().Format(reportDayLayout), types, surcharges)
+	return err
+}
+
+func surchargeArrays() ([]string, []float64) {
+	types := make([]string, 0, len(models.Surcharges))
+	surcharges := make([]float64, 0, len(models.Surcharges))
+	for packagingType, surcharge := range models.Surcharges {
+		types = append(types, string(packagingType))
+		surcharges = append(surcharges, surcharge)
+	}
+	return types, surcharges
+}
+
+// GetReport aggregates the report history from the day of from inclusive to the day of to exclusive
+// by day and packaging type. Orders are counted at the pickup point they were at when the event happened,
+// zero pointID means all points. Days are taken in the time zone of from and to, the history uses the same one
+func (r *ReportRepo) GetReport(pointID int, from, to time.Time) ([]models.ReportRow, error) {
+	ctx := context.Background()
+	rows, err := r.tm.GetQueryEngine(ctx).Query(ctx,
+		`SELECT day, packaging_type,
+			COUNT(*) FILTER (WHERE event_type = $1),
+			COUNT(*) FILTER (WHERE event_type = $2),
+			COUNT(*) FILTER (WHERE event_type = $3),
+			COALESCE(SUM(surcharge) FILTER (WHERE event_type = $2), 0),
+			COALESCE(SUM(cost) FILTER (WHERE event_type = $3), 0)
+		FROM report_events
+		WHERE day >= $4::date AND day < $5::date AND `+pointScope(6)+`
+		GROUP BY day, packaging_type
+		ORDER BY day, packaging_type`,
+		string(models.OrderAccepted), string(models.OrderIssued), string(models.ReturnAccepted),
+		from.Format(reportDayLayout), to.Format(reportDayLayout), pointID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var report []models.ReportRow
+	for rows.Next() {
+		var row models.ReportRow
+		var packagingType string
+		err = rows.Scan(&row.Day, &packagingType, &row.Accepted, &row.Issued, &row.Returned, &row.SurchargeRevenue, &row.Refunds)
+		if err != nil {
+			return nil, err
+		}
+		row.PackagingType = models.PackageType(packagingType)
+		report = append(report, row)
+	}
+
+	return report, rows.Err()
+}
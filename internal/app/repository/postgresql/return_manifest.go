@@ -0,0 +1,71 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"route/internal/app/models"
+)
+
+var ErrNoOrdersToReturn = errors.New("no orders to return")
+
+// CreateReturnManifest hands over to the courier expired or refused orders that were neither issued nor returned.
+// Orders brought in acceptance sessions of couriers of other companies stay at the point, orders accepted
+// without a session (accept-order, import, manifests) have no company and are handed over to any courier.
+// The manifest, its items and the handover of the orders are recorded in one transaction,
+// so an order can't get into two manifests. It returns ErrNoOrdersToReturn if there is nothing to hand over
+func (r *Repo) CreateReturnManifest(courierID int) (*models.ReturnManifest, error) {
+	manifest := models.ReturnManifest{CourierID: courierID}
+	err := r.tm.RunRepeatableRead(context.Background(), func(ctx context.Context) error {
+		qe := r.tm.GetQueryEngine(ctx)
+
+		err := qe.QueryRow(ctx, "INSERT INTO return_manifests (courier_id) VALUES ($1) RETURNING id, created_at",
+			courierID).Scan(&manifest.ID, &manifest.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		manifest.Orders, err = queryOrders(ctx, qe,
+			`UPDATE orders SET returned_to_courier_at = NOW(), cell_id = NULL
+			WHERE (deadline <= NOW() OR refused_at IS NOT NULL) AND issued_to_user = false AND is_returned = false AND returned_to_courier_at IS NULL
+			AND transfer_id IS NULL AND `+pointScope(1)+`
+			AND (session_id IS NULL OR session_id IN (
+				SELECT s.id FROM acceptance_sessions s JOIN couriers c ON c.id = s.courier_id
+				WHERE c.company = (SELECT company FROM couriers WHERE id = $2)))
+			RETURNING `+orderColumns,
+			r.pointID, courierID)
+		if err != nil {
+			return err
+		}
+		if len(manifest.Orders) == 0 {
+			return ErrNoOrdersToReturn
+		}
+		sort.Slice(manifest.Orders, func(i, j int) bool {
+			return manifest.Orders[i].OrderID < manifest.Orders[j].OrderID
+		})
+
+		orderIDs := make([]int, 0, len(manifest.Orders))
+		for _, order := range manifest.Orders {
+			orderIDs = append(orderIDs, order.OrderID)
+		}
+		_, err = qe.Exec(ctx, "INSERT INTO return_manifest_items (manifest_id, order_id) SELECT $1, unnest($2::int[])",
+			manifest.ID, orderIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, order := range manifest.Orders {
+			if err = r.insertEvent(ctx, qe, models.OrderReturnedToCourier, order, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &manifest, nil
+}
@@ -0,0 +1,72 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"route/internal/app/repository/database"
+)
+
+type SchedulerRepo struct {
+	tm database.TransactionManager
+}
+
+func NewScheduler(tm database.TransactionManager) *SchedulerRepo {
+	return &SchedulerRepo{tm: tm}
+}
+
+// RunLocked runs fx if no other replica holds the lock of the job. fx gets the time of the last
+// recorded run and reports whether it has run the job, its run time or error is recorded then.
+// The lock is held until the transaction ends, so it is released even if the replica dies.
+// The run time is taken from the clock of the service, the one the scheduler compares last runs with.
+// The last run is read under read committed after the lock is taken, so it includes the run of the replica
+// that released the lock just before. It returns false if the lock is held by another replica.
+func (r *SchedulerRepo) RunLocked(job string, fx func(lastRun time.Time) (bool, error)) (bool, error) {
+	locked := false
+	var runErr error
+	err := r.tm.RunReadCommitted(context.Background(), func(ctx context.Context) error {
+		qe := r.tm.GetQueryEngine(ctx)
+
+		err := qe.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock(hashtext('job:' || $1))", job).Scan(&locked)
+		if err != nil || !locked {
+			return err
+		}
+
+		var lastRun *time.Time
+		err = qe.QueryRow(ctx, "SELECT MAX(last_run_at) FROM scheduler_jobs WHERE name = $1", job).Scan(&lastRun)
+		if err != nil {
+			return err
+		}
+
+		var last time.Time
+		if lastRun != nil {
+			last = *lastRun
+		}
+
+		startedAt := time.Now()
+		var ran bool
+		ran, runErr = fx(last)
+		if runErr != nil {
+			_, err = qe.Exec(ctx,
+				`INSERT INTO scheduler_jobs (name, last_error) VALUES ($1, $2)
+				ON CONFLICT (name) DO UPDATE SET last_error = EXCLUDED.last_error, updated_at = NOW()`,
+				job, runErr.Error())
+			return err
+		}
+		if !ran {
+			return nil
+		}
+
+		_, err = qe.Exec(ctx,
+			`INSERT INTO scheduler_jobs (name, last_run_at) VALUES ($1, $2)
+			ON CONFLICT (name) DO UPDATE SET last_run_at = EXCLUDED.last_run_at, last_error = '', updated_at = NOW()`,
+			job, startedAt)
+		return err
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return locked, runErr
+}
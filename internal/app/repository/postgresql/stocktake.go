@@ -0,0 +1,44 @@
+package postgresql
+
+import (
+	"context"
+
+	"route/internal/app/models"
+	"route/internal/app/repository/database"
+)
+
+type StocktakeRepo struct {
+	tm database.TransactionManager
+}
+
+func NewStocktake(tm database.TransactionManager) *StocktakeRepo {
+	return &StocktakeRepo{tm: tm}
+}
+
+// SaveStocktake saves the stocktake report with its discrepancies
+func (r *StocktakeRepo) SaveStocktake(stocktake models.Stocktake) (*models.Stocktake, error) {
+	err := r.tm.RunRepeatableRead(context.Background(), func(ctx context.Context) error {
+		qe := r.tm.GetQueryEngine(ctx)
+
+		err := qe.QueryRow(ctx, "INSERT INTO stocktakes (pickup_point_id, scanned, stored) VALUES ($1, $2, $3) RETURNING id, created_at",
+			stocktake.PickupPointID, stocktake.Scanned, stocktake.Stored).Scan(&stocktake.ID, &stocktake.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		for _, d := range stocktake.Discrepancies {
+			_, err = qe.Exec(ctx, "INSERT INTO stocktake_items (stocktake_id, order_id, kind) VALUES ($1, $2, $3)",
+				stocktake.ID, d.OrderID, string(d.Kind))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &stocktake, nil
+}
@@ -0,0 +1,130 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"route/internal/app/models"
+	"route/internal/app/repository/database"
+)
+
+var ErrNoFreeCell = errors.New("no free storage cell")
+
+// cellAttempts is how many times a transaction placing orders to cells is run
+// if the cells it picked were taken by concurrent transactions
+const cellAttempts = 5
+
+// cellIndex is the unique index that keeps one order in a cell
+const cellIndex = "orders_cell_id_idx"
+
+type StorageRepo struct {
+	tm database.TransactionManager
+}
+
+func NewStorage(tm database.TransactionManager) *StorageRepo {
+	return &StorageRepo{tm: tm}
+}
+
+// CreateRack saves the rack with its cells
+func (r *StorageRepo) CreateRack(rack models.Rack) (*models.Rack, error) {
+	err := r.tm.RunRepeatableRead(context.Background(), func(ctx context.Context) error {
+		qe := r.tm.GetQueryEngine(ctx)
+
+		err := qe.QueryRow(ctx, "INSERT INTO storage_racks (pickup_point_id, name) VALUES ($1, $2) RETURNING id, created_at",
+			rack.PickupPointID, rack.Name).Scan(&rack.ID, &rack.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		for i := range rack.Cells {
+			rack.Cells[i].RackID = rack.ID
+			err = qe.QueryRow(ctx, "INSERT INTO storage_cells (rack_id, code, size, max_weight) VALUES ($1, $2, $3, $4) RETURNING id",
+				rack.ID, rack.Cells[i].Code, rack.Cells[i].Size, rack.Cells[i].MaxWeight).Scan(&rack.Cells[i].ID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &rack, nil
+}
+
+// ListCells returns the cells of the pickup point with the orders stored in them
+func (r *StorageRepo) ListCells(pointID int) ([]models.StorageCell, error) {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	rows, err := qe.Query(ctx,
+		`SELECT c.id, c.rack_id, c.code, c.size, c.max_weight, COALESCE(o.id, 0)
+		FROM storage_cells c JOIN storage_racks r ON r.id = c.rack_id LEFT JOIN orders o ON o.cell_id = c.id
+		WHERE r.pickup_point_id = $1 ORDER BY r.name, c.code`, pointID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cells []models.StorageCell
+	for rows.Next() {
+		var cell models.StorageCell
+		if err = rows.Scan(&cell.ID, &cell.RackID, &cell.Code, &cell.Size, &cell.MaxWeight, &cell.OrderID); err != nil {
+			return nil, err
+		}
+		cells = append(cells, cell)
+	}
+
+	return cells, rows.Err()
+}
+
+// runPlacingOrders runs fx placing orders to cells in a repeatable read transaction. allocateCell sees orders
+// of the transaction's snapshot only, so a cell taken by a transaction committed after the snapshot looks free
+// and storing an order in it violates cellIndex. The transaction is run again then with a new snapshot
+func runPlacingOrders(tm database.TransactionManager, fx func(ctx context.Context) error) error {
+	var err error
+	for attempt := 0; attempt < cellAttempts; attempt++ {
+		err = tm.RunRepeatableRead(context.Background(), fx)
+		var pgErr *pgconn.PgError
+		if !errors.As(err, &pgErr) || pgErr.ConstraintName != cellIndex {
+			return err
+		}
+	}
+	return err
+}
+
+// allocateCell picks the smallest free cell of the point that fits the order and locks it until the order is stored.
+// It must be called within runPlacingOrders. A point without cells stores orders anywhere, the cell ID is zero then. It returns ErrNoFreeCell if the point
+// has cells but none of them fits
+func allocateCell(ctx context.Context, qe database.DBops, pointID int, packagingType models.PackageType, weight float64) (int, string, error) {
+	var cellID int
+	var code string
+	err := qe.QueryRow(ctx,
+		`SELECT c.id, c.code FROM storage_cells c JOIN storage_racks r ON r.id = c.rack_id
+		WHERE r.pickup_point_id = $1 AND c.size >= $2 AND c.max_weight >= $3
+		AND NOT EXISTS (SELECT 1 FROM orders o WHERE o.cell_id = c.id)
+		ORDER BY c.size, c.max_weight, r.name, c.code LIMIT 1 FOR UPDATE OF c SKIP LOCKED`,
+		pointID, models.CellSizeFor(packagingType), weight).Scan(&cellID, &code)
+	if err == nil {
+		return cellID, code, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, "", err
+	}
+
+	var hasCells bool
+	err = qe.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM storage_cells c JOIN storage_racks r ON r.id = c.rack_id WHERE r.pickup_point_id = $1)",
+		pointID).Scan(&hasCells)
+	if err != nil {
+		return 0, "", err
+	}
+	if hasCells {
+		return 0, "", ErrNoFreeCell
+	}
+	return 0, "", nil
+}
@@ -0,0 +1,214 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"route/internal/app/models"
+	"route/internal/app/repository/database"
+)
+
+var (
+	ErrTransferNotFound      = errors.New("transfer not found")
+	ErrTransferReceived      = errors.New("transfer is already received")
+	ErrOrdersNotTransferable = errors.New("orders can't be transferred")
+)
+
+// CreateTransfer sends the orders of the repo's pickup point to another point. The orders are marked in transit
+// and get an OrderInTransit event in one transaction. It returns ErrOrdersNotTransferable if any of the orders
+// is not stored at the point, e.g. it was issued or is already in transit
+func (r *Repo) CreateTransfer(toPointID int, orderIDs []int) (*models.Transfer, error) {
+	transfer := models.Transfer{FromPointID: r.pointID, ToPointID: toPointID}
+	err := r.tm.RunRepeatableRead(context.Background(), func(ctx context.Context) error {
+		qe := r.tm.GetQueryEngine(ctx)
+
+		err := qe.QueryRow(ctx, "INSERT INTO transfers (from_point_id, to_point_id) VALUES ($1, $2) RETURNING id, created_at",
+			r.pointID, toPointID).Scan(&transfer.ID, &transfer.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		transfer.Orders, err = queryOrders(ctx, qe,
+			`UPDATE orders SET transfer_id = $1, cell_id = NULL
+			WHERE id = ANY($2::int[]) AND pickup_point_id = $3 AND transfer_id IS NULL
+			AND issued_to_user = false AND is_returned = false AND returned_to_courier_at IS NULL
+			RETURNING `+orderColumns,
+			transfer.ID, orderIDs, r.pointID)
+		if err != nil {
+			return err
+		}
+		if len(transfer.Orders) != len(orderIDs) {
+			return ErrOrdersNotTransferable
+		}
+		sortOrders(transfer.Orders)
+
+		_, err = qe.Exec(ctx, "INSERT INTO transfer_items (transfer_id, order_id) SELECT $1, unnest($2::int[])",
+			transfer.ID, orderIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, order := range transfer.Orders {
+			if err = r.insertEvent(ctx, qe, models.OrderInTransit, order, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+// GetTransfer returns the transfer from or to the repo's pickup point with its orders
+func (r *Repo) GetTransfer(transferID int64) (*models.Transfer, error) {
+	var transfer *models.Transfer
+	err := r.tm.RunRepeatableRead(context.Background(), func(ctx context.Context) error {
+		qe := r.tm.GetQueryEngine(ctx)
+
+		var err error
+		transfer, err = r.getTransfer(ctx, qe, transferID, "")
+		if err != nil {
+			return err
+		}
+
+		transfer.Orders, err = queryOrders(ctx, qe,
+			"SELECT "+orderColumns+" FROM orders WHERE id IN (SELECT order_id FROM transfer_items WHERE transfer_id = $1) ORDER BY id",
+			transferID)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// ReceiveTransfer receives the orders of the transfer at the repo's pickup point.
+// The orders move to the point, get its storage cells and an OrderTransferred event in one transaction.
+// It returns ErrNoFreeCell if the point has no free cell for some order
+func (r *Repo) ReceiveTransfer(transferID int64) (*models.Transfer, error) {
+	var transfer *models.Transfer
+	err := runPlacingOrders(r.tm, func(ctx context.Context) error {
+		qe := r.tm.GetQueryEngine(ctx)
+
+		var err error
+		transfer, err = r.getTransfer(ctx, qe, transferID, "FOR UPDATE")
+		if err != nil {
+			return err
+		}
+		if r.pointID != 0 && transfer.ToPointID != r.pointID {
+			return ErrTransferNotFound
+		}
+		if transfer.Received() {
+			return ErrTransferReceived
+		}
+
+		err = qe.QueryRow(ctx, "UPDATE transfers SET received_at = NOW() WHERE id = $1 RETURNING received_at",
+			transferID).Scan(&transfer.ReceivedAt)
+		if err != nil {
+			return err
+		}
+
+		if err = placeTransferredOrders(ctx, qe, transfer); err != nil {
+			return err
+		}
+
+		transfer.Orders, err = queryOrders(ctx, qe,
+			"UPDATE orders SET pickup_point_id = $1, transfer_id = NULL WHERE transfer_id = $2 RETURNING "+orderColumns,
+			transfer.ToPointID, transferID)
+		if err != nil {
+			return err
+		}
+		sortOrders(transfer.Orders)
+
+		for _, order := range transfer.Orders {
+			if err = r.insertEvent(ctx, qe, models.OrderTransferred, order, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// getTransfer returns the transfer without orders, lock is an optional locking clause.
+// A transfer not from or to the repo's pickup point is not found
+func (r *Repo) getTransfer(ctx context.Context, qe database.DBops, transferID int64, lock string) (*models.Transfer, error) {
+	transfer := models.Transfer{ID: transferID}
+	var receivedAt *time.Time
+	err := qe.QueryRow(ctx,
+		"SELECT from_point_id, to_point_id, created_at, received_at FROM transfers WHERE id = $1 "+
+			"AND COALESCE(NULLIF($2::int, 0), from_point_id) IN (from_point_id, to_point_id) "+lock,
+		transferID, r.pointID).Scan(&transfer.FromPointID, &transfer.ToPointID, &transfer.CreatedAt, &receivedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTransferNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if receivedAt != nil {
+		transfer.ReceivedAt = *receivedAt
+	}
+	return &transfer, nil
+}
+
+// placeTransferredOrders places the orders of the transfer to free storage cells of the destination point
+func placeTransferredOrders(ctx context.Context, qe database.DBops, transfer *models.Transfer) error {
+	rows, err := qe.Query(ctx,
+		`SELECT o.id, o.weight, COALESCE(p.type, '') FROM orders o LEFT JOIN packaging_types p ON p.id = o.packaging_type_id
+		WHERE o.transfer_id = $1 ORDER BY o.id`, transfer.ID)
+	if err != nil {
+		return err
+	}
+
+	type item struct {
+		orderID       int
+		weight        float64
+		packagingType string
+	}
+	var items []item
+	for rows.Next() {
+		var it item
+		if err = rows.Scan(&it.orderID, &it.weight, &it.packagingType); err != nil {
+			rows.Close()
+			return err
+		}
+		items = append(items, it)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, it := range items {
+		cellID, _, err := allocateCell(ctx, qe, transfer.ToPointID, models.PackageType(it.packagingType), it.weight)
+		if err != nil {
+			return err
+		}
+		if _, err = qe.Exec(ctx, "UPDATE orders SET cell_id = NULLIF($1, 0) WHERE id = $2", cellID, it.orderID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortOrders sorts orders by ID, UPDATE ... RETURNING returns them in no particular order
+func sortOrders(orders []models.Order) {
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].OrderID < orders[j].OrderID
+	})
+}
@@ -0,0 +1,249 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"route/internal/app/models"
+	"route/internal/app/repository/database"
+)
+
+var ErrWebhookNotFound = errors.New("webhook subscription not found")
+
+const (
+	deliveryPending   = "pending"
+	deliveryDelivered = "delivered"
+	deliveryFailed    = "failed"
+)
+
+type WebhookRepo struct {
+	tm database.TransactionManager
+	// claimTTL is how long deliveries stay claimed by a replica that stopped before recording them
+	claimTTL time.Duration
+}
+
+func NewWebhook(tm database.TransactionManager) *WebhookRepo {
+	return &WebhookRepo{tm: tm, claimTTL: defaultClaimTTL}
+}
+
+// CreateSubscription saves the subscription and returns its ID
+func (r *WebhookRepo) CreateSubscription(sub models.WebhookSubscription) (int64, error) {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	eventTypes := make([]string, len(sub.EventTypes))
+	for i, eventType := range sub.EventTypes {
+		eventTypes[i] = string(eventType)
+	}
+
+	var id int64
+	err := qe.QueryRow(ctx, "INSERT INTO webhook_subscriptions (url, event_types, secret) VALUES ($1, $2, $3) RETURNING id",
+		sub.URL, eventTypes, sub.Secret).Scan(&id)
+	return id, err
+}
+
+// ListSubscriptions returns all subscriptions in order of creation
+func (r *WebhookRepo) ListSubscriptions() ([]models.WebhookSubscription, error) {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	rows, err := qe.Query(ctx, "SELECT id, url, event_types, secret, created_at FROM webhook_subscriptions ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []models.WebhookSubscription
+	for rows.Next() {
+		var sub models.WebhookSubscription
+		var eventTypes []string
+		if err = rows.Scan(&sub.ID, &sub.URL, &eventTypes, &sub.Secret, &sub.CreatedAt); err != nil {
+			return nil, err
+		}
+		for _, eventType := range eventTypes {
+			sub.EventTypes = append(sub.EventTypes, models.EventType(eventType))
+		}
+		subs = append(subs, sub)
+	}
+
+	return subs, rows.Err()
+}
+
+// DeleteSubscription deletes the subscription with its deliveries and their logs
+func (r *WebhookRepo) DeleteSubscription(id int64) error {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	tag, err := qe.Exec(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// EnqueueDeliveries queues the event for every subscription to its type.
+// An event is queued once per subscription, so a redelivered event is ignored.
+func (r *WebhookRepo) EnqueueDeliveries(msg models.OutboxMessage, body []byte) (int, error) {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	tag, err := qe.Exec(ctx,
+		`INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, body)
+		SELECT id, $1, $2, $3 FROM webhook_subscriptions WHERE $2 = ANY (event_types)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+		msg.EventID, string(msg.EventType), body)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// ProcessDueDeliveries claims up to limit pending deliveries whose attempt is due and passes them to fx.
+// The deliveries are claimed in a short transaction and sent after it is committed, so a slow receiver
+// doesn't hold a transaction open. Every attempt is logged in its own transaction, the delivery is completed,
+// given up or rescheduled by its result. Claimed deliveries are skipped by other replicas until the claim expires.
+// It returns the number of attempts.
+func (r *WebhookRepo) ProcessDueDeliveries(limit int, fx func(delivery models.WebhookDelivery) models.WebhookAttempt) (int, error) {
+	ctx := context.Background()
+	token := uuid.New().String()
+	claimedAt := time.Now()
+	deliveries, err := r.claimDeliveries(ctx, token, limit)
+	if err != nil || deliveries == nil {
+		return 0, err
+	}
+
+	processed := 0
+	for _, delivery := range deliveries {
+		// Deliveries whose claim has expired may be sent by another replica already
+		if time.Since(claimedAt) >= r.claimTTL {
+			break
+		}
+		attempt := fx(delivery)
+		if err = r.recordAttempt(ctx, token, delivery, attempt); err != nil {
+			break
+		}
+		processed++
+	}
+
+	// Deliveries left after a failure or an expired claim are due again at once
+	_, releaseErr := r.tm.GetQueryEngine(ctx).Exec(ctx,
+		"UPDATE webhook_deliveries SET claim_token = NULL, claimed_until = NULL WHERE claim_token = $1", token)
+	if err != nil {
+		return processed, err
+	}
+	return processed, releaseErr
+}
+
+// claimDeliveries returns due deliveries claimed with the token, nil if there are none
+func (r *WebhookRepo) claimDeliveries(ctx context.Context, token string, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := r.tm.GetQueryEngine(ctx).Query(ctx,
+		`WITH claimed AS (
+			UPDATE webhook_deliveries SET claim_token = $1, claimed_until = NOW() + $2 * INTERVAL '1 millisecond'
+			WHERE id IN (
+				SELECT id FROM webhook_deliveries
+				WHERE status = $3 AND next_attempt_at <= NOW() AND (claimed_until IS NULL OR claimed_until < NOW())
+				ORDER BY id LIMIT $4 FOR UPDATE SKIP LOCKED)
+			RETURNING id, subscription_id, event_id, event_type, body, attempts)
+		SELECT c.id, c.subscription_id, s.url, s.secret, c.event_id, c.event_type, c.body, c.attempts
+		FROM claimed c JOIN webhook_subscriptions s ON s.id = c.subscription_id
+		ORDER BY c.id`,
+		token, r.claimTTL.Milliseconds(), deliveryPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		var eventType string
+		err = rows.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.URL, &delivery.Secret, &delivery.EventID,
+			&eventType, &delivery.Body, &delivery.Attempts)
+		if err != nil {
+			return nil, err
+		}
+		delivery.EventType = models.EventType(eventType)
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// recordAttempt logs the attempt and updates the delivery by its result. The attempt is not recorded
+// if the claim has expired and the delivery was claimed by another replica
+func (r *WebhookRepo) recordAttempt(ctx context.Context, token string, delivery models.WebhookDelivery, attempt models.WebhookAttempt) error {
+	return r.tm.RunRepeatableRead(ctx, func(ctx context.Context) error {
+		qe := r.tm.GetQueryEngine(ctx)
+
+		const release = ", claim_token = NULL, claimed_until = NULL"
+		var tag pgconn.CommandTag
+		var err error
+		switch {
+		case attempt.Delivered:
+			tag, err = qe.Exec(ctx,
+				"UPDATE webhook_deliveries SET status = $1, attempts = attempts + 1, last_error = NULL, delivered_at = NOW()"+release+
+					" WHERE id = $2 AND claim_token = $3",
+				deliveryDelivered, delivery.ID, token)
+		case attempt.GiveUp:
+			tag, err = qe.Exec(ctx,
+				"UPDATE webhook_deliveries SET status = $1, attempts = attempts + 1, last_error = $2"+release+
+					" WHERE id = $3 AND claim_token = $4",
+				deliveryFailed, attempt.Error, delivery.ID, token)
+		default:
+			tag, err = qe.Exec(ctx,
+				"UPDATE webhook_deliveries SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2"+release+
+					" WHERE id = $3 AND claim_token = $4",
+				attempt.Error, attempt.NextAttemptAt, delivery.ID, token)
+		}
+		if err != nil || tag.RowsAffected() == 0 {
+			return err
+		}
+
+		_, err = qe.Exec(ctx,
+			"INSERT INTO webhook_delivery_logs (delivery_id, attempt, status_code, error, duration_ms) VALUES ($1, $2, $3, $4, $5)",
+			delivery.ID, delivery.Attempts+1, attempt.StatusCode, attempt.Error, attempt.Duration.Milliseconds())
+		return err
+	})
+}
+
+// ListDeliveryLogs returns up to limit latest delivery attempts of the subscription
+func (r *WebhookRepo) ListDeliveryLogs(subscriptionID int64, limit int) ([]models.WebhookDeliveryLog, error) {
+	ctx := context.Background()
+	qe := r.tm.GetQueryEngine(ctx)
+
+	rows, err := qe.Query(ctx,
+		`SELECT l.delivery_id, d.subscription_id, d.event_id, d.event_type, l.attempt, l.status_code, l.error, l.duration_ms, l.created_at
+		FROM webhook_delivery_logs l JOIN webhook_deliveries d ON d.id = l.delivery_id
+		WHERE d.subscription_id = $1
+		ORDER BY l.id DESC LIMIT $2`,
+		subscriptionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []models.WebhookDeliveryLog
+	for rows.Next() {
+		var entry models.WebhookDeliveryLog
+		var eventType string
+		var durationMs int64
+		err = rows.Scan(&entry.DeliveryID, &entry.SubscriptionID, &entry.EventID, &eventType, &entry.Attempt,
+			&entry.StatusCode, &entry.Error, &durationMs, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		entry.EventType = models.EventType(eventType)
+		entry.Duration = time.Duration(durationMs) * time.Millisecond
+		logs = append(logs, entry)
+	}
+
+	return logs, rows.Err()
+}
@@ -3,17 +3,98 @@
 package repository
 
 import (
+	"time"
+
 	"route/internal/app/models"
 )
 
 type Repository interface {
 	AcceptOrder(order *models.Order, packagingType *models.PackagingType) error
+	AcceptOrders(orders []*models.Order, packagingTypes []*models.PackagingType) error
 	ReturnOrder(orderID int) error
-	IssueOrder(orderID int, hash string) error
+	IssueOrder(orderID int, hash string, record models.IssueRecord) error
+	RecordPickupFailure(orderID int, maxFailures int) (*models.Order, error)
+	UnlockPickup(orderID int) error
+	RefuseOrder(orderID int, reason string) error
 	ListOrders(userID, lastN int) ([]models.Order, error)
 	AcceptReturn(order models.Order) error
 	ListReturns(page, pageSize int) ([]models.Order, error)
+	// ExportOrders calls fx for every order matching the filter without loading them all into memory
+	ExportOrders(filter models.ExportFilter, fx func(order models.Order) error) error
 
 	GetAllOrders() ([]models.Order, error)
 	GetOrderByID(orderID int) (*models.Order, error)
+	ListStoredOrders() ([]models.Order, error)
+	ListPresentOrders() ([]models.Order, error)
+	MarkExpired() ([]models.Order, error)
+	ListExpiredOrders() ([]models.Order, error)
+	CreateReturnManifest(courierID int) (*models.ReturnManifest, error)
+	CreateTransfer(toPointID int, orderIDs []int) (*models.Transfer, error)
+	GetTransfer(transferID int64) (*models.Transfer, error)
+	ReceiveTransfer(transferID int64) (*models.Transfer, error)
+
+	// ForPoint returns the repository limited to orders of the pickup point, zero means all points
+	ForPoint(pointID int) Repository
+}
+
+type PickupPointRepository interface {
+	CreatePickupPoint(point models.PickupPoint) (*models.PickupPoint, error)
+	GetPickupPoint(id int) (*models.PickupPoint, error)
+	ListPickupPoints() ([]models.PickupPoint, error)
+}
+
+type StorageRepository interface {
+	CreateRack(rack models.Rack) (*models.Rack, error)
+	ListCells(pointID int) ([]models.StorageCell, error)
+}
+
+type StocktakeRepository interface {
+	SaveStocktake(stocktake models.Stocktake) (*models.Stocktake, error)
+}
+
+type ReportRepository interface {
+	GetReport(pointID int, from, to time.Time) ([]models.ReportRow, error)
+}
+
+type CourierRepository interface {
+	CreateCourier(courier models.Courier) (int, error)
+	GetCourier(id int) (*models.Courier, error)
+	OpenSession(courierID, expectedCount int) (*models.AcceptanceSession, error)
+	GetSession(id int64) (*models.AcceptanceSession, error)
+	RecordRejection(sessionID int64, rejection models.SessionRejection) error
+	CloseSession(id int64) (*models.SessionSummary, error)
+}
+
+type AuthorizationRepository interface {
+	CreateAuthorization(auth models.Authorization) (*models.Authorization, error)
+	ListAuthorizations(userID int) ([]models.Authorization, error)
+}
+
+type OutboxRepository interface {
+	ProcessPending(sink string, limit int, fx func(msg models.OutboxMessage) error) (int, error)
+}
+
+type ManifestRepository interface {
+	GetManifestItem(manifestID string, orderID int) (*models.ManifestItem, error)
+	SaveManifestItem(item models.ManifestItem) error
+}
+
+type WebhookRepository interface {
+	CreateSubscription(sub models.WebhookSubscription) (int64, error)
+	ListSubscriptions() ([]models.WebhookSubscription, error)
+	DeleteSubscription(id int64) error
+	EnqueueDeliveries(msg models.OutboxMessage, body []byte) (int, error)
+	ProcessDueDeliveries(limit int, fx func(delivery models.WebhookDelivery) models.WebhookAttempt) (int, error)
+	ListDeliveryLogs(subscriptionID int64, limit int) ([]models.WebhookDeliveryLog, error)
+}
+
+type NotificationRepository interface {
+	GetContact(userID int) (*models.Contact, error)
+	SaveContact(contact models.Contact) error
+	SaveNotification(notification models.Notification) error
+	ListOrdersToRemind(deadlineBefore time.Time) ([]models.Order, error)
+}
+
+type SchedulerRepository interface {
+	RunLocked(job string, fx func(lastRun time.Time) (bool, error)) (bool, error)
 }
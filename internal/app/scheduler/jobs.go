@@ -0,0 +1,164 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"route/internal/app/models"
+)
+
+// Names of the jobs, they identify the jobs' locks and last runs
+const (
+	JobExpireOrders = "expire-orders"
+	JobReturnList   = "return-list"
+	JobReminders    = "reminders"
+	JobCachePurge   = "cache-purge"
+	JobOutboxPurge  = "outbox-purge"
+)
+
+// Orders marks orders expired and lists the ones to return to the courier
+type Orders interface {
+	MarkExpired() ([]models.Order, error)
+	ListExpiredOrders() ([]models.Order, error)
+}
+
+// Reminders remind clients about orders expiring soon
+type Reminders interface {
+	SendReminders(within time.Duration) (int, error)
+}
+
+// Cache drops expired entries
+type Cache interface {
+	InvalidateExpired()
+}
+
+// Outbox deletes messages delivered to every sink
+type Outbox interface {
+	PurgeDelivered(retention time.Duration) (int, error)
+}
+
+// ExpireOrders marks orders expired at their deadline
+func ExpireOrders(orders Orders, every time.Duration) Job {
+	return Job{
+		Name:     JobExpireOrders,
+		Schedule: Every(every),
+		Run: func(ctx context.Context) error {
+			expired, err := orders.MarkExpired()
+			if err != nil {
+				return err
+			}
+			if len(expired) > 0 {
+				log.Printf("marked %d orders expired", len(expired))
+			}
+			return nil
+		},
+	}
+}
+
+// ReturnList writes the daily list of expired orders to return to the courier to a CSV file in dir
+func ReturnList(orders Orders, at DailyAt, dir string) Job {
+	return Job{
+		Name:     JobReturnList,
+		Schedule: at,
+		Run: func(ctx context.Context) error {
+			expired, err := orders.ListExpiredOrders()
+			if err != nil {
+				return err
+			}
+
+			path := filepath.Join(dir, "returns-"+time.Now().Format(time.DateOnly)+".csv")
+			if err = writeReturnList(path, expired); err != nil {
+				return err
+			}
+			log.Printf("return list with %d orders saved to %s", len(expired), path)
+			return nil
+		},
+	}
+}
+
+func writeReturnList(path string, orders []models.Order) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	if err = w.Write([]string{"order_id", "user_id", "deadline", "expired_at", "weight", "cost"}); err != nil {
+		return err
+	}
+	for _, order := range orders {
+		err = w.Write([]string{
+			strconv.Itoa(order.OrderID),
+			strconv.Itoa(order.UserID),
+			order.Deadline.Format(time.RFC3339),
+			order.ExpiredAt.Format(time.RFC3339),
+			strconv.FormatFloat(order.Weight, 'f', -1, 64),
+			strconv.FormatFloat(order.Cost, 'f', -1, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	if err = w.Error(); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+// SendReminders reminds clients about orders that will be returned to the courier soon
+func SendReminders(reminders Reminders, every, within time.Duration) Job {
+	return Job{
+		Name:     JobReminders,
+		Schedule: Every(every),
+		Run: func(ctx context.Context) error {
+			reminded, err := reminders.SendReminders(within)
+			if err != nil {
+				return fmt.Errorf("failed to send reminders: %w", err)
+			}
+			if reminded > 0 {
+				log.Printf("reminded clients about %d orders", reminded)
+			}
+			return nil
+		},
+	}
+}
+
+// PurgeCache drops expired entries of the in-memory cache of the process
+func PurgeCache(cache Cache, every time.Duration) Job {
+	return Job{
+		Name:     JobCachePurge,
+		Schedule: Every(every),
+		Local:    true,
+		Run: func(ctx context.Context) error {
+			cache.InvalidateExpired()
+			return nil
+		},
+	}
+}
+
+// PurgeOutbox deletes outbox messages delivered to every sink more than retention ago
+func PurgeOutbox(outbox Outbox, every, retention time.Duration) Job {
+	return Job{
+		Name:     JobOutboxPurge,
+		Schedule: Every(every),
+		Run: func(ctx context.Context) error {
+			purged, err := outbox.PurgeDelivered(retention)
+			if err != nil {
+				return fmt.Errorf("failed to purge outbox: %w", err)
+			}
+			if purged > 0 {
+				log.Printf("purged %d delivered outbox messages", purged)
+			}
+			return nil
+		},
+	}
+}
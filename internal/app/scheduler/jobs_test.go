@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+)
+
+type fakeOrders struct {
+	expired []models.Order
+}
+
+func (o *fakeOrders) MarkExpired() ([]models.Order, error) {
+	return o.expired, nil
+}
+
+func (o *fakeOrders) ListExpiredOrders() ([]models.Order, error) {
+	return o.expired, nil
+}
+
+func TestReturnList(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	dir := t.TempDir()
+	deadline := time.Date(2024, 7, 27, 0, 0, 0, 0, time.UTC)
+	orders := &fakeOrders{expired: []models.Order{
+		{OrderID: 1, UserID: 10, Deadline: deadline, ExpiredAt: deadline.Add(time.Minute), Weight: 1.5, Cost: 100},
+	}}
+	job := ReturnList(orders, DailyAt{Hour: 9}, dir)
+
+	// act
+	err := job.Run(context.Background())
+
+	// assert
+	require.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(dir, "returns-"+time.Now().Format(time.DateOnly)+".csv"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"order_id,user_id,deadline,expired_at,weight,cost",
+		"1,10,2024-07-27T00:00:00Z,2024-07-27T00:01:00Z,1.5,100",
+	}, strings.Split(strings.TrimSpace(string(content)), "\n"))
+}
+
+type fakeOutbox struct {
+	retention time.Duration
+}
+
+func (o *fakeOutbox) PurgeDelivered(retention time.Duration) (int, error) {
+	o.retention = retention
+	return 1, nil
+}
+
+func TestPurgeOutbox(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	outbox := &fakeOutbox{}
+	job := PurgeOutbox(outbox, time.Hour, 48*time.Hour)
+
+	// act
+	err := job.Run(context.Background())
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 48*time.Hour, outbox.retention)
+	assert.False(t, job.Local, "Outbox is shared by replicas, so it is purged by one of them")
+}
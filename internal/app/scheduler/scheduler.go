@@ -0,0 +1,136 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"route/internal/app/repository"
+)
+
+// Schedule tells when a job is due after its last run, a job that has never run is due at once
+type Schedule interface {
+	Next(lastRun time.Time) time.Time
+}
+
+// Every runs a job at a fixed interval
+type Every time.Duration
+
+func (e Every) Next(lastRun time.Time) time.Time {
+	if lastRun.IsZero() {
+		return lastRun
+	}
+	return lastRun.Add(time.Duration(e))
+}
+
+// DailyAt runs a job once a day at the given local time
+type DailyAt struct {
+	Hour   int
+	Minute int
+}
+
+func (d DailyAt) Next(lastRun time.Time) time.Time {
+	if lastRun.IsZero() {
+		return lastRun
+	}
+	lastRun = lastRun.Local()
+	next := time.Date(lastRun.Year(), lastRun.Month(), lastRun.Day(), d.Hour, d.Minute, 0, 0, time.Local)
+	if !next.After(lastRun) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// Job is a periodic job. Jobs are run by one replica at a time, the one holding the job's lock,
+// local jobs work with the state of the process and run on every replica.
+type Job struct {
+	Name     string
+	Schedule Schedule
+	Local    bool
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs registered jobs when they are due. Last runs of jobs are stored in the database,
+// so a job isn't run again after a restart or by another replica before it is due.
+type Scheduler struct {
+	repo      repository.SchedulerRepository
+	tick      time.Duration
+	jobs      []Job
+	localRuns map[string]time.Time
+	now       func() time.Time
+}
+
+// ErrInvalidTick is returned for a tick that is not positive
+var ErrInvalidTick = errors.New("scheduler tick must be positive")
+
+func New(repo repository.SchedulerRepository, tick time.Duration) (*Scheduler, error) {
+	if tick <= 0 {
+		return nil, ErrInvalidTick
+	}
+
+	return &Scheduler{
+		repo:      repo,
+		tick:      tick,
+		localRuns: make(map[string]time.Time),
+		now:       time.Now,
+	}, nil
+}
+
+// Register adds a job, jobs must be registered before Run
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Run checks jobs every tick until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.tick)
+	defer ticker.Stop()
+
+	for {
+		s.RunDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue runs jobs that are due, a failed job is run again on the next tick
+func (s *Scheduler) RunDue(ctx context.Context) {
+	for _, job := range s.jobs {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if job.Local {
+			s.runLocal(ctx, job)
+			continue
+		}
+
+		_, err := s.repo.RunLocked(job.Name, func(lastRun time.Time) (bool, error) {
+			if s.now().Before(job.Schedule.Next(lastRun)) {
+				return false, nil
+			}
+			return true, job.Run(ctx)
+		})
+		if err != nil {
+			log.Printf("scheduler job %s: %v", job.Name, err)
+		}
+	}
+}
+
+func (s *Scheduler) runLocal(ctx context.Context, job Job) {
+	now := s.now()
+	if now.Before(job.Schedule.Next(s.localRuns[job.Name])) {
+		return
+	}
+
+	if err := job.Run(ctx); err != nil {
+		log.Printf("scheduler job %s: %v", job.Name, err)
+		return
+	}
+	s.localRuns[job.Name] = now
+}
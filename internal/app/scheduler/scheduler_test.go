@@ -0,0 +1,187 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	mockrepository "route/internal/app/repository/mocks"
+)
+
+// runLockedAt emulates SchedulerRepository.RunLocked for a job last run at lastRun
+func runLockedAt(lastRun time.Time, ran *bool) func(string, func(time.Time) (bool, error)) (bool, error) {
+	return func(_ string, fx func(time.Time) (bool, error)) (bool, error) {
+		var err error
+		*ran, err = fx(lastRun)
+		return true, err
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	t.Parallel()
+
+	lastRun := time.Date(2024, 7, 28, 10, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		lastRun  time.Time
+		expected time.Time
+	}{
+		{
+			name:     "never run",
+			schedule: Every(time.Minute),
+			expected: time.Time{},
+		},
+		{
+			name:     "every interval",
+			schedule: Every(time.Minute),
+			lastRun:  lastRun,
+			expected: lastRun.Add(time.Minute),
+		},
+		{
+			name:     "daily later the same day",
+			schedule: DailyAt{Hour: 18},
+			lastRun:  lastRun,
+			expected: time.Date(2024, 7, 28, 18, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "daily the next day",
+			schedule: DailyAt{Hour: 9},
+			lastRun:  lastRun,
+			expected: time.Date(2024, 7, 29, 9, 0, 0, 0, time.Local),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			next := tt.schedule.Next(tt.lastRun)
+
+			// assert
+			assert.True(t, tt.expected.Equal(next), "expected %v, got %v", tt.expected, next)
+		})
+	}
+}
+
+func TestScheduler_RunDue(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 7, 28, 10, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name        string
+		lastRun     time.Time
+		jobErr      error
+		expectedRun bool
+	}{
+		{
+			name:        "never run",
+			expectedRun: true,
+		},
+		{
+			name:        "due",
+			lastRun:     now.Add(-time.Hour),
+			expectedRun: true,
+		},
+		{
+			name:    "not due",
+			lastRun: now.Add(-time.Minute),
+		},
+		{
+			name:        "failed job",
+			jobErr:      errors.New("db is down"),
+			expectedRun: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockSchedulerRepository(ctrl)
+			s, err := New(mockRepo, time.Second)
+			require.NoError(t, err)
+			s.now = func() time.Time { return now }
+
+			called := false
+			s.Register(Job{Name: "job", Schedule: Every(time.Hour), Run: func(ctx context.Context) error {
+				called = true
+				return tt.jobErr
+			}})
+
+			var ran bool
+			mockRepo.EXPECT().RunLocked("job", gomock.Any()).DoAndReturn(runLockedAt(tt.lastRun, &ran))
+
+			// act
+			s.RunDue(context.Background())
+
+			// assert
+			assert.Equal(t, tt.expectedRun, called)
+			assert.Equal(t, tt.expectedRun, ran)
+		})
+	}
+}
+
+func TestScheduler_RunDueLocal(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctrl := gomock.NewController(t)
+	mockRepo := mockrepository.NewMockSchedulerRepository(ctrl)
+	s, err := New(mockRepo, time.Second)
+	require.NoError(t, err)
+	now := time.Date(2024, 7, 28, 10, 30, 0, 0, time.Local)
+	s.now = func() time.Time { return now }
+
+	runs := 0
+	s.Register(Job{Name: "local", Schedule: Every(time.Minute), Local: true, Run: func(ctx context.Context) error {
+		runs++
+		return nil
+	}})
+
+	// act
+	s.RunDue(context.Background())
+	s.RunDue(context.Background())
+	now = now.Add(time.Minute)
+	s.RunDue(context.Background())
+
+	// assert
+	require.Equal(t, 2, runs)
+}
+
+func TestNew_InvalidTick(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		tick time.Duration
+	}{
+		{name: "zero", tick: 0},
+		{name: "negative", tick: -time.Second},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			s, err := New(nil, tt.tick)
+
+			// assert
+			assert.ErrorIs(t, err, ErrInvalidTick)
+			assert.Nil(t, s)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package sink
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/events"
+	"route/internal/app/models"
+)
+
+func testMessage(t *testing.T, orderID int) models.OutboxMessage {
+	t.Helper()
+
+	order := models.Order{OrderID: orderID, UserID: 1, Deadline: time.Now().Add(time.Hour), Cost: 100, Weight: 2}
+	msg, err := events.New(models.OrderAccepted, order, models.Box, models.NewActor(models.ActorCLI, "manager"))
+	require.NoError(t, err)
+	return *msg
+}
+
+func readLines(t *testing.T, path string) []record {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var records []record
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var r record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		records = append(records, r)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+func TestFile_SendEvent(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	file, err := NewFile(path, 1<<20, 1)
+	require.NoError(t, err)
+	msg := testMessage(t, 1)
+
+	// act
+	require.NoError(t, file.SendEvent(msg))
+	require.NoError(t, file.Close())
+
+	// assert
+	records := readLines(t, path)
+	require.Len(t, records, 1)
+	assert.Equal(t, msg.EventID, records[0].EventID)
+	assert.Equal(t, string(models.OrderAccepted), records[0].EventType)
+	assert.Equal(t, 1, records[0].OrderID)
+	assert.Contains(t, string(records[0].Event), `"packagingType":"`+string(models.Box)+`"`)
+}
+
+func TestFile_Rotate(t *testing.T) {
+	t.Parallel()
+
+	// arrange: every event is bigger than the limit, so each one goes to a new file
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	file, err := NewFile(path, 10, 2)
+	require.NoError(t, err)
+
+	// act
+	for orderID := 1; orderID <= 4; orderID++ {
+		require.NoError(t, file.SendEvent(testMessage(t, orderID)))
+	}
+	require.NoError(t, file.Close())
+
+	// assert
+	assert.Equal(t, 4, readLines(t, path)[0].OrderID)
+	assert.Equal(t, 3, readLines(t, path+".1")[0].OrderID)
+	assert.Equal(t, 2, readLines(t, path+".2")[0].OrderID)
+	assert.NoFileExists(t, path+".3")
+}
+
+func TestFile_AppendsToExisting(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	file, err := NewFile(path, 1<<20, 1)
+	require.NoError(t, err)
+	require.NoError(t, file.SendEvent(testMessage(t, 1)))
+	require.NoError(t, file.Close())
+
+	// act
+	file, err = NewFile(path, 1<<20, 1)
+	require.NoError(t, err)
+	require.NoError(t, file.SendEvent(testMessage(t, 2)))
+	require.NoError(t, file.Close())
+
+	// assert
+	assert.Len(t, readLines(t, path), 2)
+}
+
+func TestFile_ReopensAfterFailedRotate(t *testing.T) {
+	t.Parallel()
+
+	// arrange: a non-empty directory in place of the backup makes rotation fail
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	require.NoError(t, os.MkdirAll(filepath.Join(path+".1", "busy"), 0o755))
+	file, err := NewFile(path, 10, 1)
+	require.NoError(t, err)
+	require.NoError(t, file.SendEvent(testMessage(t, 1)))
+	require.Error(t, file.SendEvent(testMessage(t, 2)))
+	require.NoError(t, os.RemoveAll(path+".1"))
+
+	// act
+	err = file.SendEvent(testMessage(t, 3))
+	require.NoError(t, file.Close())
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 3, readLines(t, path)[0].OrderID)
+	assert.Equal(t, 1, readLines(t, path+".1")[0].OrderID)
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	file, err := NewFile(path, 1<<20, 1)
+	require.NoError(t, err)
+	sinks := []Named{{Name: "stdout", EventSink: Stdout{}}, {Name: "file", EventSink: file}}
+
+	// act
+	err = Close(sinks)
+
+	// assert
+	require.NoError(t, err)
+	assert.Nil(t, file.file, "File is closed")
+	assert.NoError(t, file.Close(), "Closing twice is a no-op")
+}
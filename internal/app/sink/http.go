@@ -0,0 +1,42 @@
+package sink
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"route/internal/app/models"
+)
+
+// HTTP posts every event as JSON to the endpoint, any status other than 2xx is an error
+type HTTP struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTP(url string, timeout time.Duration) *HTTP {
+	return &HTTP{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (h *HTTP) SendEvent(msg models.OutboxMessage) error {
+	body, err := Encode(msg)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.client.Post(h.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("event endpoint responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
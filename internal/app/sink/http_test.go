@@ -0,0 +1,61 @@
+package sink
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_SendEvent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		status        int
+		expectedError string
+	}{
+		{
+			name:   "accepted",
+			status: http.StatusNoContent,
+		},
+		{
+			name:          "endpoint error",
+			status:        http.StatusServiceUnavailable,
+			expectedError: "event endpoint responded with status 503",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			var received record
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+			msg := testMessage(t, 5)
+
+			// act
+			err := NewHTTP(server.URL, time.Second).SendEvent(msg)
+
+			// assert
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, msg.EventID, received.EventID)
+			assert.Equal(t, 5, received.OrderID)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package sink
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"route/internal/app/config"
+	"route/internal/app/events"
+	"route/internal/app/models"
+)
+
+// EventSink receives order events published from the outbox
+type EventSink interface {
+	SendEvent(msg models.OutboxMessage) error
+}
+
+// Named is a sink with the name its deliveries are tracked by
+type Named struct {
+	Name string
+	EventSink
+}
+
+// New creates the sinks listed in the config in the same order, kafka sink is created by the caller
+func New(cfg config.SinksConfig, kafka EventSink) ([]Named, error) {
+	sinks := make([]Named, 0, len(cfg.Modes))
+	for _, mode := range cfg.Modes {
+		var s EventSink
+		switch mode {
+		case config.OutputStdout:
+			s = Stdout{}
+		case config.OutputKafka:
+			s = kafka
+		case config.OutputFile:
+			file, err := NewFile(cfg.FilePath, cfg.FileMaxSize, cfg.FileMaxBackups)
+			if err != nil {
+				return nil, err
+			}
+			s = file
+		case config.OutputHTTP:
+			s = NewHTTP(cfg.HTTPURL, cfg.HTTPTimeout)
+		default:
+			return nil, fmt.Errorf("unknown sink %q", mode)
+		}
+		sinks = append(sinks, Named{Name: mode, EventSink: s})
+	}
+
+	return sinks, nil
+}
+
+// Close closes the sinks holding resources, e.g. files. The kafka sink is closed by the caller
+func Close(sinks []Named) error {
+	var errs []error
+	for _, s := range sinks {
+		if closer, ok := s.EventSink.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("sink %s: %w", s.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// record is a JSON representation of an event written by file and HTTP sinks
+type record struct {
+	EventID       string          `json:"event_id"`
+	EventType     string          `json:"event_type"`
+	SchemaVersion int             `json:"schema_version"`
+	OrderID       int             `json:"order_id"`
+	Event         json.RawMessage `json:"event"`
+}
+
+// Encode returns a JSON representation of the event with its decoded payload
+func Encode(msg models.OutboxMessage) ([]byte, error) {
+	event, err := events.Decode(msg.EventType, msg.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := protojson.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(record{
+		EventID:       msg.EventID,
+		EventType:     string(msg.EventType),
+		SchemaVersion: msg.SchemaVersion,
+		OrderID:       msg.OrderID,
+		Event:         payload,
+	})
+}
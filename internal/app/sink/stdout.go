@@ -0,0 +1,22 @@
+package sink
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"route/internal/app/events"
+	"route/internal/app/models"
+)
+
+// Stdout prints events instead of sending them to a broker
+type Stdout struct{}
+
+func (Stdout) SendEvent(msg models.OutboxMessage) error {
+	event, err := events.Decode(msg.EventType, msg.Payload)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Event %s v%d: %s\n", msg.EventType, msg.SchemaVersion, protojson.Format(event))
+	return nil
+}
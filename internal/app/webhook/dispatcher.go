@@ -0,0 +1,178 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"route/internal/app/config"
+	"route/internal/app/models"
+	"route/internal/app/repository"
+	"route/internal/app/sink"
+)
+
+// Headers of webhook requests
+const (
+	HeaderDeliveryID = "X-Webhook-Delivery"
+	HeaderEventType  = "X-Webhook-Event"
+	HeaderTimestamp  = "X-Webhook-Timestamp"
+	HeaderSignature  = "X-Webhook-Signature"
+)
+
+const (
+	defaultBatchSize    = 10
+	defaultPollInterval = time.Second
+	// maxErrorBody limits the part of a failed response saved in delivery logs
+	maxErrorBody = 512
+)
+
+// Sign returns the signature of the body sent at timestamp: hex-encoded HMAC-SHA256
+// of "timestamp.body" with the subscription secret prefixed by "sha256="
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sink queues events from the outbox for delivery to subscribed endpoints
+type Sink struct {
+	repo repository.WebhookRepository
+}
+
+func NewSink(repo repository.WebhookRepository) *Sink {
+	return &Sink{repo: repo}
+}
+
+func (s *Sink) SendEvent(msg models.OutboxMessage) error {
+	body, err := sink.Encode(msg)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.repo.EnqueueDeliveries(msg, body)
+	return err
+}
+
+// Dispatcher sends queued deliveries to subscribed endpoints. A failed delivery is retried
+// with exponential backoff and given up after the configured number of attempts.
+type Dispatcher struct {
+	repo         repository.WebhookRepository
+	client       *http.Client
+	policy       config.WebhookConfig
+	batchSize    int
+	pollInterval time.Duration
+	now          func() time.Time
+}
+
+func NewDispatcher(repo repository.WebhookRepository, policy config.WebhookConfig) *Dispatcher {
+	return &Dispatcher{
+		repo:         repo,
+		client:       &http.Client{Timeout: policy.Timeout},
+		policy:       policy,
+		batchSize:    defaultBatchSize,
+		pollInterval: defaultPollInterval,
+		now:          time.Now,
+	}
+}
+
+// Run delivers due webhooks until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := d.DeliverDue(); err != nil {
+			log.Printf("webhook dispatcher: %v", err)
+		}
+	}
+}
+
+// DeliverDue attempts deliveries whose time has come until none are left
+func (d *Dispatcher) DeliverDue() (int, error) {
+	total := 0
+	for {
+		processed, err := d.repo.ProcessDueDeliveries(d.batchSize, d.deliver)
+		total += processed
+		if err != nil || processed < d.batchSize {
+			return total, err
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(delivery models.WebhookDelivery) models.WebhookAttempt {
+	start := d.now()
+	statusCode, err := d.send(delivery, start)
+	attempt := models.WebhookAttempt{
+		StatusCode: statusCode,
+		Duration:   time.Since(start),
+	}
+
+	if err == nil {
+		attempt.Delivered = true
+		return attempt
+	}
+
+	attempt.Error = err.Error()
+	attempts := delivery.Attempts + 1
+	if attempts >= d.policy.MaxAttempts {
+		attempt.GiveUp = true
+		return attempt
+	}
+	attempt.NextAttemptAt = start.Add(d.backoff(attempts))
+	return attempt
+}
+
+func (d *Dispatcher) send(delivery models.WebhookDelivery, now time.Time) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, delivery.URL, bytes.NewReader(delivery.Body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDeliveryID, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderEventType, string(delivery.EventType))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, delivery.Body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		if body = bytes.TrimSpace(body); len(body) > 0 {
+			return resp.StatusCode, fmt.Errorf("endpoint responded with status %d: %s", resp.StatusCode, body)
+		}
+		return resp.StatusCode, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay after the given number of failed attempts
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.policy.InitialBackoff
+	for i := 1; i < attempts && delay < d.policy.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.policy.MaxBackoff)
+}
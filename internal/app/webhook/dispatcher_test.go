@@ -0,0 +1,175 @@
+package webhook
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"route/internal/app/config"
+	"route/internal/app/models"
+	mockrepository "route/internal/app/repository/mocks"
+)
+
+var testPolicy = config.WebhookConfig{
+	MaxAttempts:    3,
+	InitialBackoff: time.Second,
+	MaxBackoff:     3 * time.Second,
+	Timeout:        time.Second,
+}
+
+// processDeliveries emulates WebhookRepository.ProcessDueDeliveries and collects attempts
+func processDeliveries(deliveries []models.WebhookDelivery, attempts *[]models.WebhookAttempt) func(int, func(models.WebhookDelivery) models.WebhookAttempt) (int, error) {
+	return func(_ int, fx func(models.WebhookDelivery) models.WebhookAttempt) (int, error) {
+		for _, delivery := range deliveries {
+			*attempts = append(*attempts, fx(delivery))
+		}
+		return len(deliveries), nil
+	}
+}
+
+func TestDispatcher_DeliverDue(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 7, 26, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"event_type":"OrderIssued"}`)
+
+	tests := []struct {
+		name     string
+		status   int
+		attempts int
+		expected models.WebhookAttempt
+	}{
+		{
+			name:     "delivered",
+			status:   http.StatusOK,
+			expected: models.WebhookAttempt{StatusCode: http.StatusOK, Delivered: true},
+		},
+		{
+			name:     "retried with backoff",
+			status:   http.StatusInternalServerError,
+			attempts: 1,
+			expected: models.WebhookAttempt{
+				StatusCode:    http.StatusInternalServerError,
+				Error:         "endpoint responded with status 500: try later",
+				NextAttemptAt: now.Add(2 * time.Second),
+			},
+		},
+		{
+			name:     "given up after last attempt",
+			status:   http.StatusInternalServerError,
+			attempts: 2,
+			expected: models.WebhookAttempt{
+				StatusCode: http.StatusInternalServerError,
+				Error:      "endpoint responded with status 500: try later",
+				GiveUp:     true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, body, received)
+				assert.Equal(t, "7", r.Header.Get(HeaderDeliveryID))
+				assert.Equal(t, "OrderIssued", r.Header.Get(HeaderEventType))
+				timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+				assert.NoError(t, err)
+				assert.Equal(t, Sign("secret", timestamp, received), r.Header.Get(HeaderSignature))
+
+				w.WriteHeader(tt.status)
+				if tt.status != http.StatusOK {
+					_, _ = w.Write([]byte("try later\n"))
+				}
+			}))
+			defer server.Close()
+
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockWebhookRepository(ctrl)
+			dispatcher := NewDispatcher(mockRepo, testPolicy)
+			dispatcher.now = func() time.Time { return now }
+			delivery := models.WebhookDelivery{
+				ID: 7, URL: server.URL, Secret: "secret", EventType: models.OrderIssued, Body: body, Attempts: tt.attempts,
+			}
+			var attempts []models.WebhookAttempt
+			mockRepo.EXPECT().ProcessDueDeliveries(defaultBatchSize, gomock.Any()).
+				DoAndReturn(processDeliveries([]models.WebhookDelivery{delivery}, &attempts))
+
+			// act
+			processed, err := dispatcher.DeliverDue()
+
+			// assert
+			require.NoError(t, err)
+			assert.Equal(t, 1, processed)
+			require.Len(t, attempts, 1)
+			attempts[0].Duration = 0
+			assert.Equal(t, tt.expected, attempts[0])
+		})
+	}
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	t.Parallel()
+
+	dispatcher := NewDispatcher(nil, testPolicy)
+
+	assert.Equal(t, time.Second, dispatcher.backoff(1))
+	assert.Equal(t, 2*time.Second, dispatcher.backoff(2))
+	assert.Equal(t, 3*time.Second, dispatcher.backoff(3))
+	assert.Equal(t, 3*time.Second, dispatcher.backoff(10))
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	// Value computed with: printf '1721995200.{}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=0498b14aeedb720a74099d0e7e3eb64b3861e65039f8368c3071cf5e7bbd7cb1",
+		Sign("secret", 1721995200, []byte("{}")))
+}
+
+func TestSink_SendEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("queues event", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mockrepository.NewMockWebhookRepository(ctrl)
+		msg := testMessage(t)
+		mockRepo.EXPECT().EnqueueDeliveries(msg, gomock.Any()).Return(1, nil)
+
+		// act
+		err := NewSink(mockRepo).SendEvent(msg)
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		ctrl := gomock.NewController(t)
+		mockRepo := mockrepository.NewMockWebhookRepository(ctrl)
+		mockRepo.EXPECT().EnqueueDeliveries(gomock.Any(), gomock.Any()).Return(0, errors.New("database error"))
+
+		// act
+		err := NewSink(mockRepo).SendEvent(testMessage(t))
+
+		// assert
+		assert.EqualError(t, err, "database error")
+	})
+}
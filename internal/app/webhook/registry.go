@@ -0,0 +1,114 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+
+	"route/internal/app/models"
+	"route/internal/app/repository"
+)
+
+// EventTypes are order lifecycle events partners can subscribe to
+var EventTypes = []models.EventType{
+	models.OrderAccepted,
+	models.OrderIssued,
+	models.OrderReturnedToCourier,
+	models.ReturnAccepted,
+	models.OrderExpired,
+	models.OrderRefused,
+	models.OrderLocked,
+	models.OrderUnlocked,
+	models.OrderInTransit,
+	models.OrderTransferred,
+}
+
+const secretLength = 32
+
+// ValidationError is returned when a subscription or a request is rejected, as opposed to repository errors
+type ValidationError struct {
+	msg string
+}
+
+func (e ValidationError) Error() string {
+	return e.msg
+}
+
+func newValidationError(format string, args ...any) error {
+	return ValidationError{msg: fmt.Sprintf(format, args...)}
+}
+
+// Registry manages webhook subscriptions of partner systems
+type Registry struct {
+	repo repository.WebhookRepository
+}
+
+func NewRegistry(repo repository.WebhookRepository) *Registry {
+	return &Registry{repo: repo}
+}
+
+// Subscribe registers the endpoint for the event types, a secret is generated if empty
+func (r *Registry) Subscribe(endpoint string, eventTypes []models.EventType, secret string) (*models.WebhookSubscription, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, newValidationError("некорректный URL вебхука: %s", endpoint)
+	}
+
+	if len(eventTypes) == 0 {
+		return nil, newValidationError("не указаны типы событий")
+	}
+	for _, eventType := range eventTypes {
+		if !isSubscribable(eventType) {
+			return nil, newValidationError("недопустимый тип события: %s", eventType)
+		}
+	}
+
+	if secret == "" {
+		secret, err = generateSecret()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sub := models.WebhookSubscription{URL: endpoint, EventTypes: eventTypes, Secret: secret}
+	sub.ID, err = r.repo.CreateSubscription(sub)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sub, nil
+}
+
+func (r *Registry) List() ([]models.WebhookSubscription, error) {
+	return r.repo.ListSubscriptions()
+}
+
+func (r *Registry) Unsubscribe(id int64) error {
+	return r.repo.DeleteSubscription(id)
+}
+
+// ListDeliveries returns up to limit latest delivery attempts of the subscription
+func (r *Registry) ListDeliveries(subscriptionID int64, limit int) ([]models.WebhookDeliveryLog, error) {
+	if limit <= 0 {
+		return nil, newValidationError("параметр limit должен быть больше нуля")
+	}
+	return r.repo.ListDeliveryLogs(subscriptionID, limit)
+}
+
+func isSubscribable(eventType models.EventType) bool {
+	for _, t := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, secretLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
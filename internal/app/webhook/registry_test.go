@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"route/internal/app/events"
+	"route/internal/app/models"
+	mockrepository "route/internal/app/repository/mocks"
+)
+
+func testMessage(t *testing.T) models.OutboxMessage {
+	t.Helper()
+
+	order := models.Order{OrderID: 1, UserID: 2, Deadline: time.Now().Add(time.Hour), Cost: 100, Weight: 2}
+	msg, err := events.New(models.OrderIssued, order, "", models.NewActor(models.ActorGRPC, ""))
+	require.NoError(t, err)
+	return *msg
+}
+
+func TestRegistry_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		url           string
+		eventTypes    []models.EventType
+		secret        string
+		expectedError string
+	}{
+		{
+			name:       "with secret",
+			url:        "https://partner.example/hooks",
+			eventTypes: []models.EventType{models.OrderAccepted, models.OrderIssued},
+			secret:     "secret",
+		},
+		{
+			name:       "generated secret",
+			url:        "http://localhost:8080/hooks",
+			eventTypes: []models.EventType{models.ReturnAccepted},
+		},
+		{
+			name:          "invalid url",
+			url:           "partner.example/hooks",
+			eventTypes:    []models.EventType{models.OrderIssued},
+			expectedError: "некорректный URL вебхука: partner.example/hooks",
+		},
+		{
+			name:          "no event types",
+			url:           "https://partner.example/hooks",
+			expectedError: "не указаны типы событий",
+		},
+		{
+			name:          "internal event type",
+			url:           "https://partner.example/hooks",
+			eventTypes:    []models.EventType{models.CourierManifest},
+			expectedError: "недопустимый тип события: CourierManifest",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mockrepository.NewMockWebhookRepository(ctrl)
+			if tt.expectedError == "" {
+				mockRepo.EXPECT().CreateSubscription(gomock.Any()).Return(int64(3), nil)
+			}
+
+			// act
+			sub, err := NewRegistry(mockRepo).Subscribe(tt.url, tt.eventTypes, tt.secret)
+
+			// assert
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(3), sub.ID)
+			assert.Equal(t, tt.eventTypes, sub.EventTypes)
+			if tt.secret != "" {
+				assert.Equal(t, tt.secret, sub.Secret)
+			} else {
+				assert.Len(t, sub.Secret, 2*secretLength)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE outbox (
+                        id BIGSERIAL PRIMARY KEY,
+                        event_type VARCHAR(255) NOT NULL,
+                        order_id INT NOT NULL,
+                        payload BYTEA NOT NULL,
+                        created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                        sent_at TIMESTAMP,
+                        attempts INT NOT NULL DEFAULT 0,
+                        last_error TEXT
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (id) WHERE sent_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE outbox;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE outbox
+    ADD COLUMN event_id UUID NOT NULL DEFAULT gen_random_uuid(),
+    ADD COLUMN schema_version INT NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE outbox
+    DROP COLUMN event_id,
+    DROP COLUMN schema_version;
+-- +goose StatementEnd
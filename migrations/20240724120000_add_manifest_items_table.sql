@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE manifest_items (
+                                manifest_id VARCHAR(255) NOT NULL,
+                                order_id INT NOT NULL,
+                                accepted BOOLEAN NOT NULL,
+                                reason TEXT NOT NULL DEFAULT '',
+                                processed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                                PRIMARY KEY (manifest_id, order_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE manifest_items;
+-- +goose StatementEnd
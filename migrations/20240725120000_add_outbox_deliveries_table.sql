@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE outbox_sinks (
+                              sink VARCHAR(64) PRIMARY KEY,
+                              start_id BIGINT NOT NULL,
+                              created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE outbox_deliveries (
+                                   sink VARCHAR(64) NOT NULL REFERENCES outbox_sinks (sink) ON DELETE CASCADE,
+                                   outbox_id BIGINT NOT NULL REFERENCES outbox (id) ON DELETE CASCADE,
+                                   attempts INT NOT NULL DEFAULT 0,
+                                   last_error TEXT,
+                                   sent_at TIMESTAMP,
+                                   PRIMARY KEY (sink, outbox_id)
+);
+
+-- Sinks available before keep their progress, pending messages are delivered to them after the upgrade
+INSERT INTO outbox_sinks (sink, start_id)
+SELECT sink, COALESCE((SELECT MIN(id) - 1 FROM outbox WHERE sent_at IS NULL), (SELECT MAX(id) FROM outbox), 0)
+FROM (VALUES ('stdout'), ('kafka')) AS sinks (sink);
+
+DROP INDEX outbox_pending_idx;
+
+ALTER TABLE outbox
+    DROP COLUMN sent_at,
+    DROP COLUMN attempts,
+    DROP COLUMN last_error;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE outbox
+    ADD COLUMN sent_at TIMESTAMP,
+    ADD COLUMN attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN last_error TEXT;
+
+CREATE INDEX outbox_pending_idx ON outbox (id) WHERE sent_at IS NULL;
+
+UPDATE outbox SET sent_at = NOW()
+WHERE id IN (SELECT outbox_id FROM outbox_deliveries WHERE sent_at IS NOT NULL);
+
+DROP TABLE outbox_deliveries;
+DROP TABLE outbox_sinks;
+-- +goose StatementEnd
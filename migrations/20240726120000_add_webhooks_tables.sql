@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE webhook_subscriptions (
+                                       id BIGSERIAL PRIMARY KEY,
+                                       url TEXT NOT NULL,
+                                       event_types TEXT[] NOT NULL,
+                                       secret TEXT NOT NULL,
+                                       created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE webhook_deliveries (
+                                    id BIGSERIAL PRIMARY KEY,
+                                    subscription_id BIGINT NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+                                    event_id UUID NOT NULL,
+                                    event_type VARCHAR(255) NOT NULL,
+                                    body BYTEA NOT NULL,
+                                    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+                                    attempts INT NOT NULL DEFAULT 0,
+                                    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                                    last_error TEXT,
+                                    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                                    delivered_at TIMESTAMP,
+                                    UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+CREATE TABLE webhook_delivery_logs (
+                                       id BIGSERIAL PRIMARY KEY,
+                                       delivery_id BIGINT NOT NULL REFERENCES webhook_deliveries (id) ON DELETE CASCADE,
+                                       attempt INT NOT NULL,
+                                       status_code INT NOT NULL DEFAULT 0,
+                                       error TEXT NOT NULL DEFAULT '',
+                                       duration_ms INT NOT NULL,
+                                       created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX webhook_delivery_logs_delivery_idx ON webhook_delivery_logs (delivery_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE webhook_delivery_logs;
+DROP TABLE webhook_deliveries;
+DROP TABLE webhook_subscriptions;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE user_contacts (
+                               user_id INT PRIMARY KEY,
+                               phone VARCHAR(32) NOT NULL DEFAULT '',
+                               email VARCHAR(255) NOT NULL DEFAULT '',
+                               locale VARCHAR(8) NOT NULL DEFAULT 'ru',
+                               updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE notifications (
+                               id BIGSERIAL PRIMARY KEY,
+                               order_id INT NOT NULL,
+                               user_id INT NOT NULL,
+                               kind VARCHAR(32) NOT NULL,
+                               channel VARCHAR(32) NOT NULL,
+                               error TEXT NOT NULL DEFAULT '',
+                               created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX notifications_order_idx ON notifications (order_id, kind);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE notifications;
+DROP TABLE user_contacts;
+-- +goose StatementEnd
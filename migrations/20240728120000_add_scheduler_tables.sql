@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders ADD COLUMN expired_at TIMESTAMP NULL;
+
+CREATE TABLE scheduler_jobs (
+                                name VARCHAR(64) PRIMARY KEY,
+                                last_run_at TIMESTAMP NULL,
+                                last_error TEXT NOT NULL DEFAULT '',
+                                updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE scheduler_jobs;
+ALTER TABLE orders DROP COLUMN expired_at;
+-- +goose StatementEnd
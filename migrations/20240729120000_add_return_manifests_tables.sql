@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders ADD COLUMN returned_to_courier_at TIMESTAMP NULL;
+
+CREATE TABLE return_manifests (
+                                  id BIGSERIAL PRIMARY KEY,
+                                  courier_id INT NOT NULL,
+                                  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE return_manifest_items (
+                                       manifest_id BIGINT NOT NULL REFERENCES return_manifests (id) ON DELETE CASCADE,
+                                       order_id INT NOT NULL,
+                                       PRIMARY KEY (manifest_id, order_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE return_manifest_items;
+DROP TABLE return_manifests;
+ALTER TABLE orders DROP COLUMN returned_to_courier_at;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE couriers (
+                          id SERIAL PRIMARY KEY,
+                          name VARCHAR(255) NOT NULL,
+                          company VARCHAR(255) NOT NULL,
+                          created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE acceptance_sessions (
+                                     id BIGSERIAL PRIMARY KEY,
+                                     courier_id INT NOT NULL REFERENCES couriers (id),
+                                     expected_count INT NOT NULL DEFAULT 0,
+                                     opened_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                                     closed_at TIMESTAMP NULL,
+                                     accepted_count INT NOT NULL DEFAULT 0,
+                                     total_weight FLOAT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE session_rejections (
+                                    id BIGSERIAL PRIMARY KEY,
+                                    session_id BIGINT NOT NULL REFERENCES acceptance_sessions (id) ON DELETE CASCADE,
+                                    order_id INT NOT NULL,
+                                    reason TEXT NOT NULL,
+                                    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE orders ADD COLUMN session_id BIGINT NULL REFERENCES acceptance_sessions (id);
+CREATE INDEX orders_session_idx ON orders (session_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN session_id;
+DROP TABLE session_rejections;
+DROP TABLE acceptance_sessions;
+DROP TABLE couriers;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders ADD COLUMN refused_at TIMESTAMP NULL;
+ALTER TABLE orders ADD COLUMN refusal_reason TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN refusal_reason;
+ALTER TABLE orders DROP COLUMN refused_at;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders ADD COLUMN pickup_code_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN pickup_failures INT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN pickup_last_failure_at TIMESTAMP NULL;
+ALTER TABLE orders ADD COLUMN pickup_locked_at TIMESTAMP NULL;
+ALTER TABLE orders ADD COLUMN issue_override_by TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN issue_override_by;
+ALTER TABLE orders DROP COLUMN pickup_locked_at;
+ALTER TABLE orders DROP COLUMN pickup_last_failure_at;
+ALTER TABLE orders DROP COLUMN pickup_failures;
+ALTER TABLE orders DROP COLUMN pickup_code_hash;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE pickup_authorizations (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    recipient TEXT NOT NULL,
+    -- NULL authorizes all orders of the client
+    order_id INT NULL,
+    valid_until TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX pickup_authorizations_user_id_idx ON pickup_authorizations (user_id);
+
+ALTER TABLE orders ADD COLUMN received_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN authorization_id BIGINT NULL REFERENCES pickup_authorizations (id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN authorization_id;
+ALTER TABLE orders DROP COLUMN received_by;
+DROP TABLE pickup_authorizations;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE pickup_points (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    address TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Orders accepted before pickup points were introduced belong to the default point
+INSERT INTO pickup_points (id, name) VALUES (1, 'default');
+SELECT setval('pickup_points_id_seq', 1);
+
+ALTER TABLE orders ADD COLUMN pickup_point_id INT NOT NULL DEFAULT 1 REFERENCES pickup_points (id);
+CREATE INDEX orders_pickup_point_id_idx ON orders (pickup_point_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN pickup_point_id;
+DROP TABLE pickup_points;
+-- +goose StatementEnd
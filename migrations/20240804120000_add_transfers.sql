@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE transfers (
+    id BIGSERIAL PRIMARY KEY,
+    from_point_id INT NOT NULL REFERENCES pickup_points (id),
+    to_point_id INT NOT NULL REFERENCES pickup_points (id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    received_at TIMESTAMP NULL
+);
+
+CREATE TABLE transfer_items (
+    transfer_id BIGINT NOT NULL REFERENCES transfers (id),
+    order_id INT NOT NULL,
+    PRIMARY KEY (transfer_id, order_id)
+);
+
+-- Order is in transit while it is linked to a transfer
+ALTER TABLE orders ADD COLUMN transfer_id BIGINT NULL REFERENCES transfers (id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN transfer_id;
+DROP TABLE transfer_items;
+DROP TABLE transfers;
+-- +goose StatementEnd
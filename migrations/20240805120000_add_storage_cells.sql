@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE storage_racks (
+    id SERIAL PRIMARY KEY,
+    pickup_point_id INT NOT NULL REFERENCES pickup_points (id),
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (pickup_point_id, name)
+);
+
+-- size is the size class of the cell: 1 small, 2 medium, 3 large
+CREATE TABLE storage_cells (
+    id SERIAL PRIMARY KEY,
+    rack_id INT NOT NULL REFERENCES storage_racks (id),
+    code TEXT NOT NULL,
+    size SMALLINT NOT NULL,
+    max_weight DOUBLE PRECISION NOT NULL,
+    UNIQUE (rack_id, code)
+);
+
+-- A cell holds one order, the cell is free again once the order leaves the shelf
+ALTER TABLE orders ADD COLUMN cell_id INT NULL REFERENCES storage_cells (id);
+CREATE UNIQUE INDEX orders_cell_id_idx ON orders (cell_id) WHERE cell_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN cell_id;
+DROP TABLE storage_cells;
+DROP TABLE storage_racks;
+-- +goose StatementEnd
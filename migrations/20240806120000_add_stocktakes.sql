@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE stocktakes (
+    id BIGSERIAL PRIMARY KEY,
+    pickup_point_id INT NOT NULL REFERENCES pickup_points (id),
+    scanned INT NOT NULL,
+    stored INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- kind is one of missing, unexpected, wrongly_issued
+CREATE TABLE stocktake_items (
+    stocktake_id BIGINT NOT NULL REFERENCES stocktakes (id),
+    order_id INT NOT NULL,
+    kind TEXT NOT NULL,
+    PRIMARY KEY (stocktake_id, order_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE stocktake_items;
+DROP TABLE stocktakes;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Reports aggregate the order history kept in the outbox by date
+CREATE INDEX outbox_created_at_idx ON outbox (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX outbox_created_at_idx;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Reports aggregate this history instead of the outbox, it keeps the point, packaging and amounts
+-- of the order at the time of the event, so reports of past days don't change after returns to couriers
+-- and transfers. day is the date of the event in the time zone of the service
+CREATE TABLE report_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(255) NOT NULL,
+    order_id INT NOT NULL,
+    pickup_point_id INT NOT NULL,
+    packaging_type TEXT NOT NULL,
+    cost DOUBLE PRECISION NOT NULL,
+    surcharge DOUBLE PRECISION NOT NULL,
+    day DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX report_events_day_idx ON report_events (day, pickup_point_id);
+
+-- Events of orders still stored are moved from the outbox, deleted orders are lost as before
+INSERT INTO report_events (event_type, order_id, pickup_point_id, packaging_type, cost, surcharge, day, created_at)
+SELECT e.event_type, e.order_id, o.pickup_point_id, COALESCE(p.type, ''), o.cost,
+       CASE p.type WHEN 'пакет' THEN 5 WHEN 'коробка' THEN 20 WHEN 'пленка' THEN 1 ELSE 0 END,
+       e.created_at::date, e.created_at
+FROM outbox e
+JOIN orders o ON o.id = e.order_id
+LEFT JOIN packaging_types p ON p.id = o.packaging_type_id
+WHERE e.event_type IN ('OrderAccepted', 'OrderIssued', 'ReturnAccepted')
+ORDER BY e.id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE report_events;
+-- +goose StatementEnd
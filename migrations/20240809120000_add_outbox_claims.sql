@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A replica claims the pending messages of a sink until claimed_until and sends them outside of the transaction
+ALTER TABLE outbox_sinks
+    ADD COLUMN claim_token TEXT,
+    ADD COLUMN claimed_until TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE outbox_sinks
+    DROP COLUMN claim_token,
+    DROP COLUMN claimed_until;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Last runs are compared with the clock of the service, the time zone keeps them comparable
+-- whatever the time zones of the database and the service
+ALTER TABLE scheduler_jobs
+    ALTER COLUMN last_run_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE scheduler_jobs
+    ALTER COLUMN last_run_at TYPE TIMESTAMP,
+    ALTER COLUMN updated_at TYPE TIMESTAMP;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A replica claims due deliveries until claimed_until and sends them outside of the transaction
+ALTER TABLE webhook_deliveries
+    ADD COLUMN claim_token TEXT,
+    ADD COLUMN claimed_until TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE webhook_deliveries
+    DROP COLUMN claim_token,
+    DROP COLUMN claimed_until;
+-- +goose StatementEnd
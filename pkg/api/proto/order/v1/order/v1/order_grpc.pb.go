@@ -19,12 +19,37 @@ import (
 const _ = grpc.SupportPackageIsVersion8
 
 const (
-	OrderService_AcceptOrder_FullMethodName  = "/order.OrderService/AcceptOrder"
-	OrderService_ReturnOrder_FullMethodName  = "/order.OrderService/ReturnOrder"
-	OrderService_IssueOrder_FullMethodName   = "/order.OrderService/IssueOrder"
-	OrderService_ListOrders_FullMethodName   = "/order.OrderService/ListOrders"
-	OrderService_AcceptReturn_FullMethodName = "/order.OrderService/AcceptReturn"
-	OrderService_ListReturns_FullMethodName  = "/order.OrderService/ListReturns"
+	OrderService_AcceptOrder_FullMethodName           = "/order.OrderService/AcceptOrder"
+	OrderService_ReturnOrder_FullMethodName           = "/order.OrderService/ReturnOrder"
+	OrderService_IssueOrder_FullMethodName            = "/order.OrderService/IssueOrder"
+	OrderService_UnlockOrder_FullMethodName           = "/order.OrderService/UnlockOrder"
+	OrderService_RefuseOrder_FullMethodName           = "/order.OrderService/RefuseOrder"
+	OrderService_ListOrders_FullMethodName            = "/order.OrderService/ListOrders"
+	OrderService_AcceptReturn_FullMethodName          = "/order.OrderService/AcceptReturn"
+	OrderService_ListReturns_FullMethodName           = "/order.OrderService/ListReturns"
+	OrderService_CreateWebhook_FullMethodName         = "/order.OrderService/CreateWebhook"
+	OrderService_ListWebhooks_FullMethodName          = "/order.OrderService/ListWebhooks"
+	OrderService_DeleteWebhook_FullMethodName         = "/order.OrderService/DeleteWebhook"
+	OrderService_ListWebhookDeliveries_FullMethodName = "/order.OrderService/ListWebhookDeliveries"
+	OrderService_CreateCourier_FullMethodName         = "/order.OrderService/CreateCourier"
+	OrderService_OpenSession_FullMethodName           = "/order.OrderService/OpenSession"
+	OrderService_CloseSession_FullMethodName          = "/order.OrderService/CloseSession"
+	OrderService_CreateAuthorization_FullMethodName   = "/order.OrderService/CreateAuthorization"
+	OrderService_ListAuthorizations_FullMethodName    = "/order.OrderService/ListAuthorizations"
+	OrderService_CreatePickupPoint_FullMethodName     = "/order.OrderService/CreatePickupPoint"
+	OrderService_ListPickupPoints_FullMethodName      = "/order.OrderService/ListPickupPoints"
+	OrderService_CreateTransfer_FullMethodName        = "/order.OrderService/CreateTransfer"
+	OrderService_ReceiveTransfer_FullMethodName       = "/order.OrderService/ReceiveTransfer"
+	OrderService_CreateRack_FullMethodName            = "/order.OrderService/CreateRack"
+	OrderService_ListCells_FullMethodName             = "/order.OrderService/ListCells"
+	OrderService_Stocktake_FullMethodName             = "/order.OrderService/Stocktake"
+	OrderService_ExportOrders_FullMethodName          = "/order.OrderService/ExportOrders"
+	OrderService_GetReport_FullMethodName             = "/order.OrderService/GetReport"
+	OrderService_AcceptOrders_FullMethodName          = "/order.OrderService/AcceptOrders"
+	OrderService_CreateReturnManifest_FullMethodName  = "/order.OrderService/CreateReturnManifest"
+	OrderService_SetContact_FullMethodName            = "/order.OrderService/SetContact"
+	OrderService_ListDeadLetters_FullMethodName       = "/order.OrderService/ListDeadLetters"
+	OrderService_ReplayDeadLetter_FullMethodName      = "/order.OrderService/ReplayDeadLetter"
 )
 
 // OrderServiceClient is the client API for OrderService service.
@@ -34,9 +59,39 @@ type OrderServiceClient interface {
 	AcceptOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
 	ReturnOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
 	IssueOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
+	UnlockOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
+	RefuseOrder(ctx context.Context, in *RefuseOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
 	ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListResponse, error)
 	AcceptReturn(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
 	ListReturns(ctx context.Context, in *ListReturnsRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*WebhookInfo, error)
+	ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*OrderResponse, error)
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+	CreateCourier(ctx context.Context, in *CreateCourierRequest, opts ...grpc.CallOption) (*CourierInfo, error)
+	OpenSession(ctx context.Context, in *OpenSessionRequest, opts ...grpc.CallOption) (*SessionInfo, error)
+	CloseSession(ctx context.Context, in *CloseSessionRequest, opts ...grpc.CallOption) (*SessionSummary, error)
+	CreateAuthorization(ctx context.Context, in *CreateAuthorizationRequest, opts ...grpc.CallOption) (*AuthorizationInfo, error)
+	ListAuthorizations(ctx context.Context, in *ListAuthorizationsRequest, opts ...grpc.CallOption) (*ListAuthorizationsResponse, error)
+	CreatePickupPoint(ctx context.Context, in *CreatePickupPointRequest, opts ...grpc.CallOption) (*PickupPointInfo, error)
+	ListPickupPoints(ctx context.Context, in *ListPickupPointsRequest, opts ...grpc.CallOption) (*ListPickupPointsResponse, error)
+	CreateTransfer(ctx context.Context, in *CreateTransferRequest, opts ...grpc.CallOption) (*TransferInfo, error)
+	ReceiveTransfer(ctx context.Context, in *ReceiveTransferRequest, opts ...grpc.CallOption) (*TransferInfo, error)
+	CreateRack(ctx context.Context, in *CreateRackRequest, opts ...grpc.CallOption) (*RackInfo, error)
+	ListCells(ctx context.Context, in *ListCellsRequest, opts ...grpc.CallOption) (*ListCellsResponse, error)
+	// Stocktake receives order IDs scanned on the shelves and returns the saved discrepancy report
+	Stocktake(ctx context.Context, opts ...grpc.CallOption) (OrderService_StocktakeClient, error)
+	// ExportOrders streams the file with orders or returns selected with the filters of ListOrders and ListReturns
+	ExportOrders(ctx context.Context, in *ExportOrdersRequest, opts ...grpc.CallOption) (OrderService_ExportOrdersClient, error)
+	// GetReport aggregates accepted, issued and returned orders by day and packaging type
+	GetReport(ctx context.Context, in *GetReportRequest, opts ...grpc.CallOption) (*ReportInfo, error)
+	// AcceptOrders accepts a batch of orders in one transaction, the result of every order is returned in its position
+	AcceptOrders(ctx context.Context, in *AcceptOrdersRequest, opts ...grpc.CallOption) (*AcceptOrdersResponse, error)
+	// CreateReturnManifest hands over expired orders to the courier except orders brought by couriers of other companies
+	CreateReturnManifest(ctx context.Context, in *CreateReturnManifestRequest, opts ...grpc.CallOption) (*ReturnManifestInfo, error)
+	SetContact(ctx context.Context, in *SetContactRequest, opts ...grpc.CallOption) (*OrderResponse, error)
+	ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (*ListDeadLettersResponse, error)
+	ReplayDeadLetter(ctx context.Context, in *ReplayDeadLetterRequest, opts ...grpc.CallOption) (*OrderResponse, error)
 }
 
 type orderServiceClient struct {
@@ -77,6 +132,26 @@ func (c *orderServiceClient) IssueOrder(ctx context.Context, in *OrderRequest, o
 	return out, nil
 }
 
+func (c *orderServiceClient) UnlockOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*OrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_UnlockOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) RefuseOrder(ctx context.Context, in *RefuseOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_RefuseOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *orderServiceClient) ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListResponse)
@@ -107,6 +182,284 @@ func (c *orderServiceClient) ListReturns(ctx context.Context, in *ListReturnsReq
 	return out, nil
 }
 
+func (c *orderServiceClient) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*WebhookInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WebhookInfo)
+	err := c.cc.Invoke(ctx, OrderService_CreateWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*OrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_DeleteWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListWebhookDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) CreateCourier(ctx context.Context, in *CreateCourierRequest, opts ...grpc.CallOption) (*CourierInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CourierInfo)
+	err := c.cc.Invoke(ctx, OrderService_CreateCourier_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) OpenSession(ctx context.Context, in *OpenSessionRequest, opts ...grpc.CallOption) (*SessionInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionInfo)
+	err := c.cc.Invoke(ctx, OrderService_OpenSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) CloseSession(ctx context.Context, in *CloseSessionRequest, opts ...grpc.CallOption) (*SessionSummary, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionSummary)
+	err := c.cc.Invoke(ctx, OrderService_CloseSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) CreateAuthorization(ctx context.Context, in *CreateAuthorizationRequest, opts ...grpc.CallOption) (*AuthorizationInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthorizationInfo)
+	err := c.cc.Invoke(ctx, OrderService_CreateAuthorization_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ListAuthorizations(ctx context.Context, in *ListAuthorizationsRequest, opts ...grpc.CallOption) (*ListAuthorizationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuthorizationsResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListAuthorizations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) CreatePickupPoint(ctx context.Context, in *CreatePickupPointRequest, opts ...grpc.CallOption) (*PickupPointInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PickupPointInfo)
+	err := c.cc.Invoke(ctx, OrderService_CreatePickupPoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ListPickupPoints(ctx context.Context, in *ListPickupPointsRequest, opts ...grpc.CallOption) (*ListPickupPointsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPickupPointsResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListPickupPoints_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) CreateTransfer(ctx context.Context, in *CreateTransferRequest, opts ...grpc.CallOption) (*TransferInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferInfo)
+	err := c.cc.Invoke(ctx, OrderService_CreateTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ReceiveTransfer(ctx context.Context, in *ReceiveTransferRequest, opts ...grpc.CallOption) (*TransferInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferInfo)
+	err := c.cc.Invoke(ctx, OrderService_ReceiveTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) CreateRack(ctx context.Context, in *CreateRackRequest, opts ...grpc.CallOption) (*RackInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RackInfo)
+	err := c.cc.Invoke(ctx, OrderService_CreateRack_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ListCells(ctx context.Context, in *ListCellsRequest, opts ...grpc.CallOption) (*ListCellsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCellsResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListCells_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) Stocktake(ctx context.Context, opts ...grpc.CallOption) (OrderService_StocktakeClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[0], OrderService_Stocktake_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &orderServiceStocktakeClient{ClientStream: stream}
+	return x, nil
+}
+
+type OrderService_StocktakeClient interface {
+	Send(*StocktakeScan) error
+	CloseAndRecv() (*StocktakeReport, error)
+	grpc.ClientStream
+}
+
+type orderServiceStocktakeClient struct {
+	grpc.ClientStream
+}
+
+func (x *orderServiceStocktakeClient) Send(m *StocktakeScan) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *orderServiceStocktakeClient) CloseAndRecv() (*StocktakeReport, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(StocktakeReport)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *orderServiceClient) ExportOrders(ctx context.Context, in *ExportOrdersRequest, opts ...grpc.CallOption) (OrderService_ExportOrdersClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[1], OrderService_ExportOrders_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &orderServiceExportOrdersClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OrderService_ExportOrdersClient interface {
+	Recv() (*ExportChunk, error)
+	grpc.ClientStream
+}
+
+type orderServiceExportOrdersClient struct {
+	grpc.ClientStream
+}
+
+func (x *orderServiceExportOrdersClient) Recv() (*ExportChunk, error) {
+	m := new(ExportChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *orderServiceClient) GetReport(ctx context.Context, in *GetReportRequest, opts ...grpc.CallOption) (*ReportInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportInfo)
+	err := c.cc.Invoke(ctx, OrderService_GetReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) AcceptOrders(ctx context.Context, in *AcceptOrdersRequest, opts ...grpc.CallOption) (*AcceptOrdersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AcceptOrdersResponse)
+	err := c.cc.Invoke(ctx, OrderService_AcceptOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) CreateReturnManifest(ctx context.Context, in *CreateReturnManifestRequest, opts ...grpc.CallOption) (*ReturnManifestInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReturnManifestInfo)
+	err := c.cc.Invoke(ctx, OrderService_CreateReturnManifest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) SetContact(ctx context.Context, in *SetContactRequest, opts ...grpc.CallOption) (*OrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_SetContact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (*ListDeadLettersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDeadLettersResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ReplayDeadLetter(ctx context.Context, in *ReplayDeadLetterRequest, opts ...grpc.CallOption) (*OrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_ReplayDeadLetter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrderServiceServer is the server API for OrderService service.
 // All implementations must embed UnimplementedOrderServiceServer
 // for forward compatibility
@@ -114,9 +467,39 @@ type OrderServiceServer interface {
 	AcceptOrder(context.Context, *OrderRequest) (*OrderResponse, error)
 	ReturnOrder(context.Context, *OrderRequest) (*OrderResponse, error)
 	IssueOrder(context.Context, *OrderRequest) (*OrderResponse, error)
+	UnlockOrder(context.Context, *OrderRequest) (*OrderResponse, error)
+	RefuseOrder(context.Context, *RefuseOrderRequest) (*OrderResponse, error)
 	ListOrders(context.Context, *ListOrdersRequest) (*ListResponse, error)
 	AcceptReturn(context.Context, *OrderRequest) (*OrderResponse, error)
 	ListReturns(context.Context, *ListReturnsRequest) (*ListResponse, error)
+	CreateWebhook(context.Context, *CreateWebhookRequest) (*WebhookInfo, error)
+	ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error)
+	DeleteWebhook(context.Context, *DeleteWebhookRequest) (*OrderResponse, error)
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+	CreateCourier(context.Context, *CreateCourierRequest) (*CourierInfo, error)
+	OpenSession(context.Context, *OpenSessionRequest) (*SessionInfo, error)
+	CloseSession(context.Context, *CloseSessionRequest) (*SessionSummary, error)
+	CreateAuthorization(context.Context, *CreateAuthorizationRequest) (*AuthorizationInfo, error)
+	ListAuthorizations(context.Context, *ListAuthorizationsRequest) (*ListAuthorizationsResponse, error)
+	CreatePickupPoint(context.Context, *CreatePickupPointRequest) (*PickupPointInfo, error)
+	ListPickupPoints(context.Context, *ListPickupPointsRequest) (*ListPickupPointsResponse, error)
+	CreateTransfer(context.Context, *CreateTransferRequest) (*TransferInfo, error)
+	ReceiveTransfer(context.Context, *ReceiveTransferRequest) (*TransferInfo, error)
+	CreateRack(context.Context, *CreateRackRequest) (*RackInfo, error)
+	ListCells(context.Context, *ListCellsRequest) (*ListCellsResponse, error)
+	// Stocktake receives order IDs scanned on the shelves and returns the saved discrepancy report
+	Stocktake(OrderService_StocktakeServer) error
+	// ExportOrders streams the file with orders or returns selected with the filters of ListOrders and ListReturns
+	ExportOrders(*ExportOrdersRequest, OrderService_ExportOrdersServer) error
+	// GetReport aggregates accepted, issued and returned orders by day and packaging type
+	GetReport(context.Context, *GetReportRequest) (*ReportInfo, error)
+	// AcceptOrders accepts a batch of orders in one transaction, the result of every order is returned in its position
+	AcceptOrders(context.Context, *AcceptOrdersRequest) (*AcceptOrdersResponse, error)
+	// CreateReturnManifest hands over expired orders to the courier except orders brought by couriers of other companies
+	CreateReturnManifest(context.Context, *CreateReturnManifestRequest) (*ReturnManifestInfo, error)
+	SetContact(context.Context, *SetContactRequest) (*OrderResponse, error)
+	ListDeadLetters(context.Context, *ListDeadLettersRequest) (*ListDeadLettersResponse, error)
+	ReplayDeadLetter(context.Context, *ReplayDeadLetterRequest) (*OrderResponse, error)
 	mustEmbedUnimplementedOrderServiceServer()
 }
 
@@ -133,6 +516,12 @@ func (UnimplementedOrderServiceServer) ReturnOrder(context.Context, *OrderReques
 func (UnimplementedOrderServiceServer) IssueOrder(context.Context, *OrderRequest) (*OrderResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method IssueOrder not implemented")
 }
+func (UnimplementedOrderServiceServer) UnlockOrder(context.Context, *OrderRequest) (*OrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlockOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) RefuseOrder(context.Context, *RefuseOrderRequest) (*OrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefuseOrder not implemented")
+}
 func (UnimplementedOrderServiceServer) ListOrders(context.Context, *ListOrdersRequest) (*ListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListOrders not implemented")
 }
@@ -142,6 +531,75 @@ func (UnimplementedOrderServiceServer) AcceptReturn(context.Context, *OrderReque
 func (UnimplementedOrderServiceServer) ListReturns(context.Context, *ListReturnsRequest) (*ListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListReturns not implemented")
 }
+func (UnimplementedOrderServiceServer) CreateWebhook(context.Context, *CreateWebhookRequest) (*WebhookInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (UnimplementedOrderServiceServer) ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedOrderServiceServer) DeleteWebhook(context.Context, *DeleteWebhookRequest) (*OrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedOrderServiceServer) ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (UnimplementedOrderServiceServer) CreateCourier(context.Context, *CreateCourierRequest) (*CourierInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCourier not implemented")
+}
+func (UnimplementedOrderServiceServer) OpenSession(context.Context, *OpenSessionRequest) (*SessionInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OpenSession not implemented")
+}
+func (UnimplementedOrderServiceServer) CloseSession(context.Context, *CloseSessionRequest) (*SessionSummary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseSession not implemented")
+}
+func (UnimplementedOrderServiceServer) CreateAuthorization(context.Context, *CreateAuthorizationRequest) (*AuthorizationInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAuthorization not implemented")
+}
+func (UnimplementedOrderServiceServer) ListAuthorizations(context.Context, *ListAuthorizationsRequest) (*ListAuthorizationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuthorizations not implemented")
+}
+func (UnimplementedOrderServiceServer) CreatePickupPoint(context.Context, *CreatePickupPointRequest) (*PickupPointInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePickupPoint not implemented")
+}
+func (UnimplementedOrderServiceServer) ListPickupPoints(context.Context, *ListPickupPointsRequest) (*ListPickupPointsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPickupPoints not implemented")
+}
+func (UnimplementedOrderServiceServer) CreateTransfer(context.Context, *CreateTransferRequest) (*TransferInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTransfer not implemented")
+}
+func (UnimplementedOrderServiceServer) ReceiveTransfer(context.Context, *ReceiveTransferRequest) (*TransferInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReceiveTransfer not implemented")
+}
+func (UnimplementedOrderServiceServer) CreateRack(context.Context, *CreateRackRequest) (*RackInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRack not implemented")
+}
+func (UnimplementedOrderServiceServer) ListCells(context.Context, *ListCellsRequest) (*ListCellsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCells not implemented")
+}
+func (UnimplementedOrderServiceServer) Stocktake(OrderService_StocktakeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Stocktake not implemented")
+}
+func (UnimplementedOrderServiceServer) ExportOrders(*ExportOrdersRequest, OrderService_ExportOrdersServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportOrders not implemented")
+}
+func (UnimplementedOrderServiceServer) GetReport(context.Context, *GetReportRequest) (*ReportInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReport not implemented")
+}
+func (UnimplementedOrderServiceServer) AcceptOrders(context.Context, *AcceptOrdersRequest) (*AcceptOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptOrders not implemented")
+}
+func (UnimplementedOrderServiceServer) CreateReturnManifest(context.Context, *CreateReturnManifestRequest) (*ReturnManifestInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateReturnManifest not implemented")
+}
+func (UnimplementedOrderServiceServer) SetContact(context.Context, *SetContactRequest) (*OrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetContact not implemented")
+}
+func (UnimplementedOrderServiceServer) ListDeadLetters(context.Context, *ListDeadLettersRequest) (*ListDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeadLetters not implemented")
+}
+func (UnimplementedOrderServiceServer) ReplayDeadLetter(context.Context, *ReplayDeadLetterRequest) (*OrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayDeadLetter not implemented")
+}
 func (UnimplementedOrderServiceServer) mustEmbedUnimplementedOrderServiceServer() {}
 
 // UnsafeOrderServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -209,6 +667,42 @@ func _OrderService_IssueOrder_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_UnlockOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).UnlockOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_UnlockOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).UnlockOrder(ctx, req.(*OrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_RefuseOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefuseOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).RefuseOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_RefuseOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).RefuseOrder(ctx, req.(*RefuseOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _OrderService_ListOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListOrdersRequest)
 	if err := dec(in); err != nil {
@@ -263,8 +757,433 @@ func _OrderService_ListReturns_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
-// OrderService_ServiceDesc is the grpc.ServiceDesc for OrderService service.
-// It's only intended for direct use with grpc.RegisterService,
+func _OrderService_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_CreateWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).CreateWebhook(ctx, req.(*CreateWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListWebhooks(ctx, req.(*ListWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_DeleteWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).DeleteWebhook(ctx, req.(*DeleteWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_CreateCourier_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCourierRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).CreateCourier(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_CreateCourier_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).CreateCourier(ctx, req.(*CreateCourierRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_OpenSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OpenSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).OpenSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_OpenSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).OpenSession(ctx, req.(*OpenSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_CloseSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).CloseSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_CloseSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).CloseSession(ctx, req.(*CloseSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_CreateAuthorization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAuthorizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).CreateAuthorization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_CreateAuthorization_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).CreateAuthorization(ctx, req.(*CreateAuthorizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ListAuthorizations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuthorizationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListAuthorizations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListAuthorizations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListAuthorizations(ctx, req.(*ListAuthorizationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_CreatePickupPoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePickupPointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).CreatePickupPoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_CreatePickupPoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).CreatePickupPoint(ctx, req.(*CreatePickupPointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ListPickupPoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPickupPointsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListPickupPoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListPickupPoints_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListPickupPoints(ctx, req.(*ListPickupPointsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_CreateTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).CreateTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_CreateTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).CreateTransfer(ctx, req.(*CreateTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ReceiveTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReceiveTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ReceiveTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ReceiveTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ReceiveTransfer(ctx, req.(*ReceiveTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_CreateRack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).CreateRack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_CreateRack_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).CreateRack(ctx, req.(*CreateRackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ListCells_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCellsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListCells(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListCells_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListCells(ctx, req.(*ListCellsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_Stocktake_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(OrderServiceServer).Stocktake(&orderServiceStocktakeServer{ServerStream: stream})
+}
+
+type OrderService_StocktakeServer interface {
+	SendAndClose(*StocktakeReport) error
+	Recv() (*StocktakeScan, error)
+	grpc.ServerStream
+}
+
+type orderServiceStocktakeServer struct {
+	grpc.ServerStream
+}
+
+func (x *orderServiceStocktakeServer) SendAndClose(m *StocktakeReport) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *orderServiceStocktakeServer) Recv() (*StocktakeScan, error) {
+	m := new(StocktakeScan)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _OrderService_ExportOrders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportOrdersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderServiceServer).ExportOrders(m, &orderServiceExportOrdersServer{ServerStream: stream})
+}
+
+type OrderService_ExportOrdersServer interface {
+	Send(*ExportChunk) error
+	grpc.ServerStream
+}
+
+type orderServiceExportOrdersServer struct {
+	grpc.ServerStream
+}
+
+func (x *orderServiceExportOrdersServer) Send(m *ExportChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _OrderService_GetReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetReport(ctx, req.(*GetReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_AcceptOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).AcceptOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_AcceptOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).AcceptOrders(ctx, req.(*AcceptOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_CreateReturnManifest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateReturnManifestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).CreateReturnManifest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_CreateReturnManifest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).CreateReturnManifest(ctx, req.(*CreateReturnManifestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_SetContact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetContactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).SetContact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_SetContact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).SetContact(ctx, req.(*SetContactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ListDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListDeadLetters(ctx, req.(*ListDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ReplayDeadLetter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayDeadLetterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ReplayDeadLetter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ReplayDeadLetter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ReplayDeadLetter(ctx, req.(*ReplayDeadLetterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OrderService_ServiceDesc is the grpc.ServiceDesc for OrderService service.
+// It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var OrderService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "order.OrderService",
@@ -282,6 +1201,14 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "IssueOrder",
 			Handler:    _OrderService_IssueOrder_Handler,
 		},
+		{
+			MethodName: "UnlockOrder",
+			Handler:    _OrderService_UnlockOrder_Handler,
+		},
+		{
+			MethodName: "RefuseOrder",
+			Handler:    _OrderService_RefuseOrder_Handler,
+		},
 		{
 			MethodName: "ListOrders",
 			Handler:    _OrderService_ListOrders_Handler,
@@ -294,7 +1221,102 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListReturns",
 			Handler:    _OrderService_ListReturns_Handler,
 		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _OrderService_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _OrderService_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _OrderService_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _OrderService_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "CreateCourier",
+			Handler:    _OrderService_CreateCourier_Handler,
+		},
+		{
+			MethodName: "OpenSession",
+			Handler:    _OrderService_OpenSession_Handler,
+		},
+		{
+			MethodName: "CloseSession",
+			Handler:    _OrderService_CloseSession_Handler,
+		},
+		{
+			MethodName: "CreateAuthorization",
+			Handler:    _OrderService_CreateAuthorization_Handler,
+		},
+		{
+			MethodName: "ListAuthorizations",
+			Handler:    _OrderService_ListAuthorizations_Handler,
+		},
+		{
+			MethodName: "CreatePickupPoint",
+			Handler:    _OrderService_CreatePickupPoint_Handler,
+		},
+		{
+			MethodName: "ListPickupPoints",
+			Handler:    _OrderService_ListPickupPoints_Handler,
+		},
+		{
+			MethodName: "CreateTransfer",
+			Handler:    _OrderService_CreateTransfer_Handler,
+		},
+		{
+			MethodName: "ReceiveTransfer",
+			Handler:    _OrderService_ReceiveTransfer_Handler,
+		},
+		{
+			MethodName: "CreateRack",
+			Handler:    _OrderService_CreateRack_Handler,
+		},
+		{
+			MethodName: "ListCells",
+			Handler:    _OrderService_ListCells_Handler,
+		},
+		{
+			MethodName: "GetReport",
+			Handler:    _OrderService_GetReport_Handler,
+		},
+		{
+			MethodName: "AcceptOrders",
+			Handler:    _OrderService_AcceptOrders_Handler,
+		},
+		{
+			MethodName: "CreateReturnManifest",
+			Handler:    _OrderService_CreateReturnManifest_Handler,
+		},
+		{
+			MethodName: "SetContact",
+			Handler:    _OrderService_SetContact_Handler,
+		},
+		{
+			MethodName: "ListDeadLetters",
+			Handler:    _OrderService_ListDeadLetters_Handler,
+		},
+		{
+			MethodName: "ReplayDeadLetter",
+			Handler:    _OrderService_ReplayDeadLetter_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Stocktake",
+			Handler:       _OrderService_Stocktake_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportOrders",
+			Handler:       _OrderService_ExportOrders_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "order/v1/order.proto",
 }
@@ -0,0 +1,41 @@
+//go:build integration
+
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestIssueOrderByAuthorization(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+	auths := postgresql.NewAuthorization(db.DB)
+	order := &models.Order{OrderID: 97, UserID: 3, Deadline: time.Now().Add(24 * time.Hour), Cost: 100, Weight: 5}
+	require.NoError(t, repo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost)))
+
+	// act
+	auth, err := auths.CreateAuthorization(models.Authorization{UserID: 3, Recipient: "Петров Петр", ValidUntil: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	list, err := auths.ListAuthorizations(3)
+	require.NoError(t, err)
+	err = repo.IssueOrder(order.OrderID, "hash", models.IssueRecord{ReceivedBy: "Петров Петр", AuthorizationID: auth.ID})
+	require.NoError(t, err)
+	issued, err := repo.GetOrderByID(order.OrderID)
+	require.NoError(t, err)
+
+	// assert
+	require.Len(t, list, 1)
+	assert.Equal(t, auth.ID, list[0].ID)
+	assert.Equal(t, 0, list[0].OrderID, "Authorization for all orders has no order")
+	assert.Equal(t, "Петров Петр", issued.ReceivedBy)
+	assert.Equal(t, auth.ID, issued.AuthorizationID)
+}
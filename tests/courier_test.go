@@ -0,0 +1,100 @@
+//go:build integration
+
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestAcceptanceSession(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+	couriers := postgresql.NewCourier(db.DB)
+	courierID, err := couriers.CreateCourier(models.Courier{Name: "Иван", Company: "Быстрая доставка"})
+	require.NoError(t, err, "CreateCourier should not error")
+	session, err := couriers.OpenSession(courierID, 3)
+	require.NoError(t, err, "OpenSession should not error")
+
+	for _, order := range []*models.Order{
+		{OrderID: 80, UserID: 1, Deadline: time.Now().Add(time.Hour), Cost: 100, Weight: 5, SessionID: session.ID},
+		{OrderID: 81, UserID: 1, Deadline: time.Now().Add(time.Hour), Cost: 100, Weight: 2.5, SessionID: session.ID},
+	} {
+		require.NoError(t, repo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost)))
+	}
+	require.NoError(t, couriers.RecordRejection(session.ID, models.SessionRejection{OrderID: 82, Reason: "недопустимый тип упаковки"}))
+
+	// act
+	summary, err := couriers.CloseSession(session.ID)
+	require.NoError(t, err, "CloseSession should not error")
+	_, closeAgainErr := couriers.CloseSession(session.ID)
+	acceptErr := repo.AcceptOrder(&models.Order{OrderID: 83, UserID: 1, Deadline: time.Now().Add(time.Hour), Cost: 100, Weight: 1, SessionID: session.ID},
+		models.NewPackagingType(models.Package, models.PackageCost))
+
+	// assert
+	assert.Equal(t, 2, summary.Accepted)
+	assert.Equal(t, 7.5, summary.TotalWeight)
+	assert.Equal(t, 1, summary.Missing())
+	assert.Equal(t, []models.SessionRejection{{OrderID: 82, Reason: "недопустимый тип упаковки"}}, summary.Rejections)
+	assert.True(t, summary.Session.Closed())
+	assert.ErrorIs(t, closeAgainErr, postgresql.ErrSessionClosed)
+	assert.ErrorIs(t, acceptErr, postgresql.ErrSessionClosed, "Orders can't be accepted in a closed session")
+
+	accepted, err := repo.GetOrderByID(80)
+	require.NoError(t, err)
+	assert.Equal(t, session.ID, accepted.SessionID)
+}
+
+func TestCloseSession_CountsOrdersLinkedWhileWaiting(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+	couriers := postgresql.NewCourier(db.DB)
+	courierID, err := couriers.CreateCourier(models.Courier{Name: "Иван", Company: "Быстрая доставка"})
+	require.NoError(t, err, "CreateCourier should not error")
+	session, err := couriers.OpenSession(courierID, 1)
+	require.NoError(t, err, "OpenSession should not error")
+	order := &models.Order{OrderID: 84, UserID: 1, Deadline: time.Now().Add(time.Hour), Cost: 100, Weight: 3}
+	require.NoError(t, repo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost)))
+
+	// act: the order is linked to the session while CloseSession waits for the session lock
+	type result struct {
+		summary *models.SessionSummary
+		err     error
+	}
+	closed := make(chan result, 1)
+	err = db.DB.RunRepeatableRead(context.Background(), func(ctx context.Context) error {
+		qe := db.DB.GetQueryEngine(ctx)
+		if _, err := qe.Exec(ctx, "SELECT id FROM acceptance_sessions WHERE id = $1 FOR SHARE", session.ID); err != nil {
+			return err
+		}
+		if _, err := qe.Exec(ctx, "UPDATE orders SET session_id = $1 WHERE id = $2", session.ID, order.OrderID); err != nil {
+			return err
+		}
+
+		go func() {
+			summary, err := couriers.CloseSession(session.ID)
+			closed <- result{summary: summary, err: err}
+		}()
+		time.Sleep(200 * time.Millisecond)
+		return nil
+	})
+	require.NoError(t, err)
+	res := <-closed
+
+	// assert
+	require.NoError(t, res.err, "CloseSession should not error")
+	assert.Equal(t, 1, res.summary.Accepted, "The order committed while waiting is counted")
+	assert.Equal(t, 3.0, res.summary.TotalWeight)
+}
@@ -0,0 +1,55 @@
+//go:build integration
+
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestExportOrders(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+	now := time.Now()
+	for _, order := range []models.Order{
+		{OrderID: 1, UserID: 1, IsReturned: true},
+		{OrderID: 2, UserID: 2, IsReturned: true},
+		{OrderID: 3, UserID: 1},
+		{OrderID: 4, UserID: 1},
+	} {
+		_, err := db.DB.GetQueryEngine(context.Background()).Exec(context.Background(),
+			"INSERT INTO orders (id, user_id, deadline, is_returned, cost, weight) VALUES ($1, $2, $3, $4, 100, 5)",
+			order.OrderID, order.UserID, now, order.IsReturned)
+		require.NoError(t, err)
+	}
+	export := func(filter models.ExportFilter) []int {
+		var orderIDs []int
+		err := repo.ExportOrders(filter, func(order models.Order) error {
+			orderIDs = append(orderIDs, order.OrderID)
+			return nil
+		})
+		require.NoError(t, err)
+		return orderIDs
+	}
+
+	// act
+	allOrders := export(models.ExportFilter{Kind: models.OrdersExport})
+	userOrders := export(models.ExportFilter{Kind: models.OrdersExport, UserID: 1, LastN: 2})
+	allReturns := export(models.ExportFilter{Kind: models.ReturnsExport})
+	returnsPage := export(models.ExportFilter{Kind: models.ReturnsExport, Page: 2, PageSize: 1})
+
+	// assert
+	assert.Equal(t, []int{4, 3, 2, 1}, allOrders)
+	assert.Equal(t, []int{4, 3}, userOrders)
+	assert.Equal(t, []int{2, 1}, allReturns)
+	assert.Equal(t, []int{1}, returnsPage)
+}
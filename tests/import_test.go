@@ -0,0 +1,40 @@
+//go:build integration
+
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestAcceptOrders(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB).ForPoint(models.DefaultPickupPointID)
+	newOrder := func(orderID int) *models.Order {
+		return &models.Order{OrderID: orderID, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), Cost: 100, Weight: 5}
+	}
+	packageType := models.NewPackagingType(models.Package, models.PackageCost)
+
+	// act
+	err := repo.AcceptOrders([]*models.Order{newOrder(1), newOrder(2)},
+		[]*models.PackagingType{packageType, packageType})
+	duplicateErr := repo.AcceptOrders([]*models.Order{newOrder(3), newOrder(1)},
+		[]*models.PackagingType{packageType, packageType})
+	_, notFoundErr := repo.GetOrderByID(3)
+	orders, listErr := repo.GetAllOrders()
+
+	// assert
+	require.NoError(t, err)
+	assert.Error(t, duplicateErr, "Duplicate order fails the whole batch")
+	assert.ErrorIs(t, notFoundErr, postgresql.ErrOrderNotFound, "Batch is stored in one transaction")
+	require.NoError(t, listErr)
+	assert.Len(t, orders, 2)
+}
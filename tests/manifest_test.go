@@ -0,0 +1,44 @@
+//go:build integration
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestSaveManifestItem(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.NewManifest(db.DB)
+	item := models.ManifestItem{ManifestID: "m-1", OrderID: 30, Reason: "срок хранения не может быть в прошлом"}
+
+	// act: the second save of the same item is ignored
+	require.NoError(t, repo.SaveManifestItem(item))
+	require.NoError(t, repo.SaveManifestItem(models.ManifestItem{ManifestID: "m-1", OrderID: 30, Accepted: true}))
+	saved, err := repo.GetManifestItem("m-1", 30)
+
+	// assert
+	require.NoError(t, err, "GetManifestItem should not error")
+	assert.Equal(t, item, *saved, "First result should be kept")
+}
+
+func TestGetManifestItem_NotFound(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.NewManifest(db.DB)
+
+	// act
+	_, err := repo.GetManifestItem("m-2", 31)
+
+	// assert
+	assert.ErrorIs(t, err, postgresql.ErrManifestItemNotFound)
+}
@@ -0,0 +1,66 @@
+//go:build integration
+
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestSaveContact(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.NewNotification(db.DB)
+
+	// act: the second save replaces the contact
+	require.NoError(t, repo.SaveContact(models.Contact{UserID: 1, Phone: "+79990000000", Locale: models.LocaleRu}))
+	require.NoError(t, repo.SaveContact(models.Contact{UserID: 1, Email: "client@example.com", Locale: models.LocaleEn}))
+	contact, err := repo.GetContact(1)
+
+	// assert
+	require.NoError(t, err, "GetContact should not error")
+	assert.Equal(t, models.Contact{UserID: 1, Email: "client@example.com", Locale: models.LocaleEn}, *contact)
+}
+
+func TestListOrdersToRemind(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+	notificationRepo := postgresql.NewNotification(db.DB)
+	expiring := &models.Order{OrderID: 50, UserID: 1, Deadline: time.Now().Add(12 * time.Hour), Cost: 100, Weight: 5}
+	reminded := &models.Order{OrderID: 51, UserID: 1, Deadline: time.Now().Add(12 * time.Hour), Cost: 100, Weight: 5}
+	later := &models.Order{OrderID: 52, UserID: 1, Deadline: time.Now().Add(72 * time.Hour), Cost: 100, Weight: 5}
+	inTransit := &models.Order{OrderID: 53, UserID: 1, Deadline: time.Now().Add(12 * time.Hour), Cost: 100, Weight: 5}
+	handedOver := &models.Order{OrderID: 54, UserID: 1, Deadline: time.Now().Add(12 * time.Hour), Cost: 100, Weight: 5}
+	for _, order := range []*models.Order{expiring, reminded, later, inTransit, handedOver} {
+		require.NoError(t, repo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost)))
+	}
+	point, err := postgresql.NewPickupPoint(db.DB).CreatePickupPoint(models.PickupPoint{Name: "Северный"})
+	require.NoError(t, err)
+	_, err = repo.ForPoint(models.DefaultPickupPointID).CreateTransfer(point.ID, []int{inTransit.OrderID})
+	require.NoError(t, err)
+	_, err = db.DB.GetQueryEngine(context.Background()).Exec(context.Background(),
+		"UPDATE orders SET returned_to_courier_at = NOW() WHERE id = $1", handedOver.OrderID)
+	require.NoError(t, err)
+	require.NoError(t, notificationRepo.SaveNotification(models.Notification{
+		OrderID: reminded.OrderID, UserID: 1, Kind: models.ExpiryReminder, Channel: "sms",
+	}))
+
+	// act
+	orders, err := notificationRepo.ListOrdersToRemind(time.Now().Add(24 * time.Hour))
+
+	// assert
+	require.NoError(t, err, "ListOrdersToRemind should not error")
+	require.Len(t, orders, 1, "Only the expiring order without reminder at the point should be listed")
+	assert.Equal(t, expiring.OrderID, orders[0].OrderID)
+}
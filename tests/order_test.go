@@ -113,7 +113,7 @@ func TestIssueOrder(t *testing.T) {
 	newHash := "newHashValue"
 
 	// Act
-	err = repo.IssueOrder(order.OrderID, newHash)
+	err = repo.IssueOrder(order.OrderID, newHash, models.IssueRecord{})
 	require.NoError(t, err, "IssueOrder should not error")
 
 	// Assert
@@ -127,6 +127,31 @@ func TestIssueOrder(t *testing.T) {
 	assert.Equal(t, newHash, hash, "Hash should match the new hash value")
 }
 
+func TestIssueOrderTwice(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+	order := &models.Order{OrderID: 7, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), Cost: 100, Weight: 5}
+	require.NoError(t, repo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost)))
+
+	// act
+	firstErr := repo.IssueOrder(order.OrderID, "hash", models.IssueRecord{})
+	secondErr := repo.IssueOrder(order.OrderID, "hash", models.IssueRecord{})
+	refuseErr := repo.RefuseOrder(order.OrderID, "поврежден")
+
+	// assert
+	require.NoError(t, firstErr)
+	assert.ErrorIs(t, secondErr, postgresql.ErrOrderStateChanged, "Issued order can't be issued again")
+	assert.ErrorIs(t, refuseErr, postgresql.ErrOrderStateChanged, "Issued order can't be refused")
+	var issuedEvents int
+	err := db.DB.GetQueryEngine(context.Background()).QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM outbox WHERE order_id = $1 AND event_type = $2", order.OrderID, string(models.OrderIssued)).Scan(&issuedEvents)
+	require.NoError(t, err)
+	assert.Equal(t, 1, issuedEvents)
+}
+
 func TestListOrders(t *testing.T) {
 	// arrange
 	db.SetUp(t)
@@ -311,3 +336,26 @@ func TestGetOrderByID(t *testing.T) {
 	assert.Equal(t, testOrder.IsReturned, retrievedOrder.IsReturned, "IsReturned should match")
 	assert.Equal(t, testOrder.Hash, retrievedOrder.Hash, "Hash should match")
 }
+
+func TestListStoredOrders(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+
+	// Prepare Test Data
+	now := time.Now()
+	_, err := db.DB.GetQueryEngine(context.Background()).Exec(context.Background(),
+		"INSERT INTO orders (id, user_id, deadline, issued_to_user, is_returned, cost, weight, hash) VALUES ($1, 1, $2, false, false, 100, 5, ''), ($3, 1, $4, false, false, 100, 5, ''), ($5, 1, $6, true, false, 100, 5, '')",
+		12, now.Add(24*time.Hour), 13, now.Add(-24*time.Hour), 14, now.Add(24*time.Hour))
+	require.NoError(t, err, "Inserting test orders should not error")
+
+	// Act
+	orders, err := repo.ListStoredOrders()
+	require.NoError(t, err, "ListStoredOrders should not error")
+
+	// Assert
+	require.Len(t, orders, 1, "Only not issued orders with a future deadline should be returned")
+	assert.Equal(t, 12, orders[0].OrderID, "OrderID should match")
+}
@@ -0,0 +1,159 @@
+//go:build integration
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestAcceptOrderWritesOutbox(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+	order := &models.Order{OrderID: 20, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), Cost: 100, Weight: 5}
+
+	// act
+	err := repo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost))
+	require.NoError(t, err, "AcceptOrder should not error")
+
+	// assert
+	var eventType string
+	err = db.DB.GetQueryEngine(context.Background()).QueryRow(context.Background(),
+		"SELECT event_type FROM outbox WHERE order_id = $1", order.OrderID).Scan(&eventType)
+	require.NoError(t, err, "Querying outbox should not error")
+	assert.Equal(t, string(models.OrderAccepted), eventType, "Event type should match")
+}
+
+func TestProcessPending(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+	outboxRepo := postgresql.NewOutbox(db.DB)
+	order := &models.Order{OrderID: 21, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), Cost: 100, Weight: 5}
+	require.NoError(t, repo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost)))
+
+	// act: the first attempt fails, the second one succeeds
+	sent, err := outboxRepo.ProcessPending("kafka", 10, func(models.OutboxMessage) error {
+		return errors.New("kafka is down")
+	})
+	require.NoError(t, err, "ProcessPending should not error")
+	assert.Zero(t, sent, "Nothing should be sent")
+
+	sent, err = outboxRepo.ProcessPending("kafka", 10, func(models.OutboxMessage) error {
+		return nil
+	})
+	require.NoError(t, err, "ProcessPending should not error")
+
+	// assert
+	assert.Equal(t, 1, sent, "Message should be sent")
+	var attempts int
+	err = db.DB.GetQueryEngine(context.Background()).QueryRow(context.Background(),
+		`SELECT d.attempts FROM outbox_deliveries d JOIN outbox o ON o.id = d.outbox_id
+		WHERE o.order_id = $1 AND d.sink = 'kafka' AND d.sent_at IS NOT NULL`, order.OrderID).Scan(&attempts)
+	require.NoError(t, err, "Querying outbox should not error")
+	assert.Equal(t, 2, attempts, "Both attempts should be recorded")
+}
+
+func TestProcessPending_SinksAreIndependent(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+	outboxRepo := postgresql.NewOutbox(db.DB)
+	order := &models.Order{OrderID: 22, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), Cost: 100, Weight: 5}
+	require.NoError(t, repo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost)))
+
+	// act
+	kafkaSent, err := outboxRepo.ProcessPending("kafka", 10, func(models.OutboxMessage) error {
+		return errors.New("kafka is down")
+	})
+	require.NoError(t, err, "ProcessPending should not error")
+	fileSent, err := outboxRepo.ProcessPending("file", 10, func(models.OutboxMessage) error {
+		return nil
+	})
+	require.NoError(t, err, "ProcessPending should not error")
+
+	// assert
+	assert.Zero(t, kafkaSent, "Nothing should be sent to kafka")
+	assert.Equal(t, 1, fileSent, "Message should be written to file")
+}
+
+func TestProcessPending_SendsOutsideOfTransaction(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+	outboxRepo := postgresql.NewOutbox(db.DB)
+	order := &models.Order{OrderID: 23, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), Cost: 100, Weight: 5}
+	require.NoError(t, repo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost)))
+
+	// act: another replica polls the sink while the message is being sent
+	var concurrentSent int
+	var concurrentErr error
+	var claimed bool
+	sent, err := outboxRepo.ProcessPending("kafka", 10, func(models.OutboxMessage) error {
+		concurrentSent, concurrentErr = outboxRepo.ProcessPending("kafka", 10, func(models.OutboxMessage) error {
+			return nil
+		})
+		concurrentErr = errors.Join(concurrentErr, db.DB.GetQueryEngine(context.Background()).QueryRow(context.Background(),
+			"SELECT claimed_until IS NOT NULL FROM outbox_sinks WHERE sink = 'kafka'").Scan(&claimed))
+		return nil
+	})
+
+	// assert
+	require.NoError(t, err, "ProcessPending should not error")
+	assert.Equal(t, 1, sent, "Message should be sent")
+	require.NoError(t, concurrentErr)
+	assert.True(t, claimed, "The claim is committed before the messages are sent")
+	assert.Zero(t, concurrentSent, "Claimed messages are not sent by another replica")
+}
+
+func TestPurgeDelivered(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+	outboxRepo := postgresql.NewOutbox(db.DB)
+	for _, orderID := range []int{24, 25} {
+		order := &models.Order{OrderID: orderID, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), Cost: 100, Weight: 5}
+		require.NoError(t, repo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost)))
+	}
+	_, err := outboxRepo.ProcessPending("file", 10, func(msg models.OutboxMessage) error {
+		if msg.OrderID == 25 {
+			return errors.New("disk is full")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	_, err = outboxRepo.ProcessPending("kafka", 10, func(models.OutboxMessage) error {
+		return nil
+	})
+	require.NoError(t, err)
+
+	// act
+	purged, err := outboxRepo.PurgeDelivered(0)
+
+	// assert
+	require.NoError(t, err, "PurgeDelivered should not error")
+	assert.Equal(t, 1, purged, "Only the message delivered to every sink is purged")
+	var left int
+	err = db.DB.GetQueryEngine(context.Background()).QueryRow(context.Background(),
+		"SELECT order_id FROM outbox").Scan(&left)
+	require.NoError(t, err)
+	assert.Equal(t, 25, left)
+}
@@ -0,0 +1,44 @@
+//go:build integration
+
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestPickupPointScope(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	point, err := postgresql.NewPickupPoint(db.DB).CreatePickupPoint(models.PickupPoint{Name: "Северный"})
+	require.NoError(t, err)
+	repo := postgresql.New(db.DB)
+	northRepo := repo.ForPoint(point.ID)
+	defaultRepo := repo.ForPoint(models.DefaultPickupPointID)
+	order := &models.Order{OrderID: 98, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), Cost: 100, Weight: 5}
+	require.NoError(t, northRepo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost)))
+
+	// act
+	own, ownErr := northRepo.GetOrderByID(order.OrderID)
+	_, otherErr := defaultRepo.GetOrderByID(order.OrderID)
+	issueErr := defaultRepo.IssueOrder(order.OrderID, "hash", models.IssueRecord{})
+	all, allErr := repo.GetOrderByID(order.OrderID)
+	otherList, listErr := defaultRepo.ListOrders(1, 10)
+
+	// assert
+	require.NoError(t, ownErr)
+	assert.Equal(t, point.ID, own.PickupPointID)
+	assert.ErrorIs(t, otherErr, postgresql.ErrOrderNotFound)
+	assert.ErrorIs(t, issueErr, postgresql.ErrOrderNotFound, "Order of another point can't be issued")
+	require.NoError(t, allErr, "Repo of all points sees every order")
+	assert.Equal(t, point.ID, all.PickupPointID)
+	require.NoError(t, listErr)
+	assert.Empty(t, otherList)
+}
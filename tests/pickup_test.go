@@ -0,0 +1,64 @@
+//go:build integration
+
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+	"route/internal/app/pickup"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestPickupFailures(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+	order := &models.Order{OrderID: 95, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), Cost: 100, Weight: 5,
+		PickupCodeHash: pickup.HashCode(95, "123456")}
+	require.NoError(t, repo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost)))
+
+	// act
+	first, err := repo.RecordPickupFailure(order.OrderID, 2)
+	require.NoError(t, err)
+	second, err := repo.RecordPickupFailure(order.OrderID, 2)
+	require.NoError(t, err)
+	err = repo.UnlockPickup(order.OrderID)
+	require.NoError(t, err)
+	unlocked, err := repo.GetOrderByID(order.OrderID)
+	require.NoError(t, err)
+
+	// assert
+	assert.Equal(t, pickup.HashCode(95, "123456"), first.PickupCodeHash)
+	assert.Equal(t, 1, first.PickupFailures)
+	assert.True(t, first.PickupLockedAt.IsZero())
+	assert.Equal(t, 2, second.PickupFailures)
+	assert.False(t, second.PickupLockedAt.IsZero(), "Order is locked after max failures")
+	assert.Equal(t, 0, unlocked.PickupFailures)
+	assert.True(t, unlocked.PickupLockedAt.IsZero())
+}
+
+func TestIssueOrderOverride(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+	order := &models.Order{OrderID: 96, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), Cost: 100, Weight: 5}
+	require.NoError(t, repo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost)))
+
+	// act
+	err := repo.IssueOrder(order.OrderID, "hash", models.IssueRecord{OverrideBy: "Иванов"})
+	require.NoError(t, err)
+	issued, err := repo.GetOrderByID(order.OrderID)
+	require.NoError(t, err)
+
+	// assert
+	assert.True(t, issued.IssuedToUser)
+	assert.Equal(t, "Иванов", issued.IssueOverrideBy)
+}
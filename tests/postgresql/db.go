@@ -38,6 +38,58 @@ func (d *TDB) SetUp(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Не удалось очистить таблицу packaging_types: %v", err)
 	}
+	_, err = d.DB.GetQueryEngine(context.Background()).Exec(context.Background(), "TRUNCATE TABLE outbox, outbox_sinks CASCADE")
+	if err != nil {
+		t.Fatalf("Не удалось очистить таблицу outbox: %v", err)
+	}
+	_, err = d.DB.GetQueryEngine(context.Background()).Exec(context.Background(), "TRUNCATE TABLE manifest_items")
+	if err != nil {
+		t.Fatalf("Не удалось очистить таблицу manifest_items: %v", err)
+	}
+	_, err = d.DB.GetQueryEngine(context.Background()).Exec(context.Background(), "TRUNCATE TABLE webhook_subscriptions CASCADE")
+	if err != nil {
+		t.Fatalf("Не удалось очистить таблицу webhook_subscriptions: %v", err)
+	}
+	_, err = d.DB.GetQueryEngine(context.Background()).Exec(context.Background(), "TRUNCATE TABLE user_contacts, notifications")
+	if err != nil {
+		t.Fatalf("Не удалось очистить таблицы уведомлений: %v", err)
+	}
+	_, err = d.DB.GetQueryEngine(context.Background()).Exec(context.Background(), "TRUNCATE TABLE scheduler_jobs")
+	if err != nil {
+		t.Fatalf("Не удалось очистить таблицу scheduler_jobs: %v", err)
+	}
+	_, err = d.DB.GetQueryEngine(context.Background()).Exec(context.Background(), "TRUNCATE TABLE return_manifests CASCADE")
+	if err != nil {
+		t.Fatalf("Не удалось очистить таблицу return_manifests: %v", err)
+	}
+	_, err = d.DB.GetQueryEngine(context.Background()).Exec(context.Background(), "TRUNCATE TABLE couriers, acceptance_sessions, session_rejections CASCADE")
+	if err != nil {
+		t.Fatalf("Не удалось очистить таблицы курьеров: %v", err)
+	}
+	_, err = d.DB.GetQueryEngine(context.Background()).Exec(context.Background(), "TRUNCATE TABLE pickup_authorizations CASCADE")
+	if err != nil {
+		t.Fatalf("Не удалось очистить таблицу pickup_authorizations: %v", err)
+	}
+	_, err = d.DB.GetQueryEngine(context.Background()).Exec(context.Background(), "TRUNCATE TABLE transfers, transfer_items CASCADE")
+	if err != nil {
+		t.Fatalf("Не удалось очистить таблицы перемещений: %v", err)
+	}
+	_, err = d.DB.GetQueryEngine(context.Background()).Exec(context.Background(), "TRUNCATE TABLE storage_racks, storage_cells CASCADE")
+	if err != nil {
+		t.Fatalf("Не удалось очистить таблицы ячеек хранения: %v", err)
+	}
+	_, err = d.DB.GetQueryEngine(context.Background()).Exec(context.Background(), "TRUNCATE TABLE stocktakes, stocktake_items")
+	if err != nil {
+		t.Fatalf("Не удалось очистить таблицы инвентаризаций: %v", err)
+	}
+	_, err = d.DB.GetQueryEngine(context.Background()).Exec(context.Background(), "TRUNCATE TABLE report_events")
+	if err != nil {
+		t.Fatalf("Не удалось очистить таблицу report_events: %v", err)
+	}
+	_, err = d.DB.GetQueryEngine(context.Background()).Exec(context.Background(), "DELETE FROM pickup_points WHERE id <> 1")
+	if err != nil {
+		t.Fatalf("Не удалось очистить таблицу pickup_points: %v", err)
+	}
 }
 
 func (d *TDB) TearDown(t *testing.T) {
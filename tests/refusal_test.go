@@ -0,0 +1,46 @@
+//go:build integration
+
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestRefuseOrder(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+	couriers := postgresql.NewCourier(db.DB)
+	courierID, err := couriers.CreateCourier(models.Courier{Name: "Иван", Company: "Быстрая доставка"})
+	require.NoError(t, err)
+	session, err := couriers.OpenSession(courierID, 1)
+	require.NoError(t, err)
+	order := &models.Order{OrderID: 90, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), Cost: 100, Weight: 5, SessionID: session.ID}
+	require.NoError(t, repo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost)))
+
+	// act
+	err = repo.RefuseOrder(order.OrderID, "поврежден")
+	require.NoError(t, err, "RefuseOrder should not error")
+	refused, err := repo.GetOrderByID(order.OrderID)
+	require.NoError(t, err)
+	returns, err := repo.ListReturns(1, 10)
+	require.NoError(t, err)
+	manifest, err := repo.CreateReturnManifest(courierID)
+	require.NoError(t, err, "Refused order should be handed over before its deadline")
+
+	// assert
+	assert.False(t, refused.RefusedAt.IsZero())
+	assert.Equal(t, "поврежден", refused.RefusalReason)
+	assert.False(t, refused.IssuedToUser, "Refused order is never issued")
+	assert.Empty(t, returns, "Refused order is not a return")
+	require.Len(t, manifest.Orders, 1)
+	assert.Equal(t, order.OrderID, manifest.Orders[0].OrderID)
+}
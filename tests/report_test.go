@@ -0,0 +1,70 @@
+//go:build integration
+
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestGetReport(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+	reports := postgresql.NewReport(db.DB)
+	newOrder := func(orderID int, cost float64) *models.Order {
+		return &models.Order{OrderID: orderID, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), Cost: cost, Weight: 5}
+	}
+	box, film := newOrder(1, 120), newOrder(2, 11)
+	require.NoError(t, repo.AcceptOrder(box, models.NewPackagingType(models.Box, models.BoxCost)))
+	require.NoError(t, repo.AcceptOrder(film, models.NewPackagingType(models.Film, models.FilmCost)))
+	require.NoError(t, repo.IssueOrder(box.OrderID, "hash", models.IssueRecord{}))
+	require.NoError(t, repo.IssueOrder(film.OrderID, "hash", models.IssueRecord{}))
+	require.NoError(t, repo.AcceptReturn(*box))
+	from, to := time.Now().AddDate(0, 0, -1), time.Now().AddDate(0, 0, 2)
+
+	// act
+	rows, err := reports.GetReport(0, from, to)
+	otherPointRows, otherPointErr := reports.GetReport(2, from, to)
+
+	// assert
+	require.NoError(t, err)
+	total := models.Report{Rows: rows}.Total()
+	assert.Equal(t, models.ReportRow{Accepted: 2, Issued: 2, Returned: 1, SurchargeRevenue: models.BoxCost + models.FilmCost, Refunds: 120}, total)
+	byType := make(map[models.PackageType]int)
+	for _, row := range rows {
+		byType[row.PackagingType] += row.Accepted
+	}
+	assert.Equal(t, map[models.PackageType]int{models.Box: 1, models.Film: 1}, byType)
+	require.NoError(t, otherPointErr)
+	assert.Empty(t, otherPointRows)
+}
+
+func TestGetReport_KeepsReturnedToCourier(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+	reports := postgresql.NewReport(db.DB)
+	order := &models.Order{OrderID: 1, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), Cost: 120, Weight: 5}
+	require.NoError(t, repo.AcceptOrder(order, models.NewPackagingType(models.Box, models.BoxCost)))
+	require.NoError(t, repo.ReturnOrder(order.OrderID))
+	from, to := time.Now(), time.Now().AddDate(0, 0, 1)
+
+	// act
+	rows, err := reports.GetReport(0, from, to)
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, rows, 1, "Orders deleted on return to the courier stay in reports")
+	assert.Equal(t, models.Box, rows[0].PackagingType)
+	assert.Equal(t, 1, rows[0].Accepted)
+}
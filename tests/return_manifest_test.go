@@ -0,0 +1,63 @@
+//go:build integration
+
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestCreateReturnManifest(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+	couriers := postgresql.NewCourier(db.DB)
+	openSession := func(company string) int64 {
+		courierID, err := couriers.CreateCourier(models.Courier{Name: "Иван", Company: company})
+		require.NoError(t, err)
+		session, err := couriers.OpenSession(courierID, 0)
+		require.NoError(t, err)
+		return session.ID
+	}
+	sessionID, otherSessionID := openSession("Быстрая доставка"), openSession("Медленная доставка")
+	courierID, err := couriers.CreateCourier(models.Courier{Name: "Петр", Company: "Быстрая доставка"})
+	require.NoError(t, err)
+	expired := &models.Order{OrderID: 70, UserID: 1, Deadline: time.Now().Add(-time.Hour), Cost: 100, Weight: 5, SessionID: sessionID}
+	expiredToo := &models.Order{OrderID: 71, UserID: 2, Deadline: time.Now().Add(-2 * time.Hour), Cost: 100, Weight: 3, SessionID: sessionID}
+	stored := &models.Order{OrderID: 72, UserID: 1, Deadline: time.Now().Add(time.Hour), Cost: 100, Weight: 5, SessionID: sessionID}
+	otherCompany := &models.Order{OrderID: 73, UserID: 1, Deadline: time.Now().Add(-time.Hour), Cost: 100, Weight: 5, SessionID: otherSessionID}
+	withoutSession := &models.Order{OrderID: 74, UserID: 1, Deadline: time.Now().Add(-time.Hour), Cost: 100, Weight: 5}
+	for _, order := range []*models.Order{expired, expiredToo, stored, otherCompany, withoutSession} {
+		require.NoError(t, repo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost)))
+	}
+
+	// act: the second manifest has nothing to hand over
+	manifest, err := repo.CreateReturnManifest(courierID)
+	require.NoError(t, err, "CreateReturnManifest should not error")
+	_, err = repo.CreateReturnManifest(courierID)
+
+	// assert
+	assert.ErrorIs(t, err, postgresql.ErrNoOrdersToReturn)
+	assert.Equal(t, courierID, manifest.CourierID)
+	require.Len(t, manifest.Orders, 3)
+	assert.Equal(t, expired.OrderID, manifest.Orders[0].OrderID)
+	assert.Equal(t, expiredToo.OrderID, manifest.Orders[1].OrderID)
+	assert.Equal(t, withoutSession.OrderID, manifest.Orders[2].OrderID, "Orders accepted without a session have no company")
+
+	handedOver, err := repo.GetOrderByID(expired.OrderID)
+	require.NoError(t, err)
+	assert.False(t, handedOver.ReturnedToCourierAt.IsZero(), "Order should be marked handed over")
+	notHandedOver, err := repo.GetOrderByID(stored.OrderID)
+	require.NoError(t, err)
+	assert.True(t, notHandedOver.ReturnedToCourierAt.IsZero(), "Stored order should not be handed over")
+	notHandedOver, err = repo.GetOrderByID(otherCompany.OrderID)
+	require.NoError(t, err)
+	assert.True(t, notHandedOver.ReturnedToCourierAt.IsZero(), "Orders of other companies should not be handed over")
+}
@@ -0,0 +1,79 @@
+//go:build integration
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestMarkExpired(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB)
+	expired := &models.Order{OrderID: 60, UserID: 1, Deadline: time.Now().Add(-time.Hour), Cost: 100, Weight: 5}
+	stored := &models.Order{OrderID: 61, UserID: 1, Deadline: time.Now().Add(time.Hour), Cost: 100, Weight: 5}
+	for _, order := range []*models.Order{expired, stored} {
+		require.NoError(t, repo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost)))
+	}
+
+	// act: the second run finds nothing new
+	marked, err := repo.MarkExpired()
+	require.NoError(t, err, "MarkExpired should not error")
+	markedAgain, err := repo.MarkExpired()
+	require.NoError(t, err, "MarkExpired should not error")
+	toReturn, err := repo.ListExpiredOrders()
+	require.NoError(t, err, "ListExpiredOrders should not error")
+
+	// assert
+	require.Len(t, marked, 1)
+	assert.Equal(t, expired.OrderID, marked[0].OrderID)
+	assert.False(t, marked[0].ExpiredAt.IsZero())
+	assert.Empty(t, markedAgain)
+	require.Len(t, toReturn, 1)
+	assert.Equal(t, expired.OrderID, toReturn[0].OrderID)
+
+	var events int
+	err = db.DB.GetQueryEngine(context.Background()).QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM outbox WHERE order_id = $1 AND event_type = $2", expired.OrderID, string(models.OrderExpired)).Scan(&events)
+	require.NoError(t, err)
+	assert.Equal(t, 1, events, "OrderExpired event should be written once")
+}
+
+func TestRunLocked(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.NewScheduler(db.DB)
+	var lastRuns []time.Time
+	run := func(lastRun time.Time) (bool, error) {
+		lastRuns = append(lastRuns, lastRun)
+		return true, nil
+	}
+
+	// act
+	locked, err := repo.RunLocked("job", func(lastRun time.Time) (bool, error) {
+		return true, errors.New("failed")
+	})
+	require.True(t, locked)
+	require.EqualError(t, err, "failed")
+	_, err = repo.RunLocked("job", run)
+	require.NoError(t, err, "RunLocked should not error")
+	_, err = repo.RunLocked("job", run)
+	require.NoError(t, err, "RunLocked should not error")
+
+	// assert: a failed run isn't recorded as the last run
+	require.Len(t, lastRuns, 2)
+	assert.True(t, lastRuns[0].IsZero())
+	assert.WithinDuration(t, time.Now(), lastRuns[1], time.Minute, "Last run is comparable with the clock of the service")
+}
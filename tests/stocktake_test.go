@@ -0,0 +1,41 @@
+//go:build integration
+
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+	"route/internal/app/module"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestStocktake(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.New(db.DB).ForPoint(models.DefaultPickupPointID)
+	for _, orderID := range []int{81, 82, 83} {
+		order := &models.Order{OrderID: orderID, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), Cost: 100, Weight: 5}
+		require.NoError(t, repo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost)))
+	}
+	require.NoError(t, repo.IssueOrder(83, "hash", models.IssueRecord{}))
+	mod := module.New(repo).WithStocktakes(postgresql.NewStocktake(db.DB))
+
+	// act
+	report, err := mod.Stocktake([]int{81, 83, 99})
+
+	// assert
+	require.NoError(t, err)
+	assert.NotZero(t, report.ID)
+	assert.Equal(t, 2, report.Stored)
+	assert.Equal(t, []models.Discrepancy{
+		{OrderID: 82, Kind: models.Missing},
+		{OrderID: 83, Kind: models.WronglyIssued},
+		{OrderID: 99, Kind: models.Unexpected},
+	}, report.Discrepancies)
+}
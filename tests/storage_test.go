@@ -0,0 +1,90 @@
+//go:build integration
+
+package tests
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestStorageCells(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	storage := postgresql.NewStorage(db.DB)
+	_, err := storage.CreateRack(models.Rack{PickupPointID: models.DefaultPickupPointID, Name: "A", Cells: []models.StorageCell{
+		{Code: "A-01", Size: models.CellLarge, MaxWeight: 30},
+		{Code: "A-02", Size: models.CellSmall, MaxWeight: 10},
+	}})
+	require.NoError(t, err)
+	repo := postgresql.New(db.DB).ForPoint(models.DefaultPickupPointID)
+	newOrder := func(orderID int) *models.Order {
+		return &models.Order{OrderID: orderID, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), Cost: 100, Weight: 5}
+	}
+	packageType := models.NewPackagingType(models.Package, models.PackageCost)
+	boxType := models.NewPackagingType(models.Box, models.BoxCost)
+
+	// act
+	small, large, full := newOrder(91), newOrder(92), newOrder(93)
+	smallErr := repo.AcceptOrder(small, packageType)
+	largeErr := repo.AcceptOrder(large, boxType)
+	fullErr := repo.AcceptOrder(full, packageType)
+	issueErr := repo.IssueOrder(large.OrderID, "hash", models.IssueRecord{})
+	afterIssueErr := repo.AcceptOrder(full, packageType)
+	cells, listErr := storage.ListCells(models.DefaultPickupPointID)
+
+	// assert
+	require.NoError(t, smallErr)
+	assert.Equal(t, "A-02", small.Cell, "Package goes to the smallest fitting cell")
+	require.NoError(t, largeErr)
+	assert.Equal(t, "A-01", large.Cell)
+	assert.ErrorIs(t, fullErr, postgresql.ErrNoFreeCell)
+	require.NoError(t, issueErr)
+	require.NoError(t, afterIssueErr, "Cell of the issued order is free again")
+	assert.Equal(t, "A-01", full.Cell)
+	require.NoError(t, listErr)
+	require.Len(t, cells, 2)
+	assert.Equal(t, full.OrderID, cells[0].OrderID)
+	assert.Equal(t, small.OrderID, cells[1].OrderID)
+}
+
+func TestStorageCells_ConcurrentAccepts(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	const orders = 5
+	cells := make([]models.StorageCell, orders)
+	for i := range cells {
+		cells[i] = models.StorageCell{Code: fmt.Sprintf("A-%02d", i+1), Size: models.CellSmall, MaxWeight: 10}
+	}
+	_, err := postgresql.NewStorage(db.DB).CreateRack(models.Rack{PickupPointID: models.DefaultPickupPointID, Name: "A", Cells: cells})
+	require.NoError(t, err)
+	repo := postgresql.New(db.DB).ForPoint(models.DefaultPickupPointID)
+
+	// act
+	errs := make([]error, orders)
+	var wg sync.WaitGroup
+	for i := 0; i < orders; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			order := &models.Order{OrderID: 100 + i, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), Cost: 100, Weight: 5}
+			errs[i] = repo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost))
+		}(i)
+	}
+	wg.Wait()
+
+	// assert
+	for _, err := range errs {
+		assert.NoError(t, err, "Orders accepted at the same time get different cells")
+	}
+}
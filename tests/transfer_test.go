@@ -0,0 +1,53 @@
+//go:build integration
+
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestTransfer(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	point, err := postgresql.NewPickupPoint(db.DB).CreatePickupPoint(models.PickupPoint{Name: "Северный"})
+	require.NoError(t, err)
+	repo := postgresql.New(db.DB)
+	fromRepo := repo.ForPoint(models.DefaultPickupPointID)
+	toRepo := repo.ForPoint(point.ID)
+	for _, orderID := range []int{96, 97} {
+		order := &models.Order{OrderID: orderID, UserID: 1, Deadline: time.Now().Add(24 * time.Hour), Cost: 100, Weight: 5}
+		require.NoError(t, fromRepo.AcceptOrder(order, models.NewPackagingType(models.Package, models.PackageCost)))
+	}
+
+	// act
+	transfer, createErr := fromRepo.CreateTransfer(point.ID, []int{96, 97})
+	_, againErr := fromRepo.CreateTransfer(point.ID, []int{96})
+	inTransit, getErr := fromRepo.GetOrderByID(96)
+	_, wrongPointErr := fromRepo.ReceiveTransfer(transfer.ID)
+	received, receiveErr := toRepo.ReceiveTransfer(transfer.ID)
+	_, receivedAgainErr := toRepo.ReceiveTransfer(transfer.ID)
+	moved, movedErr := toRepo.GetOrderByID(97)
+
+	// assert
+	require.NoError(t, createErr)
+	assert.Len(t, transfer.Orders, 2)
+	assert.ErrorIs(t, againErr, postgresql.ErrOrdersNotTransferable, "Order in transit can't be sent again")
+	require.NoError(t, getErr)
+	assert.Equal(t, transfer.ID, inTransit.TransferID)
+	assert.ErrorIs(t, wrongPointErr, postgresql.ErrTransferNotFound, "Only the destination point receives the transfer")
+	require.NoError(t, receiveErr)
+	assert.True(t, received.Received())
+	assert.Len(t, received.Orders, 2)
+	assert.ErrorIs(t, receivedAgainErr, postgresql.ErrTransferReceived)
+	require.NoError(t, movedErr)
+	assert.Equal(t, point.ID, moved.PickupPointID)
+	assert.Zero(t, moved.TransferID)
+}
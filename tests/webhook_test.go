@@ -0,0 +1,122 @@
+//go:build integration
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"route/internal/app/models"
+	"route/internal/app/repository/postgresql"
+)
+
+func TestWebhookDeliveries(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.NewWebhook(db.DB)
+	subID, err := repo.CreateSubscription(models.WebhookSubscription{
+		URL:        "http://localhost/hooks",
+		EventTypes: []models.EventType{models.OrderIssued},
+		Secret:     "secret",
+	})
+	require.NoError(t, err, "CreateSubscription should not error")
+	issued := models.OutboxMessage{EventID: "6f1c2a4e-0b7a-4a57-9a34-9b0f3f7c1d10", EventType: models.OrderIssued, OrderID: 40}
+	accepted := models.OutboxMessage{EventID: "0a3a8f0e-8f4d-4a56-8a0c-1c2d3e4f5a6b", EventType: models.OrderAccepted, OrderID: 40}
+
+	// act: the issued event is queued once, the accepted event has no subscribers
+	queued, err := repo.EnqueueDeliveries(issued, []byte("{}"))
+	require.NoError(t, err, "EnqueueDeliveries should not error")
+	requeued, err := repo.EnqueueDeliveries(issued, []byte("{}"))
+	require.NoError(t, err, "EnqueueDeliveries should not error")
+	ignored, err := repo.EnqueueDeliveries(accepted, []byte("{}"))
+	require.NoError(t, err, "EnqueueDeliveries should not error")
+
+	failed, err := repo.ProcessDueDeliveries(10, func(models.WebhookDelivery) models.WebhookAttempt {
+		return models.WebhookAttempt{StatusCode: 500, Error: "internal error", NextAttemptAt: time.Now().Add(-time.Second)}
+	})
+	require.NoError(t, err, "ProcessDueDeliveries should not error")
+	delivered, err := repo.ProcessDueDeliveries(10, func(d models.WebhookDelivery) models.WebhookAttempt {
+		assert.Equal(t, "secret", d.Secret)
+		assert.Equal(t, 1, d.Attempts)
+		return models.WebhookAttempt{StatusCode: 200, Delivered: true}
+	})
+	require.NoError(t, err, "ProcessDueDeliveries should not error")
+	left, err := repo.ProcessDueDeliveries(10, func(models.WebhookDelivery) models.WebhookAttempt {
+		return models.WebhookAttempt{Delivered: true}
+	})
+	require.NoError(t, err, "ProcessDueDeliveries should not error")
+	logs, err := repo.ListDeliveryLogs(subID, 10)
+	require.NoError(t, err, "ListDeliveryLogs should not error")
+
+	// assert
+	assert.Equal(t, 1, queued, "Event should be queued for the subscription")
+	assert.Zero(t, requeued, "Redelivered event should be ignored")
+	assert.Zero(t, ignored, "Event without subscribers should be ignored")
+	assert.Equal(t, 1, failed, "First attempt should fail")
+	assert.Equal(t, 1, delivered, "Second attempt should deliver")
+	assert.Zero(t, left, "Delivered webhook should not be processed again")
+	require.Len(t, logs, 2, "Both attempts should be logged")
+	assert.Equal(t, 2, logs[0].Attempt)
+	assert.Equal(t, 200, logs[0].StatusCode)
+	assert.Equal(t, "internal error", logs[1].Error)
+}
+
+func TestProcessDueDeliveries_SendsOutsideOfTransaction(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.NewWebhook(db.DB)
+	subID, err := repo.CreateSubscription(models.WebhookSubscription{
+		URL:        "http://localhost/hooks",
+		EventTypes: []models.EventType{models.OrderIssued},
+		Secret:     "secret",
+	})
+	require.NoError(t, err, "CreateSubscription should not error")
+	issued := models.OutboxMessage{EventID: "3c9d7a52-1f0e-4d8b-9a6e-2b4c6d8e0f12", EventType: models.OrderIssued, OrderID: 41}
+	_, err = repo.EnqueueDeliveries(issued, []byte("{}"))
+	require.NoError(t, err, "EnqueueDeliveries should not error")
+
+	// act: another replica polls deliveries while the webhook is being sent
+	var concurrent int
+	var concurrentErr error
+	var claimed bool
+	processed, err := repo.ProcessDueDeliveries(10, func(models.WebhookDelivery) models.WebhookAttempt {
+		concurrent, concurrentErr = repo.ProcessDueDeliveries(10, func(models.WebhookDelivery) models.WebhookAttempt {
+			return models.WebhookAttempt{Delivered: true}
+		})
+		concurrentErr = errors.Join(concurrentErr, db.DB.GetQueryEngine(context.Background()).QueryRow(context.Background(),
+			"SELECT claimed_until IS NOT NULL FROM webhook_deliveries WHERE event_id = $1", issued.EventID).Scan(&claimed))
+		return models.WebhookAttempt{StatusCode: 200, Delivered: true}
+	})
+	logs, logsErr := repo.ListDeliveryLogs(subID, 10)
+
+	// assert
+	require.NoError(t, err, "ProcessDueDeliveries should not error")
+	assert.Equal(t, 1, processed, "Webhook should be sent")
+	require.NoError(t, concurrentErr)
+	assert.True(t, claimed, "The claim is committed before the webhook is sent")
+	assert.Zero(t, concurrent, "Claimed deliveries are not sent by another replica")
+	require.NoError(t, logsErr)
+	assert.Len(t, logs, 1, "The attempt should be logged once")
+}
+
+func TestDeleteSubscription_NotFound(t *testing.T) {
+	// arrange
+	db.SetUp(t)
+	defer db.TearDown(t)
+
+	repo := postgresql.NewWebhook(db.DB)
+
+	// act
+	err := repo.DeleteSubscription(100)
+
+	// assert
+	assert.ErrorIs(t, err, postgresql.ErrWebhookNotFound)
+}